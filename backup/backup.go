@@ -0,0 +1,423 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+	"github.com/CaioBittencourt/arvore-genealogica/errors"
+)
+
+// FormatVersion is bumped whenever a record changes in a way older readers can't ignore.
+const FormatVersion = 1
+
+const manifestFileName = "manifest.json"
+
+const (
+	TreeCollection          = "tree"
+	PersonCollection        = "person"
+	PersonHistoryCollection = "person_history"
+)
+
+// CollectionManifest describes one JSON lines file of the archive. SHA256 is the hex digest of the file.
+type CollectionManifest struct {
+	Name   string `json:"name"`
+	File   string `json:"file"`
+	Count  int    `json:"count"`
+	SHA256 string `json:"sha256"`
+}
+
+// Manifest is the first file of the archive, so it can be read without decompressing the whole backup.
+type Manifest struct {
+	FormatVersion int                  `json:"formatVersion"`
+	CreatedAt     time.Time            `json:"createdAt"`
+	Collections   []CollectionManifest `json:"collections"`
+}
+
+type lifeEventRecord struct {
+	Date  string `json:"date,omitempty"`
+	Place string `json:"place,omitempty"`
+}
+
+type treeRecord struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Owner     string    `json:"owner,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type personRecord struct {
+	ID              string            `json:"id"`
+	TreeID          string            `json:"treeId,omitempty"`
+	Name            string            `json:"name"`
+	Gender          string            `json:"gender,omitempty"`
+	Birth           *lifeEventRecord  `json:"birth,omitempty"`
+	Death           *lifeEventRecord  `json:"death,omitempty"`
+	ParentIDS       []string          `json:"parentIds,omitempty"`
+	ParentRoles     map[string]string `json:"parentRoles,omitempty"`
+	ParentLinkTypes map[string]string `json:"parentLinkTypes,omitempty"`
+	ChildrenIDS     []string          `json:"childrenIds,omitempty"`
+	Version         int64             `json:"version,omitempty"`
+}
+
+type personHistoryRecord struct {
+	ID           string        `json:"id"`
+	TreeID       string        `json:"treeId,omitempty"`
+	PersonID     string        `json:"personId"`
+	ChangeType   string        `json:"changeType"`
+	Before       *personRecord `json:"before,omitempty"`
+	After        *personRecord `json:"after,omitempty"`
+	ClaimedActor string        `json:"actor,omitempty"`
+	Source       string        `json:"source,omitempty"`
+	Reason       string        `json:"reason,omitempty"`
+	Timestamp    time.Time     `json:"timestamp"`
+}
+
+func buildLifeEventRecord(lifeEvent *domain.LifeEvent) *lifeEventRecord {
+	if lifeEvent == nil {
+		return nil
+	}
+
+	return &lifeEventRecord{Date: lifeEvent.Date, Place: lifeEvent.Place}
+}
+
+func buildDomainLifeEvent(lifeEvent *lifeEventRecord) *domain.LifeEvent {
+	if lifeEvent == nil {
+		return nil
+	}
+
+	return &domain.LifeEvent{Date: lifeEvent.Date, Place: lifeEvent.Place}
+}
+
+func buildPersonRecord(treeID string, person domain.Person) personRecord {
+	record := personRecord{
+		ID:      person.ID,
+		TreeID:  treeID,
+		Name:    person.Name,
+		Gender:  string(person.Gender),
+		Birth:   buildLifeEventRecord(person.Birth),
+		Death:   buildLifeEventRecord(person.Death),
+		Version: person.Version,
+	}
+
+	for _, parent := range person.Parents {
+		record.ParentIDS = append(record.ParentIDS, parent.ID)
+		if role, ok := person.ParentRoles[parent.ID]; ok {
+			if record.ParentRoles == nil {
+				record.ParentRoles = make(map[string]string)
+			}
+			record.ParentRoles[parent.ID] = string(role)
+		}
+		if linkType, ok := person.ParentLinkTypes[parent.ID]; ok {
+			if record.ParentLinkTypes == nil {
+				record.ParentLinkTypes = make(map[string]string)
+			}
+			record.ParentLinkTypes[parent.ID] = string(linkType)
+		}
+	}
+
+	for _, children := range person.Children {
+		record.ChildrenIDS = append(record.ChildrenIDS, children.ID)
+	}
+
+	return record
+}
+
+func buildDomainPerson(record personRecord) domain.Person {
+	person := domain.Person{
+		ID:      record.ID,
+		Name:    record.Name,
+		Gender:  domain.GenderType(record.Gender),
+		Birth:   buildDomainLifeEvent(record.Birth),
+		Death:   buildDomainLifeEvent(record.Death),
+		Version: record.Version,
+	}
+
+	for _, parentID := range record.ParentIDS {
+		person.AddParent(&domain.Person{ID: parentID}, domain.ParentRoleType(record.ParentRoles[parentID]))
+		person.SetParentLinkType(parentID, domain.LinkType(record.ParentLinkTypes[parentID]))
+	}
+
+	for _, childrenID := range record.ChildrenIDS {
+		person.Children = append(person.Children, &domain.Person{ID: childrenID})
+	}
+
+	return person
+}
+
+func buildPersonRecordPointer(treeID string, person *domain.Person) *personRecord {
+	if person == nil {
+		return nil
+	}
+
+	record := buildPersonRecord(treeID, *person)
+	return &record
+}
+
+func buildDomainPersonPointer(record *personRecord) *domain.Person {
+	if record == nil {
+		return nil
+	}
+
+	person := buildDomainPerson(*record)
+	return &person
+}
+
+type collectionFile struct {
+	manifest CollectionManifest
+	content  []byte
+}
+
+func encodeCollection(name string, records []interface{}) (collectionFile, error) {
+	var content bytes.Buffer
+	encoder := json.NewEncoder(&content)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return collectionFile{}, err
+		}
+	}
+
+	digest := sha256.Sum256(content.Bytes())
+	return collectionFile{
+		manifest: CollectionManifest{Name: name, File: name + ".jsonl", Count: len(records), SHA256: hex.EncodeToString(digest[:])},
+		content:  content.Bytes(),
+	}, nil
+}
+
+// Write stores every collection of backup as a gzipped tar of JSON lines files, listed with their checksums on
+// a manifest that comes first.
+func Write(writer io.Writer, backup domain.Backup, createdAt time.Time) (*Manifest, error) {
+	var trees, persons, history []interface{}
+	for _, tree := range backup.Trees {
+		trees = append(trees, treeRecord{ID: tree.ID, Name: tree.Name, Owner: tree.Owner, CreatedAt: tree.CreatedAt})
+	}
+
+	for _, treePerson := range backup.Persons {
+		persons = append(persons, buildPersonRecord(treePerson.TreeID, treePerson.Person))
+	}
+
+	for _, treePersonChange := range backup.History {
+		change := treePersonChange.Change
+		history = append(history, personHistoryRecord{
+			ID:           change.ID,
+			TreeID:       treePersonChange.TreeID,
+			PersonID:     change.PersonID,
+			ChangeType:   string(change.Type),
+			Before:       buildPersonRecordPointer(treePersonChange.TreeID, change.Before),
+			After:        buildPersonRecordPointer(treePersonChange.TreeID, change.After),
+			ClaimedActor: change.ClaimedActor,
+			Source:       change.Source,
+			Reason:       change.Reason,
+			Timestamp:    change.Timestamp,
+		})
+	}
+
+	manifest := Manifest{FormatVersion: FormatVersion, CreatedAt: createdAt.UTC()}
+	var files []collectionFile
+	for _, collection := range []struct {
+		name    string
+		records []interface{}
+	}{
+		{TreeCollection, trees},
+		{PersonCollection, persons},
+		{PersonHistoryCollection, history},
+	} {
+		file, err := encodeCollection(collection.name, collection.records)
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, file)
+		manifest.Collections = append(manifest.Collections, file.manifest)
+	}
+
+	manifestContent, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	gzipWriter := gzip.NewWriter(writer)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	writeFile := func(name string, content []byte) error {
+		header := &tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), ModTime: manifest.CreatedAt}
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return err
+		}
+
+		_, err := tarWriter.Write(content)
+		return err
+	}
+
+	if err := writeFile(manifestFileName, manifestContent); err != nil {
+		return nil, err
+	}
+
+	for _, file := range files {
+		if err := writeFile(file.manifest.File, file.content); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return nil, err
+	}
+
+	if err := gzipWriter.Close(); err != nil {
+		return nil, err
+	}
+
+	return &manifest, nil
+}
+
+func invalidBackupError(format string, args ...interface{}) error {
+	return errors.NewApplicationError(fmt.Sprintf(format, args...), errors.InvalidBackupErrorCode)
+}
+
+func decodeCollection(file CollectionManifest, content []byte, decode func(decoder *json.Decoder) error) error {
+	digest := sha256.Sum256(content)
+	if hex.EncodeToString(digest[:]) != file.SHA256 {
+		return invalidBackupError("checksum mismatch on %s", file.File)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	count := 0
+	for decoder.More() {
+		if err := decode(decoder); err != nil {
+			return invalidBackupError("invalid record %d on %s: %s", count+1, file.File, err)
+		}
+		count++
+	}
+
+	if count != file.Count {
+		return invalidBackupError("%s has %d records, the manifest lists %d", file.File, count, file.Count)
+	}
+
+	return nil
+}
+
+// Read checks the format version, checksums and record counts of an archive written by Write before decoding it.
+func Read(reader io.Reader) (*domain.Backup, *Manifest, error) {
+	gzipReader, err := gzip.NewReader(reader)
+	if err != nil {
+		return nil, nil, invalidBackupError("backup is not gzipped: %s", err)
+	}
+	defer gzipReader.Close()
+
+	tarReader := tar.NewReader(gzipReader)
+
+	header, err := tarReader.Next()
+	if err != nil {
+		return nil, nil, invalidBackupError("backup is not a tar archive: %s", err)
+	}
+
+	if header.Name != manifestFileName {
+		return nil, nil, invalidBackupError("backup must start with %s, found %s", manifestFileName, header.Name)
+	}
+
+	var manifest Manifest
+	if err := json.NewDecoder(tarReader).Decode(&manifest); err != nil {
+		return nil, nil, invalidBackupError("invalid manifest: %s", err)
+	}
+
+	if manifest.FormatVersion < 1 || manifest.FormatVersion > FormatVersion {
+		return nil, nil, invalidBackupError("unsupported backup format version %d", manifest.FormatVersion)
+	}
+
+	collectionsByFile := make(map[string]CollectionManifest, len(manifest.Collections))
+	for _, collection := range manifest.Collections {
+		collectionsByFile[collection.File] = collection
+	}
+
+	contentsByCollection := map[string][]byte{}
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, invalidBackupError("invalid tar archive: %s", err)
+		}
+
+		collection, ok := collectionsByFile[header.Name]
+		if !ok {
+			return nil, nil, invalidBackupError("%s is not listed on the manifest", header.Name)
+		}
+
+		content, err := io.ReadAll(tarReader)
+		if err != nil {
+			return nil, nil, invalidBackupError("failed to read %s: %s", header.Name, err)
+		}
+		contentsByCollection[collection.Name] = content
+	}
+
+	backup := domain.Backup{}
+	for _, collection := range manifest.Collections {
+		content, ok := contentsByCollection[collection.Name]
+		if !ok {
+			return nil, nil, invalidBackupError("%s is listed on the manifest but missing from the backup", collection.File)
+		}
+
+		var decode func(decoder *json.Decoder) error
+		switch collection.Name {
+		case TreeCollection:
+			decode = func(decoder *json.Decoder) error {
+				var record treeRecord
+				if err := decoder.Decode(&record); err != nil {
+					return err
+				}
+
+				backup.Trees = append(backup.Trees, domain.Tree{ID: record.ID, Name: record.Name, Owner: record.Owner, CreatedAt: record.CreatedAt})
+				return nil
+			}
+		case PersonCollection:
+			decode = func(decoder *json.Decoder) error {
+				var record personRecord
+				if err := decoder.Decode(&record); err != nil {
+					return err
+				}
+
+				backup.Persons = append(backup.Persons, domain.TreePerson{TreeID: record.TreeID, Person: buildDomainPerson(record)})
+				return nil
+			}
+		case PersonHistoryCollection:
+			decode = func(decoder *json.Decoder) error {
+				var record personHistoryRecord
+				if err := decoder.Decode(&record); err != nil {
+					return err
+				}
+
+				backup.History = append(backup.History, domain.TreePersonChange{
+					TreeID: record.TreeID,
+					Change: domain.PersonChange{
+						ID:           record.ID,
+						PersonID:     record.PersonID,
+						Type:         domain.PersonChangeType(record.ChangeType),
+						Before:       buildDomainPersonPointer(record.Before),
+						After:        buildDomainPersonPointer(record.After),
+						ClaimedActor: record.ClaimedActor,
+						Source:       record.Source,
+						Reason:       record.Reason,
+						Timestamp:    record.Timestamp,
+					},
+				})
+				return nil
+			}
+		default:
+			return nil, nil, invalidBackupError("unknown collection %s", collection.Name)
+		}
+
+		if err := decodeCollection(collection, content, decode); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return &backup, &manifest, nil
+}
@@ -0,0 +1,153 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"testing"
+	"time"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+	"github.com/CaioBittencourt/arvore-genealogica/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+var createdAt = time.Date(2023, 3, 20, 12, 0, 0, 0, time.UTC)
+
+var familyBackup = domain.Backup{
+	Trees: []domain.Tree{{ID: "IDTree", Name: "Bittencourt", Owner: "caio", CreatedAt: createdAt}},
+	Persons: []domain.TreePerson{
+		{TreeID: "IDTree", Person: domain.Person{ID: "IDLuis", Name: "Luis", Gender: domain.Male, Children: []*domain.Person{{ID: "IDCaio"}}, Version: 2}},
+		{TreeID: "IDTree", Person: domain.Person{
+			ID:      "IDCaio",
+			Name:    "Caio",
+			Gender:  domain.Male,
+			Birth:   &domain.LifeEvent{Date: "1 JAN 1990", Place: "Porto Alegre"},
+			Parents: []*domain.Person{{ID: "IDLuis"}},
+			Version: 1,
+		}},
+		{Person: domain.Person{ID: "IDZeze", Name: "Zézé", Gender: domain.Female, Version: 1}},
+	},
+	History: []domain.TreePersonChange{
+		{TreeID: "IDTree", Change: domain.PersonChange{
+			ID:           "IDChange",
+			PersonID:     "IDLuis",
+			Type:         domain.PersonUpdatedChange,
+			Before:       &domain.Person{ID: "IDLuis", Name: "Luis", Gender: domain.Male, Version: 1},
+			After:        &domain.Person{ID: "IDLuis", Name: "Luis", Gender: domain.Male, Children: []*domain.Person{{ID: "IDCaio"}}, Version: 2},
+			ClaimedActor: "cli:caio",
+			Source:       "cli",
+			Timestamp:    createdAt,
+		}},
+	},
+}
+
+type archiveFile struct {
+	name    string
+	content string
+}
+
+func writeArchive(t *testing.T, files []archiveFile) []byte {
+	var archive bytes.Buffer
+	gzipWriter := gzip.NewWriter(&archive)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for _, file := range files {
+		assert.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: file.name, Mode: 0o644, Size: int64(len(file.content))}))
+		_, err := tarWriter.Write([]byte(file.content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, tarWriter.Close())
+	assert.NoError(t, gzipWriter.Close())
+
+	return archive.Bytes()
+}
+
+func TestWriteAndRead(t *testing.T) {
+	var archive bytes.Buffer
+	manifest, err := Write(&archive, familyBackup, createdAt)
+	assert.NoError(t, err)
+	assert.Equal(t, FormatVersion, manifest.FormatVersion)
+	assert.Equal(t, []string{TreeCollection, PersonCollection, PersonHistoryCollection}, []string{manifest.Collections[0].Name, manifest.Collections[1].Name, manifest.Collections[2].Name})
+	assert.Equal(t, []int{1, 3, 1}, []int{manifest.Collections[0].Count, manifest.Collections[1].Count, manifest.Collections[2].Count})
+
+	backup, readManifest, err := Read(&archive)
+	assert.NoError(t, err)
+	assert.Equal(t, manifest, readManifest)
+	assert.Equal(t, familyBackup, *backup)
+}
+
+func TestRead(t *testing.T) {
+	type testArgs struct {
+		testName        string
+		archive         []byte
+		expectedErrCode errors.ApplicationErrorCode
+	}
+
+	emptyFileChecksum := "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+	tests := []testArgs{
+		{
+			testName:        "should fail when the backup is not gzipped",
+			archive:         []byte("manifest.json"),
+			expectedErrCode: errors.InvalidBackupErrorCode,
+		},
+		{
+			testName: "should fail when the manifest is not the first file",
+			archive: writeArchive(t, []archiveFile{
+				{name: "person.jsonl", content: ""},
+			}),
+			expectedErrCode: errors.InvalidBackupErrorCode,
+		},
+		{
+			testName: "should fail on an unsupported format version",
+			archive: writeArchive(t, []archiveFile{
+				{name: "manifest.json", content: `{"formatVersion": 99, "collections": []}`},
+			}),
+			expectedErrCode: errors.InvalidBackupErrorCode,
+		},
+		{
+			testName: "should fail when a file does not match its checksum",
+			archive: writeArchive(t, []archiveFile{
+				{name: "manifest.json", content: `{"formatVersion": 1, "collections": [{"name": "person", "file": "person.jsonl", "count": 0, "sha256": "` + emptyFileChecksum + `"}]}`},
+				{name: "person.jsonl", content: `{"id": "IDCaio", "name": "Caio"}` + "\n"},
+			}),
+			expectedErrCode: errors.InvalidBackupErrorCode,
+		},
+		{
+			testName: "should fail when a file listed on the manifest is missing",
+			archive: writeArchive(t, []archiveFile{
+				{name: "manifest.json", content: `{"formatVersion": 1, "collections": [{"name": "person", "file": "person.jsonl", "count": 0, "sha256": "` + emptyFileChecksum + `"}]}`},
+			}),
+			expectedErrCode: errors.InvalidBackupErrorCode,
+		},
+		{
+			testName: "should fail when the record count does not match the manifest",
+			archive: writeArchive(t, []archiveFile{
+				{name: "manifest.json", content: `{"formatVersion": 1, "collections": [{"name": "person", "file": "person.jsonl", "count": 1, "sha256": "` + emptyFileChecksum + `"}]}`},
+				{name: "person.jsonl", content: ""},
+			}),
+			expectedErrCode: errors.InvalidBackupErrorCode,
+		},
+		{
+			testName: "should read a backup without any record",
+			archive: writeArchive(t, []archiveFile{
+				{name: "manifest.json", content: `{"formatVersion": 1, "collections": [{"name": "person", "file": "person.jsonl", "count": 0, "sha256": "` + emptyFileChecksum + `"}]}`},
+				{name: "person.jsonl", content: ""},
+			}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(tt testArgs) func(t *testing.T) {
+			return func(t *testing.T) {
+				_, _, err := Read(bytes.NewReader(tt.archive))
+				if tt.expectedErrCode != "" {
+					assert.True(t, errors.ErrorHasCode(err, tt.expectedErrCode), "unexpected error: %v", err)
+					return
+				}
+
+				assert.NoError(t, err)
+			}
+		}(tt))
+	}
+}
@@ -0,0 +1,162 @@
+package chart
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+	"github.com/CaioBittencourt/arvore-genealogica/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func linkPersons(parent *domain.Person, children ...*domain.Person) {
+	for _, child := range children {
+		parent.Children = append(parent.Children, child)
+		child.Parents = append(child.Parents, parent)
+	}
+}
+
+// buildFamilyGraph builds a graph around Caio: both of his parents, the mother of his father, his wife and the son
+// they had, and a daughter he had with another woman.
+func buildFamilyGraph() domain.FamilyGraph {
+	zeze := &domain.Person{ID: "IDZeze", Name: "Zézé <Vó>", Gender: domain.Female, Generation: 2}
+	luis := &domain.Person{ID: "IDLuis", Name: "Luis", Gender: domain.Male, Generation: 1}
+	dayse := &domain.Person{ID: "IDDayse", Name: "Dayse", Gender: domain.Female, Generation: 1}
+	caio := &domain.Person{ID: "IDCaio", Name: "Caio", Gender: domain.Male, Generation: 0, Birth: &domain.LifeEvent{Date: "1994"}}
+	ana := &domain.Person{ID: "IDAna", Name: "Ana", Gender: domain.Female, Generation: 0}
+	bia := &domain.Person{ID: "IDBia", Name: "Bia", Gender: domain.Female, Generation: 0}
+	pedro := &domain.Person{ID: "IDPedro", Name: "Pedro", Gender: domain.Male, Generation: -1}
+	julia := &domain.Person{ID: "IDJulia", Name: "Julia", Gender: domain.Female, Generation: -1}
+
+	linkPersons(zeze, luis)
+	linkPersons(dayse, caio)
+	linkPersons(luis, caio)
+	linkPersons(caio, pedro, julia)
+	linkPersons(ana, pedro)
+	linkPersons(bia, julia)
+
+	members := make(map[string]*domain.Person)
+	for _, member := range []*domain.Person{zeze, luis, dayse, caio, ana, bia, pedro, julia} {
+		members[member.ID] = member
+	}
+
+	return domain.FamilyGraph{Members: members}
+}
+
+func assertNoOverlappingBoxes(t *testing.T, layout *Layout) {
+	for personAID, boxA := range layout.Boxes {
+		for personBID, boxB := range layout.Boxes {
+			if personAID != personBID && boxA.Row == boxB.Row {
+				distance := boxA.Column - boxB.Column
+				assert.False(t, distance > -1 && distance < 1, "%s overlaps %s", personAID, personBID)
+			}
+		}
+	}
+}
+
+func TestBuildLayout(t *testing.T) {
+	type testArgs struct {
+		testName          string
+		chartType         ChartType
+		expectedPersonIDS []string
+		expectedRows      map[string]int
+		expectedAdjacent  [][2]string
+		expectedLinks     []Link
+	}
+
+	tests := []testArgs{
+		{
+			testName:          "should draw only ancestors on pedigree charts with the father on the left",
+			chartType:         PedigreeChart,
+			expectedPersonIDS: []string{"IDCaio", "IDLuis", "IDDayse", "IDZeze"},
+			expectedRows:      map[string]int{"IDZeze": 0, "IDLuis": 1, "IDDayse": 1, "IDCaio": 2},
+			expectedAdjacent:  [][2]string{{"IDLuis", "IDDayse"}},
+			expectedLinks: []Link{
+				{ParentIDS: []string{"IDLuis", "IDDayse"}, ChildID: "IDCaio"},
+				{ParentIDS: []string{"IDZeze"}, ChildID: "IDLuis"},
+			},
+		},
+		{
+			testName:          "should draw descendants beside the co-parent they were had with",
+			chartType:         DescendantsChart,
+			expectedPersonIDS: []string{"IDCaio", "IDAna", "IDBia", "IDPedro", "IDJulia"},
+			expectedRows:      map[string]int{"IDCaio": 0, "IDAna": 0, "IDBia": 0, "IDPedro": 1, "IDJulia": 1},
+			expectedAdjacent:  [][2]string{{"IDCaio", "IDAna"}, {"IDAna", "IDBia"}},
+			expectedLinks: []Link{
+				{ParentIDS: []string{"IDCaio"}, ChildID: "IDJulia"},
+				{ParentIDS: []string{"IDBia"}, ChildID: "IDJulia"},
+				{ParentIDS: []string{"IDCaio", "IDAna"}, ChildID: "IDPedro"},
+			},
+		},
+		{
+			testName:          "should draw ancestors and descendants on hourglass charts",
+			chartType:         HourglassChart,
+			expectedPersonIDS: []string{"IDZeze", "IDLuis", "IDDayse", "IDCaio", "IDAna", "IDBia", "IDPedro", "IDJulia"},
+			expectedRows:      map[string]int{"IDZeze": 0, "IDLuis": 1, "IDCaio": 2, "IDAna": 2, "IDPedro": 3},
+			expectedAdjacent:  [][2]string{{"IDLuis", "IDDayse"}, {"IDCaio", "IDAna"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(tt testArgs) func(t *testing.T) {
+			return func(t *testing.T) {
+				layout, err := BuildLayout(buildFamilyGraph(), "IDCaio", tt.chartType)
+				assert.NoError(t, err)
+
+				var personIDS []string
+				for personID := range layout.Boxes {
+					personIDS = append(personIDS, personID)
+				}
+				assert.ElementsMatch(t, tt.expectedPersonIDS, personIDS)
+
+				for personID, row := range tt.expectedRows {
+					assert.Equal(t, row, layout.Boxes[personID].Row, personID)
+				}
+
+				for _, adjacent := range tt.expectedAdjacent {
+					assert.Equal(t, layout.Boxes[adjacent[0]].Column+1, layout.Boxes[adjacent[1]].Column, adjacent)
+				}
+
+				for _, link := range tt.expectedLinks {
+					assert.Contains(t, layout.Links, link)
+				}
+
+				assertNoOverlappingBoxes(t, layout)
+			}
+		}(tt))
+	}
+}
+
+func TestBuildLayoutPersonNotInGraph(t *testing.T) {
+	_, err := BuildLayout(buildFamilyGraph(), "IDUnknown", PedigreeChart)
+	assert.True(t, errors.ErrorHasCode(err, errors.PersonNotFoundInGraph))
+}
+
+func TestParseChartType(t *testing.T) {
+	chartType, err := ParseChartType("")
+	assert.NoError(t, err)
+	assert.Equal(t, HourglassChart, chartType)
+
+	chartType, err = ParseChartType("descendants")
+	assert.NoError(t, err)
+	assert.Equal(t, DescendantsChart, chartType)
+
+	_, err = ParseChartType("fan")
+	assert.True(t, errors.ErrorHasCode(err, errors.InvalidQueryParameterErrorCode))
+}
+
+func TestEncodeSVG(t *testing.T) {
+	layout, err := BuildLayout(buildFamilyGraph(), "IDCaio", HourglassChart)
+	assert.NoError(t, err)
+
+	var buffer bytes.Buffer
+	assert.NoError(t, EncodeSVG(&buffer, layout, "IDCaio"))
+	svg := buffer.String()
+
+	assert.Contains(t, svg, `<svg xmlns="http://www.w3.org/2000/svg"`)
+	assert.Contains(t, svg, "Zézé &lt;Vó&gt;")
+	assert.Contains(t, svg, `<g id="person-IDCaio">`)
+	assert.Contains(t, svg, `stroke="#ff9f1c" stroke-width="3"`)
+	assert.Contains(t, svg, ">1994 –</text>")
+	assert.Contains(t, svg, "</svg>\n")
+}
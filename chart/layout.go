@@ -0,0 +1,313 @@
+package chart
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+	"github.com/CaioBittencourt/arvore-genealogica/errors"
+)
+
+type ChartType string
+
+const (
+	PedigreeChart    ChartType = "pedigree"
+	DescendantsChart ChartType = "descendants"
+	HourglassChart   ChartType = "hourglass"
+)
+
+func ParseChartType(value string) (ChartType, error) {
+	switch chartType := ChartType(value); chartType {
+	case PedigreeChart, DescendantsChart, HourglassChart:
+		return chartType, nil
+	case "":
+		return HourglassChart, nil
+	}
+
+	return "", errors.NewApplicationError(fmt.Sprintf("chart type %s is not supported", value), errors.InvalidQueryParameterErrorCode)
+}
+
+// Box is a person placed on the chart. Column is measured in boxes, row 0 is the oldest generation drawn.
+type Box struct {
+	Person *domain.Person
+	Column float64
+	Row    int
+}
+
+// Link is a line from a couple, or a single parent, to one of their children. Parents are adjacent boxes of the
+// same row, so their children hang from the middle of them.
+type Link struct {
+	ParentIDS []string
+	ChildID   string
+}
+
+type Layout struct {
+	Boxes   map[string]*Box
+	Links   []Link
+	Columns float64
+	Rows    int
+}
+
+type treeLayout struct {
+	columnByID             map[string]float64
+	generationByID         map[string]int
+	nextColumnByGeneration map[int]float64
+	placed                 map[string]bool
+}
+
+func newTreeLayout() *treeLayout {
+	return &treeLayout{
+		columnByID:             make(map[string]float64),
+		generationByID:         make(map[string]int),
+		nextColumnByGeneration: make(map[int]float64),
+		placed:                 make(map[string]bool),
+	}
+}
+
+func (tl *treeLayout) shift(personIDS []string, delta float64) {
+	for _, personID := range personIDS {
+		tl.columnByID[personID] += delta
+		generation := tl.generationByID[personID]
+		if tl.columnByID[personID]+1 > tl.nextColumnByGeneration[generation] {
+			tl.nextColumnByGeneration[generation] = tl.columnByID[personID] + 1
+		}
+	}
+}
+
+// place draws the unit of person, the person with the spouses drawn beside it, centered over the subtrees of the
+// relatives returned by next. Subtrees are placed left to right and pushed right when they would overlap a box
+// already placed on any of their rows, so co-parents stay together and lines never cross.
+func (tl *treeLayout) place(person *domain.Person, unitOf func(*domain.Person) []*domain.Person, next func(*domain.Person) []*domain.Person) []string {
+	var unit []*domain.Person
+	for _, member := range unitOf(person) {
+		if !tl.placed[member.ID] {
+			tl.placed[member.ID] = true
+			unit = append(unit, member)
+		}
+	}
+
+	var subtreeIDS []string
+	var nextColumns []float64
+	for _, relative := range next(person) {
+		if tl.placed[relative.ID] {
+			continue
+		}
+
+		subtreeIDS = append(subtreeIDS, tl.place(relative, unitOf, next)...)
+		nextColumns = append(nextColumns, tl.columnByID[relative.ID])
+	}
+
+	generation := person.Generation
+	width := float64(len(unit))
+	column := tl.nextColumnByGeneration[generation]
+	if len(nextColumns) > 0 {
+		sort.Float64s(nextColumns)
+		column = (nextColumns[0]+nextColumns[len(nextColumns)-1])/2 - (width-1)/2
+	}
+
+	if column < tl.nextColumnByGeneration[generation] {
+		tl.shift(subtreeIDS, tl.nextColumnByGeneration[generation]-column)
+		column = tl.nextColumnByGeneration[generation]
+	}
+
+	for i, member := range unit {
+		tl.columnByID[member.ID] = column + float64(i)
+		tl.generationByID[member.ID] = generation
+		subtreeIDS = append(subtreeIDS, member.ID)
+	}
+	tl.nextColumnByGeneration[generation] = column + width
+
+	return subtreeIDS
+}
+
+func membersOf(familyGraph domain.FamilyGraph, persons []*domain.Person) []*domain.Person {
+	var members []*domain.Person
+	for _, person := range persons {
+		if member, ok := familyGraph.Members[person.ID]; ok {
+			members = append(members, member)
+		}
+	}
+
+	return members
+}
+
+// parentsOf returns the father first, so pedigree charts keep the paternal line on the left.
+func parentsOf(familyGraph domain.FamilyGraph) func(*domain.Person) []*domain.Person {
+	return func(person *domain.Person) []*domain.Person {
+		parents := membersOf(familyGraph, person.Parents)
+		sort.SliceStable(parents, func(i, j int) bool {
+			if parents[i].Gender != parents[j].Gender {
+				return parents[i].Gender == domain.Male
+			}
+			return parents[i].ID < parents[j].ID
+		})
+
+		return parents
+	}
+}
+
+func coParentsOf(familyGraph domain.FamilyGraph, person *domain.Person) []*domain.Person {
+	var coParents []*domain.Person
+	added := map[string]bool{person.ID: true}
+	for _, children := range membersOf(familyGraph, person.Children) {
+		for _, coParent := range membersOf(familyGraph, children.Parents) {
+			if !added[coParent.ID] {
+				added[coParent.ID] = true
+				coParents = append(coParents, coParent)
+			}
+		}
+	}
+
+	sort.Slice(coParents, func(i, j int) bool {
+		return coParents[i].ID < coParents[j].ID
+	})
+
+	return coParents
+}
+
+// childrenOf returns the children grouped by co-parent, in the order the co-parents are drawn.
+func childrenOf(familyGraph domain.FamilyGraph) func(*domain.Person) []*domain.Person {
+	return func(person *domain.Person) []*domain.Person {
+		coParentIndexByID := make(map[string]int)
+		for i, coParent := range coParentsOf(familyGraph, person) {
+			coParentIndexByID[coParent.ID] = i
+		}
+
+		coParentIndex := func(children *domain.Person) int {
+			for _, parent := range children.Parents {
+				if index, ok := coParentIndexByID[parent.ID]; ok {
+					return index
+				}
+			}
+			return -1
+		}
+
+		children := membersOf(familyGraph, person.Children)
+		sort.SliceStable(children, func(i, j int) bool {
+			if coParentIndex(children[i]) != coParentIndex(children[j]) {
+				return coParentIndex(children[i]) < coParentIndex(children[j])
+			}
+			return children[i].ID < children[j].ID
+		})
+
+		return children
+	}
+}
+
+func onlyPerson(person *domain.Person) []*domain.Person {
+	return []*domain.Person{person}
+}
+
+func layoutAncestors(familyGraph domain.FamilyGraph, root *domain.Person) *treeLayout {
+	ancestors := newTreeLayout()
+	ancestors.place(root, onlyPerson, parentsOf(familyGraph))
+	return ancestors
+}
+
+func layoutDescendants(familyGraph domain.FamilyGraph, root *domain.Person) *treeLayout {
+	descendants := newTreeLayout()
+	unitOf := func(person *domain.Person) []*domain.Person {
+		return append([]*domain.Person{person}, coParentsOf(familyGraph, person)...)
+	}
+
+	descendants.place(root, unitOf, childrenOf(familyGraph))
+	return descendants
+}
+
+// BuildLayout places the ancestors of the person above it on pedigree charts, its descendants and their co-parents
+// below it on descendants charts, and both on hourglass charts. Rows come from the Generation of each member.
+func BuildLayout(familyGraph domain.FamilyGraph, personID string, chartType ChartType) (*Layout, error) {
+	root, ok := familyGraph.Members[personID]
+	if !ok {
+		return nil, errors.NewApplicationError("person not in graph", errors.PersonNotFoundInGraph)
+	}
+
+	columnByID := make(map[string]float64)
+	switch chartType {
+	case PedigreeChart:
+		columnByID = layoutAncestors(familyGraph, root).columnByID
+	case DescendantsChart:
+		columnByID = layoutDescendants(familyGraph, root).columnByID
+	case HourglassChart:
+		descendants := layoutDescendants(familyGraph, root)
+		ancestors := layoutAncestors(familyGraph, root)
+		// ancestors only share the row of the person with the descendants, so aligning the person is enough
+		delta := descendants.columnByID[root.ID] - ancestors.columnByID[root.ID]
+		for personID, column := range ancestors.columnByID {
+			columnByID[personID] = column + delta
+		}
+		for personID, column := range descendants.columnByID {
+			columnByID[personID] = column
+		}
+	default:
+		return nil, errors.NewApplicationError(fmt.Sprintf("chart type %s is not supported", chartType), errors.InvalidQueryParameterErrorCode)
+	}
+
+	return buildLayoutFromColumns(familyGraph, columnByID), nil
+}
+
+func buildLayoutFromColumns(familyGraph domain.FamilyGraph, columnByID map[string]float64) *Layout {
+	minColumn, maxColumn := 0.0, 0.0
+	minGeneration, maxGeneration := 0, 0
+	first := true
+	for personID, column := range columnByID {
+		generation := familyGraph.Members[personID].Generation
+		if first || column < minColumn {
+			minColumn = column
+		}
+		if first || column > maxColumn {
+			maxColumn = column
+		}
+		if first || generation < minGeneration {
+			minGeneration = generation
+		}
+		if first || generation > maxGeneration {
+			maxGeneration = generation
+		}
+		first = false
+	}
+
+	layout := &Layout{
+		Boxes:   make(map[string]*Box, len(columnByID)),
+		Columns: maxColumn - minColumn + 1,
+		Rows:    maxGeneration - minGeneration + 1,
+	}
+	for personID, column := range columnByID {
+		member := familyGraph.Members[personID]
+		layout.Boxes[personID] = &Box{Person: member, Column: column - minColumn, Row: maxGeneration - member.Generation}
+	}
+
+	var childIDS []string
+	for personID := range layout.Boxes {
+		childIDS = append(childIDS, personID)
+	}
+	sort.Strings(childIDS)
+
+	for _, childID := range childIDS {
+		var parentIDS []string
+		for _, parent := range layout.Boxes[childID].Person.Parents {
+			if _, ok := layout.Boxes[parent.ID]; ok {
+				parentIDS = append(parentIDS, parent.ID)
+			}
+		}
+
+		if len(parentIDS) == 2 && !areAdjacent(layout.Boxes[parentIDS[0]], layout.Boxes[parentIDS[1]]) {
+			layout.Links = append(layout.Links, Link{ParentIDS: parentIDS[:1], ChildID: childID}, Link{ParentIDS: parentIDS[1:], ChildID: childID})
+			continue
+		}
+
+		if len(parentIDS) > 0 {
+			sort.Slice(parentIDS, func(i, j int) bool {
+				return layout.Boxes[parentIDS[i]].Column < layout.Boxes[parentIDS[j]].Column
+			})
+			layout.Links = append(layout.Links, Link{ParentIDS: parentIDS, ChildID: childID})
+		}
+	}
+
+	return layout
+}
+
+func areAdjacent(boxA *Box, boxB *Box) bool {
+	distance := boxA.Column - boxB.Column
+	return boxA.Row == boxB.Row && (distance == 1 || distance == -1)
+}
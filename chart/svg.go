@@ -0,0 +1,135 @@
+package chart
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+)
+
+const MediaType = "image/svg+xml"
+
+const (
+	boxWidth      = 160.0
+	boxHeight     = 48.0
+	columnWidth   = 190.0
+	rowHeight     = 110.0
+	margin        = 20.0
+	highlightFill = "#fff3bf"
+)
+
+var fillColorByGender = map[domain.GenderType]string{
+	domain.Male:   "#cfe2ff",
+	domain.Female: "#f8d7e8",
+}
+
+const unknownGenderFillColor = "#e9ecef"
+
+func escape(s string) string {
+	var builder strings.Builder
+	xml.EscapeText(&builder, []byte(s))
+	return builder.String()
+}
+
+func boxX(box *Box) float64 {
+	return margin + box.Column*columnWidth
+}
+
+func boxY(box *Box) float64 {
+	return margin + float64(box.Row)*rowHeight
+}
+
+func lifeSpan(person *domain.Person) string {
+	var birth, death string
+	if person.Birth != nil {
+		birth = person.Birth.Date
+	}
+	if person.Death != nil {
+		death = person.Death.Date
+	}
+
+	if birth == "" && death == "" {
+		return ""
+	}
+
+	return strings.TrimSpace(birth + " – " + death)
+}
+
+func sortedBoxes(layout *Layout) []*Box {
+	var boxes []*Box
+	for _, box := range layout.Boxes {
+		boxes = append(boxes, box)
+	}
+
+	sort.Slice(boxes, func(i, j int) bool {
+		if boxes[i].Row != boxes[j].Row {
+			return boxes[i].Row < boxes[j].Row
+		}
+		return boxes[i].Column < boxes[j].Column
+	})
+
+	return boxes
+}
+
+// EncodeSVG draws the layout as a standalone SVG document. Children hang from an orthogonal line leaving the middle
+// of their parents, and the person the chart was built for is highlighted.
+func EncodeSVG(writer io.Writer, layout *Layout, personID string) error {
+	width := 2*margin + (layout.Columns-1)*columnWidth + boxWidth
+	height := 2*margin + float64(layout.Rows-1)*rowHeight + boxHeight
+
+	w := bufio.NewWriter(writer)
+	fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" width="%g" height="%g" viewBox="0 0 %g %g" font-family="Helvetica, Arial, sans-serif" font-size="12">`+"\n", width, height, width, height)
+
+	fmt.Fprintln(w, `  <g fill="none" stroke="#495057" stroke-width="1.5">`)
+	for _, link := range layout.Links {
+		child := layout.Boxes[link.ChildID]
+		parents := make([]*Box, 0, len(link.ParentIDS))
+		for _, parentID := range link.ParentIDS {
+			parents = append(parents, layout.Boxes[parentID])
+		}
+
+		startX := boxX(parents[0]) + boxWidth/2
+		startY := boxY(parents[0]) + boxHeight
+		if len(parents) == 2 {
+			// a couple is drawn as a line between both boxes, the children leaving from its middle
+			startX = (boxX(parents[0]) + boxWidth + boxX(parents[1])) / 2
+			startY = boxY(parents[0]) + boxHeight/2
+			fmt.Fprintf(w, `    <line x1="%g" y1="%g" x2="%g" y2="%g"/>`+"\n", boxX(parents[0])+boxWidth, startY, boxX(parents[1]), startY)
+		}
+
+		endX := boxX(child) + boxWidth/2
+		endY := boxY(child)
+		middleY := endY - (rowHeight-boxHeight)/2
+		fmt.Fprintf(w, `    <path d="M%g %g V%g H%g V%g"/>`+"\n", startX, startY, middleY, endX, endY)
+	}
+	fmt.Fprintln(w, "  </g>")
+
+	for _, box := range sortedBoxes(layout) {
+		fillColor, ok := fillColorByGender[box.Person.Gender]
+		if !ok {
+			fillColor = unknownGenderFillColor
+		}
+		stroke := `stroke="#495057"`
+		if box.Person.ID == personID {
+			fillColor = highlightFill
+			stroke = `stroke="#ff9f1c" stroke-width="3"`
+		}
+
+		x, y := boxX(box), boxY(box)
+		fmt.Fprintf(w, `  <g id="%s">`+"\n", escape("person-"+box.Person.ID))
+		fmt.Fprintf(w, `    <rect x="%g" y="%g" width="%g" height="%g" rx="6" fill="%s" %s/>`+"\n", x, y, boxWidth, boxHeight, fillColor, stroke)
+		fmt.Fprintf(w, `    <text x="%g" y="%g" text-anchor="middle" font-weight="bold">%s</text>`+"\n", x+boxWidth/2, y+20, escape(box.Person.Name))
+		if span := lifeSpan(box.Person); span != "" {
+			fmt.Fprintf(w, `    <text x="%g" y="%g" text-anchor="middle" fill="#495057">%s</text>`+"\n", x+boxWidth/2, y+37, escape(span))
+		}
+		fmt.Fprintln(w, "  </g>")
+	}
+
+	fmt.Fprintln(w, "</svg>")
+
+	return w.Flush()
+}
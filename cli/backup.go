@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/CaioBittencourt/arvore-genealogica/backup"
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+)
+
+type restoreOutput struct {
+	Manifest  backup.Manifest        `json:"manifest"`
+	Integrity domain.IntegrityReport `json:"integrity"`
+}
+
+func runBackup(ctx context.Context, services Services, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("backup", flag.ContinueOnError)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: backup <file>")
+	}
+
+	file, err := os.Create(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	manifest, err := services.BackupService.Backup(ctx, file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(flags.Arg(0))
+		return err
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(manifest)
+}
+
+func runRestore(ctx context.Context, services Services, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("restore", flag.ContinueOnError)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: restore <file>")
+	}
+
+	file, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	manifest, report, err := services.BackupService.Restore(ctx, file)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(restoreOutput{
+		Manifest:  *manifest,
+		Integrity: *report,
+	})
+}
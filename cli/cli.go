@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+	"github.com/CaioBittencourt/arvore-genealogica/service"
+)
+
+// Services are the dependencies a command may use, built once by main.
+type Services struct {
+	PersonService service.PersonService
+	BackupService service.BackupService
+}
+
+type command func(ctx context.Context, services Services, args []string, out io.Writer) error
+
+var commandsByName = map[string]command{
+	"integrity":      runIntegrity,
+	"import-gedcom":  runImportGedcom,
+	"import-gedcomx": runImportGedcomX,
+	"import-csv":     runImportCSV,
+	"export-gedcom":  runExportGedcom,
+	"backup":         runBackup,
+	"restore":        runRestore,
+}
+
+func availableCommands() []string {
+	var names []string
+	for name := range commandsByName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func Run(ctx context.Context, services Services, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing command, available commands: %v", availableCommands())
+	}
+
+	cmd, ok := commandsByName[args[0]]
+	if !ok {
+		return fmt.Errorf("unknown command %q, available commands: %v", args[0], availableCommands())
+	}
+
+	return cmd(ctx, services, args[1:], out)
+}
+
+// cliChangeSource is the source of the changes made by the commands.
+const cliChangeSource = "cli"
+
+func changeMetadataFromEnvironment(reason string) domain.ChangeMetadata {
+	return domain.ChangeMetadata{ClaimedActor: actorFromEnvironment(), Source: cliChangeSource, Reason: reason}
+}
+
+func actorFromEnvironment() string {
+	if user := os.Getenv("USER"); user != "" {
+		return "cli:" + user
+	}
+
+	return "cli"
+}
@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+	"github.com/CaioBittencourt/arvore-genealogica/gedcom"
+	"github.com/CaioBittencourt/arvore-genealogica/gedcomx"
+	"github.com/CaioBittencourt/arvore-genealogica/spreadsheet"
+)
+
+var encodersByFormat = map[string]func(writer io.Writer, persons []domain.Person) error{
+	"gedcom":  gedcom.Encode,
+	"gedcom7": gedcom.EncodeVersion7,
+	"gedcomx": gedcomx.Encode,
+	"csv":     spreadsheet.Encode,
+}
+
+func runExportGedcom(ctx context.Context, services Services, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("export-gedcom", flag.ContinueOnError)
+	treeID := flags.String("tree", "", "id of the tree to export, the default tree when empty")
+	personID := flags.String("person", "", "export only the family tree of this person")
+	output := flags.String("o", "", "file to write, the standard output when empty")
+	format := flags.String("format", "gedcom", "gedcom for GEDCOM 5.5.1, gedcom7 for GEDCOM 7, gedcomx for GEDCOM X JSON or csv")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	encode, ok := encodersByFormat[*format]
+	if !ok {
+		return fmt.Errorf("unknown format %q", *format)
+	}
+
+	ctx = domain.ContextWithTreeID(ctx, *treeID)
+
+	var persons []domain.Person
+	if *personID != "" {
+		familyGraph, err := services.PersonService.GetFamilyGraphByPersonID(ctx, *personID, domain.DefaultFamilyGraphScope())
+		if err != nil {
+			return err
+		}
+		persons = gedcom.PersonsFromFamilyGraph(*familyGraph)
+	} else {
+		var err error
+		persons, err = services.PersonService.GetAllPersons(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+
+	return encode(out, persons)
+}
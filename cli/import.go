@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+	"github.com/CaioBittencourt/arvore-genealogica/gedcom"
+	"github.com/CaioBittencourt/arvore-genealogica/server"
+	"github.com/CaioBittencourt/arvore-genealogica/service"
+)
+
+func runImportGedcom(ctx context.Context, services Services, args []string, out io.Writer) error {
+	return runImport(ctx, "import-gedcom", service.NewImportService(services.PersonService).ImportGedcom, args, out)
+}
+
+func runImportGedcomX(ctx context.Context, services Services, args []string, out io.Writer) error {
+	return runImport(ctx, "import-gedcomx", service.NewImportService(services.PersonService).ImportGedcomX, args, out)
+}
+
+type importFunc func(ctx context.Context, reader io.Reader) (*gedcom.ImportReport, []domain.BatchItemError, error)
+
+func runImport(ctx context.Context, name string, importFile importFunc, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	treeID := flags.String("tree", "", "id of the tree to import into, the default tree when empty")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: %s [-tree <treeId>] <file>", name)
+	}
+
+	file, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	ctx = domain.ContextWithTreeID(ctx, *treeID)
+	ctx = domain.ContextWithChangeMetadata(ctx, changeMetadataFromEnvironment(name))
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+
+	report, itemErrors, err := importFile(ctx, file)
+	if len(itemErrors) > 0 {
+		if encodeErr := encoder.Encode(server.StorePersonBatchErrorResponse{
+			ErrorResponse: server.BuildErrorResponseFromError(err),
+			Items:         server.BuildBatchItemErrorResponses(itemErrors),
+		}); encodeErr != nil {
+			return encodeErr
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return encoder.Encode(server.BuildImportReportResponseFromImportReport(*report))
+}
+
+func runImportCSV(ctx context.Context, services Services, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("import-csv", flag.ContinueOnError)
+	treeID := flags.String("tree", "", "id of the tree to import into, the default tree when empty")
+	dryRun := flags.Bool("dry-run", false, "only check the rows, nothing is stored")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: import-csv [-tree <treeId>] [-dry-run] <file>")
+	}
+
+	file, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	ctx = domain.ContextWithTreeID(ctx, *treeID)
+	ctx = domain.ContextWithChangeMetadata(ctx, changeMetadataFromEnvironment("import-csv"))
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+
+	report, err := service.NewImportService(services.PersonService).ImportCSV(ctx, file, *dryRun)
+	if report != nil {
+		if encodeErr := encoder.Encode(server.BuildCSVImportReportResponseFromImportReport(*report)); encodeErr != nil {
+			return encodeErr
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,30 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"io"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+)
+
+func runIntegrity(ctx context.Context, services Services, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("integrity", flag.ContinueOnError)
+	repair := flags.Bool("repair", false, "repair asymmetric links and dangling ids inside a transaction")
+	treeID := flags.String("tree", "", "id of the tree to check, the default tree when empty")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	ctx = domain.ContextWithTreeID(ctx, *treeID)
+	ctx = domain.ContextWithChangeMetadata(ctx, changeMetadataFromEnvironment(""))
+	report, err := services.PersonService.CheckIntegrity(ctx, *repair)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
@@ -16,24 +16,122 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/person": {
+        "/admin/integrity": {
+            "get": {
+                "description": "Scans every person for asymmetric links, dangling ids, persons with more than 2 parents and ancestry cycles",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Check referential integrity of persons",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.IntegrityReport"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/integrity/repair": {
             "post": {
-                "description": "Store person",
+                "description": "Repairs asymmetric links and dangling ids inside a transaction, skipping the ones changed since the check. Returns the issues found before the repair",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Store person",
+                "summary": "Repair referential integrity of persons",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.IntegrityReport"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/export/csv": {
+            "get": {
+                "description": "Export every person of the tree as a CSV file with the columns accepted by the CSV import",
+                "produces": [
+                    "text/csv"
+                ],
+                "summary": "Export every person as CSV",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/export/gedcom": {
+            "get": {
+                "description": "Export every person of the tree as a GEDCOM 5.5.1 file",
+                "produces": [
+                    "text/plain"
+                ],
+                "summary": "Export every person as GEDCOM",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/graphql": {
+            "post": {
+                "description": "Runs a GraphQL query or mutation over persons. A Person exposes its parents, children and spouses as persons, so relatives can be traversed to any depth, along with relationships(to:) and baconNumber(to:). Persons are read in batches, one call per generation instead of one per person. createPerson links the new person only to relatives still on the version sent. The schema is at graph/schema.graphql",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "GraphQL query",
                 "parameters": [
                     {
-                        "description": "Person to store",
-                        "name": "person",
+                        "description": "Query, operation name and variables",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/server.StorePersonRequest"
+                            "$ref": "#/definitions/server.GraphQLRequest"
                         }
                     }
                 ],
@@ -41,7 +139,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/server.PersonResponse"
+                            "$ref": "#/definitions/server.GraphQLResponse"
                         }
                     },
                     "400": {
@@ -49,6 +147,47 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
+                    }
+                }
+            }
+        },
+        "/import/csv": {
+            "post": {
+                "description": "Import the rows of a CSV file inside a single transaction. The header names the columns: id, name, gender, mother, father, other_parents, birth_date, birth_place, death_date and death_place, only name and gender are required. Mother and father reference the id of another row or of a stored person, and other_parents has the parents that are not the biological mother or father as id:link type or id:link type:role entries separated by semicolons. Every invalid row is reported with its line, with dryRun nothing is stored",
+                "consumes": [
+                    "text/plain",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Import CSV file",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "CSV file, when not sent as the request body",
+                        "name": "file",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "only check the rows",
+                        "name": "dryRun",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.CSVImportReportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/server.CSVImportErrorResponse"
+                        }
                     },
                     "500": {
                         "description": "Internal Server Error",
@@ -59,37 +198,120 @@ const docTemplate = `{
                 }
             }
         },
-        "/person/:id/baconNumber/:id2": {
-            "get": {
-                "description": "Get bacons number between two persons",
+        "/import/gedcom": {
+            "post": {
+                "description": "Import the individuals and families of a GEDCOM 5.5.1 or GEDCOM 7 file inside a single transaction. FAM records become parent links, spouses are inferred from common children. Returns the id stored for each individual cross reference and the skipped records and unsupported tags",
+                "consumes": [
+                    "text/plain",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Import GEDCOM file",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "GEDCOM file, when not sent as the request body",
+                        "name": "file",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.ImportReportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/server.StorePersonBatchErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/import/gedcomx": {
+            "post": {
+                "description": "Import the persons and relationships of a GEDCOM X JSON document inside a single transaction. ParentChild relationships become parent links, Couple relationships are inferred from common children. Returns the id stored for each person id and the skipped persons and relationships and unsupported fact types",
+                "consumes": [
+                    "application/json",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Import GEDCOM X document",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "GEDCOM X document, when not sent as the request body",
+                        "name": "file",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.ImportReportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/server.StorePersonBatchErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/person": {
+            "post": {
+                "description": "Store person. If-Match must hold the ETag of every parent and child referenced, or \"*\"",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Get bacons number between two persons",
+                "summary": "Store person",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Person ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Person to store",
+                        "name": "person",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.StorePersonRequest"
+                        }
                     },
                     {
                         "type": "string",
-                        "description": "Person 2 ID",
-                        "name": "id2",
-                        "in": "path",
-                        "required": true
+                        "description": "ETags of the referenced parents and children",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/server.GetBaconsNumberBetweenTwoPersonsResponse"
+                            "$ref": "#/definitions/server.PersonResponse"
                         }
                     },
                     "400": {
@@ -104,6 +326,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -113,16 +347,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/person/:id/relationship/:id2": {
+        "/person/:id": {
             "get": {
-                "description": "Get relationship between two persons",
+                "description": "Get person with its parents and children. The ETag header holds the version to send on If-Match when linking to this person",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Get relationship between two persons",
+                "summary": "Get person",
                 "parameters": [
                     {
                         "type": "string",
@@ -130,20 +364,13 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Person 2 ID",
-                        "name": "id2",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/server.PersonWithRelationship"
+                            "$ref": "#/definitions/server.PersonResponse"
                         }
                     },
                     "400": {
@@ -167,16 +394,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/person/:id/tree": {
+        "/person/:id/baconNumber/:id2": {
             "get": {
-                "description": "Get family tree with relationships for person",
+                "description": "Get the weight of the lightest path between two persons, by default the minimum number of jumps through spouses, parents and children",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Get family tree with relationships for person",
+                "summary": "Get bacons number between two persons",
                 "parameters": [
                     {
                         "type": "string",
@@ -184,13 +411,69 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Person 2 ID",
+                        "name": "id2",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated link types to follow, like biological,donor",
+                        "name": "linkTypes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Do not jump through spouses, for blood only distances",
+                        "name": "excludeSpouses",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "ancestors",
+                            "descendants"
+                        ],
+                        "type": "string",
+                        "description": "Only jump to parents or only jump to children",
+                        "name": "direction",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Weight of jumping to a spouse",
+                        "name": "spouseWeight",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Weight of jumping to a parent",
+                        "name": "parentWeight",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Weight of jumping to a child",
+                        "name": "childWeight",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of jumps",
+                        "name": "maxDepth",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/server.PersonTreeResponse"
+                            "$ref": "#/definitions/server.GetBaconsNumberBetweenTwoPersonsResponse"
                         }
                     },
                     "400": {
@@ -205,6 +488,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -213,25 +502,1263 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "server.ErrorResponse": {
-            "type": "object",
-            "properties": {
-                "errorCode": {
-                    "type": "string"
+        },
+        "/person/:id/chart.svg": {
+            "get": {
+                "description": "Draw the family tree of a person as an SVG chart. Pedigree charts show the ancestors of the person, descendants charts its descendants beside the co-parents they were had with and hourglass charts show both",
+                "produces": [
+                    "image/svg+xml"
+                ],
+                "summary": "Draw family tree of person as SVG",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Person ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "pedigree, descendants or hourglass, defaults to hourglass",
+                        "name": "type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/person/:id/history": {
+            "get": {
+                "description": "Get every change recorded for a person, oldest first. The claimedActor and reason come from the X-Actor and X-Change-Reason headers of the mutation and are not checked, anyone can send them. The source is set by the server, the address of the HTTP or gRPC client or cli for the commands, and can not be forged through headers",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get change history of person",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Person ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.PersonHistoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/person/:id/relationship/:id2": {
+            "get": {
+                "description": "Get relationship between two persons, only following the parent links of linkTypes when it is set",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get relationship between two persons",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Person ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Person 2 ID",
+                        "name": "id2",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated link types to follow, like biological,donor",
+                        "name": "linkTypes",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.PersonWithRelationship"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/person/:id/snapshot": {
+            "get": {
+                "description": "Rebuilds a person and its parents and children from the change history",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get person as of a point in time",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Person ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC3339 timestamp",
+                        "name": "at",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.PersonResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/person/:id/tree": {
+            "get": {
+                "description": "Get family tree with relationships for person. The Accept header, or the format query parameter, selects the format: JSON by default, application/x-gedcom (gedcom) for GEDCOM 5.5.1, text/vnd.familysearch.gedcom (gedcom7) for GEDCOM 7, application/x-gedcomx-v1+json (gedcomx) for GEDCOM X, text/vnd.graphviz (dot) for a Graphviz DOT graph, text/vnd.mermaid (mermaid) for a Mermaid flowchart, text/vnd.plantuml (plantuml) for a PlantUML diagram, text/csv (csv) for a CSV file, application/ld+json (jsonld) for schema.org persons whose ids are their /person/:id address or application/x-ndjson (ndjson) to write one member per line. Every format, ndjson and the pages included, resolves the whole tree before writing the response",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-gedcom",
+                    "text/vnd.familysearch.gedcom",
+                    "application/x-gedcomx-v1+json",
+                    "text/vnd.graphviz",
+                    "text/vnd.mermaid",
+                    "text/vnd.plantuml",
+                    "text/csv",
+                    "application/ld+json",
+                    "application/x-ndjson"
+                ],
+                "summary": "Get family tree with relationships for person",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Person ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "json, ndjson, gedcom, gedcom7, gedcomx, dot, mermaid, plantuml, csv or jsonld, overrides the Accept header",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "add the relationship with the person to each member of the dot graph",
+                        "name": "labels",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC3339 timestamp to rebuild the tree from the change history as it was then, with the same scope",
+                        "name": "asOf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "generations of ancestors, all of them by default",
+                        "name": "ancestors",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "generations of descendants, defaults to 1",
+                        "name": "descendants",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "generations of ancestors whose children and grandchildren are included, 1 for siblings and nephews, 2 for uncles and cousins too, defaults to 2",
+                        "name": "collaterals",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "include the other parents of the children of the person, defaults to true",
+                        "name": "spouses",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "members per page, at most 1000, from the oldest generation to the youngest, then by name. The response becomes a PersonTreePageResponse. Generations are relative to the person, so the whole tree is still resolved for each page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "nextCursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.PersonTreeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/person/:id/tree.ged": {
+            "get": {
+                "description": "Export the family tree of a person as a GEDCOM 5.5.1 file. Co-parents become FAM records and the xrefs are derived from the person ids, so exporting twice gives the same ids",
+                "produces": [
+                    "text/plain"
+                ],
+                "summary": "Export family tree of person as GEDCOM",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Person ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/person/:id/tree/events": {
+            "get": {
+                "description": "Pushes the persons created, updated or deleted and the parent links added or removed that affect the family tree of the person, as Server-Sent Events named after the type of the event or, when the request upgrades to a WebSocket, as JSON messages. Takes the same ancestors, descendants, collaterals and spouses parameters as /person/:id/tree",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "summary": "Subscribe to changes of the family tree of person",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Person ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "generations of ancestors, all of them by default",
+                        "name": "ancestors",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "generations of descendants, defaults to 1",
+                        "name": "descendants",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "generations of ancestors whose children and grandchildren are included, defaults to 2",
+                        "name": "collaterals",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "include the other parents of the children of the person, defaults to true",
+                        "name": "spouses",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.TreeEventResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/person/{id}/children/{childId}": {
+            "put": {
+                "description": "Add childId to the children of the person with role and linkType, with the same checks of linking a parent. If-Match must hold the ETag of both persons, or \"*\"",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Link child",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Person ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Child ID",
+                        "name": "childId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "mother",
+                            "father",
+                            "parent"
+                        ],
+                        "type": "string",
+                        "default": "parent",
+                        "description": "Role of the person for the child",
+                        "name": "role",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "biological",
+                            "adoptive",
+                            "foster",
+                            "step",
+                            "donor",
+                            "surrogate"
+                        ],
+                        "type": "string",
+                        "default": "biological",
+                        "description": "Link type of the person for the child",
+                        "name": "linkType",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETags of the person and of the child",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.PersonResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove childId from the children of the person. If-Match must hold the ETag of both persons, or \"*\"",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Unlink child",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Person ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Child ID",
+                        "name": "childId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETags of the person and of the child",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.PersonResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/person/{id}/parents/{parentId}": {
+            "put": {
+                "description": "Add parentId to the parents of the person with role and linkType, or change the role and link type of a current parent. A person has at most two biological parents, a single mother and a single father of each link type, and can not become an ancestor of itself. Mothers must be female and fathers male, generic parents allow two parents of the same gender. If-Match must hold the ETag of both persons, or \"*\"",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Link parent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Person ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Parent ID",
+                        "name": "parentId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "mother",
+                            "father",
+                            "parent"
+                        ],
+                        "type": "string",
+                        "default": "parent",
+                        "description": "Role of the parent",
+                        "name": "role",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "biological",
+                            "adoptive",
+                            "foster",
+                            "step",
+                            "donor",
+                            "surrogate"
+                        ],
+                        "type": "string",
+                        "default": "biological",
+                        "description": "Link type of the parent",
+                        "name": "linkType",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETags of the person and of the parent",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.PersonResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove parentId from the parents of the person. If-Match must hold the ETag of both persons, or \"*\"",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Unlink parent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Person ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Parent ID",
+                        "name": "parentId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETags of the person and of the parent",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.PersonResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/persons/batch": {
+            "post": {
+                "description": "Store persons in batch inside a single transaction. Persons reference each other through their tempId. Returns the id stored for each tempId",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Store persons in batch",
+                "parameters": [
+                    {
+                        "description": "Persons to store",
+                        "name": "persons",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.StorePersonBatchRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETags of the stored persons referenced by the batch",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.StorePersonBatchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/server.StorePersonBatchErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/trees": {
+            "get": {
+                "description": "Get trees, optionally only the ones of an owner",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get trees",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Owner of the trees",
+                        "name": "owner",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.TreesResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Store a family tree. Persons are stored on a tree through the /trees/:treeId/person routes",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Store tree",
+                "parameters": [
+                    {
+                        "description": "Tree to store",
+                        "name": "tree",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.StoreTreeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.TreeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/trees/:treeId": {
+            "get": {
+                "description": "Get tree",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get tree",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tree ID",
+                        "name": "treeId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.TreeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete tree with every person and change history of it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Delete tree",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tree ID",
+                        "name": "treeId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "domain.IntegrityIssue": {
+            "type": "object",
+            "properties": {
+                "fixable": {
+                    "type": "boolean"
+                },
+                "personId": {
+                    "type": "string"
+                },
+                "relatedPersonIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "type": {
+                    "$ref": "#/definitions/domain.IntegrityIssueType"
+                }
+            }
+        },
+        "domain.IntegrityIssueType": {
+            "type": "string",
+            "enum": [
+                "asymmetric_parent_link",
+                "asymmetric_child_link",
+                "dangling_parent_id",
+                "dangling_child_id",
+                "too_many_parents",
+                "ancestry_cycle",
+                "duplicated_person_id",
+                "unknown_tree"
+            ],
+            "x-enum-varnames": [
+                "AsymmetricParentLinkIssue",
+                "AsymmetricChildLinkIssue",
+                "DanglingParentIDIssue",
+                "DanglingChildIDIssue",
+                "TooManyParentsIssue",
+                "AncestryCycleIssue",
+                "DuplicatedPersonIDIssue",
+                "UnknownTreeIssue"
+            ]
+        },
+        "domain.IntegrityReport": {
+            "type": "object",
+            "properties": {
+                "issues": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.IntegrityIssue"
+                    }
+                },
+                "personsChecked": {
+                    "type": "integer"
+                },
+                "repaired": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "server.BatchItemErrorResponse": {
+            "type": "object",
+            "properties": {
+                "errorCode": {
+                    "type": "string"
+                },
+                "errorMessage": {
+                    "type": "string"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "tempId": {
+                    "type": "string"
+                }
+            }
+        },
+        "server.CSVImportErrorResponse": {
+            "type": "object",
+            "properties": {
+                "dryRun": {
+                    "type": "boolean"
+                },
+                "errorCode": {
+                    "type": "string"
+                },
+                "errorMessage": {
+                    "type": "string"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/server.RowErrorResponse"
+                    }
+                },
+                "ids": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "rows": {
+                    "type": "integer"
+                }
+            }
+        },
+        "server.CSVImportReportResponse": {
+            "type": "object",
+            "properties": {
+                "dryRun": {
+                    "type": "boolean"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/server.RowErrorResponse"
+                    }
+                },
+                "ids": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "rows": {
+                    "type": "integer"
+                }
+            }
+        },
+        "server.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "errorCode": {
+                    "type": "string"
+                },
+                "errorMessage": {
+                    "type": "string"
+                }
+            }
+        },
+        "server.GetBaconsNumberBetweenTwoPersonsResponse": {
+            "type": "object",
+            "properties": {
+                "baconsNumber": {
+                    "type": "integer"
+                }
+            }
+        },
+        "server.GraphQLErrorResponse": {
+            "type": "object",
+            "properties": {
+                "extensions": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "message": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "array",
+                    "items": {}
+                }
+            }
+        },
+        "server.GraphQLRequest": {
+            "type": "object",
+            "properties": {
+                "operationName": {
+                    "type": "string"
                 },
-                "errorMessage": {
+                "query": {
                     "type": "string"
+                },
+                "variables": {
+                    "type": "object",
+                    "additionalProperties": true
                 }
             }
         },
-        "server.GetBaconsNumberBetweenTwoPersonsResponse": {
+        "server.GraphQLResponse": {
             "type": "object",
             "properties": {
-                "baconsNumber": {
+                "data": {
+                    "type": "object"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/server.GraphQLErrorResponse"
+                    }
+                }
+            }
+        },
+        "server.ImportReportResponse": {
+            "type": "object",
+            "properties": {
+                "families": {
+                    "type": "integer"
+                },
+                "ids": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "persons": {
                     "type": "integer"
+                },
+                "skipped": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/server.SkippedRecordResponse"
+                    }
+                },
+                "unsupportedTags": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "server.LifeEvent": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string"
+                },
+                "place": {
+                    "type": "string"
+                }
+            }
+        },
+        "server.PersonChangeResponse": {
+            "type": "object",
+            "properties": {
+                "after": {
+                    "$ref": "#/definitions/server.PersonSnapshotResponse"
+                },
+                "before": {
+                    "$ref": "#/definitions/server.PersonSnapshotResponse"
+                },
+                "claimedActor": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "personId": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "server.PersonHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/server.PersonChangeResponse"
+                    }
                 }
             }
         },
@@ -244,20 +1771,32 @@ const docTemplate = `{
                 "id": {
                     "type": "string"
                 },
+                "linkType": {
+                    "type": "string"
+                },
                 "name": {
                     "type": "string"
+                },
+                "role": {
+                    "type": "string"
                 }
             }
         },
         "server.PersonResponse": {
             "type": "object",
             "properties": {
+                "birth": {
+                    "$ref": "#/definitions/server.LifeEvent"
+                },
                 "children": {
                     "type": "array",
                     "items": {
                         "$ref": "#/definitions/server.PersonRelativesResponse"
                     }
                 },
+                "death": {
+                    "$ref": "#/definitions/server.LifeEvent"
+                },
                 "gender": {
                     "type": "string"
                 },
@@ -272,6 +1811,35 @@ const docTemplate = `{
                     "items": {
                         "$ref": "#/definitions/server.PersonRelativesResponse"
                     }
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "server.PersonSnapshotResponse": {
+            "type": "object",
+            "properties": {
+                "childrenIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "gender": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parentIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
@@ -309,11 +1877,17 @@ const docTemplate = `{
         "server.Relationship": {
             "type": "object",
             "properties": {
+                "linkType": {
+                    "type": "string"
+                },
                 "person": {
                     "$ref": "#/definitions/server.RelationshipPerson"
                 },
                 "relationship": {
                     "type": "string"
+                },
+                "role": {
+                    "type": "string"
                 }
             }
         },
@@ -331,15 +1905,141 @@ const docTemplate = `{
                 }
             }
         },
+        "server.RowErrorResponse": {
+            "type": "object",
+            "properties": {
+                "column": {
+                    "type": "string"
+                },
+                "errorCode": {
+                    "type": "string"
+                },
+                "errorMessage": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "line": {
+                    "type": "integer"
+                }
+            }
+        },
+        "server.SkippedRecordResponse": {
+            "type": "object",
+            "properties": {
+                "line": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "tag": {
+                    "type": "string"
+                },
+                "xref": {
+                    "type": "string"
+                }
+            }
+        },
+        "server.StorePersonBatchErrorResponse": {
+            "type": "object",
+            "properties": {
+                "errorCode": {
+                    "type": "string"
+                },
+                "errorMessage": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/server.BatchItemErrorResponse"
+                    }
+                }
+            }
+        },
+        "server.StorePersonBatchItemRequest": {
+            "type": "object",
+            "properties": {
+                "birth": {
+                    "$ref": "#/definitions/server.LifeEvent"
+                },
+                "childrenIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "death": {
+                    "$ref": "#/definitions/server.LifeEvent"
+                },
+                "fatherId": {
+                    "type": "string"
+                },
+                "gender": {
+                    "type": "string"
+                },
+                "motherId": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parentIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "parentLinkTypes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "tempId": {
+                    "type": "string"
+                }
+            }
+        },
+        "server.StorePersonBatchRequest": {
+            "type": "object",
+            "properties": {
+                "persons": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/server.StorePersonBatchItemRequest"
+                    }
+                }
+            }
+        },
+        "server.StorePersonBatchResponse": {
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
         "server.StorePersonRequest": {
             "type": "object",
             "properties": {
+                "birth": {
+                    "$ref": "#/definitions/server.LifeEvent"
+                },
                 "childrenIds": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
+                "death": {
+                    "$ref": "#/definitions/server.LifeEvent"
+                },
                 "fatherId": {
                     "type": "string"
                 },
@@ -351,6 +2051,77 @@ const docTemplate = `{
                 },
                 "name": {
                     "type": "string"
+                },
+                "parentIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "parentLinkTypes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "server.StoreTreeRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "owner": {
+                    "type": "string"
+                }
+            }
+        },
+        "server.TreeEventResponse": {
+            "type": "object",
+            "properties": {
+                "person": {
+                    "$ref": "#/definitions/server.PersonSnapshotResponse"
+                },
+                "personId": {
+                    "type": "string"
+                },
+                "relatedPersonId": {
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "server.TreeResponse": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "owner": {
+                    "type": "string"
+                }
+            }
+        },
+        "server.TreesResponse": {
+            "type": "object",
+            "properties": {
+                "trees": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/server.TreeResponse"
+                    }
                 }
             }
         }
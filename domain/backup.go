@@ -0,0 +1,63 @@
+package domain
+
+import "sort"
+
+// TreePerson is a person along with the tree it belongs to, empty for the default tree.
+type TreePerson struct {
+	TreeID string
+	Person Person
+}
+
+// TreePersonChange is a history entry along with the tree of its person, empty for the default tree.
+type TreePersonChange struct {
+	TreeID string
+	Change PersonChange
+}
+
+// Backup holds every stored collection, across all trees, with the ids used by the backend it was dumped from.
+type Backup struct {
+	Trees   []Tree
+	Persons []TreePerson
+	History []TreePersonChange
+}
+
+// Validate checks the links of every tree of the backup, and that every person belongs to a known tree and is
+// listed only once. Links between persons of different trees are reported as dangling.
+func (b Backup) Validate() IntegrityReport {
+	report := IntegrityReport{}
+
+	knownTreeIDS := map[string]bool{"": true}
+	for _, tree := range b.Trees {
+		knownTreeIDS[tree.ID] = true
+	}
+
+	seenPersonIDS := make(map[string]bool, len(b.Persons))
+	personsByTreeID := map[string][]Person{}
+	for _, treePerson := range b.Persons {
+		if seenPersonIDS[treePerson.Person.ID] {
+			report.Issues = append(report.Issues, IntegrityIssue{Type: DuplicatedPersonIDIssue, PersonID: treePerson.Person.ID})
+			continue
+		}
+		seenPersonIDS[treePerson.Person.ID] = true
+
+		if !knownTreeIDS[treePerson.TreeID] {
+			report.Issues = append(report.Issues, IntegrityIssue{Type: UnknownTreeIssue, PersonID: treePerson.Person.ID, RelatedPersonIDS: []string{treePerson.TreeID}})
+		}
+
+		personsByTreeID[treePerson.TreeID] = append(personsByTreeID[treePerson.TreeID], treePerson.Person)
+	}
+
+	var treeIDS []string
+	for treeID := range personsByTreeID {
+		treeIDS = append(treeIDS, treeID)
+	}
+	sort.Strings(treeIDS)
+
+	for _, treeID := range treeIDS {
+		treeReport := CheckIntegrity(personsByTreeID[treeID])
+		report.PersonsChecked += treeReport.PersonsChecked
+		report.Issues = append(report.Issues, treeReport.Issues...)
+	}
+
+	return report
+}
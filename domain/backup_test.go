@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackupValidate(t *testing.T) {
+	type testArgs struct {
+		testName               string
+		backup                 Backup
+		expectedPersonsChecked int
+		expectedIssues         []IntegrityIssue
+	}
+
+	stub := func(id string) *Person {
+		return &Person{ID: id}
+	}
+
+	tests := []testArgs{
+		{
+			testName: "should not find issues on trees with symmetric links",
+			backup: Backup{
+				Trees: []Tree{{ID: "IDTree", Name: "Bittencourt"}},
+				Persons: []TreePerson{
+					{Person: Person{ID: "IDLuis", Children: []*Person{stub("IDCaio")}}},
+					{Person: Person{ID: "IDCaio", Parents: []*Person{stub("IDLuis")}}},
+					{TreeID: "IDTree", Person: Person{ID: "IDDayse"}},
+				},
+			},
+			expectedPersonsChecked: 3,
+		},
+		{
+			testName: "should find persons of unknown trees",
+			backup: Backup{
+				Persons: []TreePerson{
+					{TreeID: "IDUnexistingTree", Person: Person{ID: "IDCaio"}},
+				},
+			},
+			expectedPersonsChecked: 1,
+			expectedIssues: []IntegrityIssue{
+				{Type: UnknownTreeIssue, PersonID: "IDCaio", RelatedPersonIDS: []string{"IDUnexistingTree"}},
+			},
+		},
+		{
+			testName: "should find duplicated person ids",
+			backup: Backup{
+				Persons: []TreePerson{
+					{Person: Person{ID: "IDCaio"}},
+					{Person: Person{ID: "IDCaio"}},
+				},
+			},
+			expectedPersonsChecked: 1,
+			expectedIssues: []IntegrityIssue{
+				{Type: DuplicatedPersonIDIssue, PersonID: "IDCaio"},
+			},
+		},
+		{
+			testName: "should find links between different trees as dangling",
+			backup: Backup{
+				Trees: []Tree{{ID: "IDTree", Name: "Bittencourt"}},
+				Persons: []TreePerson{
+					{Person: Person{ID: "IDLuis", Children: []*Person{stub("IDCaio")}}},
+					{TreeID: "IDTree", Person: Person{ID: "IDCaio", Parents: []*Person{stub("IDLuis")}}},
+				},
+			},
+			expectedPersonsChecked: 2,
+			expectedIssues: []IntegrityIssue{
+				{Type: DanglingChildIDIssue, PersonID: "IDLuis", RelatedPersonIDS: []string{"IDCaio"}, Fixable: true},
+				{Type: DanglingParentIDIssue, PersonID: "IDCaio", RelatedPersonIDS: []string{"IDLuis"}, Fixable: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(tt testArgs) func(t *testing.T) {
+			return func(t *testing.T) {
+				report := tt.backup.Validate()
+
+				assert.Equal(t, tt.expectedPersonsChecked, report.PersonsChecked)
+				assert.ElementsMatch(t, tt.expectedIssues, report.Issues)
+			}
+		}(tt))
+	}
+}
@@ -0,0 +1,187 @@
+package domain
+
+import (
+	"container/heap"
+	"fmt"
+
+	"github.com/CaioBittencourt/arvore-genealogica/errors"
+)
+
+// PathDirection restricts the jumps of a bacons number to parents or to children.
+type PathDirection string
+
+const (
+	AnyPathDirection         PathDirection = ""
+	AncestorsPathDirection   PathDirection = "ancestors"
+	DescendantsPathDirection PathDirection = "descendants"
+)
+
+func (d PathDirection) IsValid() bool {
+	switch d {
+	case AnyPathDirection, AncestorsPathDirection, DescendantsPathDirection:
+		return true
+	}
+
+	return false
+}
+
+// BaconsNumberOptions chooses the jumps a bacons number can take and how much each of them costs. The zero value
+// counts every jump through spouses, parents and children of every link type as 1, without a depth limit.
+type BaconsNumberOptions struct {
+	LinkTypes      []LinkType
+	ExcludeSpouses bool
+	// Direction only jumps to parents for ancestors or to children for descendants, never to spouses
+	Direction PathDirection
+	// weights of 0 count as 1
+	SpouseWeight uint
+	ParentWeight uint
+	ChildWeight  uint
+	// MaxDepth is the maximum number of jumps, 0 for no limit
+	MaxDepth uint
+}
+
+func (o BaconsNumberOptions) Validate() error {
+	if !o.Direction.IsValid() {
+		return errors.NewApplicationError(fmt.Sprintf("direction %s is not valid", o.Direction), errors.InvalidQueryParameterErrorCode)
+	}
+
+	for _, linkType := range o.LinkTypes {
+		if !linkType.IsValid() {
+			return errors.NewApplicationError(fmt.Sprintf("parent link type %s is not valid", linkType), errors.InvalidParentLinkTypeErrorCode)
+		}
+	}
+
+	return nil
+}
+
+// Reversed returns the options to search from the other person, so an ancestor of A is searched as a descendant of
+// B and jumping to a parent costs what jumping to a child did.
+func (o BaconsNumberOptions) Reversed() BaconsNumberOptions {
+	reversed := o
+	reversed.ParentWeight, reversed.ChildWeight = o.ChildWeight, o.ParentWeight
+
+	switch o.Direction {
+	case AncestorsPathDirection:
+		reversed.Direction = DescendantsPathDirection
+	case DescendantsPathDirection:
+		reversed.Direction = AncestorsPathDirection
+	}
+
+	return reversed
+}
+
+func jumpWeight(weight uint) uint {
+	if weight == 0 {
+		return 1
+	}
+
+	return weight
+}
+
+type personJump struct {
+	person *Person
+	weight uint
+}
+
+func (o BaconsNumberOptions) jumpsFrom(person *Person) []personJump {
+	var jumps []personJump
+	if o.Direction == AnyPathDirection && !o.ExcludeSpouses {
+		for _, spouse := range person.Spouses {
+			jumps = append(jumps, personJump{person: spouse, weight: jumpWeight(o.SpouseWeight)})
+		}
+	}
+
+	if o.Direction != DescendantsPathDirection {
+		for _, parent := range person.Parents {
+			jumps = append(jumps, personJump{person: parent, weight: jumpWeight(o.ParentWeight)})
+		}
+	}
+
+	if o.Direction != AncestorsPathDirection {
+		for _, children := range person.Children {
+			jumps = append(jumps, personJump{person: children, weight: jumpWeight(o.ChildWeight)})
+		}
+	}
+
+	return jumps
+}
+
+// pathState keeps the depth only when it is limited, otherwise each person is settled a single time.
+type pathState struct {
+	personID string
+	depth    uint
+}
+
+type pathStep struct {
+	state    pathState
+	person   *Person
+	distance uint
+}
+
+type pathQueue []pathStep
+
+func (pq pathQueue) Len() int            { return len(pq) }
+func (pq pathQueue) Less(i, j int) bool  { return pq[i].distance < pq[j].distance }
+func (pq pathQueue) Swap(i, j int)       { pq[i], pq[j] = pq[j], pq[i] }
+func (pq *pathQueue) Push(x interface{}) { *pq = append(*pq, x.(pathStep)) }
+func (pq *pathQueue) Pop() interface{} {
+	old := *pq
+	step := old[len(old)-1]
+	*pq = old[:len(old)-1]
+	return step
+}
+
+// BaconsNumber is the weight of the lightest path between both persons, found with Dijkstra over the jumps allowed by
+// options. With the zero options it is the minimum number of jumps. Returns nil when there is no such path.
+func (fg FamilyGraph) BaconsNumber(personIDA string, personIDB string, options BaconsNumberOptions) *uint {
+	if personIDA == personIDB {
+		zero := uint(0)
+		return &zero
+	}
+
+	familyGraph := fg.WithLinkTypes(options.LinkTypes)
+
+	personA, ok := familyGraph.Members[personIDA]
+	if !ok {
+		return nil
+	}
+
+	if _, ok := familyGraph.Members[personIDB]; !ok {
+		return nil
+	}
+
+	start := pathState{personID: personA.ID}
+	distanceByState := map[pathState]uint{start: 0}
+	queue := &pathQueue{{state: start, person: personA}}
+	for queue.Len() > 0 {
+		step := heap.Pop(queue).(pathStep)
+		if step.distance > distanceByState[step.state] {
+			continue
+		}
+
+		if step.person.ID == personIDB {
+			return &step.distance
+		}
+
+		if options.MaxDepth > 0 && step.state.depth == options.MaxDepth {
+			continue
+		}
+
+		for _, jump := range options.jumpsFrom(step.person) {
+			nextState := pathState{personID: jump.person.ID}
+			if options.MaxDepth > 0 {
+				nextState.depth = step.state.depth + 1
+			}
+
+			distance := step.distance + jump.weight
+			if currentDistance, ok := distanceByState[nextState]; ok && currentDistance <= distance {
+				continue
+			}
+
+			distanceByState[nextState] = distance
+			heap.Push(queue, pathStep{state: nextState, person: jump.person, distance: distance})
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,197 @@
+package domain
+
+import (
+	"fmt"
+
+	"github.com/CaioBittencourt/arvore-genealogica/errors"
+)
+
+// PersonsLoader reads the persons with the given ids along with their parents and their children, the children with
+// the ids of all their parents. Persons that are not found are left out.
+type PersonsLoader func(personIDS []string) ([]Person, error)
+
+// baconsNumberSearch is one of the two sides of SearchBaconsNumber. Every person closer than levels jumps to the
+// side start was already loaded, frontier holds the ones exactly levels jumps away.
+type baconsNumberSearch struct {
+	options  BaconsNumberOptions
+	loaded   map[string]bool
+	frontier []string
+	levels   uint
+}
+
+func newBaconsNumberSearch(personID string, options BaconsNumberOptions) *baconsNumberSearch {
+	return &baconsNumberSearch{options: options, loaded: map[string]bool{}, frontier: []string{personID}}
+}
+
+func (s *baconsNumberSearch) expand(familyGraph FamilyGraph, persons []Person) {
+	for _, personID := range s.frontier {
+		s.loaded[personID] = true
+	}
+
+	var frontier []string
+	inFrontier := map[string]bool{}
+	for _, person := range persons {
+		member := familyGraph.addLoadedPerson(person)
+		for _, jump := range s.options.jumpsFrom(member) {
+			if s.loaded[jump.person.ID] || inFrontier[jump.person.ID] {
+				continue
+			}
+
+			inFrontier[jump.person.ID] = true
+			frontier = append(frontier, jump.person.ID)
+		}
+	}
+
+	s.frontier = frontier
+	s.levels++
+}
+
+func (fg FamilyGraph) member(personID string) *Person {
+	member, ok := fg.Members[personID]
+	if !ok {
+		member = &Person{ID: personID}
+		fg.Members[personID] = member
+	}
+
+	return member
+}
+
+func linkLoadedParent(child *Person, parent *Person) {
+	if !child.isParent(*parent) {
+		child.Parents = append(child.Parents, parent)
+	}
+
+	if !parent.isChildren(*child) {
+		parent.Children = append(parent.Children, child)
+	}
+}
+
+func linkLoadedSpouses(person *Person, spouse *Person) {
+	if person.ID == spouse.ID {
+		return
+	}
+
+	if !person.HasSpouse(*spouse) {
+		person.Spouses = append(person.Spouses, spouse)
+	}
+
+	if !spouse.HasSpouse(*person) {
+		spouse.Spouses = append(spouse.Spouses, person)
+	}
+}
+
+// addLoadedPerson merges a person read by a PersonsLoader into the graph. The other parents of its children become
+// its spouses.
+func (fg FamilyGraph) addLoadedPerson(person Person) *Person {
+	member := fg.member(person.ID)
+	member.Name = person.Name
+	member.Gender = person.Gender
+	member.ParentRoles = person.ParentRoles
+	member.ParentLinkTypes = person.ParentLinkTypes
+
+	for _, parent := range person.Parents {
+		linkLoadedParent(member, fg.member(parent.ID))
+	}
+
+	for _, children := range person.Children {
+		childMember := fg.member(children.ID)
+		childMember.ParentRoles = children.ParentRoles
+		childMember.ParentLinkTypes = children.ParentLinkTypes
+
+		linkLoadedParent(childMember, member)
+		for _, childParent := range children.Parents {
+			spouse := fg.member(childParent.ID)
+			linkLoadedParent(childMember, spouse)
+			linkLoadedSpouses(member, spouse)
+		}
+	}
+
+	return member
+}
+
+func (o BaconsNumberOptions) minimumJumpWeight() uint {
+	minimum := jumpWeight(o.SpouseWeight)
+	for _, weight := range []uint{jumpWeight(o.ParentWeight), jumpWeight(o.ChildWeight)} {
+		if weight < minimum {
+			minimum = weight
+		}
+	}
+
+	return minimum
+}
+
+// SearchBaconsNumber finds the bacons number of FamilyGraph.BaconsNumber without having the whole tree in memory.
+// It runs a bidirectional search from both persons, loading with loadPersons the frontier of the side that is smaller
+// one jump at a time, and stops when the lightest path found can no longer be beaten by one going through persons
+// that were not loaded yet. Returns nil when there is no path and an error when more than maxPersons would be loaded.
+func SearchBaconsNumber(personIDA string, personIDB string, options BaconsNumberOptions, maxPersons int, loadPersons PersonsLoader) (*uint, error) {
+	forward := newBaconsNumberSearch(personIDA, options)
+	backward := newBaconsNumberSearch(personIDB, options.Reversed())
+
+	startIDS := []string{personIDA}
+	if personIDB != personIDA {
+		startIDS = append(startIDS, personIDB)
+	}
+
+	persons, err := loadPersons(startIDS)
+	if err != nil {
+		return nil, err
+	}
+
+	personByID := map[string]Person{}
+	for _, person := range persons {
+		personByID[person.ID] = person
+	}
+
+	for _, personID := range []string{personIDA, personIDB} {
+		if _, ok := personByID[personID]; !ok {
+			return nil, errors.NewApplicationError(fmt.Sprintf("person with id %s not found", personID), errors.PersonNotFoundErrorCode)
+		}
+	}
+
+	if personIDA == personIDB {
+		zero := uint(0)
+		return &zero, nil
+	}
+
+	familyGraph := FamilyGraph{Members: map[string]*Person{}}
+	forward.expand(familyGraph, []Person{personByID[personIDA]})
+	backward.expand(familyGraph, []Person{personByID[personIDB]})
+	loadedPersons := 2
+
+	minimumJumpWeight := options.minimumJumpWeight()
+	for {
+		baconsNumber := familyGraph.BaconsNumber(personIDA, personIDB, options)
+		if len(forward.frontier) == 0 || len(backward.frontier) == 0 {
+			return baconsNumber, nil
+		}
+
+		// a path that was not found yet has a jump between two persons neither side loaded, so it has at least one
+		// jump more than the levels of both sides together
+		unloadedPathJumps := forward.levels + backward.levels + 1
+		if baconsNumber != nil && *baconsNumber <= unloadedPathJumps*minimumJumpWeight {
+			return baconsNumber, nil
+		}
+
+		if options.MaxDepth > 0 && unloadedPathJumps > options.MaxDepth {
+			return baconsNumber, nil
+		}
+
+		search := forward
+		if len(backward.frontier) < len(forward.frontier) {
+			search = backward
+		}
+
+		loadedPersons += len(search.frontier)
+		if loadedPersons > maxPersons {
+			return nil, errors.NewApplicationError(fmt.Sprintf("search stopped after loading %d persons", maxPersons), errors.SearchLimitReachedErrorCode)
+		}
+
+		persons, err := loadPersons(search.frontier)
+		if err != nil {
+			return nil, err
+		}
+
+		search.expand(familyGraph, persons)
+	}
+}
@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/CaioBittencourt/arvore-genealogica/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func buildPersonsLoader(familyGraph FamilyGraph, loadedPersonIDS *[]string) PersonsLoader {
+	return func(personIDS []string) ([]Person, error) {
+		var persons []Person
+		for _, personID := range personIDS {
+			*loadedPersonIDS = append(*loadedPersonIDS, personID)
+			if member, ok := familyGraph.Members[personID]; ok {
+				persons = append(persons, *member)
+			}
+		}
+
+		return persons, nil
+	}
+}
+
+// buildDistantMarriageFamilyGraph has two families of generations ancestors each, joined by the marriage of their
+// youngest persons, so their oldest ancestors are 2*generations+1 jumps apart.
+func buildDistantMarriageFamilyGraph(generations int) FamilyGraph {
+	familyGraph := FamilyGraph{Members: map[string]*Person{}}
+	for _, family := range []string{"Silva", "Souza"} {
+		var child *Person
+		for generation := 0; generation <= generations; generation++ {
+			person := familyGraph.member(fmt.Sprintf("ID%s%d", family, generation))
+			if child != nil {
+				linkLoadedParent(child, person)
+			}
+
+			child = person
+		}
+	}
+
+	bento := familyGraph.member("IDBento")
+	linkLoadedParent(bento, familyGraph.member("IDSilva0"))
+	linkLoadedParent(bento, familyGraph.member("IDSouza0"))
+	linkLoadedSpouses(familyGraph.Members["IDSilva0"], familyGraph.Members["IDSouza0"])
+
+	return familyGraph
+}
+
+func TestSearchBaconsNumber(t *testing.T) {
+	familyGraph := buildFamilyGraph()
+	familyGraph.Members["IDCauã"].SetParentLinkType("IDVivian", AdoptiveLinkType)
+
+	optionsList := []BaconsNumberOptions{
+		{},
+		{ExcludeSpouses: true},
+		{SpouseWeight: 3},
+		{ParentWeight: 2, ChildWeight: 5},
+		{Direction: AncestorsPathDirection},
+		{Direction: DescendantsPathDirection},
+		{MaxDepth: 3},
+		{SpouseWeight: 3, MaxDepth: 1},
+		{LinkTypes: []LinkType{BiologicalLinkType}},
+	}
+
+	for _, options := range optionsList {
+		for personIDA := range familyGraph.Members {
+			for personIDB := range familyGraph.Members {
+				t.Run(fmt.Sprintf("%s to %s with %+v", personIDA, personIDB, options), func(t *testing.T) {
+					var loadedPersonIDS []string
+					baconsNumber, err := SearchBaconsNumber(personIDA, personIDB, options, 100, buildPersonsLoader(familyGraph, &loadedPersonIDS))
+					assert.NoError(t, err)
+					assert.Equal(t, familyGraph.BaconsNumber(personIDA, personIDB, options), baconsNumber)
+				})
+			}
+		}
+	}
+}
+
+func TestSearchBaconsNumberThroughDistantMarriage(t *testing.T) {
+	familyGraph := buildDistantMarriageFamilyGraph(10)
+
+	var loadedPersonIDS []string
+	baconsNumber, err := SearchBaconsNumber("IDSilva10", "IDSouza10", BaconsNumberOptions{}, 100, buildPersonsLoader(familyGraph, &loadedPersonIDS))
+	assert.NoError(t, err)
+	if assert.NotNil(t, baconsNumber) {
+		assert.Equal(t, uint(21), *baconsNumber)
+	}
+
+	baconsNumber, err = SearchBaconsNumber("IDSilva10", "IDBento", BaconsNumberOptions{Direction: DescendantsPathDirection}, 100, buildPersonsLoader(familyGraph, &loadedPersonIDS))
+	assert.NoError(t, err)
+	if assert.NotNil(t, baconsNumber) {
+		assert.Equal(t, uint(11), *baconsNumber)
+	}
+
+	loadedPersonIDS = nil
+	baconsNumber, err = SearchBaconsNumber("IDSilva10", "IDSilva9", BaconsNumberOptions{}, 100, buildPersonsLoader(familyGraph, &loadedPersonIDS))
+	assert.NoError(t, err)
+	if assert.NotNil(t, baconsNumber) {
+		assert.Equal(t, uint(1), *baconsNumber)
+	}
+	assert.ElementsMatch(t, []string{"IDSilva10", "IDSilva9"}, loadedPersonIDS, "should only load the persons it needs")
+}
+
+func TestSearchBaconsNumberErrors(t *testing.T) {
+	familyGraph := buildDistantMarriageFamilyGraph(10)
+
+	var loadedPersonIDS []string
+	_, err := SearchBaconsNumber("IDSilva10", "IDSouza10", BaconsNumberOptions{}, 10, buildPersonsLoader(familyGraph, &loadedPersonIDS))
+	assert.True(t, errors.ErrorHasCode(err, errors.SearchLimitReachedErrorCode), "unexpected error: %v", err)
+	assert.LessOrEqual(t, len(loadedPersonIDS), 10)
+
+	_, err = SearchBaconsNumber("IDSilva10", "unexistingID", BaconsNumberOptions{}, 10, buildPersonsLoader(familyGraph, &loadedPersonIDS))
+	assert.True(t, errors.ErrorHasCode(err, errors.PersonNotFoundErrorCode), "unexpected error: %v", err)
+
+	_, err = SearchBaconsNumber("IDSilva10", "IDSilva10", BaconsNumberOptions{}, 10, buildPersonsLoader(FamilyGraph{Members: map[string]*Person{}}, &loadedPersonIDS))
+	assert.True(t, errors.ErrorHasCode(err, errors.PersonNotFoundErrorCode), "unexpected error: %v", err)
+}
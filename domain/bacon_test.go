@@ -0,0 +1,151 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/CaioBittencourt/arvore-genealogica/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBaconsNumber(t *testing.T) {
+	type baconsNumberArgs struct {
+		personIDA string
+		personIDB string
+		options   BaconsNumberOptions
+	}
+
+	type testArgs struct {
+		testName             string
+		familyGraph          FamilyGraph
+		baconsNumberArgs     baconsNumberArgs
+		expectedBaconsNumber *uint
+	}
+
+	baconsNumber := func(value uint) *uint {
+		return &value
+	}
+
+	familyGraph := buildFamilyGraph()
+	tests := []testArgs{
+		{
+			testName:             "should return nil bacon number if person A is not found on the family graph",
+			familyGraph:          familyGraph,
+			baconsNumberArgs:     baconsNumberArgs{personIDA: "unexistingID", personIDB: "IDLuis"},
+			expectedBaconsNumber: nil,
+		},
+		{
+			testName:             "should return nil bacon number if person B is not found on the family graph",
+			familyGraph:          familyGraph,
+			baconsNumberArgs:     baconsNumberArgs{personIDA: "IDLuis", personIDB: "unexistingID"},
+			expectedBaconsNumber: nil,
+		},
+		{
+			testName:             "should return correct bacons number between uncle / nephew",
+			familyGraph:          familyGraph,
+			baconsNumberArgs:     baconsNumberArgs{personIDA: "IDLuis", personIDB: "IDLivia"},
+			expectedBaconsNumber: baconsNumber(3),
+		},
+		{
+			testName:             "should return correct bacons number for grandparents",
+			familyGraph:          familyGraph,
+			baconsNumberArgs:     baconsNumberArgs{personIDA: "IDCaio", personIDB: "IDZézé"},
+			expectedBaconsNumber: baconsNumber(2),
+		},
+		{
+			testName:             "should return correct bacons number for cousins",
+			familyGraph:          familyGraph,
+			baconsNumberArgs:     baconsNumberArgs{personIDA: "IDCaio", personIDB: "IDLivia"},
+			expectedBaconsNumber: baconsNumber(4),
+		},
+		{
+			testName:             "should return correct bacons number for spouses",
+			familyGraph:          familyGraph,
+			baconsNumberArgs:     baconsNumberArgs{personIDA: "IDDayse", personIDB: "IDLuis"},
+			expectedBaconsNumber: baconsNumber(1),
+		},
+		{
+			testName:             "should go through a child in common when spouses are excluded",
+			familyGraph:          familyGraph,
+			baconsNumberArgs:     baconsNumberArgs{personIDA: "IDDayse", personIDB: "IDLuis", options: BaconsNumberOptions{ExcludeSpouses: true}},
+			expectedBaconsNumber: baconsNumber(2),
+		},
+		{
+			testName:             "should take the lightest path instead of the one with less jumps",
+			familyGraph:          familyGraph,
+			baconsNumberArgs:     baconsNumberArgs{personIDA: "IDDayse", personIDB: "IDLuis", options: BaconsNumberOptions{SpouseWeight: 3}},
+			expectedBaconsNumber: baconsNumber(2),
+		},
+		{
+			testName:             "should add the weights of the jumps",
+			familyGraph:          familyGraph,
+			baconsNumberArgs:     baconsNumberArgs{personIDA: "IDCaio", personIDB: "IDLivia", options: BaconsNumberOptions{ParentWeight: 2}},
+			expectedBaconsNumber: baconsNumber(6),
+		},
+		{
+			testName:             "should only jump to parents for ancestors",
+			familyGraph:          familyGraph,
+			baconsNumberArgs:     baconsNumberArgs{personIDA: "IDCauã", personIDB: "IDZézé", options: BaconsNumberOptions{Direction: AncestorsPathDirection}},
+			expectedBaconsNumber: baconsNumber(3),
+		},
+		{
+			testName:             "should not find cousins through ancestors only",
+			familyGraph:          familyGraph,
+			baconsNumberArgs:     baconsNumberArgs{personIDA: "IDCaio", personIDB: "IDLivia", options: BaconsNumberOptions{Direction: AncestorsPathDirection}},
+			expectedBaconsNumber: nil,
+		},
+		{
+			testName:             "should only jump to children for descendants",
+			familyGraph:          familyGraph,
+			baconsNumberArgs:     baconsNumberArgs{personIDA: "IDZézé", personIDB: "IDCauã", options: BaconsNumberOptions{Direction: DescendantsPathDirection}},
+			expectedBaconsNumber: baconsNumber(3),
+		},
+		{
+			testName:             "should not find spouses through descendants only",
+			familyGraph:          familyGraph,
+			baconsNumberArgs:     baconsNumberArgs{personIDA: "IDDayse", personIDB: "IDLuis", options: BaconsNumberOptions{Direction: DescendantsPathDirection}},
+			expectedBaconsNumber: nil,
+		},
+		{
+			testName:             "should not find persons beyond the max depth",
+			familyGraph:          familyGraph,
+			baconsNumberArgs:     baconsNumberArgs{personIDA: "IDCaio", personIDB: "IDLivia", options: BaconsNumberOptions{MaxDepth: 3}},
+			expectedBaconsNumber: nil,
+		},
+		{
+			testName:             "should take a heavier path when the lightest one is beyond the max depth",
+			familyGraph:          familyGraph,
+			baconsNumberArgs:     baconsNumberArgs{personIDA: "IDDayse", personIDB: "IDLuis", options: BaconsNumberOptions{SpouseWeight: 3, MaxDepth: 1}},
+			expectedBaconsNumber: baconsNumber(3),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(tt testArgs) func(t *testing.T) {
+			return func(t *testing.T) {
+				baconsNumber := tt.familyGraph.BaconsNumber(tt.baconsNumberArgs.personIDA, tt.baconsNumberArgs.personIDB, tt.baconsNumberArgs.options)
+				if tt.expectedBaconsNumber == nil {
+					assert.Nil(t, baconsNumber)
+				} else if assert.NotNil(t, baconsNumber) {
+					assert.Equal(t, *tt.expectedBaconsNumber, *baconsNumber)
+				}
+			}
+		}(tt))
+	}
+}
+
+func TestReversedBaconsNumberOptions(t *testing.T) {
+	options := BaconsNumberOptions{Direction: AncestorsPathDirection, ParentWeight: 2, ChildWeight: 3, SpouseWeight: 4}
+
+	assert.Equal(t, BaconsNumberOptions{Direction: DescendantsPathDirection, ParentWeight: 3, ChildWeight: 2, SpouseWeight: 4}, options.Reversed())
+	assert.Equal(t, options, options.Reversed().Reversed())
+}
+
+func TestValidateBaconsNumberOptions(t *testing.T) {
+	assert.NoError(t, BaconsNumberOptions{Direction: DescendantsPathDirection, LinkTypes: []LinkType{BiologicalLinkType}}.Validate())
+
+	err := BaconsNumberOptions{Direction: "siblings"}.Validate()
+	assert.True(t, errors.ErrorHasCode(err, errors.InvalidQueryParameterErrorCode), "unexpected error: %v", err)
+
+	err = BaconsNumberOptions{LinkTypes: []LinkType{"godparent"}}.Validate()
+	assert.True(t, errors.ErrorHasCode(err, errors.InvalidParentLinkTypeErrorCode), "unexpected error: %v", err)
+}
@@ -0,0 +1,220 @@
+package domain
+
+import (
+	"fmt"
+
+	"github.com/CaioBittencourt/arvore-genealogica/errors"
+)
+
+// BatchPerson is a person to be created in a batch. Its Parents and Children reference either the TempID of
+// another person of the same batch or the ID of a person already stored.
+type BatchPerson struct {
+	TempID string
+	Person Person
+}
+
+type BatchItemError struct {
+	Index  int
+	TempID string
+	Err    error
+}
+
+type PersonBatch []BatchPerson
+
+func (pb PersonBatch) TempIDS() map[string]int {
+	indexByTempID := make(map[string]int, len(pb))
+	for i, batchPerson := range pb {
+		if _, ok := indexByTempID[batchPerson.TempID]; !ok {
+			indexByTempID[batchPerson.TempID] = i
+		}
+	}
+
+	return indexByTempID
+}
+
+// ExternalIDS returns the referenced ids that are not temporary ids of the batch, so they must already be stored.
+func (pb PersonBatch) ExternalIDS() []string {
+	indexByTempID := pb.TempIDS()
+
+	var externalIDS []string
+	alreadyAdded := make(map[string]bool)
+	for _, batchPerson := range pb {
+		for _, relative := range append(append([]*Person{}, batchPerson.Person.Parents...), batchPerson.Person.Children...) {
+			if _, ok := indexByTempID[relative.ID]; ok || alreadyAdded[relative.ID] {
+				continue
+			}
+
+			alreadyAdded[relative.ID] = true
+			externalIDS = append(externalIDS, relative.ID)
+		}
+	}
+
+	return externalIDS
+}
+
+func addBatchLink(parentsByPersonID map[string]map[string]LinkType, parentID string, childrenID string, linkType LinkType) {
+	if _, ok := parentsByPersonID[childrenID]; !ok {
+		parentsByPersonID[childrenID] = make(map[string]LinkType)
+	}
+
+	parentsByPersonID[childrenID][parentID] = linkType
+}
+
+// addBatchChildrenLink links a parent to a child it lists as children. The child's own link to the parent, stored
+// or declared on the batch in any order, has the link type, so this one is only biological when there is none.
+func addBatchChildrenLink(parentsByPersonID map[string]map[string]LinkType, parentID string, childrenID string) {
+	if _, ok := parentsByPersonID[childrenID][parentID]; ok {
+		return
+	}
+
+	addBatchLink(parentsByPersonID, parentID, childrenID, BiologicalLinkType)
+}
+
+func biologicalBatchParentsCount(linkTypeByParentID map[string]LinkType) int {
+	count := 0
+	for _, linkType := range linkTypeByParentID {
+		if linkType == BiologicalLinkType {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Validate applies the Person.Validate rules to every person of the batch plus the checks that only make sense
+// for the whole batch: unique temporary ids, existing references, parents count after every link is created and
+// ancestry cycles. Only biological parents count for the parents limit. existingPersons must hold the stored persons referenced by the batch with their parents.
+func (pb PersonBatch) Validate(existingPersons []Person) []BatchItemError {
+	var itemErrors []BatchItemError
+	addItemError := func(index int, err error) {
+		itemErrors = append(itemErrors, BatchItemError{Index: index, TempID: pb[index].TempID, Err: err})
+	}
+
+	existingPersonsByID := make(map[string]Person, len(existingPersons))
+	for _, existingPerson := range existingPersons {
+		existingPersonsByID[existingPerson.ID] = existingPerson
+	}
+
+	indexByTempID := make(map[string]int, len(pb))
+	for i, batchPerson := range pb {
+		if batchPerson.TempID == "" {
+			addItemError(i, errors.NewApplicationError("temporary id is required", errors.InvalidBatchErrorCode))
+			continue
+		}
+
+		if _, ok := indexByTempID[batchPerson.TempID]; ok {
+			addItemError(i, errors.NewApplicationError(fmt.Sprintf("temporary id %s is duplicated", batchPerson.TempID), errors.InvalidBatchErrorCode))
+			continue
+		}
+
+		if _, ok := existingPersonsByID[batchPerson.TempID]; ok {
+			addItemError(i, errors.NewApplicationError(fmt.Sprintf("temporary id %s conflicts with a stored person", batchPerson.TempID), errors.InvalidBatchErrorCode))
+			continue
+		}
+
+		indexByTempID[batchPerson.TempID] = i
+	}
+
+	// link type of the parents every person will have once the batch is stored, keyed by temporary or stored id
+	parentsByPersonID := make(map[string]map[string]LinkType)
+	for _, existingPerson := range existingPersons {
+		for _, parent := range existingPerson.Parents {
+			addBatchLink(parentsByPersonID, parent.ID, existingPerson.ID, existingPerson.ParentLinkType(parent.ID))
+		}
+	}
+
+	referenceExists := func(personID string) bool {
+		_, isTempID := indexByTempID[personID]
+		_, isStored := existingPersonsByID[personID]
+		return isTempID || isStored
+	}
+
+	for i, batchPerson := range pb {
+		referencesAreValid := true
+		for _, relative := range append(append([]*Person{}, batchPerson.Person.Parents...), batchPerson.Person.Children...) {
+			if !referenceExists(relative.ID) {
+				addItemError(i, errors.NewApplicationError(fmt.Sprintf("person with id %s not found", relative.ID), errors.PersonNotFoundErrorCode))
+				referencesAreValid = false
+			}
+		}
+
+		if !referencesAreValid {
+			continue
+		}
+
+		var storedChildren []Person
+		for _, children := range batchPerson.Person.Children {
+			if storedChild, ok := existingPersonsByID[children.ID]; ok {
+				storedChildren = append(storedChildren, storedChild)
+			}
+		}
+
+		if err := batchPerson.Person.Validate(storedChildren); err != nil {
+			addItemError(i, err)
+			continue
+		}
+
+		var parents []Person
+		for _, parent := range batchPerson.Person.Parents {
+			if index, ok := indexByTempID[parent.ID]; ok {
+				parents = append(parents, Person{ID: parent.ID, Gender: pb[index].Person.Gender})
+			} else {
+				parents = append(parents, existingPersonsByID[parent.ID])
+			}
+		}
+
+		if err := batchPerson.Person.ValidateParentGenders(parents); err != nil {
+			addItemError(i, err)
+			continue
+		}
+
+		for _, parent := range batchPerson.Person.Parents {
+			addBatchLink(parentsByPersonID, parent.ID, batchPerson.TempID, batchPerson.Person.ParentLinkType(parent.ID))
+		}
+
+		for _, children := range batchPerson.Person.Children {
+			addBatchChildrenLink(parentsByPersonID, batchPerson.TempID, children.ID)
+		}
+	}
+
+	if len(itemErrors) > 0 {
+		return itemErrors
+	}
+
+	for i, batchPerson := range pb {
+		if biologicalBatchParentsCount(parentsByPersonID[batchPerson.TempID]) > 2 {
+			addItemError(i, errors.NewApplicationError("not allowed to have more than 2 parents", errors.TooManyParentsForPersonErrorCode))
+		}
+
+		for _, children := range batchPerson.Person.Children {
+			if _, ok := existingPersonsByID[children.ID]; ok && biologicalBatchParentsCount(parentsByPersonID[children.ID]) > 2 {
+				addItemError(i, errors.NewApplicationError("children already have two parents", errors.ChildrenAlreadyHasTwoParents))
+			}
+		}
+	}
+
+	var linkedPersons []Person
+	for personID, parentIDS := range parentsByPersonID {
+		linkedPerson := Person{ID: personID}
+		for parentID := range parentIDS {
+			linkedPerson.Parents = append(linkedPerson.Parents, &Person{ID: parentID})
+		}
+		linkedPersons = append(linkedPersons, linkedPerson)
+	}
+
+	linkedPersonsByID := make(map[string]Person, len(linkedPersons))
+	for _, linkedPerson := range linkedPersons {
+		linkedPersonsByID[linkedPerson.ID] = linkedPerson
+	}
+
+	for _, cycle := range findAncestryCycles(linkedPersons, linkedPersonsByID) {
+		for _, personID := range cycle {
+			if index, ok := indexByTempID[personID]; ok {
+				addItemError(index, errors.NewApplicationError("person can not be an ancestor of itself", errors.AncestryCycleErrorCode))
+				break
+			}
+		}
+	}
+
+	return itemErrors
+}
@@ -0,0 +1,149 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/CaioBittencourt/arvore-genealogica/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPersonBatchValidate(t *testing.T) {
+	type expectedItemError struct {
+		index int
+		code  errors.ApplicationErrorCode
+	}
+
+	type testArgs struct {
+		testName           string
+		batch              PersonBatch
+		existingPersons    []Person
+		expectedItemErrors []expectedItemError
+	}
+
+	ref := func(id string) *Person {
+		return &Person{ID: id}
+	}
+
+	tests := []testArgs{
+		{
+			testName: "should validate batch referencing persons sent later and stored persons",
+			batch: PersonBatch{
+				{TempID: "caio", Person: Person{Name: "Caio", Gender: Male, Parents: []*Person{ref("luis"), ref("IDDayse")}}},
+				{TempID: "luis", Person: Person{Name: "Luis", Gender: Male, Parents: []*Person{ref("IDZézé")}}},
+			},
+			existingPersons: []Person{{ID: "IDDayse"}, {ID: "IDZézé"}},
+		},
+		{
+			testName: "should report duplicated and missing temporary ids",
+			batch: PersonBatch{
+				{TempID: "caio", Person: Person{Name: "Caio", Gender: Male}},
+				{TempID: "caio", Person: Person{Name: "Caio", Gender: Male}},
+				{Person: Person{Name: "Luis", Gender: Male}},
+			},
+			expectedItemErrors: []expectedItemError{{index: 1, code: errors.InvalidBatchErrorCode}, {index: 2, code: errors.InvalidBatchErrorCode}},
+		},
+		{
+			testName: "should report person validation errors and unknown references per item",
+			batch: PersonBatch{
+				{TempID: "caio", Person: Person{Name: "C", Gender: Male}},
+				{TempID: "luis", Person: Person{Name: "Luis", Gender: "unexistingGender"}},
+				{TempID: "dayse", Person: Person{Name: "Dayse", Gender: Female, Children: []*Person{ref("unexistingID")}}},
+			},
+			expectedItemErrors: []expectedItemError{
+				{index: 0, code: errors.InvalidPersonNameErrorCode},
+				{index: 1, code: errors.InvalidPersonGenderErrorCode},
+				{index: 2, code: errors.PersonNotFoundErrorCode},
+			},
+		},
+		{
+			testName: "should report stored children that would have more than 2 parents",
+			batch: PersonBatch{
+				{TempID: "luis", Person: Person{Name: "Luis", Gender: Male, Children: []*Person{ref("IDCaio")}}},
+				{TempID: "dayse", Person: Person{Name: "Dayse", Gender: Female, Children: []*Person{ref("IDCaio")}}},
+			},
+			existingPersons:    []Person{{ID: "IDCaio", Parents: []*Person{ref("IDClaudia")}}},
+			expectedItemErrors: []expectedItemError{{index: 0, code: errors.ChildrenAlreadyHasTwoParents}, {index: 1, code: errors.ChildrenAlreadyHasTwoParents}},
+		},
+		{
+			testName: "should report batch persons that would have more than 2 parents",
+			batch: PersonBatch{
+				{TempID: "caio", Person: Person{Name: "Caio", Gender: Male, Parents: []*Person{ref("luis"), ref("dayse")}}},
+				{TempID: "luis", Person: Person{Name: "Luis", Gender: Male}},
+				{TempID: "dayse", Person: Person{Name: "Dayse", Gender: Female}},
+				{TempID: "claudia", Person: Person{Name: "Claudia", Gender: Female, Children: []*Person{ref("caio")}}},
+			},
+			expectedItemErrors: []expectedItemError{{index: 0, code: errors.TooManyParentsForPersonErrorCode}},
+		},
+		{
+			testName: "should allow parents that are not biological beyond 2 parents",
+			batch: PersonBatch{
+				{TempID: "caio", Person: Person{Name: "Caio", Gender: Male, Parents: []*Person{ref("luis"), ref("dayse"), ref("IDClaudia")},
+					ParentLinkTypes: map[string]LinkType{"IDClaudia": StepLinkType}}},
+				{TempID: "luis", Person: Person{Name: "Luis", Gender: Male}},
+				{TempID: "dayse", Person: Person{Name: "Dayse", Gender: Female}},
+			},
+			existingPersons: []Person{{ID: "IDClaudia", Name: "Claudia", Gender: Female}},
+		},
+		{
+			testName: "should keep the link type declared by the child when its parent lists it after it",
+			batch: PersonBatch{
+				{TempID: "caio", Person: Person{Name: "Caio", Gender: Male, Parents: []*Person{ref("luis"), ref("dayse"), ref("claudia")},
+					ParentLinkTypes: map[string]LinkType{"claudia": AdoptiveLinkType}}},
+				{TempID: "luis", Person: Person{Name: "Luis", Gender: Male}},
+				{TempID: "dayse", Person: Person{Name: "Dayse", Gender: Female}},
+				{TempID: "claudia", Person: Person{Name: "Claudia", Gender: Female, Children: []*Person{ref("caio")}}},
+			},
+		},
+		{
+			testName: "should keep the link type declared by the child when its parent lists it before it",
+			batch: PersonBatch{
+				{TempID: "claudia", Person: Person{Name: "Claudia", Gender: Female, Children: []*Person{ref("caio")}}},
+				{TempID: "luis", Person: Person{Name: "Luis", Gender: Male}},
+				{TempID: "dayse", Person: Person{Name: "Dayse", Gender: Female}},
+				{TempID: "caio", Person: Person{Name: "Caio", Gender: Male, Parents: []*Person{ref("luis"), ref("dayse"), ref("claudia")},
+					ParentLinkTypes: map[string]LinkType{"claudia": AdoptiveLinkType}}},
+			},
+		},
+		{
+			testName: "should report mothers and fathers with another gender, in the batch or stored",
+			batch: PersonBatch{
+				{TempID: "caio", Person: Person{Name: "Caio", Gender: Male, Parents: []*Person{ref("luis")}, ParentRoles: map[string]ParentRoleType{"luis": MotherParentRole}}},
+				{TempID: "luis", Person: Person{Name: "Luis", Gender: Male, Parents: []*Person{ref("IDZézé")}, ParentRoles: map[string]ParentRoleType{"IDZézé": FatherParentRole}}},
+				{TempID: "vivian", Person: Person{Name: "Vivian", Gender: Female, Parents: []*Person{ref("IDZézé")}, ParentRoles: map[string]ParentRoleType{"IDZézé": MotherParentRole}}},
+			},
+			existingPersons: []Person{{ID: "IDZézé", Gender: Female}},
+			expectedItemErrors: []expectedItemError{
+				{index: 0, code: errors.InvalidParentRoleErrorCode},
+				{index: 1, code: errors.InvalidParentRoleErrorCode},
+			},
+		},
+		{
+			testName: "should report ancestry cycles",
+			batch: PersonBatch{
+				{TempID: "caio", Person: Person{Name: "Caio", Gender: Male, Parents: []*Person{ref("luis")}}},
+				{TempID: "luis", Person: Person{Name: "Luis", Gender: Male, Parents: []*Person{ref("caio")}}},
+			},
+			expectedItemErrors: []expectedItemError{{code: errors.AncestryCycleErrorCode}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(tt testArgs) func(t *testing.T) {
+			return func(t *testing.T) {
+				itemErrors := tt.batch.Validate(tt.existingPersons)
+
+				assert.Len(t, itemErrors, len(tt.expectedItemErrors))
+				for i, expected := range tt.expectedItemErrors {
+					if i >= len(itemErrors) {
+						break
+					}
+
+					assert.True(t, errors.ErrorHasCode(itemErrors[i].Err, expected.code), itemErrors[i].Err.Error())
+					if expected.code != errors.AncestryCycleErrorCode {
+						assert.Equal(t, expected.index, itemErrors[i].Index)
+					}
+				}
+			}
+		}(tt))
+	}
+}
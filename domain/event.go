@@ -0,0 +1,159 @@
+package domain
+
+import (
+	"reflect"
+	"time"
+)
+
+type TreeEventType string
+
+const (
+	PersonCreatedTreeEvent TreeEventType = "person_created"
+	PersonUpdatedTreeEvent TreeEventType = "person_updated"
+	PersonDeletedTreeEvent TreeEventType = "person_deleted"
+	LinkAddedTreeEvent     TreeEventType = "link_added"
+	LinkRemovedTreeEvent   TreeEventType = "link_removed"
+)
+
+// TreeEvent is a change to a tree, pushed to the viewers of the family graphs it affects. Link events are about the
+// child, with the parent as RelatedPersonID. Person is nil when it was deleted and, like on PersonChange, its Parents
+// and Children only have their IDs filled.
+type TreeEvent struct {
+	Type            TreeEventType
+	PersonID        string
+	RelatedPersonID string
+	Person          *Person
+	// AffectedPersonIDS are the person and its parents and children before and after the change
+	AffectedPersonIDS []string
+	Timestamp         time.Time
+}
+
+// Affects tells whether the event changes a family graph with these members.
+func (e TreeEvent) Affects(memberIDS map[string]bool) bool {
+	for _, personID := range e.AffectedPersonIDS {
+		if memberIDS[personID] {
+			return true
+		}
+	}
+
+	return false
+}
+
+func relativeIDS(person *Person) []string {
+	if person == nil {
+		return nil
+	}
+
+	var ids []string
+	for _, relative := range append(append([]*Person{}, person.Parents...), person.Children...) {
+		ids = append(ids, relative.ID)
+	}
+
+	return ids
+}
+
+func parentIDSet(person *Person) map[string]bool {
+	parentIDS := map[string]bool{}
+	if person == nil {
+		return parentIDS
+	}
+
+	for _, parent := range person.Parents {
+		parentIDS[parent.ID] = true
+	}
+
+	return parentIDS
+}
+
+func sameLifeEvent(a *LifeEvent, b *LifeEvent) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
+
+func personDetailsChanged(before Person, after Person) bool {
+	sameParentRoles := len(before.ParentRoles) == 0 && len(after.ParentRoles) == 0 || reflect.DeepEqual(before.ParentRoles, after.ParentRoles)
+	sameParentLinkTypes := len(before.ParentLinkTypes) == 0 && len(after.ParentLinkTypes) == 0 || reflect.DeepEqual(before.ParentLinkTypes, after.ParentLinkTypes)
+
+	return before.Name != after.Name ||
+		before.Gender != after.Gender ||
+		!sameLifeEvent(before.Birth, after.Birth) ||
+		!sameLifeEvent(before.Death, after.Death) ||
+		!sameParentRoles ||
+		!sameParentLinkTypes
+}
+
+// BuildPersonUpdatedTreeEvent is the only event that can be told of a change whose previous state is unknown.
+func BuildPersonUpdatedTreeEvent(after Person, timestamp time.Time) TreeEvent {
+	return TreeEvent{
+		Type:              PersonUpdatedTreeEvent,
+		PersonID:          after.ID,
+		Person:            &after,
+		AffectedPersonIDS: append([]string{after.ID}, relativeIDS(&after)...),
+		Timestamp:         timestamp,
+	}
+}
+
+// BuildTreeEvents turns a change to a person into events, before being nil when it was created and after when it was
+// deleted. Links are only read from the parents of the child, so the children of the parent, which change along with
+// them, do not report the same link twice.
+func BuildTreeEvents(before *Person, after *Person, timestamp time.Time) []TreeEvent {
+	if before == nil && after == nil {
+		return nil
+	}
+
+	person := after
+	if person == nil {
+		person = before
+	}
+
+	var affectedPersonIDS []string
+	isAffected := map[string]bool{}
+	for _, personID := range append(append([]string{person.ID}, relativeIDS(before)...), relativeIDS(after)...) {
+		if !isAffected[personID] {
+			isAffected[personID] = true
+			affectedPersonIDS = append(affectedPersonIDS, personID)
+		}
+	}
+
+	newTreeEvent := func(eventType TreeEventType, relatedPersonID string) TreeEvent {
+		return TreeEvent{
+			Type:              eventType,
+			PersonID:          person.ID,
+			RelatedPersonID:   relatedPersonID,
+			Person:            after,
+			AffectedPersonIDS: affectedPersonIDS,
+			Timestamp:         timestamp,
+		}
+	}
+
+	var treeEvents []TreeEvent
+	switch {
+	case before == nil:
+		treeEvents = append(treeEvents, newTreeEvent(PersonCreatedTreeEvent, ""))
+	case after == nil:
+		return []TreeEvent{newTreeEvent(PersonDeletedTreeEvent, "")}
+	case personDetailsChanged(*before, *after):
+		treeEvents = append(treeEvents, newTreeEvent(PersonUpdatedTreeEvent, ""))
+	}
+
+	parentIDSBefore := parentIDSet(before)
+	parentIDSAfter := parentIDSet(after)
+	for _, parent := range after.Parents {
+		if !parentIDSBefore[parent.ID] {
+			treeEvents = append(treeEvents, newTreeEvent(LinkAddedTreeEvent, parent.ID))
+		}
+	}
+
+	if before != nil {
+		for _, parent := range before.Parents {
+			if !parentIDSAfter[parent.ID] {
+				treeEvents = append(treeEvents, newTreeEvent(LinkRemovedTreeEvent, parent.ID))
+			}
+		}
+	}
+
+	return treeEvents
+}
@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildTreeEvents(t *testing.T) {
+	type treeEventSummary struct {
+		Type            TreeEventType
+		RelatedPersonID string
+	}
+
+	type testArgs struct {
+		testName                  string
+		before                    *Person
+		after                     *Person
+		expectedEvents            []treeEventSummary
+		expectedAffectedPersonIDS []string
+	}
+
+	luis := &Person{ID: "IDLuis"}
+	dayse := &Person{ID: "IDDayse"}
+	caua := &Person{ID: "IDCauã"}
+	vivian := Person{ID: "IDVivian", Name: "Vivian", Gender: Female, Parents: []*Person{luis}, Children: []*Person{caua}}
+
+	renamedVivian := vivian
+	renamedVivian.Name = "Vivian Regis"
+
+	vivianWithDayse := vivian
+	vivianWithDayse.Parents = []*Person{luis, dayse}
+
+	vivianWithMother := vivianWithDayse
+	vivianWithMother.ParentRoles = map[string]ParentRoleType{dayse.ID: MotherParentRole}
+
+	vivianWithoutParents := vivian
+	vivianWithoutParents.Parents = nil
+
+	vivianWithChildren := vivian
+	vivianWithChildren.Children = []*Person{caua, {ID: "IDBento"}}
+
+	tests := []testArgs{
+		{
+			testName:                  "should create the person with links to its parents",
+			after:                     &vivian,
+			expectedEvents:            []treeEventSummary{{Type: PersonCreatedTreeEvent}, {Type: LinkAddedTreeEvent, RelatedPersonID: "IDLuis"}},
+			expectedAffectedPersonIDS: []string{"IDVivian", "IDLuis", "IDCauã"},
+		},
+		{
+			testName:                  "should delete the person",
+			before:                    &vivian,
+			expectedEvents:            []treeEventSummary{{Type: PersonDeletedTreeEvent}},
+			expectedAffectedPersonIDS: []string{"IDVivian", "IDLuis", "IDCauã"},
+		},
+		{
+			testName:                  "should update the person when its details change",
+			before:                    &vivian,
+			after:                     &renamedVivian,
+			expectedEvents:            []treeEventSummary{{Type: PersonUpdatedTreeEvent}},
+			expectedAffectedPersonIDS: []string{"IDVivian", "IDLuis", "IDCauã"},
+		},
+		{
+			testName:                  "should add a link to the new parent",
+			before:                    &vivian,
+			after:                     &vivianWithDayse,
+			expectedEvents:            []treeEventSummary{{Type: LinkAddedTreeEvent, RelatedPersonID: "IDDayse"}},
+			expectedAffectedPersonIDS: []string{"IDVivian", "IDLuis", "IDCauã", "IDDayse"},
+		},
+		{
+			testName:                  "should update the person when the role of a parent changes",
+			before:                    &vivianWithDayse,
+			after:                     &vivianWithMother,
+			expectedEvents:            []treeEventSummary{{Type: PersonUpdatedTreeEvent}},
+			expectedAffectedPersonIDS: []string{"IDVivian", "IDLuis", "IDDayse", "IDCauã"},
+		},
+		{
+			testName:                  "should remove the link to the removed parent",
+			before:                    &vivian,
+			after:                     &vivianWithoutParents,
+			expectedEvents:            []treeEventSummary{{Type: LinkRemovedTreeEvent, RelatedPersonID: "IDLuis"}},
+			expectedAffectedPersonIDS: []string{"IDVivian", "IDLuis", "IDCauã"},
+		},
+		{
+			testName: "should not report the links of the children, which their own parents report",
+			before:   &vivian,
+			after:    &vivianWithChildren,
+		},
+	}
+
+	timestamp := time.Date(2023, 6, 1, 10, 0, 0, 0, time.UTC)
+	for _, tt := range tests {
+		t.Run(tt.testName, func(tt testArgs) func(t *testing.T) {
+			return func(t *testing.T) {
+				treeEvents := BuildTreeEvents(tt.before, tt.after, timestamp)
+
+				var events []treeEventSummary
+				for _, treeEvent := range treeEvents {
+					events = append(events, treeEventSummary{Type: treeEvent.Type, RelatedPersonID: treeEvent.RelatedPersonID})
+					assert.Equal(t, "IDVivian", treeEvent.PersonID)
+					assert.Equal(t, tt.after, treeEvent.Person)
+					assert.Equal(t, timestamp, treeEvent.Timestamp)
+					assert.ElementsMatch(t, tt.expectedAffectedPersonIDS, treeEvent.AffectedPersonIDS)
+				}
+				assert.Equal(t, tt.expectedEvents, events)
+			}
+		}(tt))
+	}
+}
+
+func TestTreeEventAffects(t *testing.T) {
+	treeEvent := TreeEvent{PersonID: "IDVivian", AffectedPersonIDS: []string{"IDVivian", "IDLuis"}}
+
+	assert.True(t, treeEvent.Affects(map[string]bool{"IDLuis": true, "IDCaio": true}))
+	assert.False(t, treeEvent.Affects(map[string]bool{"IDCaio": true}))
+}
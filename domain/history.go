@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type PersonChangeType string
+
+const (
+	PersonCreatedChange PersonChangeType = "created"
+	PersonUpdatedChange PersonChangeType = "updated"
+	PersonDeletedChange PersonChangeType = "deleted"
+)
+
+// PersonChange is an entry of the append only history of a person. Before is nil when the person was created and
+// After is nil when it was deleted. Parents and Children of the snapshots only have their IDs filled.
+type PersonChange struct {
+	ID           string
+	PersonID     string
+	Type         PersonChangeType
+	Before       *Person
+	After        *Person
+	ClaimedActor string
+	Source       string
+	Reason       string
+	Timestamp    time.Time
+}
+
+// ChangeMetadata is recorded with every change. ClaimedActor and Reason are sent by the client and never checked, so
+// the actor is only who the client claims to be. Source is set by the server from the connection, like the address of
+// the client, and can not be overridden by it.
+type ChangeMetadata struct {
+	ClaimedActor string
+	Source       string
+	Reason       string
+}
+
+type changeMetadataContextKey struct{}
+
+func ContextWithChangeMetadata(ctx context.Context, changeMetadata ChangeMetadata) context.Context {
+	return context.WithValue(ctx, changeMetadataContextKey{}, changeMetadata)
+}
+
+func ChangeMetadataFromContext(ctx context.Context) ChangeMetadata {
+	changeMetadata, _ := ctx.Value(changeMetadataContextKey{}).(ChangeMetadata)
+	return changeMetadata
+}
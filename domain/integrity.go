@@ -0,0 +1,249 @@
+package domain
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+type IntegrityIssueType string
+
+const (
+	AsymmetricParentLinkIssue IntegrityIssueType = "asymmetric_parent_link"
+	AsymmetricChildLinkIssue  IntegrityIssueType = "asymmetric_child_link"
+	DanglingParentIDIssue     IntegrityIssueType = "dangling_parent_id"
+	DanglingChildIDIssue      IntegrityIssueType = "dangling_child_id"
+	TooManyParentsIssue       IntegrityIssueType = "too_many_parents"
+	AncestryCycleIssue        IntegrityIssueType = "ancestry_cycle"
+	DuplicatedPersonIDIssue   IntegrityIssueType = "duplicated_person_id"
+	UnknownTreeIssue          IntegrityIssueType = "unknown_tree"
+)
+
+// IntegrityIssue describes a broken link found on PersonID. RelatedPersonIDS holds the other end of the
+// link, every person in the cycle for AncestryCycleIssue, or the missing tree for UnknownTreeIssue.
+type IntegrityIssue struct {
+	Type             IntegrityIssueType `json:"type"`
+	PersonID         string             `json:"personId"`
+	RelatedPersonIDS []string           `json:"relatedPersonIds"`
+	Fixable          bool               `json:"fixable"`
+}
+
+// IntegrityReport is written as is by the admin endpoints and by the cli, so both print the same json.
+type IntegrityReport struct {
+	PersonsChecked int              `json:"personsChecked"`
+	Repaired       bool             `json:"repaired"`
+	Issues         []IntegrityIssue `json:"issues"`
+}
+
+func (ii IntegrityIssue) MarshalJSON() ([]byte, error) {
+	type integrityIssue IntegrityIssue
+	if ii.RelatedPersonIDS == nil {
+		ii.RelatedPersonIDS = []string{}
+	}
+
+	return json.Marshal(integrityIssue(ii))
+}
+
+func (ir IntegrityReport) MarshalJSON() ([]byte, error) {
+	type integrityReport IntegrityReport
+	if ir.Issues == nil {
+		ir.Issues = []IntegrityIssue{}
+	}
+
+	return json.Marshal(integrityReport(ir))
+}
+
+func (ir IntegrityReport) FixableIssues() []IntegrityIssue {
+	var fixableIssues []IntegrityIssue
+	for _, issue := range ir.Issues {
+		if issue.Fixable {
+			fixableIssues = append(fixableIssues, issue)
+		}
+	}
+
+	return fixableIssues
+}
+
+// RecheckFixableIssues keeps the fixable issues that still hold for persons, which must be read again right before
+// the repair so links changed since the check are not repaired on stale data.
+func RecheckFixableIssues(issues []IntegrityIssue, persons []Person) []IntegrityIssue {
+	personsByID := make(map[string]Person, len(persons))
+	for _, person := range persons {
+		personsByID[person.ID] = person
+	}
+
+	parentsAfterRepair := make(map[string]int, len(persons))
+	for _, person := range persons {
+		parentsAfterRepair[person.ID] = person.biologicalParentsCount()
+	}
+
+	var stillHolding []IntegrityIssue
+	for _, issue := range issues {
+		if !issue.Fixable || len(issue.RelatedPersonIDS) == 0 {
+			continue
+		}
+
+		person, ok := personsByID[issue.PersonID]
+		if !ok {
+			continue
+		}
+
+		relatedPersonID := issue.RelatedPersonIDS[0]
+		relatedPerson, relatedExists := personsByID[relatedPersonID]
+
+		var holds bool
+		switch issue.Type {
+		case AsymmetricParentLinkIssue:
+			holds = containsPersonID(person.Parents, relatedPersonID) && relatedExists && !containsPersonID(relatedPerson.Children, person.ID)
+		case AsymmetricChildLinkIssue:
+			holds = containsPersonID(person.Children, relatedPersonID) && relatedExists && !containsPersonID(relatedPerson.Parents, person.ID) &&
+				parentsAfterRepair[relatedPersonID] < 2
+			if holds {
+				parentsAfterRepair[relatedPersonID]++
+			}
+		case DanglingParentIDIssue:
+			holds = containsPersonID(person.Parents, relatedPersonID) && !relatedExists
+		case DanglingChildIDIssue:
+			holds = containsPersonID(person.Children, relatedPersonID) && !relatedExists
+		}
+
+		if holds {
+			stillHolding = append(stillHolding, issue)
+		}
+	}
+
+	return stillHolding
+}
+
+func containsPersonID(persons []*Person, personID string) bool {
+	for _, person := range persons {
+		if person.ID == personID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CheckIntegrity expects persons as loaded from storage, where Parents and Children only need their IDs filled.
+func CheckIntegrity(persons []Person) IntegrityReport {
+	report := IntegrityReport{PersonsChecked: len(persons)}
+
+	personsByID := make(map[string]Person, len(persons))
+	for _, person := range persons {
+		personsByID[person.ID] = person
+	}
+
+	sortedPersons := make([]Person, len(persons))
+	copy(sortedPersons, persons)
+	sort.Slice(sortedPersons, func(i, j int) bool { return sortedPersons[i].ID < sortedPersons[j].ID })
+
+	// biological parents gained by fixing asymmetric links, so we dont repair a child into having more than 2 parents
+	parentsAfterRepair := make(map[string]int, len(persons))
+	for _, person := range sortedPersons {
+		parentsAfterRepair[person.ID] = person.biologicalParentsCount()
+	}
+
+	for _, person := range sortedPersons {
+		if person.biologicalParentsCount() > 2 {
+			report.Issues = append(report.Issues, IntegrityIssue{
+				Type:             TooManyParentsIssue,
+				PersonID:         person.ID,
+				RelatedPersonIDS: personIDS(person.Parents),
+			})
+		}
+
+		for _, parent := range person.Parents {
+			storedParent, ok := personsByID[parent.ID]
+			if !ok {
+				report.Issues = append(report.Issues, IntegrityIssue{Type: DanglingParentIDIssue, PersonID: person.ID, RelatedPersonIDS: []string{parent.ID}, Fixable: true})
+				continue
+			}
+
+			if !containsPersonID(storedParent.Children, person.ID) {
+				report.Issues = append(report.Issues, IntegrityIssue{Type: AsymmetricParentLinkIssue, PersonID: person.ID, RelatedPersonIDS: []string{parent.ID}, Fixable: true})
+			}
+		}
+
+		for _, children := range person.Children {
+			storedChildren, ok := personsByID[children.ID]
+			if !ok {
+				report.Issues = append(report.Issues, IntegrityIssue{Type: DanglingChildIDIssue, PersonID: person.ID, RelatedPersonIDS: []string{children.ID}, Fixable: true})
+				continue
+			}
+
+			if !containsPersonID(storedChildren.Parents, person.ID) {
+				fixable := parentsAfterRepair[children.ID] < 2
+				if fixable {
+					parentsAfterRepair[children.ID]++
+				}
+
+				report.Issues = append(report.Issues, IntegrityIssue{Type: AsymmetricChildLinkIssue, PersonID: person.ID, RelatedPersonIDS: []string{children.ID}, Fixable: fixable})
+			}
+		}
+	}
+
+	for _, cycle := range findAncestryCycles(sortedPersons, personsByID) {
+		report.Issues = append(report.Issues, IntegrityIssue{Type: AncestryCycleIssue, PersonID: cycle[0], RelatedPersonIDS: cycle})
+	}
+
+	return report
+}
+
+func personIDS(persons []*Person) []string {
+	var ids []string
+	for _, person := range persons {
+		ids = append(ids, person.ID)
+	}
+
+	return ids
+}
+
+const (
+	notVisited = iota
+	visiting
+	visited
+)
+
+// findAncestryCycles walks parent links with a DFS and returns every cycle found, each one listed once.
+func findAncestryCycles(persons []Person, personsByID map[string]Person) [][]string {
+	var cycles [][]string
+	state := make(map[string]int, len(persons))
+	var path []string
+
+	var visit func(personID string)
+	visit = func(personID string) {
+		state[personID] = visiting
+		path = append(path, personID)
+
+		for _, parent := range personsByID[personID].Parents {
+			if _, ok := personsByID[parent.ID]; !ok {
+				continue
+			}
+
+			switch state[parent.ID] {
+			case notVisited:
+				visit(parent.ID)
+			case visiting:
+				for i := len(path) - 1; i >= 0; i-- {
+					if path[i] == parent.ID {
+						cycle := make([]string, len(path)-i)
+						copy(cycle, path[i:])
+						cycles = append(cycles, cycle)
+						break
+					}
+				}
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[personID] = visited
+	}
+
+	for _, person := range persons {
+		if state[person.ID] == notVisited {
+			visit(person.ID)
+		}
+	}
+
+	return cycles
+}
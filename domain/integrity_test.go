@@ -0,0 +1,191 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckIntegrity(t *testing.T) {
+	type testArgs struct {
+		testName       string
+		persons        []Person
+		expectedIssues []IntegrityIssue
+	}
+
+	stub := func(id string) *Person {
+		return &Person{ID: id}
+	}
+
+	tests := []testArgs{
+		{
+			testName: "should not find issues on symmetric links",
+			persons: []Person{
+				{ID: "IDLuis", Children: []*Person{stub("IDCaio")}},
+				{ID: "IDDayse", Children: []*Person{stub("IDCaio")}},
+				{ID: "IDCaio", Parents: []*Person{stub("IDLuis"), stub("IDDayse")}},
+			},
+		},
+		{
+			testName: "should find asymmetric links on both sides",
+			persons: []Person{
+				{ID: "IDLuis"},
+				{ID: "IDDayse", Children: []*Person{stub("IDCaio")}},
+				{ID: "IDCaio", Parents: []*Person{stub("IDLuis")}},
+			},
+			expectedIssues: []IntegrityIssue{
+				{Type: AsymmetricParentLinkIssue, PersonID: "IDCaio", RelatedPersonIDS: []string{"IDLuis"}, Fixable: true},
+				{Type: AsymmetricChildLinkIssue, PersonID: "IDDayse", RelatedPersonIDS: []string{"IDCaio"}, Fixable: true},
+			},
+		},
+		{
+			testName: "should not allow repairing a child into having more than 2 parents",
+			persons: []Person{
+				{ID: "IDA", Children: []*Person{stub("IDCaio")}},
+				{ID: "IDB", Children: []*Person{stub("IDCaio")}},
+				{ID: "IDC", Children: []*Person{stub("IDCaio")}},
+				{ID: "IDCaio", Parents: []*Person{stub("IDA")}},
+			},
+			expectedIssues: []IntegrityIssue{
+				{Type: AsymmetricChildLinkIssue, PersonID: "IDB", RelatedPersonIDS: []string{"IDCaio"}, Fixable: true},
+				{Type: AsymmetricChildLinkIssue, PersonID: "IDC", RelatedPersonIDS: []string{"IDCaio"}, Fixable: false},
+			},
+		},
+		{
+			testName: "should find dangling ids",
+			persons: []Person{
+				{ID: "IDCaio", Parents: []*Person{stub("IDUnexisting")}, Children: []*Person{stub("IDUnexistingChild")}},
+			},
+			expectedIssues: []IntegrityIssue{
+				{Type: DanglingParentIDIssue, PersonID: "IDCaio", RelatedPersonIDS: []string{"IDUnexisting"}, Fixable: true},
+				{Type: DanglingChildIDIssue, PersonID: "IDCaio", RelatedPersonIDS: []string{"IDUnexistingChild"}, Fixable: true},
+			},
+		},
+		{
+			testName: "should find persons with more than 2 parents",
+			persons: []Person{
+				{ID: "IDA", Children: []*Person{stub("IDCaio")}},
+				{ID: "IDB", Children: []*Person{stub("IDCaio")}},
+				{ID: "IDC", Children: []*Person{stub("IDCaio")}},
+				{ID: "IDCaio", Parents: []*Person{stub("IDA"), stub("IDB"), stub("IDC")}},
+			},
+			expectedIssues: []IntegrityIssue{
+				{Type: TooManyParentsIssue, PersonID: "IDCaio", RelatedPersonIDS: []string{"IDA", "IDB", "IDC"}},
+			},
+		},
+		{
+			testName: "should not count parents that are not biological as too many parents",
+			persons: []Person{
+				{ID: "IDA", Children: []*Person{stub("IDCaio")}},
+				{ID: "IDB", Children: []*Person{stub("IDCaio")}},
+				{ID: "IDC", Children: []*Person{stub("IDCaio")}},
+				{ID: "IDCaio", Parents: []*Person{stub("IDA"), stub("IDB"), stub("IDC")}, ParentLinkTypes: map[string]LinkType{"IDC": FosterLinkType}},
+			},
+		},
+		{
+			testName: "should find ancestry cycles",
+			persons: []Person{
+				{ID: "IDA", Parents: []*Person{stub("IDB")}, Children: []*Person{stub("IDB")}},
+				{ID: "IDB", Parents: []*Person{stub("IDA")}, Children: []*Person{stub("IDA")}},
+			},
+			expectedIssues: []IntegrityIssue{
+				{Type: AncestryCycleIssue, PersonID: "IDA", RelatedPersonIDS: []string{"IDA", "IDB"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(tt testArgs) func(t *testing.T) {
+			return func(t *testing.T) {
+				report := CheckIntegrity(tt.persons)
+
+				assert.Equal(t, len(tt.persons), report.PersonsChecked)
+				assert.ElementsMatch(t, tt.expectedIssues, report.Issues)
+			}
+		}(tt))
+	}
+}
+
+func TestRecheckFixableIssues(t *testing.T) {
+	type testArgs struct {
+		testName       string
+		issues         []IntegrityIssue
+		persons        []Person
+		expectedIssues []IntegrityIssue
+	}
+
+	stub := func(id string) *Person {
+		return &Person{ID: id}
+	}
+
+	asymmetricParentLink := IntegrityIssue{Type: AsymmetricParentLinkIssue, PersonID: "IDCaio", RelatedPersonIDS: []string{"IDLuis"}, Fixable: true}
+	asymmetricChildLink := IntegrityIssue{Type: AsymmetricChildLinkIssue, PersonID: "IDDayse", RelatedPersonIDS: []string{"IDCaio"}, Fixable: true}
+	danglingParentID := IntegrityIssue{Type: DanglingParentIDIssue, PersonID: "IDLuis", RelatedPersonIDS: []string{"IDUnexisting"}, Fixable: true}
+
+	tests := []testArgs{
+		{
+			testName: "should keep issues that still hold",
+			issues:   []IntegrityIssue{asymmetricParentLink, asymmetricChildLink, danglingParentID},
+			persons: []Person{
+				{ID: "IDLuis", Parents: []*Person{stub("IDUnexisting")}},
+				{ID: "IDDayse", Children: []*Person{stub("IDCaio")}},
+				{ID: "IDCaio", Parents: []*Person{stub("IDLuis")}},
+			},
+			expectedIssues: []IntegrityIssue{asymmetricParentLink, asymmetricChildLink, danglingParentID},
+		},
+		{
+			testName: "should drop issues already fixed since the check",
+			issues:   []IntegrityIssue{asymmetricParentLink, asymmetricChildLink, danglingParentID},
+			persons: []Person{
+				{ID: "IDLuis", Children: []*Person{stub("IDCaio")}},
+				{ID: "IDDayse", Children: []*Person{stub("IDCaio")}},
+				{ID: "IDCaio", Parents: []*Person{stub("IDLuis"), stub("IDDayse")}},
+			},
+		},
+		{
+			testName: "should drop issues whose link was removed since the check",
+			issues:   []IntegrityIssue{asymmetricParentLink, asymmetricChildLink},
+			persons: []Person{
+				{ID: "IDLuis"},
+				{ID: "IDDayse"},
+				{ID: "IDCaio"},
+			},
+		},
+		{
+			testName: "should drop asymmetric child links when the child gained two parents since the check",
+			issues:   []IntegrityIssue{asymmetricChildLink},
+			persons: []Person{
+				{ID: "IDDayse", Children: []*Person{stub("IDCaio")}},
+				{ID: "IDCaio", Parents: []*Person{stub("IDA"), stub("IDB")}},
+			},
+		},
+		{
+			testName: "should drop dangling ids when the person was deleted since the check",
+			issues:   []IntegrityIssue{danglingParentID},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(tt testArgs) func(t *testing.T) {
+			return func(t *testing.T) {
+				assert.Equal(t, tt.expectedIssues, RecheckFixableIssues(tt.issues, tt.persons))
+			}
+		}(tt))
+	}
+}
+
+func TestIntegrityReportJSON(t *testing.T) {
+	report := IntegrityReport{
+		PersonsChecked: 1,
+		Issues:         []IntegrityIssue{{Type: TooManyParentsIssue, PersonID: "IDCaio"}},
+	}
+
+	reportJSON, err := json.Marshal(report)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"personsChecked":1,"repaired":false,"issues":[{"type":"too_many_parents","personId":"IDCaio","relatedPersonIds":[],"fixable":false}]}`, string(reportJSON))
+
+	reportJSON, err = json.Marshal(IntegrityReport{})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"personsChecked":0,"repaired":false,"issues":[]}`, string(reportJSON))
+}
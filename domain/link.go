@@ -0,0 +1,150 @@
+package domain
+
+import (
+	"fmt"
+
+	"github.com/CaioBittencourt/arvore-genealogica/errors"
+)
+
+// AncestorIDS returns the ids of every ancestor of personID reachable through the parents of the graph members.
+func (fg FamilyGraph) AncestorIDS(personID string) map[string]bool {
+	ancestorIDS := make(map[string]bool)
+
+	person, ok := fg.Members[personID]
+	if !ok {
+		return ancestorIDS
+	}
+
+	toVisit := append([]*Person{}, person.Parents...)
+	for len(toVisit) > 0 {
+		parent := toVisit[0]
+		toVisit = toVisit[1:]
+		if ancestorIDS[parent.ID] {
+			continue
+		}
+
+		ancestorIDS[parent.ID] = true
+		toVisit = append(toVisit, parent.Parents...)
+	}
+
+	return ancestorIDS
+}
+
+// ValidateAncestry refuses to make parent a parent of child when child is parent or one of its ancestors.
+func ValidateAncestry(child Person, parent Person, parentAncestorIDS map[string]bool) error {
+	if child.ID == parent.ID || parentAncestorIDS[child.ID] {
+		return errors.NewApplicationError("person can not be an ancestor of itself", errors.AncestryCycleErrorCode)
+	}
+
+	return nil
+}
+
+func (p Person) HasParent(possibleParent Person) bool {
+	return p.isParent(possibleParent)
+}
+
+// ValidateParentLink checks that parent can become a parent of child with role and linkType, both read with their
+// immediate relatives. It re-runs the Validate checks of parent, refuses links that would make a person its own
+// ancestor, checks role against the gender of parent and the roles of the other parents and, for biological links,
+// that child keeps at most two biological parents. When parent already is a parent of child only its role and link
+// type are checked.
+func ValidateParentLink(child Person, parent Person, role ParentRoleType, linkType LinkType, parentAncestorIDS map[string]bool) error {
+	if !child.HasParent(parent) {
+		if err := ValidateAncestry(child, parent, parentAncestorIDS); err != nil {
+			return err
+		}
+
+		if err := parent.Validate(nil); err != nil {
+			return err
+		}
+	}
+
+	if !role.IsValid() {
+		return errors.NewApplicationError(fmt.Sprintf("parent role %s is not valid", role), errors.InvalidParentRoleErrorCode)
+	}
+
+	if !linkType.IsValid() {
+		return errors.NewApplicationError(fmt.Sprintf("parent link type %s is not valid", linkType), errors.InvalidParentLinkTypeErrorCode)
+	}
+
+	linkedChild := Person{ID: child.ID}
+	for _, currentParent := range child.Parents {
+		if currentParent.ID != parent.ID {
+			linkedChild.AddParent(currentParent, child.ParentRole(currentParent.ID))
+			linkedChild.SetParentLinkType(currentParent.ID, child.ParentLinkType(currentParent.ID))
+		}
+	}
+	linkedChild.AddParent(&parent, role)
+	linkedChild.SetParentLinkType(parent.ID, linkType)
+
+	if linkedChild.biologicalParentsCount() > 2 {
+		return errors.NewApplicationError("children already have two parents", errors.ChildrenAlreadyHasTwoParents)
+	}
+
+	if err := linkedChild.validateParentLinks(); err != nil {
+		return err
+	}
+
+	return linkedChild.ValidateParentGenders([]Person{parent})
+}
+
+// ParseLinkTypes validates the link types asked by a client, an empty list means every link type.
+func ParseLinkTypes(values []string) ([]LinkType, error) {
+	var linkTypes []LinkType
+	for _, value := range values {
+		linkType := LinkType(value)
+		if !linkType.IsValid() {
+			return nil, errors.NewApplicationError(fmt.Sprintf("parent link type %s is not valid", value), errors.InvalidParentLinkTypeErrorCode)
+		}
+		linkTypes = append(linkTypes, linkType)
+	}
+
+	return linkTypes, nil
+}
+
+// WithLinkTypes returns a copy of the graph keeping only the parent and children links of linkTypes, so relationships
+// and bacons numbers can be found through biological links only. Spouses are kept while they still have a child in
+// common. Without linkTypes the graph itself is returned.
+func (fg FamilyGraph) WithLinkTypes(linkTypes []LinkType) FamilyGraph {
+	if len(linkTypes) == 0 {
+		return fg
+	}
+
+	linkTypeAllowed := make(map[LinkType]bool, len(linkTypes))
+	for _, linkType := range linkTypes {
+		linkTypeAllowed[linkType] = true
+	}
+
+	members := make(map[string]*Person, len(fg.Members))
+	for personID, member := range fg.Members {
+		filteredMember := *member
+		filteredMember.Parents, filteredMember.Children, filteredMember.Spouses = nil, nil, nil
+		members[personID] = &filteredMember
+	}
+
+	for personID, member := range fg.Members {
+		filteredMember := members[personID]
+		for _, parent := range member.Parents {
+			if filteredParent, ok := members[parent.ID]; ok && linkTypeAllowed[member.ParentLinkType(parent.ID)] {
+				filteredMember.Parents = append(filteredMember.Parents, filteredParent)
+			}
+		}
+
+		for _, children := range member.Children {
+			if filteredChildren, ok := members[children.ID]; ok && linkTypeAllowed[filteredChildren.ParentLinkType(personID)] {
+				filteredMember.Children = append(filteredMember.Children, filteredChildren)
+			}
+		}
+	}
+
+	for personID, member := range fg.Members {
+		filteredMember := members[personID]
+		for _, spouse := range member.Spouses {
+			if filteredSpouse, ok := members[spouse.ID]; ok && filteredMember.isSpouse(*filteredSpouse) {
+				filteredMember.Spouses = append(filteredMember.Spouses, filteredSpouse)
+			}
+		}
+	}
+
+	return FamilyGraph{Members: members}
+}
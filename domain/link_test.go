@@ -0,0 +1,194 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/CaioBittencourt/arvore-genealogica/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAncestorIDS(t *testing.T) {
+	familyGraph := buildFamilyGraph()
+
+	assert.Equal(t, map[string]bool{"IDVivian": true, "IDDayse": true, "IDLuis": true, "IDZézé": true}, familyGraph.AncestorIDS("IDCauã"))
+	assert.Equal(t, map[string]bool{}, familyGraph.AncestorIDS("IDZézé"))
+	assert.Equal(t, map[string]bool{}, familyGraph.AncestorIDS("unexistingID"))
+}
+
+func TestValidateAncestry(t *testing.T) {
+	caio, luis := Person{ID: "IDCaio"}, Person{ID: "IDLuis"}
+
+	assert.NoError(t, ValidateAncestry(caio, luis, map[string]bool{"IDZézé": true}))
+	assert.NoError(t, ValidateAncestry(caio, luis, nil))
+
+	assert.True(t, errors.ErrorHasCode(ValidateAncestry(caio, luis, map[string]bool{"IDCaio": true}), errors.AncestryCycleErrorCode))
+	assert.True(t, errors.ErrorHasCode(ValidateAncestry(caio, caio, nil), errors.AncestryCycleErrorCode))
+}
+
+func TestValidateParentLink(t *testing.T) {
+	type testArgs struct {
+		testName          string
+		child             Person
+		parent            Person
+		role              ParentRoleType
+		linkType          LinkType
+		parentAncestorIDS map[string]bool
+		expectedErrorCode errors.ApplicationErrorCode
+	}
+
+	luis := &Person{ID: "IDLuis", Name: "Luis", Gender: Male}
+	dayse := &Person{ID: "IDDayse", Name: "Dayse", Gender: Female}
+	zeze := &Person{ID: "IDZézé", Name: "Zézé", Gender: Female}
+
+	tests := []testArgs{
+		{
+			testName: "should allow a mother when the child has a father",
+			child:    Person{ID: "IDCaio", Name: "Caio", Gender: Male, Parents: []*Person{luis}, ParentRoles: map[string]ParentRoleType{"IDLuis": FatherParentRole}},
+			parent:   *dayse,
+			role:     MotherParentRole,
+		},
+		{
+			testName: "should allow the first parent",
+			child:    Person{ID: "IDCaio", Name: "Caio", Gender: Male},
+			parent:   *luis,
+			role:     GenericParentRole,
+		},
+		{
+			testName: "should allow two generic parents with the same gender",
+			child:    Person{ID: "IDCaio", Name: "Caio", Gender: Male, Parents: []*Person{dayse}},
+			parent:   *zeze,
+			role:     GenericParentRole,
+		},
+		{
+			testName: "should allow changing the role of a current parent",
+			child:    Person{ID: "IDCaio", Name: "Caio", Gender: Male, Parents: []*Person{luis, dayse}},
+			parent:   *dayse,
+			role:     MotherParentRole,
+		},
+		{
+			testName:          "should not allow a child with two parents",
+			child:             Person{ID: "IDCaio", Name: "Caio", Gender: Male, Parents: []*Person{luis, dayse}},
+			parent:            *zeze,
+			role:              GenericParentRole,
+			expectedErrorCode: errors.ChildrenAlreadyHasTwoParents,
+		},
+		{
+			testName: "should allow an adoptive parent for a child with two parents",
+			child:    Person{ID: "IDCaio", Name: "Caio", Gender: Male, Parents: []*Person{luis, dayse}},
+			parent:   *zeze,
+			role:     GenericParentRole,
+			linkType: AdoptiveLinkType,
+		},
+		{
+			testName: "should allow an adoptive mother next to the biological mother",
+			child:    Person{ID: "IDCaio", Name: "Caio", Gender: Male, Parents: []*Person{dayse}, ParentRoles: map[string]ParentRoleType{"IDDayse": MotherParentRole}},
+			parent:   *zeze,
+			role:     MotherParentRole,
+			linkType: AdoptiveLinkType,
+		},
+		{
+			testName: "should not allow a foster parent to become biological when the child has two biological parents",
+			child: Person{ID: "IDCaio", Name: "Caio", Gender: Male, Parents: []*Person{luis, dayse, zeze},
+				ParentLinkTypes: map[string]LinkType{"IDZézé": FosterLinkType}},
+			parent:            *zeze,
+			role:              GenericParentRole,
+			linkType:          BiologicalLinkType,
+			expectedErrorCode: errors.ChildrenAlreadyHasTwoParents,
+		},
+		{
+			testName:          "should not allow an unknown link type",
+			child:             Person{ID: "IDCaio", Name: "Caio", Gender: Male},
+			parent:            *zeze,
+			role:              GenericParentRole,
+			linkType:          "godparent",
+			expectedErrorCode: errors.InvalidParentLinkTypeErrorCode,
+		},
+		{
+			testName:          "should not allow two mothers",
+			child:             Person{ID: "IDCaio", Name: "Caio", Gender: Male, Parents: []*Person{dayse}, ParentRoles: map[string]ParentRoleType{"IDDayse": MotherParentRole}},
+			parent:            *zeze,
+			role:              MotherParentRole,
+			expectedErrorCode: errors.ParentRoleConflictErrorCode,
+		},
+		{
+			testName:          "should not allow a father that is not male",
+			child:             Person{ID: "IDCaio", Name: "Caio", Gender: Male},
+			parent:            *zeze,
+			role:              FatherParentRole,
+			expectedErrorCode: errors.InvalidParentRoleErrorCode,
+		},
+		{
+			testName:          "should not allow an unknown role",
+			child:             Person{ID: "IDCaio", Name: "Caio", Gender: Male},
+			parent:            *zeze,
+			role:              "grandmother",
+			expectedErrorCode: errors.InvalidParentRoleErrorCode,
+		},
+		{
+			testName:          "should not allow a person to be its own parent",
+			child:             *luis,
+			parent:            *luis,
+			role:              GenericParentRole,
+			expectedErrorCode: errors.AncestryCycleErrorCode,
+		},
+		{
+			testName:          "should not allow an ancestor of the parent to become its descendant",
+			child:             *zeze,
+			parent:            Person{ID: "IDCaio", Name: "Caio", Gender: Male, Parents: []*Person{luis}},
+			role:              GenericParentRole,
+			parentAncestorIDS: map[string]bool{"IDLuis": true, "IDZézé": true},
+			expectedErrorCode: errors.AncestryCycleErrorCode,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(tt testArgs) func(t *testing.T) {
+			return func(t *testing.T) {
+				linkType := tt.linkType
+				if linkType == "" {
+					linkType = BiologicalLinkType
+				}
+
+				err := ValidateParentLink(tt.child, tt.parent, tt.role, linkType, tt.parentAncestorIDS)
+				if tt.expectedErrorCode == "" {
+					assert.NoError(t, err)
+					return
+				}
+
+				assert.True(t, errors.ErrorHasCode(err, tt.expectedErrorCode), "unexpected error: %v", err)
+			}
+		}(tt))
+	}
+}
+
+func TestParseLinkTypes(t *testing.T) {
+	linkTypes, err := ParseLinkTypes([]string{"biological", "donor"})
+	assert.NoError(t, err)
+	assert.Equal(t, []LinkType{BiologicalLinkType, DonorLinkType}, linkTypes)
+
+	_, err = ParseLinkTypes([]string{"biological", "godparent"})
+	assert.True(t, errors.ErrorHasCode(err, errors.InvalidParentLinkTypeErrorCode), "unexpected error: %v", err)
+}
+
+func TestWithLinkTypes(t *testing.T) {
+	familyGraph := buildFamilyGraph()
+	familyGraph.Members["IDVivian"].SetParentLinkType("IDLuis", AdoptiveLinkType)
+	familyGraph.Members["IDCaio"].SetParentLinkType("IDLuis", AdoptiveLinkType)
+
+	assert.Same(t, familyGraph.Members["IDLuis"], familyGraph.WithLinkTypes(nil).Members["IDLuis"])
+
+	biologicalGraph := familyGraph.WithLinkTypes([]LinkType{BiologicalLinkType})
+	assert.Empty(t, biologicalGraph.Members["IDLuis"].Children)
+	assert.Empty(t, biologicalGraph.Members["IDLuis"].Spouses)
+	assert.Empty(t, biologicalGraph.Members["IDDayse"].Spouses)
+	assert.Equal(t, []*Person{biologicalGraph.Members["IDDayse"]}, biologicalGraph.Members["IDCaio"].Parents)
+	assert.Len(t, familyGraph.Members["IDLuis"].Children, 2, "the original graph must not change")
+
+	assert.Nil(t, familyGraph.BaconsNumber("IDCaio", "IDZézé", BaconsNumberOptions{LinkTypes: []LinkType{BiologicalLinkType}}))
+	assert.Equal(t, uint(2), *familyGraph.BaconsNumber("IDCaio", "IDZézé", BaconsNumberOptions{}))
+
+	assert.Empty(t, biologicalGraph.FindRelationshipBetweenPersons("IDCaio", "IDLuis").Relationships)
+	relationship := familyGraph.FindRelationshipBetweenPersons("IDCaio", "IDLuis").Relationships["IDLuis"]
+	assert.Equal(t, ParentRelashionship, relationship.Relationship)
+	assert.Equal(t, AdoptiveLinkType, relationship.LinkType)
+}
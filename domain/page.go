@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"container/heap"
+	"sort"
+)
+
+// MemberCursor is the position of a member in the order of SortedMembers. Pages start right after it, so members
+// added or removed before it do not shift the next page.
+type MemberCursor struct {
+	Generation int
+	Name       string
+	ID         string
+}
+
+func memberCursorOf(person Person) MemberCursor {
+	return MemberCursor{Generation: person.Generation, Name: person.Name, ID: person.ID}
+}
+
+func (c MemberCursor) comesBefore(other MemberCursor) bool {
+	if c.Generation != other.Generation {
+		return c.Generation > other.Generation
+	}
+
+	if c.Name != other.Name {
+		return c.Name < other.Name
+	}
+
+	return c.ID < other.ID
+}
+
+// SortedMembers orders the members from the oldest generation to the youngest, then by name and id.
+func (fg FamilyGraph) SortedMembers() []*Person {
+	members := make([]*Person, 0, len(fg.Members))
+	for _, member := range fg.Members {
+		members = append(members, member)
+	}
+
+	sort.Slice(members, func(i, j int) bool {
+		return memberCursorOf(*members[i]).comesBefore(memberCursorOf(*members[j]))
+	})
+
+	return members
+}
+
+// lastMembers is a heap of members whose root is the last one in the order of SortedMembers.
+type lastMembers []*Person
+
+func (lm lastMembers) Len() int { return len(lm) }
+func (lm lastMembers) Less(i, j int) bool {
+	return memberCursorOf(*lm[j]).comesBefore(memberCursorOf(*lm[i]))
+}
+func (lm lastMembers) Swap(i, j int)       { lm[i], lm[j] = lm[j], lm[i] }
+func (lm *lastMembers) Push(x interface{}) { *lm = append(*lm, x.(*Person)) }
+func (lm *lastMembers) Pop() interface{} {
+	old := *lm
+	member := old[len(old)-1]
+	*lm = old[:len(old)-1]
+	return member
+}
+
+// MembersPage returns up to limit members of SortedMembers after the cursor, nil for the first page, along with the
+// cursor of the next page, which is nil on the last one. Only the members of the page, and the one after it, are
+// kept sorted, so a page of a large graph does not sort every member again.
+func (fg FamilyGraph) MembersPage(after *MemberCursor, limit int) ([]*Person, *MemberCursor) {
+	capacity := limit
+	if len(fg.Members) < capacity {
+		capacity = len(fg.Members)
+	}
+
+	page := make(lastMembers, 0, capacity+1)
+	for _, member := range fg.Members {
+		position := memberCursorOf(*member)
+		if after != nil && !after.comesBefore(position) {
+			continue
+		}
+
+		if len(page) <= limit {
+			heap.Push(&page, member)
+		} else if position.comesBefore(memberCursorOf(*page[0])) {
+			page[0] = member
+			heap.Fix(&page, 0)
+		}
+	}
+
+	members := []*Person(page)
+	sort.Slice(members, func(i, j int) bool {
+		return memberCursorOf(*members[i]).comesBefore(memberCursorOf(*members[j]))
+	})
+
+	if len(members) <= limit {
+		return members, nil
+	}
+
+	next := memberCursorOf(*members[limit-1])
+	return members[:limit], &next
+}
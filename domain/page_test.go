@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func memberIDS(members []*Person) []string {
+	var ids []string
+	for _, member := range members {
+		ids = append(ids, member.ID)
+	}
+
+	return ids
+}
+
+func TestSortedMembers(t *testing.T) {
+	familyGraph := buildFamilyGraph()
+
+	assert.Equal(t,
+		[]string{"IDZézé", "IDClaudia", "IDDayse", "IDLuis", "IDCaio", "IDLivia", "IDVivian", "IDCauã"},
+		memberIDS(familyGraph.SortedMembers()),
+	)
+}
+
+func TestMembersPage(t *testing.T) {
+	type testArgs struct {
+		testName           string
+		after              *MemberCursor
+		limit              int
+		expectedMemberIDS  []string
+		expectedNextCursor *MemberCursor
+	}
+
+	familyGraph := buildFamilyGraph()
+	tests := []testArgs{
+		{
+			testName:           "should return the first page with the cursor of its last member",
+			limit:              3,
+			expectedMemberIDS:  []string{"IDZézé", "IDClaudia", "IDDayse"},
+			expectedNextCursor: &MemberCursor{Generation: 1, Name: "Dayse", ID: "IDDayse"},
+		},
+		{
+			testName:           "should return the members after the cursor",
+			after:              &MemberCursor{Generation: 1, Name: "Dayse", ID: "IDDayse"},
+			limit:              3,
+			expectedMemberIDS:  []string{"IDLuis", "IDCaio", "IDLivia"},
+			expectedNextCursor: &MemberCursor{Generation: 0, Name: "Livia", ID: "IDLivia"},
+		},
+		{
+			testName:          "should return the last page without a cursor",
+			after:             &MemberCursor{Generation: 0, Name: "Livia", ID: "IDLivia"},
+			limit:             3,
+			expectedMemberIDS: []string{"IDVivian", "IDCauã"},
+		},
+		{
+			testName:          "should not allocate more than the members for a huge limit",
+			after:             &MemberCursor{Generation: 0, Name: "Livia", ID: "IDLivia"},
+			limit:             4294967295,
+			expectedMemberIDS: []string{"IDVivian", "IDCauã"},
+		},
+		{
+			testName:          "should start after the position of a cursor whose member is gone",
+			after:             &MemberCursor{Generation: 0, Name: "Caua", ID: "IDRemoved"},
+			limit:             10,
+			expectedMemberIDS: []string{"IDLivia", "IDVivian", "IDCauã"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(tt testArgs) func(t *testing.T) {
+			return func(t *testing.T) {
+				members, nextCursor := familyGraph.MembersPage(tt.after, tt.limit)
+				assert.Equal(t, tt.expectedMemberIDS, memberIDS(members))
+				assert.Equal(t, tt.expectedNextCursor, nextCursor)
+			}
+		}(tt))
+	}
+}
+
+func TestMembersPageWalksSortedMembers(t *testing.T) {
+	familyGraph := buildFamilyGraph()
+	sortedMemberIDS := memberIDS(familyGraph.SortedMembers())
+
+	for limit := 1; limit <= len(sortedMemberIDS)+1; limit++ {
+		var walkedMemberIDS []string
+		var after *MemberCursor
+		for {
+			members, nextCursor := familyGraph.MembersPage(after, limit)
+			assert.LessOrEqual(t, len(members), limit)
+			walkedMemberIDS = append(walkedMemberIDS, memberIDS(members)...)
+			if nextCursor == nil {
+				break
+			}
+			after = nextCursor
+		}
+
+		assert.Equal(t, sortedMemberIDS, walkedMemberIDS, "limit %d", limit)
+	}
+}
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"container/list"
+	"fmt"
 	"math"
 
 	"github.com/CaioBittencourt/arvore-genealogica/errors"
@@ -35,16 +36,89 @@ func (s GenderType) IsValid() bool {
 	return false
 }
 
+// ParentRoleType tells whether a parent is the mother, the father or a parent without a gendered role, which allows
+// two parents of the same gender.
+type ParentRoleType string
+
+const (
+	MotherParentRole  ParentRoleType = "mother"
+	FatherParentRole  ParentRoleType = "father"
+	GenericParentRole ParentRoleType = "parent"
+)
+
+func (r ParentRoleType) IsValid() bool {
+	switch r {
+	case MotherParentRole, FatherParentRole, GenericParentRole:
+		return true
+	}
+
+	return false
+}
+
+// Gender returns the gender a parent with the role must have, empty for generic parents.
+func (r ParentRoleType) Gender() GenderType {
+	switch r {
+	case MotherParentRole:
+		return Female
+	case FatherParentRole:
+		return Male
+	}
+
+	return ""
+}
+
+// LinkType tells how a parent is related to a child. Only biological links count for the two parents limit, so a
+// person may also have adoptive, foster or step parents, donors or a surrogate.
+type LinkType string
+
+const (
+	BiologicalLinkType LinkType = "biological"
+	AdoptiveLinkType   LinkType = "adoptive"
+	FosterLinkType     LinkType = "foster"
+	StepLinkType       LinkType = "step"
+	DonorLinkType      LinkType = "donor"
+	SurrogateLinkType  LinkType = "surrogate"
+)
+
+func (t LinkType) IsValid() bool {
+	switch t {
+	case BiologicalLinkType, AdoptiveLinkType, FosterLinkType, StepLinkType, DonorLinkType, SurrogateLinkType:
+		return true
+	}
+
+	return false
+}
+
+// LifeEvent keeps the date as it was informed, since genealogy dates are often partial or approximate (ABT 1900).
+type LifeEvent struct {
+	Date  string
+	Place string
+}
+
 type RelationshipPerson struct {
 	ID     string
 	Name   string
 	Gender GenderType
 }
 
+// ParentRole is only set for the mother and the father of the person and LinkType only for parents that are not
+// biological.
 type Relationship struct {
 	Person       RelationshipPerson
 	Relationship RelationshipType
+	ParentRole   ParentRoleType
+	LinkType     LinkType
 }
+
+// Label is the parent role of mothers and fathers and the relationship type of everyone else.
+func (r Relationship) Label() string {
+	if r.Relationship == ParentRelashionship && r.ParentRole != "" {
+		return string(r.ParentRole)
+	}
+
+	return string(r.Relationship)
+}
+
 type FamilyGraph struct {
 	Members map[string]*Person
 }
@@ -52,14 +126,25 @@ type Person struct {
 	ID       string
 	Name     string
 	Gender   GenderType
+	Birth    *LifeEvent
+	Death    *LifeEvent
 	Parents  []*Person
 	Children []*Person
+	// ParentRoles holds the role of each parent by its id, parents without a role are generic parents
+	ParentRoles map[string]ParentRoleType
+	// ParentLinkTypes holds the link type of each parent by its id, parents without a link type are biological
+	ParentLinkTypes map[string]LinkType
 
 	Spouses       []*Person
 	Generation    int
 	Relationships map[string]Relationship
+	Version       int64
 }
 
+// UnversionedPersonVersion is the Version a relative is expected to be on when it was stored before versions existed
+// and has none. Relatives with a Version of 0 are updated without checking it.
+const UnversionedPersonVersion int64 = -1
+
 func buildRelationshipWithPerson(person Person, relationshipType RelationshipType) Relationship {
 	return Relationship{
 		Person: RelationshipPerson{
@@ -72,7 +157,7 @@ func buildRelationshipWithPerson(person Person, relationshipType RelationshipTyp
 }
 
 func (p Person) Validate(childrens []Person) error {
-	if len(p.Parents) > 2 {
+	if p.biologicalParentsCount() > 2 {
 		return errors.NewApplicationError("not allowed to have more than 2 parents", errors.TooManyParentsForPersonErrorCode)
 	}
 
@@ -84,8 +169,12 @@ func (p Person) Validate(childrens []Person) error {
 		return errors.NewApplicationError("gender has to be male of female", errors.InvalidPersonGenderErrorCode)
 	}
 
+	if err := p.validateParentLinks(); err != nil {
+		return err
+	}
+
 	for _, children := range childrens {
-		if len(children.Parents) > 1 {
+		if children.biologicalParentsCount() > 1 {
 			return errors.NewApplicationError("children already have two parents", errors.ChildrenAlreadyHasTwoParents)
 		}
 	}
@@ -93,6 +182,108 @@ func (p Person) Validate(childrens []Person) error {
 	return nil
 }
 
+// ParentRole returns GenericParentRole for parents stored without a role.
+func (p Person) ParentRole(parentID string) ParentRoleType {
+	if role, ok := p.ParentRoles[parentID]; ok {
+		return role
+	}
+
+	return GenericParentRole
+}
+
+// AddParent appends parent to the parents, keeping role for it unless it is the generic one.
+func (p *Person) AddParent(parent *Person, role ParentRoleType) {
+	p.Parents = append(p.Parents, parent)
+	if role == "" || role == GenericParentRole {
+		return
+	}
+
+	if p.ParentRoles == nil {
+		p.ParentRoles = make(map[string]ParentRoleType)
+	}
+	p.ParentRoles[parent.ID] = role
+}
+
+// ParentLinkType returns BiologicalLinkType for parents stored without a link type.
+func (p Person) ParentLinkType(parentID string) LinkType {
+	if linkType, ok := p.ParentLinkTypes[parentID]; ok {
+		return linkType
+	}
+
+	return BiologicalLinkType
+}
+
+// SetParentLinkType keeps linkType for parentID unless it is the biological one.
+func (p *Person) SetParentLinkType(parentID string, linkType LinkType) {
+	if linkType == "" || linkType == BiologicalLinkType {
+		delete(p.ParentLinkTypes, parentID)
+		return
+	}
+
+	if p.ParentLinkTypes == nil {
+		p.ParentLinkTypes = make(map[string]LinkType)
+	}
+	p.ParentLinkTypes[parentID] = linkType
+}
+
+func (p Person) biologicalParentsCount() int {
+	count := 0
+	for _, parent := range p.Parents {
+		if p.ParentLinkType(parent.ID) == BiologicalLinkType {
+			count++
+		}
+	}
+
+	return count
+}
+
+type parentRoleByLinkType struct {
+	linkType LinkType
+	role     ParentRoleType
+}
+
+// validateParentLinks allows a single mother and a single father for each link type, so an adopted person keeps
+// its biological mother next to the adoptive one.
+func (p Person) validateParentLinks() error {
+	parentIDByRole := make(map[parentRoleByLinkType]string)
+	for _, parent := range p.Parents {
+		linkType := p.ParentLinkType(parent.ID)
+		if !linkType.IsValid() {
+			return errors.NewApplicationError(fmt.Sprintf("parent link type %s is not valid", linkType), errors.InvalidParentLinkTypeErrorCode)
+		}
+
+		role := p.ParentRole(parent.ID)
+		if !role.IsValid() {
+			return errors.NewApplicationError(fmt.Sprintf("parent role %s is not valid", role), errors.InvalidParentRoleErrorCode)
+		}
+
+		if role == GenericParentRole {
+			continue
+		}
+
+		key := parentRoleByLinkType{linkType: linkType, role: role}
+		if parentID, ok := parentIDByRole[key]; ok && parentID != parent.ID {
+			return errors.NewApplicationError(fmt.Sprintf("person already has a %s %s", linkType, role), errors.ParentRoleConflictErrorCode)
+		}
+		parentIDByRole[key] = parent.ID
+	}
+
+	return nil
+}
+
+// ValidateParentGenders checks that mothers are female and fathers are male. parents are the stored parents that
+// were read, parents that were not read are not checked.
+func (p Person) ValidateParentGenders(parents []Person) error {
+	for _, parent := range parents {
+		role := p.ParentRole(parent.ID)
+		if expectedGender := role.Gender(); expectedGender != "" && parent.Gender != expectedGender {
+			return errors.NewApplicationError(fmt.Sprintf("%s must be %s", role, expectedGender), errors.InvalidParentRoleErrorCode)
+		}
+	}
+
+	return nil
+}
+
 func (p Person) HasSpouse(possibleSpouse Person) bool {
 	for _, parent := range p.Spouses {
 		if parent.ID == possibleSpouse.ID {
@@ -198,6 +389,8 @@ func (p Person) isUncleOrAunt(possibleUncle Person) bool {
 func (p Person) FindNextGenerationRelationships(personToFindRelationship Person) *Relationship {
 	if p.isParent(personToFindRelationship) {
 		relationship := buildRelationshipWithPerson(personToFindRelationship, ParentRelashionship)
+		relationship.ParentRole = p.ParentRoles[personToFindRelationship.ID]
+		relationship.LinkType = p.ParentLinkTypes[personToFindRelationship.ID]
 		return &relationship
 	}
 
@@ -363,72 +556,3 @@ func (fg *FamilyGraph) PopulateFamilyWithRelationships(personID string) error {
 
 	return nil
 }
-
-type PersonJumps struct {
-	Person         *Person
-	JumpsUntilHere uint
-}
-
-func (fg *FamilyGraph) BaconsNumber(personIDA string, personIDB string) *uint {
-	if personIDA == personIDB {
-		zero := uint(0)
-		return &zero
-	}
-
-	personA, ok := fg.Members[personIDA]
-	if !ok {
-		return nil
-	}
-
-	personB, ok := fg.Members[personIDB]
-	if !ok {
-		return nil
-	}
-
-	currentPerson := &PersonJumps{Person: personA, JumpsUntilHere: 0}
-	visitPersonQueue := list.New()
-	personAlreadyOnQueue := make(map[string]bool)
-	personAlreadyOnQueue[currentPerson.Person.ID] = true
-
-	var minimumJumps *uint
-	// BSF to find the shortest path
-	for currentPerson != nil || visitPersonQueue.Len() > 0 {
-
-		if currentPerson.Person.ID == personB.ID {
-			minimumJumps = &currentPerson.JumpsUntilHere
-			break
-		}
-
-		for _, currentSpouse := range currentPerson.Person.Spouses {
-			if _, ok := personAlreadyOnQueue[currentSpouse.ID]; !ok {
-				visitPersonQueue.PushBack(&PersonJumps{Person: currentSpouse, JumpsUntilHere: currentPerson.JumpsUntilHere + 1})
-				personAlreadyOnQueue[currentSpouse.ID] = true
-			}
-		}
-
-		for _, currentParent := range currentPerson.Person.Parents {
-			if _, ok := personAlreadyOnQueue[currentParent.ID]; !ok {
-				visitPersonQueue.PushBack(&PersonJumps{Person: currentParent, JumpsUntilHere: currentPerson.JumpsUntilHere + 1})
-				personAlreadyOnQueue[currentParent.ID] = true
-			}
-		}
-
-		for _, currentChildren := range currentPerson.Person.Children {
-			if _, ok := personAlreadyOnQueue[currentChildren.ID]; !ok {
-				visitPersonQueue.PushBack(&PersonJumps{Person: currentChildren, JumpsUntilHere: currentPerson.JumpsUntilHere + 1})
-				personAlreadyOnQueue[currentChildren.ID] = true
-			}
-		}
-
-		nextPerson := visitPersonQueue.Front()
-		if nextPerson != nil {
-			visitPersonQueue.Remove(nextPerson)
-			person := nextPerson.Value.(*PersonJumps)
-			currentPerson = person
-		} else {
-			currentPerson = nil
-		}
-	}
-
-	return minimumJumps
-}
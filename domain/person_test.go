@@ -3,6 +3,7 @@ package domain
 import (
 	"testing"
 
+	"github.com/CaioBittencourt/arvore-genealogica/errors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -131,85 +132,6 @@ func TestBuildFamilyRelationships(t *testing.T) {
 	}
 }
 
-func TestBaconsNumber(t *testing.T) {
-	type baconsNumberArgs struct {
-		personIDA string
-		personIDB string
-	}
-
-	type testArgs struct {
-		testName             string
-		familyGraph          FamilyGraph
-		baconsNumberArgs     baconsNumberArgs
-		expectedBaconsNumber *uint
-	}
-
-	familyGraph := buildFamilyGraph()
-	tests := []testArgs{
-		{
-			testName:             "should return nil bacon number if person A is not found on the family graph",
-			familyGraph:          familyGraph,
-			baconsNumberArgs:     baconsNumberArgs{personIDA: "unexistingID", personIDB: "IDLuis"},
-			expectedBaconsNumber: nil,
-		},
-		{
-			testName:             "should return nil bacon number if person B is not found on the family graph",
-			familyGraph:          familyGraph,
-			baconsNumberArgs:     baconsNumberArgs{personIDA: "IDLuis", personIDB: "unexistingID"},
-			expectedBaconsNumber: nil,
-		},
-		{
-			testName:         "should return correct bacons number between uncle / nephew",
-			familyGraph:      familyGraph,
-			baconsNumberArgs: baconsNumberArgs{personIDA: "IDLuis", personIDB: "IDLivia"},
-			expectedBaconsNumber: func() *uint {
-				baconsNumber := uint(3)
-				return &baconsNumber
-			}(),
-		},
-		{
-			testName:         "should return correct bacons number for grandparents",
-			familyGraph:      familyGraph,
-			baconsNumberArgs: baconsNumberArgs{personIDA: "IDCaio", personIDB: "IDZézé"},
-			expectedBaconsNumber: func() *uint {
-				baconsNumber := uint(2)
-				return &baconsNumber
-			}(),
-		},
-		{
-			testName:         "should return correct bacons number for cousins",
-			familyGraph:      familyGraph,
-			baconsNumberArgs: baconsNumberArgs{personIDA: "IDCaio", personIDB: "IDLivia"},
-			expectedBaconsNumber: func() *uint {
-				baconsNumber := uint(4)
-				return &baconsNumber
-			}(),
-		},
-		{
-			testName:         "should return correct bacons number for spouses",
-			familyGraph:      familyGraph,
-			baconsNumberArgs: baconsNumberArgs{personIDA: "IDDayse", personIDB: "IDLuis"},
-			expectedBaconsNumber: func() *uint {
-				baconsNumber := uint(1)
-				return &baconsNumber
-			}(),
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.testName, func(tt testArgs) func(t *testing.T) {
-			return func(t *testing.T) {
-				baconsNumber := tt.familyGraph.BaconsNumber(tt.baconsNumberArgs.personIDA, tt.baconsNumberArgs.personIDB)
-				if tt.expectedBaconsNumber == nil {
-					assert.Equal(t, tt.expectedBaconsNumber, baconsNumber)
-				} else {
-					assert.Equal(t, *tt.expectedBaconsNumber, *baconsNumber)
-				}
-			}
-		}(tt))
-	}
-}
-
 func TestFindRelationshipBetweenPersons(t *testing.T) {
 	type findRelationshipArgs struct {
 		personIDA string
@@ -351,3 +273,98 @@ func TestFindRelationshipBetweenPersons(t *testing.T) {
 		}(tt))
 	}
 }
+
+func TestValidateParentRoles(t *testing.T) {
+	type testArgs struct {
+		testName          string
+		person            Person
+		parents           []Person
+		expectedErrorCode errors.ApplicationErrorCode
+	}
+
+	luis := Person{ID: "IDLuis", Name: "Luis", Gender: Male}
+	dayse := Person{ID: "IDDayse", Name: "Dayse", Gender: Female}
+	claudia := Person{ID: "IDClaudia", Name: "Claudia", Gender: Female}
+
+	withParents := func(roleByParent map[*Person]ParentRoleType) Person {
+		person := Person{Name: "Caio", Gender: Male}
+		for _, parent := range []*Person{&luis, &dayse, &claudia} {
+			if role, ok := roleByParent[parent]; ok {
+				person.AddParent(parent, role)
+			}
+		}
+
+		return person
+	}
+
+	tests := []testArgs{
+		{
+			testName: "should allow a mother and a father",
+			person:   withParents(map[*Person]ParentRoleType{&luis: FatherParentRole, &dayse: MotherParentRole}),
+			parents:  []Person{luis, dayse},
+		},
+		{
+			testName: "should allow two generic parents with the same gender",
+			person:   withParents(map[*Person]ParentRoleType{&dayse: GenericParentRole, &claudia: GenericParentRole}),
+			parents:  []Person{dayse, claudia},
+		},
+		{
+			testName:          "should not allow two mothers",
+			person:            withParents(map[*Person]ParentRoleType{&dayse: MotherParentRole, &claudia: MotherParentRole}),
+			parents:           []Person{dayse, claudia},
+			expectedErrorCode: errors.ParentRoleConflictErrorCode,
+		},
+		{
+			testName: "should allow more than two parents when only two are biological",
+			person: func() Person {
+				person := withParents(map[*Person]ParentRoleType{&luis: FatherParentRole, &dayse: MotherParentRole, &claudia: MotherParentRole})
+				person.SetParentLinkType(claudia.ID, StepLinkType)
+				return person
+			}(),
+			parents: []Person{luis, dayse, claudia},
+		},
+		{
+			testName:          "should not allow more than two biological parents",
+			person:            withParents(map[*Person]ParentRoleType{&luis: GenericParentRole, &dayse: GenericParentRole, &claudia: GenericParentRole}),
+			parents:           []Person{luis, dayse, claudia},
+			expectedErrorCode: errors.TooManyParentsForPersonErrorCode,
+		},
+		{
+			testName:          "should not allow a mother that is not female",
+			person:            withParents(map[*Person]ParentRoleType{&luis: MotherParentRole}),
+			parents:           []Person{luis},
+			expectedErrorCode: errors.InvalidParentRoleErrorCode,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(tt testArgs) func(t *testing.T) {
+			return func(t *testing.T) {
+				err := tt.person.Validate(nil)
+				if err == nil {
+					err = tt.person.ValidateParentGenders(tt.parents)
+				}
+
+				if tt.expectedErrorCode == "" {
+					assert.NoError(t, err)
+					return
+				}
+
+				assert.True(t, errors.ErrorHasCode(err, tt.expectedErrorCode), "unexpected error: %v", err)
+			}
+		}(tt))
+	}
+}
+
+func TestParentRelationshipLabel(t *testing.T) {
+	luis := &Person{ID: "IDLuis", Name: "Luis", Gender: Male}
+	dayse := &Person{ID: "IDDayse", Name: "Dayse", Gender: Female}
+	caio := Person{ID: "IDCaio", Name: "Caio", Gender: Male}
+	caio.AddParent(luis, FatherParentRole)
+	caio.AddParent(dayse, GenericParentRole)
+
+	assert.Equal(t, FatherParentRole, caio.ParentRole("IDLuis"))
+	assert.Equal(t, GenericParentRole, caio.ParentRole("IDDayse"))
+	assert.Equal(t, "father", caio.FindNextGenerationRelationships(*luis).Label())
+	assert.Equal(t, "parent", caio.FindNextGenerationRelationships(*dayse).Label())
+}
@@ -0,0 +1,19 @@
+package domain
+
+// FamilyGraphScope chooses which relatives of a person are read into its family graph.
+type FamilyGraphScope struct {
+	// AncestorGenerations of parents, grandparents and so on, nil for all of them
+	AncestorGenerations *uint
+	// DescendantGenerations of children, grandchildren and so on
+	DescendantGenerations uint
+	// CollateralGenerations is how many generations of ancestors have their children and grandchildren read, 1 for
+	// siblings and nephews, 2 for uncles and cousins as well
+	CollateralGenerations uint
+	// ExcludeSpouses leaves out the other parents of the children of the person
+	ExcludeSpouses bool
+}
+
+// DefaultFamilyGraphScope reads every ancestor, the children, siblings, nephews, uncles, cousins and spouses.
+func DefaultFamilyGraphScope() FamilyGraphScope {
+	return FamilyGraphScope{DescendantGenerations: 1, CollateralGenerations: 2}
+}
@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Tree is an isolated family workspace. Every person belongs to a single tree and relatives are only searched
+// inside it. Persons stored without a tree belong to the default tree.
+type Tree struct {
+	ID        string
+	Name      string
+	Owner     string
+	CreatedAt time.Time
+}
+
+type treeIDContextKey struct{}
+
+func ContextWithTreeID(ctx context.Context, treeID string) context.Context {
+	return context.WithValue(ctx, treeIDContextKey{}, treeID)
+}
+
+// TreeIDFromContext returns an empty string for the default tree.
+func TreeIDFromContext(ctx context.Context) string {
+	treeID, _ := ctx.Value(treeIDContextKey{}).(string)
+	return treeID
+}
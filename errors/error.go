@@ -9,6 +9,23 @@ const (
 	TooManyParentsForPersonErrorCode ApplicationErrorCode = "TOO_MANY_PARENTS_FOR_PERSON"
 	InvalidPersonGenderErrorCode     ApplicationErrorCode = "INVALID_PERSON_GENDER"
 	ChildrenAlreadyHasTwoParents     ApplicationErrorCode = "CHILDREN_ALREADY_HAVE_TWO_PARENTS"
+	InvalidPersonIDErrorCode         ApplicationErrorCode = "INVALID_PERSON_ID"
+	InvalidBatchErrorCode            ApplicationErrorCode = "INVALID_BATCH"
+	AncestryCycleErrorCode           ApplicationErrorCode = "ANCESTRY_CYCLE"
+	InvalidQueryParameterErrorCode   ApplicationErrorCode = "INVALID_QUERY_PARAMETER"
+	PersonVersionMismatchErrorCode   ApplicationErrorCode = "PERSON_VERSION_MISMATCH"
+	PersonVersionRequiredErrorCode   ApplicationErrorCode = "PERSON_VERSION_REQUIRED"
+	TreeNotFoundErrorCode            ApplicationErrorCode = "TREE_NOT_FOUND"
+	InvalidTreeNameErrorCode         ApplicationErrorCode = "INVALID_TREE_NAME"
+	InvalidGedcomErrorCode           ApplicationErrorCode = "INVALID_GEDCOM"
+	InvalidCSVErrorCode              ApplicationErrorCode = "INVALID_CSV"
+	InvalidBackupErrorCode           ApplicationErrorCode = "INVALID_BACKUP"
+	DatabaseNotEmptyErrorCode        ApplicationErrorCode = "DATABASE_NOT_EMPTY"
+	ParentRoleConflictErrorCode      ApplicationErrorCode = "PARENT_ROLE_CONFLICT"
+	InvalidParentRoleErrorCode       ApplicationErrorCode = "INVALID_PARENT_ROLE"
+	InvalidParentLinkTypeErrorCode   ApplicationErrorCode = "INVALID_PARENT_LINK_TYPE"
+	SearchLimitReachedErrorCode      ApplicationErrorCode = "SEARCH_LIMIT_REACHED"
+	InvalidHeaderErrorCode           ApplicationErrorCode = "INVALID_HEADER"
 )
 
 type ApplicationError struct {
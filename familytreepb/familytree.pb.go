@@ -0,0 +1,1957 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.30.0
+// 	protoc        (unknown)
+// source: familytree.proto
+
+package familytreepb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Gender int32
+
+const (
+	Gender_GENDER_UNSPECIFIED Gender = 0
+	Gender_GENDER_MALE        Gender = 1
+	Gender_GENDER_FEMALE      Gender = 2
+)
+
+// Enum value maps for Gender.
+var (
+	Gender_name = map[int32]string{
+		0: "GENDER_UNSPECIFIED",
+		1: "GENDER_MALE",
+		2: "GENDER_FEMALE",
+	}
+	Gender_value = map[string]int32{
+		"GENDER_UNSPECIFIED": 0,
+		"GENDER_MALE":        1,
+		"GENDER_FEMALE":      2,
+	}
+)
+
+func (x Gender) Enum() *Gender {
+	p := new(Gender)
+	*p = x
+	return p
+}
+
+func (x Gender) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Gender) Descriptor() protoreflect.EnumDescriptor {
+	return file_familytree_proto_enumTypes[0].Descriptor()
+}
+
+func (Gender) Type() protoreflect.EnumType {
+	return &file_familytree_proto_enumTypes[0]
+}
+
+func (x Gender) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Gender.Descriptor instead.
+func (Gender) EnumDescriptor() ([]byte, []int) {
+	return file_familytree_proto_rawDescGZIP(), []int{0}
+}
+
+type RelationshipType int32
+
+const (
+	RelationshipType_RELATIONSHIP_TYPE_UNSPECIFIED RelationshipType = 0
+	RelationshipType_RELATIONSHIP_TYPE_PARENT      RelationshipType = 1
+	RelationshipType_RELATIONSHIP_TYPE_CHILD       RelationshipType = 2
+	RelationshipType_RELATIONSHIP_TYPE_SIBLING     RelationshipType = 3
+	RelationshipType_RELATIONSHIP_TYPE_NEPHEW      RelationshipType = 4
+	RelationshipType_RELATIONSHIP_TYPE_COUSIN      RelationshipType = 5
+	RelationshipType_RELATIONSHIP_TYPE_SPOUSE      RelationshipType = 6
+	RelationshipType_RELATIONSHIP_TYPE_AUNT_UNCLE  RelationshipType = 7
+)
+
+// Enum value maps for RelationshipType.
+var (
+	RelationshipType_name = map[int32]string{
+		0: "RELATIONSHIP_TYPE_UNSPECIFIED",
+		1: "RELATIONSHIP_TYPE_PARENT",
+		2: "RELATIONSHIP_TYPE_CHILD",
+		3: "RELATIONSHIP_TYPE_SIBLING",
+		4: "RELATIONSHIP_TYPE_NEPHEW",
+		5: "RELATIONSHIP_TYPE_COUSIN",
+		6: "RELATIONSHIP_TYPE_SPOUSE",
+		7: "RELATIONSHIP_TYPE_AUNT_UNCLE",
+	}
+	RelationshipType_value = map[string]int32{
+		"RELATIONSHIP_TYPE_UNSPECIFIED": 0,
+		"RELATIONSHIP_TYPE_PARENT":      1,
+		"RELATIONSHIP_TYPE_CHILD":       2,
+		"RELATIONSHIP_TYPE_SIBLING":     3,
+		"RELATIONSHIP_TYPE_NEPHEW":      4,
+		"RELATIONSHIP_TYPE_COUSIN":      5,
+		"RELATIONSHIP_TYPE_SPOUSE":      6,
+		"RELATIONSHIP_TYPE_AUNT_UNCLE":  7,
+	}
+)
+
+func (x RelationshipType) Enum() *RelationshipType {
+	p := new(RelationshipType)
+	*p = x
+	return p
+}
+
+func (x RelationshipType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RelationshipType) Descriptor() protoreflect.EnumDescriptor {
+	return file_familytree_proto_enumTypes[1].Descriptor()
+}
+
+func (RelationshipType) Type() protoreflect.EnumType {
+	return &file_familytree_proto_enumTypes[1]
+}
+
+func (x RelationshipType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RelationshipType.Descriptor instead.
+func (RelationshipType) EnumDescriptor() ([]byte, []int) {
+	return file_familytree_proto_rawDescGZIP(), []int{1}
+}
+
+// PARENT_ROLE_PARENT is a parent without the mother or father role, which allows two parents of the same gender.
+type ParentRole int32
+
+const (
+	ParentRole_PARENT_ROLE_UNSPECIFIED ParentRole = 0
+	ParentRole_PARENT_ROLE_MOTHER      ParentRole = 1
+	ParentRole_PARENT_ROLE_FATHER      ParentRole = 2
+	ParentRole_PARENT_ROLE_PARENT      ParentRole = 3
+)
+
+// Enum value maps for ParentRole.
+var (
+	ParentRole_name = map[int32]string{
+		0: "PARENT_ROLE_UNSPECIFIED",
+		1: "PARENT_ROLE_MOTHER",
+		2: "PARENT_ROLE_FATHER",
+		3: "PARENT_ROLE_PARENT",
+	}
+	ParentRole_value = map[string]int32{
+		"PARENT_ROLE_UNSPECIFIED": 0,
+		"PARENT_ROLE_MOTHER":      1,
+		"PARENT_ROLE_FATHER":      2,
+		"PARENT_ROLE_PARENT":      3,
+	}
+)
+
+func (x ParentRole) Enum() *ParentRole {
+	p := new(ParentRole)
+	*p = x
+	return p
+}
+
+func (x ParentRole) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ParentRole) Descriptor() protoreflect.EnumDescriptor {
+	return file_familytree_proto_enumTypes[2].Descriptor()
+}
+
+func (ParentRole) Type() protoreflect.EnumType {
+	return &file_familytree_proto_enumTypes[2]
+}
+
+func (x ParentRole) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ParentRole.Descriptor instead.
+func (ParentRole) EnumDescriptor() ([]byte, []int) {
+	return file_familytree_proto_rawDescGZIP(), []int{2}
+}
+
+type LinkType int32
+
+const (
+	LinkType_LINK_TYPE_UNSPECIFIED LinkType = 0
+	LinkType_LINK_TYPE_BIOLOGICAL  LinkType = 1
+	LinkType_LINK_TYPE_ADOPTIVE    LinkType = 2
+	LinkType_LINK_TYPE_FOSTER      LinkType = 3
+	LinkType_LINK_TYPE_STEP        LinkType = 4
+	LinkType_LINK_TYPE_DONOR       LinkType = 5
+	LinkType_LINK_TYPE_SURROGATE   LinkType = 6
+)
+
+// Enum value maps for LinkType.
+var (
+	LinkType_name = map[int32]string{
+		0: "LINK_TYPE_UNSPECIFIED",
+		1: "LINK_TYPE_BIOLOGICAL",
+		2: "LINK_TYPE_ADOPTIVE",
+		3: "LINK_TYPE_FOSTER",
+		4: "LINK_TYPE_STEP",
+		5: "LINK_TYPE_DONOR",
+		6: "LINK_TYPE_SURROGATE",
+	}
+	LinkType_value = map[string]int32{
+		"LINK_TYPE_UNSPECIFIED": 0,
+		"LINK_TYPE_BIOLOGICAL":  1,
+		"LINK_TYPE_ADOPTIVE":    2,
+		"LINK_TYPE_FOSTER":      3,
+		"LINK_TYPE_STEP":        4,
+		"LINK_TYPE_DONOR":       5,
+		"LINK_TYPE_SURROGATE":   6,
+	}
+)
+
+func (x LinkType) Enum() *LinkType {
+	p := new(LinkType)
+	*p = x
+	return p
+}
+
+func (x LinkType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (LinkType) Descriptor() protoreflect.EnumDescriptor {
+	return file_familytree_proto_enumTypes[3].Descriptor()
+}
+
+func (LinkType) Type() protoreflect.EnumType {
+	return &file_familytree_proto_enumTypes[3]
+}
+
+func (x LinkType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use LinkType.Descriptor instead.
+func (LinkType) EnumDescriptor() ([]byte, []int) {
+	return file_familytree_proto_rawDescGZIP(), []int{3}
+}
+
+type PathDirection int32
+
+const (
+	// Jumps to spouses, parents and children.
+	PathDirection_PATH_DIRECTION_UNSPECIFIED PathDirection = 0
+	PathDirection_PATH_DIRECTION_ANCESTORS   PathDirection = 1
+	PathDirection_PATH_DIRECTION_DESCENDANTS PathDirection = 2
+)
+
+// Enum value maps for PathDirection.
+var (
+	PathDirection_name = map[int32]string{
+		0: "PATH_DIRECTION_UNSPECIFIED",
+		1: "PATH_DIRECTION_ANCESTORS",
+		2: "PATH_DIRECTION_DESCENDANTS",
+	}
+	PathDirection_value = map[string]int32{
+		"PATH_DIRECTION_UNSPECIFIED": 0,
+		"PATH_DIRECTION_ANCESTORS":   1,
+		"PATH_DIRECTION_DESCENDANTS": 2,
+	}
+)
+
+func (x PathDirection) Enum() *PathDirection {
+	p := new(PathDirection)
+	*p = x
+	return p
+}
+
+func (x PathDirection) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PathDirection) Descriptor() protoreflect.EnumDescriptor {
+	return file_familytree_proto_enumTypes[4].Descriptor()
+}
+
+func (PathDirection) Type() protoreflect.EnumType {
+	return &file_familytree_proto_enumTypes[4]
+}
+
+func (x PathDirection) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PathDirection.Descriptor instead.
+func (PathDirection) EnumDescriptor() ([]byte, []int) {
+	return file_familytree_proto_rawDescGZIP(), []int{4}
+}
+
+type LifeEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Date  string `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"`
+	Place string `protobuf:"bytes,2,opt,name=place,proto3" json:"place,omitempty"`
+}
+
+func (x *LifeEvent) Reset() {
+	*x = LifeEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_familytree_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LifeEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LifeEvent) ProtoMessage() {}
+
+func (x *LifeEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_familytree_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LifeEvent.ProtoReflect.Descriptor instead.
+func (*LifeEvent) Descriptor() ([]byte, []int) {
+	return file_familytree_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *LifeEvent) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *LifeEvent) GetPlace() string {
+	if x != nil {
+		return x.Place
+	}
+	return ""
+}
+
+type PersonReference struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id     string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name   string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Gender Gender `protobuf:"varint,3,opt,name=gender,proto3,enum=familytree.v1.Gender" json:"gender,omitempty"`
+}
+
+func (x *PersonReference) Reset() {
+	*x = PersonReference{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_familytree_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PersonReference) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PersonReference) ProtoMessage() {}
+
+func (x *PersonReference) ProtoReflect() protoreflect.Message {
+	mi := &file_familytree_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PersonReference.ProtoReflect.Descriptor instead.
+func (*PersonReference) Descriptor() ([]byte, []int) {
+	return file_familytree_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PersonReference) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PersonReference) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PersonReference) GetGender() Gender {
+	if x != nil {
+		return x.Gender
+	}
+	return Gender_GENDER_UNSPECIFIED
+}
+
+type Person struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id     string     `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name   string     `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Gender Gender     `protobuf:"varint,3,opt,name=gender,proto3,enum=familytree.v1.Gender" json:"gender,omitempty"`
+	Birth  *LifeEvent `protobuf:"bytes,4,opt,name=birth,proto3" json:"birth,omitempty"`
+	Death  *LifeEvent `protobuf:"bytes,5,opt,name=death,proto3" json:"death,omitempty"`
+	// Sent back on RelativeReference when linking a new person to this one.
+	Version  int64              `protobuf:"varint,6,opt,name=version,proto3" json:"version,omitempty"`
+	Parents  []*PersonReference `protobuf:"bytes,7,rep,name=parents,proto3" json:"parents,omitempty"`
+	Children []*PersonReference `protobuf:"bytes,8,rep,name=children,proto3" json:"children,omitempty"`
+	// Unset when no parent has the mother or father role.
+	Mother *PersonReference `protobuf:"bytes,9,opt,name=mother,proto3" json:"mother,omitempty"`
+	Father *PersonReference `protobuf:"bytes,10,opt,name=father,proto3" json:"father,omitempty"`
+}
+
+func (x *Person) Reset() {
+	*x = Person{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_familytree_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Person) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Person) ProtoMessage() {}
+
+func (x *Person) ProtoReflect() protoreflect.Message {
+	mi := &file_familytree_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Person.ProtoReflect.Descriptor instead.
+func (*Person) Descriptor() ([]byte, []int) {
+	return file_familytree_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Person) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Person) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Person) GetGender() Gender {
+	if x != nil {
+		return x.Gender
+	}
+	return Gender_GENDER_UNSPECIFIED
+}
+
+func (x *Person) GetBirth() *LifeEvent {
+	if x != nil {
+		return x.Birth
+	}
+	return nil
+}
+
+func (x *Person) GetDeath() *LifeEvent {
+	if x != nil {
+		return x.Death
+	}
+	return nil
+}
+
+func (x *Person) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Person) GetParents() []*PersonReference {
+	if x != nil {
+		return x.Parents
+	}
+	return nil
+}
+
+func (x *Person) GetChildren() []*PersonReference {
+	if x != nil {
+		return x.Children
+	}
+	return nil
+}
+
+func (x *Person) GetMother() *PersonReference {
+	if x != nil {
+		return x.Mother
+	}
+	return nil
+}
+
+func (x *Person) GetFather() *PersonReference {
+	if x != nil {
+		return x.Father
+	}
+	return nil
+}
+
+type Relationship struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Person       *PersonReference `protobuf:"bytes,1,opt,name=person,proto3" json:"person,omitempty"`
+	Relationship RelationshipType `protobuf:"varint,2,opt,name=relationship,proto3,enum=familytree.v1.RelationshipType" json:"relationship,omitempty"`
+	// Only set for parents with the mother or father role.
+	ParentRole ParentRole `protobuf:"varint,3,opt,name=parent_role,json=parentRole,proto3,enum=familytree.v1.ParentRole" json:"parent_role,omitempty"`
+	// Only set for parents that are not biological.
+	LinkType LinkType `protobuf:"varint,4,opt,name=link_type,json=linkType,proto3,enum=familytree.v1.LinkType" json:"link_type,omitempty"`
+}
+
+func (x *Relationship) Reset() {
+	*x = Relationship{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_familytree_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Relationship) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Relationship) ProtoMessage() {}
+
+func (x *Relationship) ProtoReflect() protoreflect.Message {
+	mi := &file_familytree_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Relationship.ProtoReflect.Descriptor instead.
+func (*Relationship) Descriptor() ([]byte, []int) {
+	return file_familytree_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Relationship) GetPerson() *PersonReference {
+	if x != nil {
+		return x.Person
+	}
+	return nil
+}
+
+func (x *Relationship) GetRelationship() RelationshipType {
+	if x != nil {
+		return x.Relationship
+	}
+	return RelationshipType_RELATIONSHIP_TYPE_UNSPECIFIED
+}
+
+func (x *Relationship) GetParentRole() ParentRole {
+	if x != nil {
+		return x.ParentRole
+	}
+	return ParentRole_PARENT_ROLE_UNSPECIFIED
+}
+
+func (x *Relationship) GetLinkType() LinkType {
+	if x != nil {
+		return x.LinkType
+	}
+	return LinkType_LINK_TYPE_UNSPECIFIED
+}
+
+type FamilyGraphMember struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Person        *PersonReference `protobuf:"bytes,1,opt,name=person,proto3" json:"person,omitempty"`
+	Relationships []*Relationship  `protobuf:"bytes,2,rep,name=relationships,proto3" json:"relationships,omitempty"`
+}
+
+func (x *FamilyGraphMember) Reset() {
+	*x = FamilyGraphMember{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_familytree_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FamilyGraphMember) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FamilyGraphMember) ProtoMessage() {}
+
+func (x *FamilyGraphMember) ProtoReflect() protoreflect.Message {
+	mi := &file_familytree_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FamilyGraphMember.ProtoReflect.Descriptor instead.
+func (*FamilyGraphMember) Descriptor() ([]byte, []int) {
+	return file_familytree_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *FamilyGraphMember) GetPerson() *PersonReference {
+	if x != nil {
+		return x.Person
+	}
+	return nil
+}
+
+func (x *FamilyGraphMember) GetRelationships() []*Relationship {
+	if x != nil {
+		return x.Relationships
+	}
+	return nil
+}
+
+// FamilyGraph holds every relative of person_id with its relationships to the other members.
+type FamilyGraph struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PersonId string               `protobuf:"bytes,1,opt,name=person_id,json=personId,proto3" json:"person_id,omitempty"`
+	Members  []*FamilyGraphMember `protobuf:"bytes,2,rep,name=members,proto3" json:"members,omitempty"`
+}
+
+func (x *FamilyGraph) Reset() {
+	*x = FamilyGraph{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_familytree_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FamilyGraph) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FamilyGraph) ProtoMessage() {}
+
+func (x *FamilyGraph) ProtoReflect() protoreflect.Message {
+	mi := &file_familytree_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FamilyGraph.ProtoReflect.Descriptor instead.
+func (*FamilyGraph) Descriptor() ([]byte, []int) {
+	return file_familytree_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *FamilyGraph) GetPersonId() string {
+	if x != nil {
+		return x.PersonId
+	}
+	return ""
+}
+
+func (x *FamilyGraph) GetMembers() []*FamilyGraphMember {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+// RelativeReference is a stored person to link with, on the version it was read.
+type RelativeReference struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Required, 0 for persons stored before versions existed.
+	Version *int64 `protobuf:"varint,2,opt,name=version,proto3,oneof" json:"version,omitempty"`
+}
+
+func (x *RelativeReference) Reset() {
+	*x = RelativeReference{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_familytree_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RelativeReference) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RelativeReference) ProtoMessage() {}
+
+func (x *RelativeReference) ProtoReflect() protoreflect.Message {
+	mi := &file_familytree_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RelativeReference.ProtoReflect.Descriptor instead.
+func (*RelativeReference) Descriptor() ([]byte, []int) {
+	return file_familytree_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RelativeReference) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RelativeReference) GetVersion() int64 {
+	if x != nil && x.Version != nil {
+		return *x.Version
+	}
+	return 0
+}
+
+type StorePersonRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TreeId   string               `protobuf:"bytes,1,opt,name=tree_id,json=treeId,proto3" json:"tree_id,omitempty"`
+	Name     string               `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Gender   Gender               `protobuf:"varint,3,opt,name=gender,proto3,enum=familytree.v1.Gender" json:"gender,omitempty"`
+	Birth    *LifeEvent           `protobuf:"bytes,4,opt,name=birth,proto3" json:"birth,omitempty"`
+	Death    *LifeEvent           `protobuf:"bytes,5,opt,name=death,proto3" json:"death,omitempty"`
+	Mother   *RelativeReference   `protobuf:"bytes,6,opt,name=mother,proto3" json:"mother,omitempty"`
+	Father   *RelativeReference   `protobuf:"bytes,7,opt,name=father,proto3" json:"father,omitempty"`
+	Children []*RelativeReference `protobuf:"bytes,8,rep,name=children,proto3" json:"children,omitempty"`
+	// Parents without the mother or father role.
+	Parents []*RelativeReference `protobuf:"bytes,9,rep,name=parents,proto3" json:"parents,omitempty"`
+}
+
+func (x *StorePersonRequest) Reset() {
+	*x = StorePersonRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_familytree_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StorePersonRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StorePersonRequest) ProtoMessage() {}
+
+func (x *StorePersonRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_familytree_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StorePersonRequest.ProtoReflect.Descriptor instead.
+func (*StorePersonRequest) Descriptor() ([]byte, []int) {
+	return file_familytree_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *StorePersonRequest) GetTreeId() string {
+	if x != nil {
+		return x.TreeId
+	}
+	return ""
+}
+
+func (x *StorePersonRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *StorePersonRequest) GetGender() Gender {
+	if x != nil {
+		return x.Gender
+	}
+	return Gender_GENDER_UNSPECIFIED
+}
+
+func (x *StorePersonRequest) GetBirth() *LifeEvent {
+	if x != nil {
+		return x.Birth
+	}
+	return nil
+}
+
+func (x *StorePersonRequest) GetDeath() *LifeEvent {
+	if x != nil {
+		return x.Death
+	}
+	return nil
+}
+
+func (x *StorePersonRequest) GetMother() *RelativeReference {
+	if x != nil {
+		return x.Mother
+	}
+	return nil
+}
+
+func (x *StorePersonRequest) GetFather() *RelativeReference {
+	if x != nil {
+		return x.Father
+	}
+	return nil
+}
+
+func (x *StorePersonRequest) GetChildren() []*RelativeReference {
+	if x != nil {
+		return x.Children
+	}
+	return nil
+}
+
+func (x *StorePersonRequest) GetParents() []*RelativeReference {
+	if x != nil {
+		return x.Parents
+	}
+	return nil
+}
+
+type GetFamilyTreeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TreeId   string `protobuf:"bytes,1,opt,name=tree_id,json=treeId,proto3" json:"tree_id,omitempty"`
+	PersonId string `protobuf:"bytes,2,opt,name=person_id,json=personId,proto3" json:"person_id,omitempty"`
+	// Generations of parents, grandparents and so on, all of them when unset.
+	AncestorGenerations *uint32 `protobuf:"varint,3,opt,name=ancestor_generations,json=ancestorGenerations,proto3,oneof" json:"ancestor_generations,omitempty"`
+	// Generations of children, grandchildren and so on, 1 when unset.
+	DescendantGenerations *uint32 `protobuf:"varint,4,opt,name=descendant_generations,json=descendantGenerations,proto3,oneof" json:"descendant_generations,omitempty"`
+	// Generations of ancestors whose children and grandchildren are read, 2 when unset.
+	CollateralGenerations *uint32 `protobuf:"varint,5,opt,name=collateral_generations,json=collateralGenerations,proto3,oneof" json:"collateral_generations,omitempty"`
+	// Leaves out the other parents of the children of the person.
+	ExcludeSpouses bool `protobuf:"varint,6,opt,name=exclude_spouses,json=excludeSpouses,proto3" json:"exclude_spouses,omitempty"`
+}
+
+func (x *GetFamilyTreeRequest) Reset() {
+	*x = GetFamilyTreeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_familytree_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetFamilyTreeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFamilyTreeRequest) ProtoMessage() {}
+
+func (x *GetFamilyTreeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_familytree_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFamilyTreeRequest.ProtoReflect.Descriptor instead.
+func (*GetFamilyTreeRequest) Descriptor() ([]byte, []int) {
+	return file_familytree_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetFamilyTreeRequest) GetTreeId() string {
+	if x != nil {
+		return x.TreeId
+	}
+	return ""
+}
+
+func (x *GetFamilyTreeRequest) GetPersonId() string {
+	if x != nil {
+		return x.PersonId
+	}
+	return ""
+}
+
+func (x *GetFamilyTreeRequest) GetAncestorGenerations() uint32 {
+	if x != nil && x.AncestorGenerations != nil {
+		return *x.AncestorGenerations
+	}
+	return 0
+}
+
+func (x *GetFamilyTreeRequest) GetDescendantGenerations() uint32 {
+	if x != nil && x.DescendantGenerations != nil {
+		return *x.DescendantGenerations
+	}
+	return 0
+}
+
+func (x *GetFamilyTreeRequest) GetCollateralGenerations() uint32 {
+	if x != nil && x.CollateralGenerations != nil {
+		return *x.CollateralGenerations
+	}
+	return 0
+}
+
+func (x *GetFamilyTreeRequest) GetExcludeSpouses() bool {
+	if x != nil {
+		return x.ExcludeSpouses
+	}
+	return false
+}
+
+type GetRelationshipRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TreeId          string `protobuf:"bytes,1,opt,name=tree_id,json=treeId,proto3" json:"tree_id,omitempty"`
+	PersonId        string `protobuf:"bytes,2,opt,name=person_id,json=personId,proto3" json:"person_id,omitempty"`
+	RelatedPersonId string `protobuf:"bytes,3,opt,name=related_person_id,json=relatedPersonId,proto3" json:"related_person_id,omitempty"`
+	// Only parent links of these types are followed, every link type when it is empty.
+	LinkTypes []LinkType `protobuf:"varint,4,rep,packed,name=link_types,json=linkTypes,proto3,enum=familytree.v1.LinkType" json:"link_types,omitempty"`
+}
+
+func (x *GetRelationshipRequest) Reset() {
+	*x = GetRelationshipRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_familytree_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetRelationshipRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRelationshipRequest) ProtoMessage() {}
+
+func (x *GetRelationshipRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_familytree_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRelationshipRequest.ProtoReflect.Descriptor instead.
+func (*GetRelationshipRequest) Descriptor() ([]byte, []int) {
+	return file_familytree_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetRelationshipRequest) GetTreeId() string {
+	if x != nil {
+		return x.TreeId
+	}
+	return ""
+}
+
+func (x *GetRelationshipRequest) GetPersonId() string {
+	if x != nil {
+		return x.PersonId
+	}
+	return ""
+}
+
+func (x *GetRelationshipRequest) GetRelatedPersonId() string {
+	if x != nil {
+		return x.RelatedPersonId
+	}
+	return ""
+}
+
+func (x *GetRelationshipRequest) GetLinkTypes() []LinkType {
+	if x != nil {
+		return x.LinkTypes
+	}
+	return nil
+}
+
+type GetRelationshipResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Person        *PersonReference `protobuf:"bytes,1,opt,name=person,proto3" json:"person,omitempty"`
+	Relationships []*Relationship  `protobuf:"bytes,2,rep,name=relationships,proto3" json:"relationships,omitempty"`
+}
+
+func (x *GetRelationshipResponse) Reset() {
+	*x = GetRelationshipResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_familytree_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetRelationshipResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRelationshipResponse) ProtoMessage() {}
+
+func (x *GetRelationshipResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_familytree_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRelationshipResponse.ProtoReflect.Descriptor instead.
+func (*GetRelationshipResponse) Descriptor() ([]byte, []int) {
+	return file_familytree_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetRelationshipResponse) GetPerson() *PersonReference {
+	if x != nil {
+		return x.Person
+	}
+	return nil
+}
+
+func (x *GetRelationshipResponse) GetRelationships() []*Relationship {
+	if x != nil {
+		return x.Relationships
+	}
+	return nil
+}
+
+type GetBaconsNumberRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TreeId          string `protobuf:"bytes,1,opt,name=tree_id,json=treeId,proto3" json:"tree_id,omitempty"`
+	PersonId        string `protobuf:"bytes,2,opt,name=person_id,json=personId,proto3" json:"person_id,omitempty"`
+	RelatedPersonId string `protobuf:"bytes,3,opt,name=related_person_id,json=relatedPersonId,proto3" json:"related_person_id,omitempty"`
+	// Only parent links of these types are followed, every link type when it is empty.
+	LinkTypes []LinkType `protobuf:"varint,4,rep,packed,name=link_types,json=linkTypes,proto3,enum=familytree.v1.LinkType" json:"link_types,omitempty"`
+	// Never jumps to spouses.
+	ExcludeSpouses bool `protobuf:"varint,5,opt,name=exclude_spouses,json=excludeSpouses,proto3" json:"exclude_spouses,omitempty"`
+	// Only jumps to parents or only to children.
+	Direction PathDirection `protobuf:"varint,6,opt,name=direction,proto3,enum=familytree.v1.PathDirection" json:"direction,omitempty"`
+	// Costs of each kind of jump, 0 counting as 1.
+	SpouseWeight uint32 `protobuf:"varint,7,opt,name=spouse_weight,json=spouseWeight,proto3" json:"spouse_weight,omitempty"`
+	ParentWeight uint32 `protobuf:"varint,8,opt,name=parent_weight,json=parentWeight,proto3" json:"parent_weight,omitempty"`
+	ChildWeight  uint32 `protobuf:"varint,9,opt,name=child_weight,json=childWeight,proto3" json:"child_weight,omitempty"`
+	// Maximum number of jumps, 0 for no limit.
+	MaxDepth uint32 `protobuf:"varint,10,opt,name=max_depth,json=maxDepth,proto3" json:"max_depth,omitempty"`
+}
+
+func (x *GetBaconsNumberRequest) Reset() {
+	*x = GetBaconsNumberRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_familytree_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetBaconsNumberRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBaconsNumberRequest) ProtoMessage() {}
+
+func (x *GetBaconsNumberRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_familytree_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBaconsNumberRequest.ProtoReflect.Descriptor instead.
+func (*GetBaconsNumberRequest) Descriptor() ([]byte, []int) {
+	return file_familytree_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetBaconsNumberRequest) GetTreeId() string {
+	if x != nil {
+		return x.TreeId
+	}
+	return ""
+}
+
+func (x *GetBaconsNumberRequest) GetPersonId() string {
+	if x != nil {
+		return x.PersonId
+	}
+	return ""
+}
+
+func (x *GetBaconsNumberRequest) GetRelatedPersonId() string {
+	if x != nil {
+		return x.RelatedPersonId
+	}
+	return ""
+}
+
+func (x *GetBaconsNumberRequest) GetLinkTypes() []LinkType {
+	if x != nil {
+		return x.LinkTypes
+	}
+	return nil
+}
+
+func (x *GetBaconsNumberRequest) GetExcludeSpouses() bool {
+	if x != nil {
+		return x.ExcludeSpouses
+	}
+	return false
+}
+
+func (x *GetBaconsNumberRequest) GetDirection() PathDirection {
+	if x != nil {
+		return x.Direction
+	}
+	return PathDirection_PATH_DIRECTION_UNSPECIFIED
+}
+
+func (x *GetBaconsNumberRequest) GetSpouseWeight() uint32 {
+	if x != nil {
+		return x.SpouseWeight
+	}
+	return 0
+}
+
+func (x *GetBaconsNumberRequest) GetParentWeight() uint32 {
+	if x != nil {
+		return x.ParentWeight
+	}
+	return 0
+}
+
+func (x *GetBaconsNumberRequest) GetChildWeight() uint32 {
+	if x != nil {
+		return x.ChildWeight
+	}
+	return 0
+}
+
+func (x *GetBaconsNumberRequest) GetMaxDepth() uint32 {
+	if x != nil {
+		return x.MaxDepth
+	}
+	return 0
+}
+
+type GetBaconsNumberResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BaconsNumber uint32 `protobuf:"varint,1,opt,name=bacons_number,json=baconsNumber,proto3" json:"bacons_number,omitempty"`
+}
+
+func (x *GetBaconsNumberResponse) Reset() {
+	*x = GetBaconsNumberResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_familytree_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetBaconsNumberResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBaconsNumberResponse) ProtoMessage() {}
+
+func (x *GetBaconsNumberResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_familytree_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBaconsNumberResponse.ProtoReflect.Descriptor instead.
+func (*GetBaconsNumberResponse) Descriptor() ([]byte, []int) {
+	return file_familytree_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetBaconsNumberResponse) GetBaconsNumber() uint32 {
+	if x != nil {
+		return x.BaconsNumber
+	}
+	return 0
+}
+
+type StreamDescendantsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TreeId   string `protobuf:"bytes,1,opt,name=tree_id,json=treeId,proto3" json:"tree_id,omitempty"`
+	PersonId string `protobuf:"bytes,2,opt,name=person_id,json=personId,proto3" json:"person_id,omitempty"`
+}
+
+func (x *StreamDescendantsRequest) Reset() {
+	*x = StreamDescendantsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_familytree_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StreamDescendantsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamDescendantsRequest) ProtoMessage() {}
+
+func (x *StreamDescendantsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_familytree_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamDescendantsRequest.ProtoReflect.Descriptor instead.
+func (*StreamDescendantsRequest) Descriptor() ([]byte, []int) {
+	return file_familytree_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *StreamDescendantsRequest) GetTreeId() string {
+	if x != nil {
+		return x.TreeId
+	}
+	return ""
+}
+
+func (x *StreamDescendantsRequest) GetPersonId() string {
+	if x != nil {
+		return x.PersonId
+	}
+	return ""
+}
+
+type Descendant struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Person *Person `protobuf:"bytes,1,opt,name=person,proto3" json:"person,omitempty"`
+	// 1 for children, 2 for grandchildren and so on.
+	Generation uint32 `protobuf:"varint,2,opt,name=generation,proto3" json:"generation,omitempty"`
+}
+
+func (x *Descendant) Reset() {
+	*x = Descendant{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_familytree_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Descendant) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Descendant) ProtoMessage() {}
+
+func (x *Descendant) ProtoReflect() protoreflect.Message {
+	mi := &file_familytree_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Descendant.ProtoReflect.Descriptor instead.
+func (*Descendant) Descriptor() ([]byte, []int) {
+	return file_familytree_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Descendant) GetPerson() *Person {
+	if x != nil {
+		return x.Person
+	}
+	return nil
+}
+
+func (x *Descendant) GetGeneration() uint32 {
+	if x != nil {
+		return x.Generation
+	}
+	return 0
+}
+
+var File_familytree_proto protoreflect.FileDescriptor
+
+var file_familytree_proto_rawDesc = []byte{
+	0x0a, 0x10, 0x66, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x74, 0x72, 0x65, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x12, 0x0d, 0x66, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x74, 0x72, 0x65, 0x65, 0x2e, 0x76,
+	0x31, 0x22, 0x35, 0x0a, 0x09, 0x4c, 0x69, 0x66, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x12,
+	0x0a, 0x04, 0x64, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x64, 0x61,
+	0x74, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x22, 0x64, 0x0a, 0x0f, 0x50, 0x65, 0x72, 0x73,
+	0x6f, 0x6e, 0x52, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12,
+	0x2d, 0x0a, 0x06, 0x67, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x15, 0x2e, 0x66, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x74, 0x72, 0x65, 0x65, 0x2e, 0x76, 0x31, 0x2e,
+	0x47, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x52, 0x06, 0x67, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x22, 0xbb,
+	0x03, 0x0a, 0x06, 0x50, 0x65, 0x72, 0x73, 0x6f, 0x6e, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x2d, 0x0a,
+	0x06, 0x67, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e,
+	0x66, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x74, 0x72, 0x65, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65,
+	0x6e, 0x64, 0x65, 0x72, 0x52, 0x06, 0x67, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x12, 0x2e, 0x0a, 0x05,
+	0x62, 0x69, 0x72, 0x74, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x66, 0x61,
+	0x6d, 0x69, 0x6c, 0x79, 0x74, 0x72, 0x65, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x66, 0x65,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x05, 0x62, 0x69, 0x72, 0x74, 0x68, 0x12, 0x2e, 0x0a, 0x05,
+	0x64, 0x65, 0x61, 0x74, 0x68, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x66, 0x61,
+	0x6d, 0x69, 0x6c, 0x79, 0x74, 0x72, 0x65, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x66, 0x65,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x05, 0x64, 0x65, 0x61, 0x74, 0x68, 0x12, 0x18, 0x0a, 0x07,
+	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x76,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x38, 0x0a, 0x07, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74,
+	0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x66, 0x61, 0x6d, 0x69, 0x6c, 0x79,
+	0x74, 0x72, 0x65, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x65, 0x72, 0x73, 0x6f, 0x6e, 0x52, 0x65,
+	0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x52, 0x07, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x73,
+	0x12, 0x3a, 0x0a, 0x08, 0x63, 0x68, 0x69, 0x6c, 0x64, 0x72, 0x65, 0x6e, 0x18, 0x08, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x66, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x74, 0x72, 0x65, 0x65, 0x2e,
+	0x76, 0x31, 0x2e, 0x50, 0x65, 0x72, 0x73, 0x6f, 0x6e, 0x52, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e,
+	0x63, 0x65, 0x52, 0x08, 0x63, 0x68, 0x69, 0x6c, 0x64, 0x72, 0x65, 0x6e, 0x12, 0x36, 0x0a, 0x06,
+	0x6d, 0x6f, 0x74, 0x68, 0x65, 0x72, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x66,
+	0x61, 0x6d, 0x69, 0x6c, 0x79, 0x74, 0x72, 0x65, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x65, 0x72,
+	0x73, 0x6f, 0x6e, 0x52, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x52, 0x06, 0x6d, 0x6f,
+	0x74, 0x68, 0x65, 0x72, 0x12, 0x36, 0x0a, 0x06, 0x66, 0x61, 0x74, 0x68, 0x65, 0x72, 0x18, 0x0a,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x66, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x74, 0x72, 0x65,
+	0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x65, 0x72, 0x73, 0x6f, 0x6e, 0x52, 0x65, 0x66, 0x65, 0x72,
+	0x65, 0x6e, 0x63, 0x65, 0x52, 0x06, 0x66, 0x61, 0x74, 0x68, 0x65, 0x72, 0x22, 0xfd, 0x01, 0x0a,
+	0x0c, 0x52, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x68, 0x69, 0x70, 0x12, 0x36, 0x0a,
+	0x06, 0x70, 0x65, 0x72, 0x73, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e,
+	0x66, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x74, 0x72, 0x65, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x65,
+	0x72, 0x73, 0x6f, 0x6e, 0x52, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x52, 0x06, 0x70,
+	0x65, 0x72, 0x73, 0x6f, 0x6e, 0x12, 0x43, 0x0a, 0x0c, 0x72, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x68, 0x69, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1f, 0x2e, 0x66, 0x61,
+	0x6d, 0x69, 0x6c, 0x79, 0x74, 0x72, 0x65, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x6c, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x68, 0x69, 0x70, 0x54, 0x79, 0x70, 0x65, 0x52, 0x0c, 0x72, 0x65,
+	0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x68, 0x69, 0x70, 0x12, 0x3a, 0x0a, 0x0b, 0x70, 0x61,
+	0x72, 0x65, 0x6e, 0x74, 0x5f, 0x72, 0x6f, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x19, 0x2e, 0x66, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x74, 0x72, 0x65, 0x65, 0x2e, 0x76, 0x31, 0x2e,
+	0x50, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x52, 0x6f, 0x6c, 0x65, 0x52, 0x0a, 0x70, 0x61, 0x72, 0x65,
+	0x6e, 0x74, 0x52, 0x6f, 0x6c, 0x65, 0x12, 0x34, 0x0a, 0x09, 0x6c, 0x69, 0x6e, 0x6b, 0x5f, 0x74,
+	0x79, 0x70, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x66, 0x61, 0x6d, 0x69,
+	0x6c, 0x79, 0x74, 0x72, 0x65, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x6e, 0x6b, 0x54, 0x79,
+	0x70, 0x65, 0x52, 0x08, 0x6c, 0x69, 0x6e, 0x6b, 0x54, 0x79, 0x70, 0x65, 0x22, 0x8e, 0x01, 0x0a,
+	0x11, 0x46, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x47, 0x72, 0x61, 0x70, 0x68, 0x4d, 0x65, 0x6d, 0x62,
+	0x65, 0x72, 0x12, 0x36, 0x0a, 0x06, 0x70, 0x65, 0x72, 0x73, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x66, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x74, 0x72, 0x65, 0x65, 0x2e,
+	0x76, 0x31, 0x2e, 0x50, 0x65, 0x72, 0x73, 0x6f, 0x6e, 0x52, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e,
+	0x63, 0x65, 0x52, 0x06, 0x70, 0x65, 0x72, 0x73, 0x6f, 0x6e, 0x12, 0x41, 0x0a, 0x0d, 0x72, 0x65,
+	0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x68, 0x69, 0x70, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x1b, 0x2e, 0x66, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x74, 0x72, 0x65, 0x65, 0x2e, 0x76,
+	0x31, 0x2e, 0x52, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x68, 0x69, 0x70, 0x52, 0x0d,
+	0x72, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x68, 0x69, 0x70, 0x73, 0x22, 0x66, 0x0a,
+	0x0b, 0x46, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x47, 0x72, 0x61, 0x70, 0x68, 0x12, 0x1b, 0x0a, 0x09,
+	0x70, 0x65, 0x72, 0x73, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x70, 0x65, 0x72, 0x73, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x3a, 0x0a, 0x07, 0x6d, 0x65, 0x6d,
+	0x62, 0x65, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x66, 0x61, 0x6d,
+	0x69, 0x6c, 0x79, 0x74, 0x72, 0x65, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x61, 0x6d, 0x69, 0x6c,
+	0x79, 0x47, 0x72, 0x61, 0x70, 0x68, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x52, 0x07, 0x6d, 0x65,
+	0x6d, 0x62, 0x65, 0x72, 0x73, 0x22, 0x4e, 0x0a, 0x11, 0x52, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x76,
+	0x65, 0x52, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1d, 0x0a, 0x07, 0x76, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x07, 0x76,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x88, 0x01, 0x01, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x76, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0xbe, 0x03, 0x0a, 0x12, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x50,
+	0x65, 0x72, 0x73, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07,
+	0x74, 0x72, 0x65, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x74,
+	0x72, 0x65, 0x65, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x2d, 0x0a, 0x06, 0x67, 0x65, 0x6e,
+	0x64, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x66, 0x61, 0x6d, 0x69,
+	0x6c, 0x79, 0x74, 0x72, 0x65, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x6e, 0x64, 0x65, 0x72,
+	0x52, 0x06, 0x67, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x12, 0x2e, 0x0a, 0x05, 0x62, 0x69, 0x72, 0x74,
+	0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x66, 0x61, 0x6d, 0x69, 0x6c, 0x79,
+	0x74, 0x72, 0x65, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x66, 0x65, 0x45, 0x76, 0x65, 0x6e,
+	0x74, 0x52, 0x05, 0x62, 0x69, 0x72, 0x74, 0x68, 0x12, 0x2e, 0x0a, 0x05, 0x64, 0x65, 0x61, 0x74,
+	0x68, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x66, 0x61, 0x6d, 0x69, 0x6c, 0x79,
+	0x74, 0x72, 0x65, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x66, 0x65, 0x45, 0x76, 0x65, 0x6e,
+	0x74, 0x52, 0x05, 0x64, 0x65, 0x61, 0x74, 0x68, 0x12, 0x38, 0x0a, 0x06, 0x6d, 0x6f, 0x74, 0x68,
+	0x65, 0x72, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x66, 0x61, 0x6d, 0x69, 0x6c,
+	0x79, 0x74, 0x72, 0x65, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x76,
+	0x65, 0x52, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x52, 0x06, 0x6d, 0x6f, 0x74, 0x68,
+	0x65, 0x72, 0x12, 0x38, 0x0a, 0x06, 0x66, 0x61, 0x74, 0x68, 0x65, 0x72, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x20, 0x2e, 0x66, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x74, 0x72, 0x65, 0x65, 0x2e,
+	0x76, 0x31, 0x2e, 0x52, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x76, 0x65, 0x52, 0x65, 0x66, 0x65, 0x72,
+	0x65, 0x6e, 0x63, 0x65, 0x52, 0x06, 0x66, 0x61, 0x74, 0x68, 0x65, 0x72, 0x12, 0x3c, 0x0a, 0x08,
+	0x63, 0x68, 0x69, 0x6c, 0x64, 0x72, 0x65, 0x6e, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x20,
+	0x2e, 0x66, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x74, 0x72, 0x65, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x52,
+	0x65, 0x6c, 0x61, 0x74, 0x69, 0x76, 0x65, 0x52, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65,
+	0x52, 0x08, 0x63, 0x68, 0x69, 0x6c, 0x64, 0x72, 0x65, 0x6e, 0x12, 0x3a, 0x0a, 0x07, 0x70, 0x61,
+	0x72, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x66, 0x61,
+	0x6d, 0x69, 0x6c, 0x79, 0x74, 0x72, 0x65, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x6c, 0x61,
+	0x74, 0x69, 0x76, 0x65, 0x52, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x52, 0x07, 0x70,
+	0x61, 0x72, 0x65, 0x6e, 0x74, 0x73, 0x22, 0xf4, 0x02, 0x0a, 0x14, 0x47, 0x65, 0x74, 0x46, 0x61,
+	0x6d, 0x69, 0x6c, 0x79, 0x54, 0x72, 0x65, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x17, 0x0a, 0x07, 0x74, 0x72, 0x65, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x74, 0x72, 0x65, 0x65, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x65, 0x72, 0x73,
+	0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x65, 0x72,
+	0x73, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x36, 0x0a, 0x14, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x74, 0x6f,
+	0x72, 0x5f, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0d, 0x48, 0x00, 0x52, 0x13, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x47,
+	0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x88, 0x01, 0x01, 0x12, 0x3a, 0x0a,
+	0x16, 0x64, 0x65, 0x73, 0x63, 0x65, 0x6e, 0x64, 0x61, 0x6e, 0x74, 0x5f, 0x67, 0x65, 0x6e, 0x65,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x01, 0x52,
+	0x15, 0x64, 0x65, 0x73, 0x63, 0x65, 0x6e, 0x64, 0x61, 0x6e, 0x74, 0x47, 0x65, 0x6e, 0x65, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x88, 0x01, 0x01, 0x12, 0x3a, 0x0a, 0x16, 0x63, 0x6f, 0x6c,
+	0x6c, 0x61, 0x74, 0x65, 0x72, 0x61, 0x6c, 0x5f, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x02, 0x52, 0x15, 0x63, 0x6f, 0x6c,
+	0x6c, 0x61, 0x74, 0x65, 0x72, 0x61, 0x6c, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x88, 0x01, 0x01, 0x12, 0x27, 0x0a, 0x0f, 0x65, 0x78, 0x63, 0x6c, 0x75, 0x64, 0x65,
+	0x5f, 0x73, 0x70, 0x6f, 0x75, 0x73, 0x65, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e,
+	0x65, 0x78, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x53, 0x70, 0x6f, 0x75, 0x73, 0x65, 0x73, 0x42, 0x17,
+	0x0a, 0x15, 0x5f, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x5f, 0x67, 0x65, 0x6e, 0x65,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x42, 0x19, 0x0a, 0x17, 0x5f, 0x64, 0x65, 0x73, 0x63,
+	0x65, 0x6e, 0x64, 0x61, 0x6e, 0x74, 0x5f, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x42, 0x19, 0x0a, 0x17, 0x5f, 0x63, 0x6f, 0x6c, 0x6c, 0x61, 0x74, 0x65, 0x72, 0x61,
+	0x6c, 0x5f, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0xb2, 0x01,
+	0x0a, 0x16, 0x47, 0x65, 0x74, 0x52, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x68, 0x69,
+	0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x74, 0x72, 0x65, 0x65,
+	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x74, 0x72, 0x65, 0x65, 0x49,
+	0x64, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x65, 0x72, 0x73, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x65, 0x72, 0x73, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x2a,
+	0x0a, 0x11, 0x72, 0x65, 0x6c, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x70, 0x65, 0x72, 0x73, 0x6f, 0x6e,
+	0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x72, 0x65, 0x6c, 0x61, 0x74,
+	0x65, 0x64, 0x50, 0x65, 0x72, 0x73, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x36, 0x0a, 0x0a, 0x6c, 0x69,
+	0x6e, 0x6b, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x17,
+	0x2e, 0x66, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x74, 0x72, 0x65, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4c,
+	0x69, 0x6e, 0x6b, 0x54, 0x79, 0x70, 0x65, 0x52, 0x09, 0x6c, 0x69, 0x6e, 0x6b, 0x54, 0x79, 0x70,
+	0x65, 0x73, 0x22, 0x94, 0x01, 0x0a, 0x17, 0x47, 0x65, 0x74, 0x52, 0x65, 0x6c, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x68, 0x69, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x36,
+	0x0a, 0x06, 0x70, 0x65, 0x72, 0x73, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e,
+	0x2e, 0x66, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x74, 0x72, 0x65, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50,
+	0x65, 0x72, 0x73, 0x6f, 0x6e, 0x52, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x52, 0x06,
+	0x70, 0x65, 0x72, 0x73, 0x6f, 0x6e, 0x12, 0x41, 0x0a, 0x0d, 0x72, 0x65, 0x6c, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x68, 0x69, 0x70, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e,
+	0x66, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x74, 0x72, 0x65, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65,
+	0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x68, 0x69, 0x70, 0x52, 0x0d, 0x72, 0x65, 0x6c, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x68, 0x69, 0x70, 0x73, 0x22, 0xa1, 0x03, 0x0a, 0x16, 0x47, 0x65,
+	0x74, 0x42, 0x61, 0x63, 0x6f, 0x6e, 0x73, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x74, 0x72, 0x65, 0x65, 0x5f, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x74, 0x72, 0x65, 0x65, 0x49, 0x64, 0x12, 0x1b, 0x0a,
+	0x09, 0x70, 0x65, 0x72, 0x73, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x70, 0x65, 0x72, 0x73, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x2a, 0x0a, 0x11, 0x72, 0x65,
+	0x6c, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x70, 0x65, 0x72, 0x73, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x72, 0x65, 0x6c, 0x61, 0x74, 0x65, 0x64, 0x50, 0x65,
+	0x72, 0x73, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x36, 0x0a, 0x0a, 0x6c, 0x69, 0x6e, 0x6b, 0x5f, 0x74,
+	0x79, 0x70, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x66, 0x61, 0x6d,
+	0x69, 0x6c, 0x79, 0x74, 0x72, 0x65, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x6e, 0x6b, 0x54,
+	0x79, 0x70, 0x65, 0x52, 0x09, 0x6c, 0x69, 0x6e, 0x6b, 0x54, 0x79, 0x70, 0x65, 0x73, 0x12, 0x27,
+	0x0a, 0x0f, 0x65, 0x78, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x5f, 0x73, 0x70, 0x6f, 0x75, 0x73, 0x65,
+	0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x65, 0x78, 0x63, 0x6c, 0x75, 0x64, 0x65,
+	0x53, 0x70, 0x6f, 0x75, 0x73, 0x65, 0x73, 0x12, 0x3a, 0x0a, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1c, 0x2e, 0x66, 0x61, 0x6d,
+	0x69, 0x6c, 0x79, 0x74, 0x72, 0x65, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x68, 0x44,
+	0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x70, 0x6f, 0x75, 0x73, 0x65, 0x5f, 0x77, 0x65,
+	0x69, 0x67, 0x68, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x73, 0x70, 0x6f, 0x75,
+	0x73, 0x65, 0x57, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x70, 0x61, 0x72, 0x65,
+	0x6e, 0x74, 0x5f, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x0c, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x57, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x21, 0x0a,
+	0x0c, 0x63, 0x68, 0x69, 0x6c, 0x64, 0x5f, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x0b, 0x63, 0x68, 0x69, 0x6c, 0x64, 0x57, 0x65, 0x69, 0x67, 0x68, 0x74,
+	0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x61, 0x78, 0x5f, 0x64, 0x65, 0x70, 0x74, 0x68, 0x18, 0x0a, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x08, 0x6d, 0x61, 0x78, 0x44, 0x65, 0x70, 0x74, 0x68, 0x22, 0x3e, 0x0a,
+	0x17, 0x47, 0x65, 0x74, 0x42, 0x61, 0x63, 0x6f, 0x6e, 0x73, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x62, 0x61, 0x63, 0x6f,
+	0x6e, 0x73, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x0c, 0x62, 0x61, 0x63, 0x6f, 0x6e, 0x73, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x22, 0x50, 0x0a,
+	0x18, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x44, 0x65, 0x73, 0x63, 0x65, 0x6e, 0x64, 0x61, 0x6e,
+	0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x74, 0x72, 0x65,
+	0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x74, 0x72, 0x65, 0x65,
+	0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x65, 0x72, 0x73, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x65, 0x72, 0x73, 0x6f, 0x6e, 0x49, 0x64, 0x22,
+	0x5b, 0x0a, 0x0a, 0x44, 0x65, 0x73, 0x63, 0x65, 0x6e, 0x64, 0x61, 0x6e, 0x74, 0x12, 0x2d, 0x0a,
+	0x06, 0x70, 0x65, 0x72, 0x73, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e,
+	0x66, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x74, 0x72, 0x65, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x65,
+	0x72, 0x73, 0x6f, 0x6e, 0x52, 0x06, 0x70, 0x65, 0x72, 0x73, 0x6f, 0x6e, 0x12, 0x1e, 0x0a, 0x0a,
+	0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x0a, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2a, 0x44, 0x0a, 0x06,
+	0x47, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x12, 0x16, 0x0a, 0x12, 0x47, 0x45, 0x4e, 0x44, 0x45, 0x52,
+	0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x0f,
+	0x0a, 0x0b, 0x47, 0x45, 0x4e, 0x44, 0x45, 0x52, 0x5f, 0x4d, 0x41, 0x4c, 0x45, 0x10, 0x01, 0x12,
+	0x11, 0x0a, 0x0d, 0x47, 0x45, 0x4e, 0x44, 0x45, 0x52, 0x5f, 0x46, 0x45, 0x4d, 0x41, 0x4c, 0x45,
+	0x10, 0x02, 0x2a, 0x8b, 0x02, 0x0a, 0x10, 0x52, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x68, 0x69, 0x70, 0x54, 0x79, 0x70, 0x65, 0x12, 0x21, 0x0a, 0x1d, 0x52, 0x45, 0x4c, 0x41, 0x54,
+	0x49, 0x4f, 0x4e, 0x53, 0x48, 0x49, 0x50, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x55, 0x4e, 0x53,
+	0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x1c, 0x0a, 0x18, 0x52, 0x45,
+	0x4c, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x53, 0x48, 0x49, 0x50, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f,
+	0x50, 0x41, 0x52, 0x45, 0x4e, 0x54, 0x10, 0x01, 0x12, 0x1b, 0x0a, 0x17, 0x52, 0x45, 0x4c, 0x41,
+	0x54, 0x49, 0x4f, 0x4e, 0x53, 0x48, 0x49, 0x50, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x43, 0x48,
+	0x49, 0x4c, 0x44, 0x10, 0x02, 0x12, 0x1d, 0x0a, 0x19, 0x52, 0x45, 0x4c, 0x41, 0x54, 0x49, 0x4f,
+	0x4e, 0x53, 0x48, 0x49, 0x50, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x53, 0x49, 0x42, 0x4c, 0x49,
+	0x4e, 0x47, 0x10, 0x03, 0x12, 0x1c, 0x0a, 0x18, 0x52, 0x45, 0x4c, 0x41, 0x54, 0x49, 0x4f, 0x4e,
+	0x53, 0x48, 0x49, 0x50, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x4e, 0x45, 0x50, 0x48, 0x45, 0x57,
+	0x10, 0x04, 0x12, 0x1c, 0x0a, 0x18, 0x52, 0x45, 0x4c, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x53, 0x48,
+	0x49, 0x50, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x43, 0x4f, 0x55, 0x53, 0x49, 0x4e, 0x10, 0x05,
+	0x12, 0x1c, 0x0a, 0x18, 0x52, 0x45, 0x4c, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x53, 0x48, 0x49, 0x50,
+	0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x53, 0x50, 0x4f, 0x55, 0x53, 0x45, 0x10, 0x06, 0x12, 0x20,
+	0x0a, 0x1c, 0x52, 0x45, 0x4c, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x53, 0x48, 0x49, 0x50, 0x5f, 0x54,
+	0x59, 0x50, 0x45, 0x5f, 0x41, 0x55, 0x4e, 0x54, 0x5f, 0x55, 0x4e, 0x43, 0x4c, 0x45, 0x10, 0x07,
+	0x2a, 0x71, 0x0a, 0x0a, 0x50, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x52, 0x6f, 0x6c, 0x65, 0x12, 0x1b,
+	0x0a, 0x17, 0x50, 0x41, 0x52, 0x45, 0x4e, 0x54, 0x5f, 0x52, 0x4f, 0x4c, 0x45, 0x5f, 0x55, 0x4e,
+	0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x16, 0x0a, 0x12, 0x50,
+	0x41, 0x52, 0x45, 0x4e, 0x54, 0x5f, 0x52, 0x4f, 0x4c, 0x45, 0x5f, 0x4d, 0x4f, 0x54, 0x48, 0x45,
+	0x52, 0x10, 0x01, 0x12, 0x16, 0x0a, 0x12, 0x50, 0x41, 0x52, 0x45, 0x4e, 0x54, 0x5f, 0x52, 0x4f,
+	0x4c, 0x45, 0x5f, 0x46, 0x41, 0x54, 0x48, 0x45, 0x52, 0x10, 0x02, 0x12, 0x16, 0x0a, 0x12, 0x50,
+	0x41, 0x52, 0x45, 0x4e, 0x54, 0x5f, 0x52, 0x4f, 0x4c, 0x45, 0x5f, 0x50, 0x41, 0x52, 0x45, 0x4e,
+	0x54, 0x10, 0x03, 0x2a, 0xaf, 0x01, 0x0a, 0x08, 0x4c, 0x69, 0x6e, 0x6b, 0x54, 0x79, 0x70, 0x65,
+	0x12, 0x19, 0x0a, 0x15, 0x4c, 0x49, 0x4e, 0x4b, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x55, 0x4e,
+	0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x18, 0x0a, 0x14, 0x4c,
+	0x49, 0x4e, 0x4b, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x42, 0x49, 0x4f, 0x4c, 0x4f, 0x47, 0x49,
+	0x43, 0x41, 0x4c, 0x10, 0x01, 0x12, 0x16, 0x0a, 0x12, 0x4c, 0x49, 0x4e, 0x4b, 0x5f, 0x54, 0x59,
+	0x50, 0x45, 0x5f, 0x41, 0x44, 0x4f, 0x50, 0x54, 0x49, 0x56, 0x45, 0x10, 0x02, 0x12, 0x14, 0x0a,
+	0x10, 0x4c, 0x49, 0x4e, 0x4b, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x46, 0x4f, 0x53, 0x54, 0x45,
+	0x52, 0x10, 0x03, 0x12, 0x12, 0x0a, 0x0e, 0x4c, 0x49, 0x4e, 0x4b, 0x5f, 0x54, 0x59, 0x50, 0x45,
+	0x5f, 0x53, 0x54, 0x45, 0x50, 0x10, 0x04, 0x12, 0x13, 0x0a, 0x0f, 0x4c, 0x49, 0x4e, 0x4b, 0x5f,
+	0x54, 0x59, 0x50, 0x45, 0x5f, 0x44, 0x4f, 0x4e, 0x4f, 0x52, 0x10, 0x05, 0x12, 0x17, 0x0a, 0x13,
+	0x4c, 0x49, 0x4e, 0x4b, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x53, 0x55, 0x52, 0x52, 0x4f, 0x47,
+	0x41, 0x54, 0x45, 0x10, 0x06, 0x2a, 0x6d, 0x0a, 0x0d, 0x50, 0x61, 0x74, 0x68, 0x44, 0x69, 0x72,
+	0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1e, 0x0a, 0x1a, 0x50, 0x41, 0x54, 0x48, 0x5f, 0x44,
+	0x49, 0x52, 0x45, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49,
+	0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x1c, 0x0a, 0x18, 0x50, 0x41, 0x54, 0x48, 0x5f, 0x44,
+	0x49, 0x52, 0x45, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x41, 0x4e, 0x43, 0x45, 0x53, 0x54, 0x4f,
+	0x52, 0x53, 0x10, 0x01, 0x12, 0x1e, 0x0a, 0x1a, 0x50, 0x41, 0x54, 0x48, 0x5f, 0x44, 0x49, 0x52,
+	0x45, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x44, 0x45, 0x53, 0x43, 0x45, 0x4e, 0x44, 0x41, 0x4e,
+	0x54, 0x53, 0x10, 0x02, 0x32, 0xcd, 0x03, 0x0a, 0x11, 0x46, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x54,
+	0x72, 0x65, 0x65, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x47, 0x0a, 0x0b, 0x53, 0x74,
+	0x6f, 0x72, 0x65, 0x50, 0x65, 0x72, 0x73, 0x6f, 0x6e, 0x12, 0x21, 0x2e, 0x66, 0x61, 0x6d, 0x69,
+	0x6c, 0x79, 0x74, 0x72, 0x65, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x50,
+	0x65, 0x72, 0x73, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x66,
+	0x61, 0x6d, 0x69, 0x6c, 0x79, 0x74, 0x72, 0x65, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x65, 0x72,
+	0x73, 0x6f, 0x6e, 0x12, 0x50, 0x0a, 0x0d, 0x47, 0x65, 0x74, 0x46, 0x61, 0x6d, 0x69, 0x6c, 0x79,
+	0x54, 0x72, 0x65, 0x65, 0x12, 0x23, 0x2e, 0x66, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x74, 0x72, 0x65,
+	0x65, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x46, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x54, 0x72,
+	0x65, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x66, 0x61, 0x6d, 0x69,
+	0x6c, 0x79, 0x74, 0x72, 0x65, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x61, 0x6d, 0x69, 0x6c, 0x79,
+	0x47, 0x72, 0x61, 0x70, 0x68, 0x12, 0x60, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x52, 0x65, 0x6c, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x68, 0x69, 0x70, 0x12, 0x25, 0x2e, 0x66, 0x61, 0x6d, 0x69, 0x6c,
+	0x79, 0x74, 0x72, 0x65, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x52, 0x65, 0x6c, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x68, 0x69, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x26, 0x2e, 0x66, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x74, 0x72, 0x65, 0x65, 0x2e, 0x76, 0x31, 0x2e,
+	0x47, 0x65, 0x74, 0x52, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x68, 0x69, 0x70, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x60, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x42, 0x61,
+	0x63, 0x6f, 0x6e, 0x73, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x25, 0x2e, 0x66, 0x61, 0x6d,
+	0x69, 0x6c, 0x79, 0x74, 0x72, 0x65, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x42, 0x61,
+	0x63, 0x6f, 0x6e, 0x73, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x26, 0x2e, 0x66, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x74, 0x72, 0x65, 0x65, 0x2e, 0x76,
+	0x31, 0x2e, 0x47, 0x65, 0x74, 0x42, 0x61, 0x63, 0x6f, 0x6e, 0x73, 0x4e, 0x75, 0x6d, 0x62, 0x65,
+	0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x59, 0x0a, 0x11, 0x53, 0x74, 0x72,
+	0x65, 0x61, 0x6d, 0x44, 0x65, 0x73, 0x63, 0x65, 0x6e, 0x64, 0x61, 0x6e, 0x74, 0x73, 0x12, 0x27,
+	0x2e, 0x66, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x74, 0x72, 0x65, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x53,
+	0x74, 0x72, 0x65, 0x61, 0x6d, 0x44, 0x65, 0x73, 0x63, 0x65, 0x6e, 0x64, 0x61, 0x6e, 0x74, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x66, 0x61, 0x6d, 0x69, 0x6c, 0x79,
+	0x74, 0x72, 0x65, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x73, 0x63, 0x65, 0x6e, 0x64, 0x61,
+	0x6e, 0x74, 0x30, 0x01, 0x42, 0x3c, 0x5a, 0x3a, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63,
+	0x6f, 0x6d, 0x2f, 0x43, 0x61, 0x69, 0x6f, 0x42, 0x69, 0x74, 0x74, 0x65, 0x6e, 0x63, 0x6f, 0x75,
+	0x72, 0x74, 0x2f, 0x61, 0x72, 0x76, 0x6f, 0x72, 0x65, 0x2d, 0x67, 0x65, 0x6e, 0x65, 0x61, 0x6c,
+	0x6f, 0x67, 0x69, 0x63, 0x61, 0x2f, 0x66, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x74, 0x72, 0x65, 0x65,
+	0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_familytree_proto_rawDescOnce sync.Once
+	file_familytree_proto_rawDescData = file_familytree_proto_rawDesc
+)
+
+func file_familytree_proto_rawDescGZIP() []byte {
+	file_familytree_proto_rawDescOnce.Do(func() {
+		file_familytree_proto_rawDescData = protoimpl.X.CompressGZIP(file_familytree_proto_rawDescData)
+	})
+	return file_familytree_proto_rawDescData
+}
+
+var file_familytree_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
+var file_familytree_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_familytree_proto_goTypes = []interface{}{
+	(Gender)(0),                      // 0: familytree.v1.Gender
+	(RelationshipType)(0),            // 1: familytree.v1.RelationshipType
+	(ParentRole)(0),                  // 2: familytree.v1.ParentRole
+	(LinkType)(0),                    // 3: familytree.v1.LinkType
+	(PathDirection)(0),               // 4: familytree.v1.PathDirection
+	(*LifeEvent)(nil),                // 5: familytree.v1.LifeEvent
+	(*PersonReference)(nil),          // 6: familytree.v1.PersonReference
+	(*Person)(nil),                   // 7: familytree.v1.Person
+	(*Relationship)(nil),             // 8: familytree.v1.Relationship
+	(*FamilyGraphMember)(nil),        // 9: familytree.v1.FamilyGraphMember
+	(*FamilyGraph)(nil),              // 10: familytree.v1.FamilyGraph
+	(*RelativeReference)(nil),        // 11: familytree.v1.RelativeReference
+	(*StorePersonRequest)(nil),       // 12: familytree.v1.StorePersonRequest
+	(*GetFamilyTreeRequest)(nil),     // 13: familytree.v1.GetFamilyTreeRequest
+	(*GetRelationshipRequest)(nil),   // 14: familytree.v1.GetRelationshipRequest
+	(*GetRelationshipResponse)(nil),  // 15: familytree.v1.GetRelationshipResponse
+	(*GetBaconsNumberRequest)(nil),   // 16: familytree.v1.GetBaconsNumberRequest
+	(*GetBaconsNumberResponse)(nil),  // 17: familytree.v1.GetBaconsNumberResponse
+	(*StreamDescendantsRequest)(nil), // 18: familytree.v1.StreamDescendantsRequest
+	(*Descendant)(nil),               // 19: familytree.v1.Descendant
+}
+var file_familytree_proto_depIdxs = []int32{
+	0,  // 0: familytree.v1.PersonReference.gender:type_name -> familytree.v1.Gender
+	0,  // 1: familytree.v1.Person.gender:type_name -> familytree.v1.Gender
+	5,  // 2: familytree.v1.Person.birth:type_name -> familytree.v1.LifeEvent
+	5,  // 3: familytree.v1.Person.death:type_name -> familytree.v1.LifeEvent
+	6,  // 4: familytree.v1.Person.parents:type_name -> familytree.v1.PersonReference
+	6,  // 5: familytree.v1.Person.children:type_name -> familytree.v1.PersonReference
+	6,  // 6: familytree.v1.Person.mother:type_name -> familytree.v1.PersonReference
+	6,  // 7: familytree.v1.Person.father:type_name -> familytree.v1.PersonReference
+	6,  // 8: familytree.v1.Relationship.person:type_name -> familytree.v1.PersonReference
+	1,  // 9: familytree.v1.Relationship.relationship:type_name -> familytree.v1.RelationshipType
+	2,  // 10: familytree.v1.Relationship.parent_role:type_name -> familytree.v1.ParentRole
+	3,  // 11: familytree.v1.Relationship.link_type:type_name -> familytree.v1.LinkType
+	6,  // 12: familytree.v1.FamilyGraphMember.person:type_name -> familytree.v1.PersonReference
+	8,  // 13: familytree.v1.FamilyGraphMember.relationships:type_name -> familytree.v1.Relationship
+	9,  // 14: familytree.v1.FamilyGraph.members:type_name -> familytree.v1.FamilyGraphMember
+	0,  // 15: familytree.v1.StorePersonRequest.gender:type_name -> familytree.v1.Gender
+	5,  // 16: familytree.v1.StorePersonRequest.birth:type_name -> familytree.v1.LifeEvent
+	5,  // 17: familytree.v1.StorePersonRequest.death:type_name -> familytree.v1.LifeEvent
+	11, // 18: familytree.v1.StorePersonRequest.mother:type_name -> familytree.v1.RelativeReference
+	11, // 19: familytree.v1.StorePersonRequest.father:type_name -> familytree.v1.RelativeReference
+	11, // 20: familytree.v1.StorePersonRequest.children:type_name -> familytree.v1.RelativeReference
+	11, // 21: familytree.v1.StorePersonRequest.parents:type_name -> familytree.v1.RelativeReference
+	3,  // 22: familytree.v1.GetRelationshipRequest.link_types:type_name -> familytree.v1.LinkType
+	6,  // 23: familytree.v1.GetRelationshipResponse.person:type_name -> familytree.v1.PersonReference
+	8,  // 24: familytree.v1.GetRelationshipResponse.relationships:type_name -> familytree.v1.Relationship
+	3,  // 25: familytree.v1.GetBaconsNumberRequest.link_types:type_name -> familytree.v1.LinkType
+	4,  // 26: familytree.v1.GetBaconsNumberRequest.direction:type_name -> familytree.v1.PathDirection
+	7,  // 27: familytree.v1.Descendant.person:type_name -> familytree.v1.Person
+	12, // 28: familytree.v1.FamilyTreeService.StorePerson:input_type -> familytree.v1.StorePersonRequest
+	13, // 29: familytree.v1.FamilyTreeService.GetFamilyTree:input_type -> familytree.v1.GetFamilyTreeRequest
+	14, // 30: familytree.v1.FamilyTreeService.GetRelationship:input_type -> familytree.v1.GetRelationshipRequest
+	16, // 31: familytree.v1.FamilyTreeService.GetBaconsNumber:input_type -> familytree.v1.GetBaconsNumberRequest
+	18, // 32: familytree.v1.FamilyTreeService.StreamDescendants:input_type -> familytree.v1.StreamDescendantsRequest
+	7,  // 33: familytree.v1.FamilyTreeService.StorePerson:output_type -> familytree.v1.Person
+	10, // 34: familytree.v1.FamilyTreeService.GetFamilyTree:output_type -> familytree.v1.FamilyGraph
+	15, // 35: familytree.v1.FamilyTreeService.GetRelationship:output_type -> familytree.v1.GetRelationshipResponse
+	17, // 36: familytree.v1.FamilyTreeService.GetBaconsNumber:output_type -> familytree.v1.GetBaconsNumberResponse
+	19, // 37: familytree.v1.FamilyTreeService.StreamDescendants:output_type -> familytree.v1.Descendant
+	33, // [33:38] is the sub-list for method output_type
+	28, // [28:33] is the sub-list for method input_type
+	28, // [28:28] is the sub-list for extension type_name
+	28, // [28:28] is the sub-list for extension extendee
+	0,  // [0:28] is the sub-list for field type_name
+}
+
+func init() { file_familytree_proto_init() }
+func file_familytree_proto_init() {
+	if File_familytree_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_familytree_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LifeEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_familytree_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PersonReference); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_familytree_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Person); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_familytree_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Relationship); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_familytree_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FamilyGraphMember); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_familytree_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FamilyGraph); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_familytree_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RelativeReference); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_familytree_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StorePersonRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_familytree_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetFamilyTreeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_familytree_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetRelationshipRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_familytree_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetRelationshipResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_familytree_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetBaconsNumberRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_familytree_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetBaconsNumberResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_familytree_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StreamDescendantsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_familytree_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Descendant); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_familytree_proto_msgTypes[6].OneofWrappers = []interface{}{}
+	file_familytree_proto_msgTypes[8].OneofWrappers = []interface{}{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_familytree_proto_rawDesc,
+			NumEnums:      5,
+			NumMessages:   15,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_familytree_proto_goTypes,
+		DependencyIndexes: file_familytree_proto_depIdxs,
+		EnumInfos:         file_familytree_proto_enumTypes,
+		MessageInfos:      file_familytree_proto_msgTypes,
+	}.Build()
+	File_familytree_proto = out.File
+	file_familytree_proto_rawDesc = nil
+	file_familytree_proto_goTypes = nil
+	file_familytree_proto_depIdxs = nil
+}
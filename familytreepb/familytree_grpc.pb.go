@@ -0,0 +1,287 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: familytree.proto
+
+package familytreepb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	FamilyTreeService_StorePerson_FullMethodName       = "/familytree.v1.FamilyTreeService/StorePerson"
+	FamilyTreeService_GetFamilyTree_FullMethodName     = "/familytree.v1.FamilyTreeService/GetFamilyTree"
+	FamilyTreeService_GetRelationship_FullMethodName   = "/familytree.v1.FamilyTreeService/GetRelationship"
+	FamilyTreeService_GetBaconsNumber_FullMethodName   = "/familytree.v1.FamilyTreeService/GetBaconsNumber"
+	FamilyTreeService_StreamDescendants_FullMethodName = "/familytree.v1.FamilyTreeService/StreamDescendants"
+)
+
+// FamilyTreeServiceClient is the client API for FamilyTreeService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type FamilyTreeServiceClient interface {
+	StorePerson(ctx context.Context, in *StorePersonRequest, opts ...grpc.CallOption) (*Person, error)
+	GetFamilyTree(ctx context.Context, in *GetFamilyTreeRequest, opts ...grpc.CallOption) (*FamilyGraph, error)
+	GetRelationship(ctx context.Context, in *GetRelationshipRequest, opts ...grpc.CallOption) (*GetRelationshipResponse, error)
+	GetBaconsNumber(ctx context.Context, in *GetBaconsNumberRequest, opts ...grpc.CallOption) (*GetBaconsNumberResponse, error)
+	// StreamDescendants sends the descendants of a person one generation after the other.
+	StreamDescendants(ctx context.Context, in *StreamDescendantsRequest, opts ...grpc.CallOption) (FamilyTreeService_StreamDescendantsClient, error)
+}
+
+type familyTreeServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewFamilyTreeServiceClient(cc grpc.ClientConnInterface) FamilyTreeServiceClient {
+	return &familyTreeServiceClient{cc}
+}
+
+func (c *familyTreeServiceClient) StorePerson(ctx context.Context, in *StorePersonRequest, opts ...grpc.CallOption) (*Person, error) {
+	out := new(Person)
+	err := c.cc.Invoke(ctx, FamilyTreeService_StorePerson_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *familyTreeServiceClient) GetFamilyTree(ctx context.Context, in *GetFamilyTreeRequest, opts ...grpc.CallOption) (*FamilyGraph, error) {
+	out := new(FamilyGraph)
+	err := c.cc.Invoke(ctx, FamilyTreeService_GetFamilyTree_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *familyTreeServiceClient) GetRelationship(ctx context.Context, in *GetRelationshipRequest, opts ...grpc.CallOption) (*GetRelationshipResponse, error) {
+	out := new(GetRelationshipResponse)
+	err := c.cc.Invoke(ctx, FamilyTreeService_GetRelationship_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *familyTreeServiceClient) GetBaconsNumber(ctx context.Context, in *GetBaconsNumberRequest, opts ...grpc.CallOption) (*GetBaconsNumberResponse, error) {
+	out := new(GetBaconsNumberResponse)
+	err := c.cc.Invoke(ctx, FamilyTreeService_GetBaconsNumber_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *familyTreeServiceClient) StreamDescendants(ctx context.Context, in *StreamDescendantsRequest, opts ...grpc.CallOption) (FamilyTreeService_StreamDescendantsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &FamilyTreeService_ServiceDesc.Streams[0], FamilyTreeService_StreamDescendants_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &familyTreeServiceStreamDescendantsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type FamilyTreeService_StreamDescendantsClient interface {
+	Recv() (*Descendant, error)
+	grpc.ClientStream
+}
+
+type familyTreeServiceStreamDescendantsClient struct {
+	grpc.ClientStream
+}
+
+func (x *familyTreeServiceStreamDescendantsClient) Recv() (*Descendant, error) {
+	m := new(Descendant)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// FamilyTreeServiceServer is the server API for FamilyTreeService service.
+// All implementations must embed UnimplementedFamilyTreeServiceServer
+// for forward compatibility
+type FamilyTreeServiceServer interface {
+	StorePerson(context.Context, *StorePersonRequest) (*Person, error)
+	GetFamilyTree(context.Context, *GetFamilyTreeRequest) (*FamilyGraph, error)
+	GetRelationship(context.Context, *GetRelationshipRequest) (*GetRelationshipResponse, error)
+	GetBaconsNumber(context.Context, *GetBaconsNumberRequest) (*GetBaconsNumberResponse, error)
+	// StreamDescendants sends the descendants of a person one generation after the other.
+	StreamDescendants(*StreamDescendantsRequest, FamilyTreeService_StreamDescendantsServer) error
+	mustEmbedUnimplementedFamilyTreeServiceServer()
+}
+
+// UnimplementedFamilyTreeServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedFamilyTreeServiceServer struct {
+}
+
+func (UnimplementedFamilyTreeServiceServer) StorePerson(context.Context, *StorePersonRequest) (*Person, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StorePerson not implemented")
+}
+func (UnimplementedFamilyTreeServiceServer) GetFamilyTree(context.Context, *GetFamilyTreeRequest) (*FamilyGraph, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFamilyTree not implemented")
+}
+func (UnimplementedFamilyTreeServiceServer) GetRelationship(context.Context, *GetRelationshipRequest) (*GetRelationshipResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRelationship not implemented")
+}
+func (UnimplementedFamilyTreeServiceServer) GetBaconsNumber(context.Context, *GetBaconsNumberRequest) (*GetBaconsNumberResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBaconsNumber not implemented")
+}
+func (UnimplementedFamilyTreeServiceServer) StreamDescendants(*StreamDescendantsRequest, FamilyTreeService_StreamDescendantsServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamDescendants not implemented")
+}
+func (UnimplementedFamilyTreeServiceServer) mustEmbedUnimplementedFamilyTreeServiceServer() {}
+
+// UnsafeFamilyTreeServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to FamilyTreeServiceServer will
+// result in compilation errors.
+type UnsafeFamilyTreeServiceServer interface {
+	mustEmbedUnimplementedFamilyTreeServiceServer()
+}
+
+func RegisterFamilyTreeServiceServer(s grpc.ServiceRegistrar, srv FamilyTreeServiceServer) {
+	s.RegisterService(&FamilyTreeService_ServiceDesc, srv)
+}
+
+func _FamilyTreeService_StorePerson_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StorePersonRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FamilyTreeServiceServer).StorePerson(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FamilyTreeService_StorePerson_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FamilyTreeServiceServer).StorePerson(ctx, req.(*StorePersonRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FamilyTreeService_GetFamilyTree_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFamilyTreeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FamilyTreeServiceServer).GetFamilyTree(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FamilyTreeService_GetFamilyTree_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FamilyTreeServiceServer).GetFamilyTree(ctx, req.(*GetFamilyTreeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FamilyTreeService_GetRelationship_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRelationshipRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FamilyTreeServiceServer).GetRelationship(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FamilyTreeService_GetRelationship_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FamilyTreeServiceServer).GetRelationship(ctx, req.(*GetRelationshipRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FamilyTreeService_GetBaconsNumber_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBaconsNumberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FamilyTreeServiceServer).GetBaconsNumber(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FamilyTreeService_GetBaconsNumber_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FamilyTreeServiceServer).GetBaconsNumber(ctx, req.(*GetBaconsNumberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FamilyTreeService_StreamDescendants_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamDescendantsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FamilyTreeServiceServer).StreamDescendants(m, &familyTreeServiceStreamDescendantsServer{stream})
+}
+
+type FamilyTreeService_StreamDescendantsServer interface {
+	Send(*Descendant) error
+	grpc.ServerStream
+}
+
+type familyTreeServiceStreamDescendantsServer struct {
+	grpc.ServerStream
+}
+
+func (x *familyTreeServiceStreamDescendantsServer) Send(m *Descendant) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// FamilyTreeService_ServiceDesc is the grpc.ServiceDesc for FamilyTreeService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var FamilyTreeService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "familytree.v1.FamilyTreeService",
+	HandlerType: (*FamilyTreeServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "StorePerson",
+			Handler:    _FamilyTreeService_StorePerson_Handler,
+		},
+		{
+			MethodName: "GetFamilyTree",
+			Handler:    _FamilyTreeService_GetFamilyTree_Handler,
+		},
+		{
+			MethodName: "GetRelationship",
+			Handler:    _FamilyTreeService_GetRelationship_Handler,
+		},
+		{
+			MethodName: "GetBaconsNumber",
+			Handler:    _FamilyTreeService_GetBaconsNumber_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamDescendants",
+			Handler:       _FamilyTreeService_StreamDescendants_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "familytree.proto",
+}
@@ -0,0 +1,328 @@
+package gedcom
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+)
+
+const (
+	MediaType   = "application/x-gedcom"
+	V7MediaType = "text/vnd.familysearch.gedcom"
+	// 5.5.1 lines can have at most 255 characters, longer values are split in CONC lines
+	maxLineValueLength = 200
+	// xref ids can have at most 20 characters between the @ signs
+	maxXRefLength = 20
+)
+
+const (
+	Version551 = "5.5.1"
+	Version7   = "7.0"
+)
+
+// xrefForID derives a stable xref from the id: ObjectIDs are written in base 36 so they fit the 20 characters of
+// a GEDCOM xref, other ids are kept when short enough and hashed otherwise. GEDCOM 7 only accepts upper case xrefs.
+func xrefForID(prefix string, id string) string {
+	if idBytes, err := hex.DecodeString(id); err == nil && len(idBytes) == 12 {
+		return prefix + strings.ToUpper(new(big.Int).SetBytes(idBytes).Text(36))
+	}
+
+	xref := prefix + strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, id)
+	if len(xref) <= maxXRefLength && xref != prefix {
+		return strings.ToUpper(xref)
+	}
+
+	hash := sha1.Sum([]byte(id))
+	return prefix + strings.ToUpper(new(big.Int).SetBytes(hash[:12]).Text(36))
+}
+
+// family is a couple, or a single parent, and the children they have together through the same link type.
+type family struct {
+	xref        string
+	linkType    domain.LinkType
+	parentIDS   []string
+	childrenIDS []string
+}
+
+// familyKey keeps the keys of biological families as they were before link types, so their xrefs do not change.
+func familyKey(parentIDS []string, linkType domain.LinkType) string {
+	key := strings.Join(parentIDS, "+")
+	if linkType != domain.BiologicalLinkType {
+		key = string(linkType) + ":" + key
+	}
+
+	return key
+}
+
+// parentCouples groups the exported parents of person by link type. A FAM has at most two parents, so more parents
+// with the same link type are split in couples in id order.
+func parentCouples(person domain.Person, exported map[string]bool) map[domain.LinkType][][]string {
+	parentIDSByLinkType := make(map[domain.LinkType][]string)
+	for _, parent := range person.Parents {
+		if exported[parent.ID] {
+			linkType := person.ParentLinkType(parent.ID)
+			parentIDSByLinkType[linkType] = append(parentIDSByLinkType[linkType], parent.ID)
+		}
+	}
+
+	couplesByLinkType := make(map[domain.LinkType][][]string, len(parentIDSByLinkType))
+	for linkType, parentIDS := range parentIDSByLinkType {
+		sort.Strings(parentIDS)
+		for len(parentIDS) > 2 {
+			couplesByLinkType[linkType] = append(couplesByLinkType[linkType], parentIDS[:2])
+			parentIDS = parentIDS[2:]
+		}
+		couplesByLinkType[linkType] = append(couplesByLinkType[linkType], parentIDS)
+	}
+
+	return couplesByLinkType
+}
+
+// buildFamilies groups the children by their parents and the link type they have with them. Spouses without
+// children in common on persons still get a family, so they are exported as a couple.
+func buildFamilies(persons []domain.Person, exported map[string]bool) []*family {
+	familiesByKey := make(map[string]*family)
+	familyFor := func(parentIDS []string, linkType domain.LinkType) *family {
+		sort.Strings(parentIDS)
+		key := familyKey(parentIDS, linkType)
+		if currentFamily, ok := familiesByKey[key]; ok {
+			return currentFamily
+		}
+
+		currentFamily := &family{xref: xrefForID("F", key), linkType: linkType, parentIDS: parentIDS}
+		familiesByKey[key] = currentFamily
+		return currentFamily
+	}
+
+	for _, person := range persons {
+		for linkType, couples := range parentCouples(person, exported) {
+			for _, parentIDS := range couples {
+				currentFamily := familyFor(parentIDS, linkType)
+				currentFamily.childrenIDS = append(currentFamily.childrenIDS, person.ID)
+			}
+		}
+	}
+
+	for _, person := range persons {
+		for _, spouse := range person.Spouses {
+			if exported[spouse.ID] {
+				familyFor([]string{person.ID, spouse.ID}, domain.BiologicalLinkType)
+			}
+		}
+	}
+
+	var families []*family
+	for _, currentFamily := range familiesByKey {
+		families = append(families, currentFamily)
+	}
+	sort.Slice(families, func(i, j int) bool {
+		return families[i].xref < families[j].xref
+	})
+
+	return families
+}
+
+// pedigreeByLinkType is the FAMC.PEDI of each link type that is not biological. 5.5.1 has no value for step
+// parents, donors and surrogates, so the link type is written as it is; GEDCOM 7 writes them as OTHER with a PHRASE.
+var pedigreeByLinkType = map[domain.LinkType]string{
+	domain.AdoptiveLinkType:  "adopted",
+	domain.FosterLinkType:    "foster",
+	domain.StepLinkType:      "step",
+	domain.DonorLinkType:     "donor",
+	domain.SurrogateLinkType: "surrogate",
+}
+
+func (gw gedcomWriter) childFamily(familyXRef string, linkType domain.LinkType, version string) {
+	gw.line(1, "", "FAMC", "@"+familyXRef+"@")
+
+	pedigree, ok := pedigreeByLinkType[linkType]
+	if !ok {
+		return
+	}
+
+	switch {
+	case version == Version551:
+		gw.line(2, "", "PEDI", pedigree)
+	case linkType == domain.AdoptiveLinkType || linkType == domain.FosterLinkType:
+		gw.line(2, "", "PEDI", strings.ToUpper(pedigree))
+	default:
+		gw.line(2, "", "PEDI", "OTHER")
+		gw.line(3, "", "PHRASE", pedigree)
+	}
+}
+
+// parentTag writes the first parent of a couple as HUSB and the second as WIFE, a single mother is a WIFE.
+func parentTag(index int, parentsCount int, gender domain.GenderType) string {
+	if index > 0 || parentsCount == 1 && gender == domain.Female {
+		return "WIFE"
+	}
+
+	return "HUSB"
+}
+
+type gedcomWriter struct {
+	writer *bufio.Writer
+	// GEDCOM 7 has no line length limit and dropped CONC
+	splitLongValues bool
+}
+
+// line writes a GEDCOM line, splitting long values in CONC lines and multi line values in CONT lines.
+func (gw gedcomWriter) line(level int, xref string, tag string, value string) {
+	prefix := fmt.Sprintf("%d ", level)
+	if xref != "" {
+		prefix += "@" + xref + "@ "
+	}
+
+	for i, valueLine := range strings.Split(value, "\n") {
+		currentTag := tag
+		if i > 0 {
+			prefix, currentTag = fmt.Sprintf("%d ", level+1), "CONT"
+		}
+
+		for {
+			chunk := valueLine
+			if gw.splitLongValues && len(chunk) > maxLineValueLength {
+				cut := maxLineValueLength
+				// never split a multi byte character or leave a space at the edge of a chunk
+				for cut > 1 && (!utf8.RuneStart(valueLine[cut]) || valueLine[cut-1] == ' ' || valueLine[cut] == ' ') {
+					cut--
+				}
+				chunk = valueLine[:cut]
+			}
+
+			gw.writer.WriteString(prefix + currentTag)
+			if chunk != "" {
+				gw.writer.WriteString(" " + chunk)
+			}
+			gw.writer.WriteString("\r\n")
+
+			valueLine = valueLine[len(chunk):]
+			if valueLine == "" {
+				break
+			}
+			prefix, currentTag = fmt.Sprintf("%d ", level+1), "CONC"
+		}
+	}
+}
+
+func (gw gedcomWriter) lifeEvent(tag string, lifeEvent *domain.LifeEvent) {
+	if lifeEvent == nil {
+		return
+	}
+
+	gw.line(1, "", tag, "")
+	if lifeEvent.Date != "" {
+		gw.line(2, "", "DATE", lifeEvent.Date)
+	}
+	if lifeEvent.Place != "" {
+		gw.line(2, "", "PLAC", lifeEvent.Place)
+	}
+}
+
+var sexByGender = map[domain.GenderType]string{domain.Male: "M", domain.Female: "F"}
+
+// Encode writes persons as a GEDCOM 5.5.1 lineage linked file. Links to persons that are not in persons are left out.
+func Encode(writer io.Writer, persons []domain.Person) error {
+	return encode(writer, persons, Version551)
+}
+
+// EncodeVersion7 writes persons as a GEDCOM 7 file.
+func EncodeVersion7(writer io.Writer, persons []domain.Person) error {
+	return encode(writer, persons, Version7)
+}
+
+func encode(writer io.Writer, persons []domain.Person, version string) error {
+	persons = append([]domain.Person{}, persons...)
+	sort.Slice(persons, func(i, j int) bool {
+		return persons[i].ID < persons[j].ID
+	})
+
+	exported := make(map[string]bool, len(persons))
+	gendersByID := make(map[string]domain.GenderType, len(persons))
+	for _, person := range persons {
+		exported[person.ID] = true
+		gendersByID[person.ID] = person.Gender
+	}
+
+	families := buildFamilies(persons, exported)
+	childFamiliesByPersonID := make(map[string][]*family)
+	spouseFamiliesByPersonID := make(map[string][]string)
+	for _, currentFamily := range families {
+		for _, parentID := range currentFamily.parentIDS {
+			spouseFamiliesByPersonID[parentID] = append(spouseFamiliesByPersonID[parentID], currentFamily.xref)
+		}
+		for _, childrenID := range currentFamily.childrenIDS {
+			childFamiliesByPersonID[childrenID] = append(childFamiliesByPersonID[childrenID], currentFamily)
+		}
+	}
+
+	gw := gedcomWriter{writer: bufio.NewWriter(writer), splitLongValues: version == Version551}
+	gw.line(0, "", "HEAD", "")
+	gw.line(1, "", "GEDC", "")
+	gw.line(2, "", "VERS", version)
+	if version == Version551 {
+		gw.line(2, "", "FORM", "LINEAGE-LINKED")
+		gw.line(1, "", "CHAR", "UTF-8")
+	}
+	gw.line(1, "", "SOUR", "ARVORE_GENEALOGICA")
+
+	for _, person := range persons {
+		gw.line(0, xrefForID("I", person.ID), "INDI", "")
+		gw.line(1, "", "NAME", person.Name)
+		if sex, ok := sexByGender[person.Gender]; ok {
+			gw.line(1, "", "SEX", sex)
+		}
+		gw.lifeEvent("BIRT", person.Birth)
+		gw.lifeEvent("DEAT", person.Death)
+
+		for _, childFamily := range childFamiliesByPersonID[person.ID] {
+			gw.childFamily(childFamily.xref, childFamily.linkType, version)
+		}
+		for _, familyXRef := range spouseFamiliesByPersonID[person.ID] {
+			gw.line(1, "", "FAMS", "@"+familyXRef+"@")
+		}
+	}
+
+	for _, currentFamily := range families {
+		gw.line(0, currentFamily.xref, "FAM", "")
+
+		// 5.5.1 only knows HUSB and WIFE, a couple with the same gender is written in id order
+		parentIDS := append([]string{}, currentFamily.parentIDS...)
+		if len(parentIDS) == 2 && gendersByID[parentIDS[0]] == domain.Female && gendersByID[parentIDS[1]] != domain.Female {
+			parentIDS[0], parentIDS[1] = parentIDS[1], parentIDS[0]
+		}
+		for i, parentID := range parentIDS {
+			gw.line(1, "", parentTag(i, len(parentIDS), gendersByID[parentID]), "@"+xrefForID("I", parentID)+"@")
+		}
+
+		for _, childrenID := range currentFamily.childrenIDS {
+			gw.line(1, "", "CHIL", "@"+xrefForID("I", childrenID)+"@")
+		}
+	}
+
+	gw.line(0, "", "TRLR", "")
+
+	return gw.writer.Flush()
+}
+
+func PersonsFromFamilyGraph(familyGraph domain.FamilyGraph) []domain.Person {
+	var persons []domain.Person
+	for _, member := range familyGraph.Members {
+		persons = append(persons, *member)
+	}
+
+	return persons
+}
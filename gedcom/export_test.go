@@ -0,0 +1,151 @@
+package gedcom
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncode(t *testing.T) {
+	luis := &domain.Person{ID: "6421b9a2c3f1a6f7d2e4b001", Name: "Luis Bittencourt", Gender: domain.Male}
+	dayse := &domain.Person{ID: "6421b9a2c3f1a6f7d2e4b002", Name: "Dayse Bittencourt", Gender: domain.Female, Birth: &domain.LifeEvent{Date: "ABT 1962", Place: "Porto Alegre"}}
+	caio := &domain.Person{ID: "6421b9a2c3f1a6f7d2e4b003", Name: "Caio Bittencourt", Gender: domain.Male, Parents: []*domain.Person{luis, dayse}}
+	claudia := &domain.Person{ID: "6421b9a2c3f1a6f7d2e4b004", Name: "Claudia", Gender: domain.Female, Spouses: []*domain.Person{caio}}
+	luis.Children = []*domain.Person{caio}
+	dayse.Children = []*domain.Person{caio}
+
+	persons := []domain.Person{*caio, *luis, *claudia, *dayse}
+
+	t.Run("should derive stable xrefs from the person ids", func(t *testing.T) {
+		assert.Equal(t, "I305S2CFSJZQH7QEX2BL", xrefForID("I", luis.ID))
+		assert.Equal(t, xrefForID("I", luis.ID), xrefForID("I", luis.ID))
+		assert.Equal(t, "IIDLUIS", xrefForID("I", "IDLuis"))
+		assert.LessOrEqual(t, len(xrefForID("F", luis.ID+"+"+dayse.ID)), maxXRefLength)
+	})
+
+	t.Run("should write the same file no matter the order of persons", func(t *testing.T) {
+		var fileA, fileB bytes.Buffer
+		assert.NoError(t, Encode(&fileA, persons))
+		assert.NoError(t, Encode(&fileB, []domain.Person{*dayse, *claudia, *luis, *caio}))
+
+		assert.Equal(t, fileA.String(), fileB.String())
+		assert.True(t, strings.HasPrefix(fileA.String(), "0 HEAD\r\n"))
+		assert.True(t, strings.HasSuffix(fileA.String(), "0 TRLR\r\n"))
+	})
+
+	t.Run("should be imported back with the same persons and families", func(t *testing.T) {
+		var file bytes.Buffer
+		assert.NoError(t, Encode(&file, persons))
+
+		records, err := Parse(&file)
+		assert.NoError(t, err)
+
+		batch, report := BuildPersonBatch(records)
+		assert.Empty(t, report.UnsupportedTags)
+		assert.Equal(t, 1, report.Families)
+		// the couple without children can only be kept as a family in GEDCOM
+		assert.Len(t, report.Skipped, 1)
+
+		caioXRef := xrefForID("I", caio.ID)
+		expectedBatch := domain.PersonBatch{
+			{TempID: xrefForID("I", luis.ID), Person: domain.Person{Name: luis.Name, Gender: luis.Gender}},
+			{TempID: xrefForID("I", dayse.ID), Person: domain.Person{Name: dayse.Name, Gender: dayse.Gender, Birth: dayse.Birth}},
+			{TempID: caioXRef, Person: domain.Person{Name: caio.Name, Gender: caio.Gender, Parents: []*domain.Person{{ID: xrefForID("I", luis.ID)}, {ID: xrefForID("I", dayse.ID)}}, ParentRoles: map[string]domain.ParentRoleType{
+				xrefForID("I", luis.ID):  domain.FatherParentRole,
+				xrefForID("I", dayse.ID): domain.MotherParentRole,
+			}}},
+			{TempID: xrefForID("I", claudia.ID), Person: domain.Person{Name: claudia.Name, Gender: claudia.Gender}},
+		}
+		assert.Equal(t, expectedBatch, batch)
+	})
+
+	t.Run("should keep persons and families on a GEDCOM 7 round trip", func(t *testing.T) {
+		var file bytes.Buffer
+		assert.NoError(t, EncodeVersion7(&file, persons))
+		assert.True(t, strings.HasPrefix(file.String(), "0 HEAD\r\n1 GEDC\r\n2 VERS 7.0\r\n"))
+		assert.NotContains(t, file.String(), "CHAR")
+
+		var file551 bytes.Buffer
+		assert.NoError(t, Encode(&file551, persons))
+		records551, err := Parse(&file551)
+		assert.NoError(t, err)
+		batch551, _ := BuildPersonBatch(records551)
+
+		records, err := Parse(&file)
+		assert.NoError(t, err)
+
+		batch, report := BuildPersonBatch(records)
+		assert.Equal(t, Version7, report.Version)
+		assert.Empty(t, report.UnsupportedTags)
+		assert.Equal(t, batch551, batch)
+	})
+
+	t.Run("should not split long values in GEDCOM 7", func(t *testing.T) {
+		longName := strings.Repeat("Zézé ", 60)
+		var file bytes.Buffer
+		assert.NoError(t, EncodeVersion7(&file, []domain.Person{{ID: "IDZeze", Name: longName, Gender: domain.Male}}))
+
+		assert.NotContains(t, file.String(), "CONC")
+	})
+
+	t.Run("should split long values in CONC lines", func(t *testing.T) {
+		longName := strings.Repeat("Zézé ", 60)
+		var file bytes.Buffer
+		assert.NoError(t, Encode(&file, []domain.Person{{ID: "IDZeze", Name: longName, Gender: domain.Male}}))
+
+		for _, line := range strings.Split(file.String(), "\r\n") {
+			assert.LessOrEqual(t, len(line), 255)
+		}
+		assert.Contains(t, file.String(), "2 CONC ")
+
+		records, err := Parse(&file)
+		assert.NoError(t, err)
+		assert.Equal(t, longName, records[1].Child("NAME").Value)
+	})
+
+	t.Run("should write a family for each link type when a person has more than two parents", func(t *testing.T) {
+		tunico := &domain.Person{ID: "6421b9a2c3f1a6f7d2e4b005", Name: "Tunico", Gender: domain.Male}
+		ana := &domain.Person{ID: "6421b9a2c3f1a6f7d2e4b006", Name: "Ana", Gender: domain.Female}
+		adoptedCaio := *caio
+		adoptedCaio.Parents = []*domain.Person{luis, dayse, tunico, ana}
+		adoptedCaio.SetParentLinkType(tunico.ID, domain.AdoptiveLinkType)
+		adoptedCaio.SetParentLinkType(ana.ID, domain.StepLinkType)
+		tunico.Children = []*domain.Person{&adoptedCaio}
+		ana.Children = []*domain.Person{&adoptedCaio}
+
+		adoptedPersons := []domain.Person{adoptedCaio, *luis, *dayse, *tunico, *ana}
+
+		var file bytes.Buffer
+		assert.NoError(t, Encode(&file, adoptedPersons))
+		assert.Equal(t, 3, strings.Count(file.String(), " FAM\r\n"))
+		assert.Contains(t, file.String(), "1 FAMC @"+xrefForID("F", "adoptive:"+tunico.ID)+"@\r\n2 PEDI adopted\r\n")
+		assert.Contains(t, file.String(), "1 FAMC @"+xrefForID("F", "step:"+ana.ID)+"@\r\n2 PEDI step\r\n")
+		assert.Contains(t, file.String(), "0 @"+xrefForID("F", "step:"+ana.ID)+"@ FAM\r\n1 WIFE @"+xrefForID("I", ana.ID)+"@\r\n")
+
+		var file7 bytes.Buffer
+		assert.NoError(t, EncodeVersion7(&file7, adoptedPersons))
+		assert.Contains(t, file7.String(), "2 PEDI ADOPTED\r\n")
+		assert.Contains(t, file7.String(), "2 PEDI OTHER\r\n3 PHRASE step\r\n")
+
+		for _, encoded := range []*bytes.Buffer{&file, &file7} {
+			records, err := Parse(encoded)
+			assert.NoError(t, err)
+
+			batch, report := BuildPersonBatch(records)
+			assert.Empty(t, report.Skipped)
+			assert.Empty(t, report.UnsupportedTags)
+			for _, batchPerson := range batch {
+				if batchPerson.TempID == xrefForID("I", caio.ID) {
+					assert.Len(t, batchPerson.Person.Parents, 4)
+					assert.Equal(t, map[string]domain.LinkType{
+						xrefForID("I", tunico.ID): domain.AdoptiveLinkType,
+						xrefForID("I", ana.ID):    domain.StepLinkType,
+					}, batchPerson.Person.ParentLinkTypes)
+				}
+			}
+		}
+	})
+}
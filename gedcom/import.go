@@ -0,0 +1,359 @@
+package gedcom
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+)
+
+type SkippedRecord struct {
+	Line   int
+	XRef   string
+	Tag    string
+	Reason string
+}
+
+// ImportReport describes what could not be imported. UnsupportedTags counts the ignored tags by their path, like
+// INDI.OCCU or FAM.MARR.
+type ImportReport struct {
+	Version         string
+	Persons         int
+	Families        int
+	IDByXRef        map[string]string
+	Skipped         []SkippedRecord
+	UnsupportedTags map[string]int
+}
+
+func (ir *ImportReport) skip(record Record, reason string) {
+	ir.Skipped = append(ir.Skipped, SkippedRecord{Line: record.Line, XRef: record.XRef, Tag: record.Tag, Reason: reason})
+}
+
+func (ir *ImportReport) unsupported(path string) {
+	ir.UnsupportedTags[path]++
+}
+
+var supportedTagsByPath = map[string]bool{
+	"HEAD":      true,
+	"TRLR":      true,
+	"INDI":      true,
+	"INDI.NAME": true,
+	"INDI.SEX":  true,
+	"INDI.BIRT": true,
+	"INDI.DEAT": true,
+	// families are read from the FAM records, FAMC is only read for the PEDI of the link with the parents
+	"INDI.FAMC": true,
+	"INDI.FAMS": true,
+	"FAM":       true,
+	"FAM.HUSB":  true,
+	"FAM.WIFE":  true,
+	"FAM.CHIL":  true,
+}
+
+var supportedNameTags = map[string]bool{"GIVN": true, "SURN": true}
+
+var supportedChildFamilyTags = map[string]bool{"PEDI": true}
+
+// linkTypeByPedigree reads the FAMC.PEDI values of 5.5.1 and GEDCOM 7, and the link types Encode writes for the
+// ones GEDCOM has no value for. GEDCOM 7 writes those as OTHER with a PHRASE.
+var linkTypeByPedigree = map[string]domain.LinkType{
+	"birth":     domain.BiologicalLinkType,
+	"adopted":   domain.AdoptiveLinkType,
+	"foster":    domain.FosterLinkType,
+	"step":      domain.StepLinkType,
+	"donor":     domain.DonorLinkType,
+	"surrogate": domain.SurrogateLinkType,
+}
+
+var supportedLifeEventTags = map[string]bool{"DATE": true, "PLAC": true}
+
+// parseName turns "Caio /Bittencourt/" into "Caio Bittencourt", falling back to the GIVN and SURN sub records.
+func parseName(nameRecord Record) string {
+	name := strings.Join(strings.Fields(strings.ReplaceAll(nameRecord.Value, "/", " ")), " ")
+	if name != "" {
+		return name
+	}
+
+	var nameParts []string
+	for _, tag := range []string{"GIVN", "SURN"} {
+		if part := nameRecord.Child(tag); part != nil && part.Value != "" {
+			nameParts = append(nameParts, strings.TrimSpace(part.Value))
+		}
+	}
+
+	return strings.Join(nameParts, " ")
+}
+
+func parseSex(value string) domain.GenderType {
+	switch strings.ToUpper(strings.TrimSpace(value)) {
+	case "M":
+		return domain.Male
+	case "F":
+		return domain.Female
+	}
+
+	return ""
+}
+
+func parseLifeEvent(eventRecord Record, path string, report *ImportReport) *domain.LifeEvent {
+	lifeEvent := domain.LifeEvent{}
+	for _, child := range eventRecord.Children {
+		if !supportedLifeEventTags[child.Tag] {
+			report.unsupported(path + "." + child.Tag)
+			continue
+		}
+
+		switch child.Tag {
+		case "DATE":
+			lifeEvent.Date = strings.TrimSpace(child.Value)
+		case "PLAC":
+			lifeEvent.Place = strings.TrimSpace(child.Value)
+		}
+	}
+
+	if lifeEvent.Date == "" && lifeEvent.Place == "" {
+		return nil
+	}
+
+	return &lifeEvent
+}
+
+// parseChildFamily returns the xref of the family of a FAMC record and the link type of its PEDI, biological when
+// there is none. Pedigrees that have no link type, like sealing, are reported and read as biological.
+func parseChildFamily(childFamily Record, report *ImportReport) (string, domain.LinkType) {
+	linkType := domain.BiologicalLinkType
+	for _, child := range childFamily.Children {
+		path := "INDI.FAMC." + child.Tag
+		if !supportedChildFamilyTags[child.Tag] {
+			report.unsupported(path)
+			continue
+		}
+
+		pedigree := strings.ToLower(strings.TrimSpace(child.Value))
+		if phrase := child.Child("PHRASE"); pedigree == "other" && phrase != nil {
+			pedigree = strings.ToLower(strings.TrimSpace(phrase.Value))
+		}
+
+		pedigreeLinkType, ok := linkTypeByPedigree[pedigree]
+		if !ok {
+			report.unsupported(path)
+			continue
+		}
+		linkType = pedigreeLinkType
+	}
+
+	return strings.Trim(childFamily.Value, "@"), linkType
+}
+
+// buildPersonFromIndividual also returns the link type with the parents of each family the individual is a
+// child of, by the family xref.
+func buildPersonFromIndividual(individual Record, report *ImportReport) (*domain.Person, map[string]domain.LinkType) {
+	person := domain.Person{}
+	linkTypeByFamilyXRef := make(map[string]domain.LinkType)
+	for _, child := range individual.Children {
+		path := "INDI." + child.Tag
+		if !supportedTagsByPath[path] {
+			report.unsupported(path)
+			continue
+		}
+
+		switch child.Tag {
+		case "NAME":
+			// only the first name is kept, the others are usually aliases or married names
+			if person.Name == "" {
+				person.Name = parseName(*child)
+			}
+
+			for _, nameChild := range child.Children {
+				if !supportedNameTags[nameChild.Tag] {
+					report.unsupported(path + "." + nameChild.Tag)
+				}
+			}
+		case "SEX":
+			person.Gender = parseSex(child.Value)
+		case "BIRT":
+			person.Birth = parseLifeEvent(*child, path, report)
+		case "DEAT":
+			person.Death = parseLifeEvent(*child, path, report)
+		case "FAMC":
+			familyXRef, linkType := parseChildFamily(*child, report)
+			linkTypeByFamilyXRef[familyXRef] = linkType
+		}
+	}
+
+	if len(person.Name) < 2 {
+		report.skip(individual, "name must have more than 1 character")
+		return nil, nil
+	}
+
+	if !person.Gender.IsValid() {
+		report.skip(individual, "sex must be M or F")
+		return nil, nil
+	}
+
+	return &person, linkTypeByFamilyXRef
+}
+
+// linkFamily adds the husband and wife of family as parents of each of its children, with the link type of the FAMC
+// of the children, as the father and the mother when their gender matches and the child has no other father or
+// mother with that link type, and as generic parents otherwise. Spouses are not stored, they are inferred from the
+// children they have in common.
+func linkFamily(family Record, personsByXRef map[string]*domain.Person, linkTypesByXRef map[string]map[string]domain.LinkType, report *ImportReport) {
+	var parentXRefs []string
+	roleByParentXRef := make(map[string]domain.ParentRoleType)
+	var childrenRecords []*Record
+	for _, child := range family.Children {
+		path := "FAM." + child.Tag
+		if !supportedTagsByPath[path] {
+			report.unsupported(path)
+			continue
+		}
+
+		xref := strings.Trim(child.Value, "@")
+		if _, ok := personsByXRef[xref]; !ok {
+			report.skip(*child, fmt.Sprintf("individual %s was not imported", xref))
+			continue
+		}
+
+		switch child.Tag {
+		case "HUSB", "WIFE":
+			parentXRefs = append(parentXRefs, xref)
+			roleByParentXRef[xref] = parentRoleByFamilyTag(child.Tag, personsByXRef[xref].Gender)
+		case "CHIL":
+			childrenRecords = append(childrenRecords, child)
+		}
+	}
+
+	if len(childrenRecords) == 0 {
+		report.skip(family, "family has no imported children, spouses are only linked through their children")
+		return
+	}
+
+	for _, childrenRecord := range childrenRecords {
+		childrenXRef := strings.Trim(childrenRecord.Value, "@")
+		children := personsByXRef[childrenXRef]
+
+		linkType, ok := linkTypesByXRef[childrenXRef][family.XRef]
+		if !ok {
+			linkType = domain.BiologicalLinkType
+		}
+
+		var parentsToAdd []*domain.Person
+		for _, parentXRef := range parentXRefs {
+			alreadyParent := parentXRef == childrenXRef
+			for _, parent := range children.Parents {
+				alreadyParent = alreadyParent || parent.ID == parentXRef
+			}
+
+			if !alreadyParent {
+				parentsToAdd = append(parentsToAdd, &domain.Person{ID: parentXRef})
+			}
+		}
+
+		if linkType == domain.BiologicalLinkType && biologicalParentsCount(*children)+len(parentsToAdd) > 2 {
+			report.skip(*childrenRecord, fmt.Sprintf("individual %s already has biological parents from another family", childrenXRef))
+			continue
+		}
+
+		for _, parent := range parentsToAdd {
+			role := roleByParentXRef[parent.ID]
+			if hasParentWithRole(*children, role, linkType) {
+				role = domain.GenericParentRole
+			}
+
+			children.AddParent(parent, role)
+			children.SetParentLinkType(parent.ID, linkType)
+		}
+	}
+
+	report.Families++
+}
+
+func biologicalParentsCount(person domain.Person) int {
+	count := 0
+	for _, parent := range person.Parents {
+		if person.ParentLinkType(parent.ID) == domain.BiologicalLinkType {
+			count++
+		}
+	}
+
+	return count
+}
+
+func hasParentWithRole(person domain.Person, role domain.ParentRoleType, linkType domain.LinkType) bool {
+	for _, parent := range person.Parents {
+		if person.ParentRole(parent.ID) == role && person.ParentLinkType(parent.ID) == linkType {
+			return true
+		}
+	}
+
+	return false
+}
+
+func parentRoleByFamilyTag(tag string, gender domain.GenderType) domain.ParentRoleType {
+	if tag == "HUSB" && gender == domain.Male {
+		return domain.FatherParentRole
+	}
+
+	if tag == "WIFE" && gender == domain.Female {
+		return domain.MotherParentRole
+	}
+
+	return domain.GenericParentRole
+}
+
+// BuildPersonBatch maps the INDI records to persons and the FAM records to their parent links. The cross reference
+// of each individual is used as its temporary id. Records that break the person rules are skipped and reported.
+func BuildPersonBatch(records []*Record) (domain.PersonBatch, ImportReport) {
+	report := ImportReport{UnsupportedTags: map[string]int{}}
+
+	var individualXRefs []string
+	personsByXRef := make(map[string]*domain.Person)
+	linkTypesByXRef := make(map[string]map[string]domain.LinkType)
+	for _, record := range records {
+		if !supportedTagsByPath[record.Tag] {
+			report.unsupported(record.Tag)
+			continue
+		}
+
+		if record.Tag == "HEAD" {
+			if gedc := record.Child("GEDC"); gedc != nil && gedc.Child("VERS") != nil {
+				report.Version = gedc.Child("VERS").Value
+			}
+		}
+
+		if record.Tag != "INDI" {
+			continue
+		}
+
+		if record.XRef == "" {
+			report.skip(*record, "individual has no cross reference")
+			continue
+		}
+
+		if _, ok := personsByXRef[record.XRef]; ok {
+			report.skip(*record, fmt.Sprintf("individual %s is duplicated", record.XRef))
+			continue
+		}
+
+		if person, linkTypeByFamilyXRef := buildPersonFromIndividual(*record, &report); person != nil {
+			personsByXRef[record.XRef] = person
+			linkTypesByXRef[record.XRef] = linkTypeByFamilyXRef
+			individualXRefs = append(individualXRefs, record.XRef)
+		}
+	}
+
+	for _, record := range records {
+		if record.Tag == "FAM" {
+			linkFamily(*record, personsByXRef, linkTypesByXRef, &report)
+		}
+	}
+
+	var batch domain.PersonBatch
+	for _, xref := range individualXRefs {
+		batch = append(batch, domain.BatchPerson{TempID: xref, Person: *personsByXRef[xref]})
+	}
+	report.Persons = len(batch)
+
+	return batch, report
+}
@@ -0,0 +1,208 @@
+package gedcom
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildPersonBatch(t *testing.T) {
+	type testArgs struct {
+		testName                string
+		file                    string
+		expectedBatch           domain.PersonBatch
+		expectedFamilies        int
+		expectedSkippedXRefs    []string
+		expectedUnsupportedTags map[string]int
+	}
+
+	ref := func(id string) *domain.Person {
+		return &domain.Person{ID: id}
+	}
+
+	tests := []testArgs{
+		{
+			testName: "should map individuals and link children to both parents of their family",
+			file: `0 HEAD
+1 GEDC
+2 VERS 5.5.1
+0 @I1@ INDI
+1 NAME Luis /Bittencourt/
+1 SEX M
+1 BIRT
+2 DATE ABT 1960
+2 PLAC Porto Alegre
+1 OCCU Engineer
+0 @I2@ INDI
+1 NAME
+2 GIVN Dayse
+2 SURN Bittencourt
+1 SEX F
+0 @I3@ INDI
+1 NAME Caio /Bittencourt/
+1 SEX M
+1 DEAT
+2 DATE 2090
+2 SOUR @S1@
+0 @F1@ FAM
+1 HUSB @I1@
+1 WIFE @I2@
+1 MARR
+2 DATE 1985
+1 CHIL @I3@
+0 @S1@ SOUR
+0 TRLR
+`,
+			expectedBatch: domain.PersonBatch{
+				{TempID: "I1", Person: domain.Person{Name: "Luis Bittencourt", Gender: domain.Male, Birth: &domain.LifeEvent{Date: "ABT 1960", Place: "Porto Alegre"}}},
+				{TempID: "I2", Person: domain.Person{Name: "Dayse Bittencourt", Gender: domain.Female}},
+				{TempID: "I3", Person: domain.Person{Name: "Caio Bittencourt", Gender: domain.Male, Death: &domain.LifeEvent{Date: "2090"}, Parents: []*domain.Person{ref("I1"), ref("I2")}, ParentRoles: map[string]domain.ParentRoleType{"I1": domain.FatherParentRole, "I2": domain.MotherParentRole}}},
+			},
+			expectedFamilies: 1,
+			expectedUnsupportedTags: map[string]int{
+				"INDI.OCCU":      1,
+				"INDI.DEAT.SOUR": 1,
+				"FAM.MARR":       1,
+				"SOUR":           1,
+			},
+		},
+		{
+			testName: "should skip individuals that break the person rules and their links",
+			file: `0 @I1@ INDI
+1 NAME Luis
+1 SEX U
+0 @I2@ INDI
+1 NAME Caio
+1 SEX M
+0 @F1@ FAM
+1 HUSB @I1@
+1 CHIL @I2@
+`,
+			expectedBatch: domain.PersonBatch{
+				{TempID: "I2", Person: domain.Person{Name: "Caio", Gender: domain.Male}},
+			},
+			expectedFamilies:        1,
+			expectedSkippedXRefs:    []string{"I1", ""},
+			expectedUnsupportedTags: map[string]int{},
+		},
+		{
+			testName: "should skip families without children and children that already have two parents",
+			file: `0 @I1@ INDI
+1 NAME Luis
+1 SEX M
+0 @I2@ INDI
+1 NAME Dayse
+1 SEX F
+0 @I3@ INDI
+1 NAME Claudia
+1 SEX F
+0 @I4@ INDI
+1 NAME Caio
+1 SEX M
+0 @F1@ FAM
+1 HUSB @I1@
+1 WIFE @I2@
+1 CHIL @I4@
+0 @F2@ FAM
+1 HUSB @I1@
+1 WIFE @I3@
+1 CHIL @I4@
+0 @F3@ FAM
+1 HUSB @I1@
+1 WIFE @I3@
+`,
+			expectedBatch: domain.PersonBatch{
+				{TempID: "I1", Person: domain.Person{Name: "Luis", Gender: domain.Male}},
+				{TempID: "I2", Person: domain.Person{Name: "Dayse", Gender: domain.Female}},
+				{TempID: "I3", Person: domain.Person{Name: "Claudia", Gender: domain.Female}},
+				{TempID: "I4", Person: domain.Person{Name: "Caio", Gender: domain.Male, Parents: []*domain.Person{ref("I1"), ref("I2")}, ParentRoles: map[string]domain.ParentRoleType{"I1": domain.FatherParentRole, "I2": domain.MotherParentRole}}},
+			},
+			expectedFamilies:        2,
+			expectedSkippedXRefs:    []string{"", "F3"},
+			expectedUnsupportedTags: map[string]int{},
+		},
+		{
+			testName: "should link the parents of other families with the link type of the pedigree",
+			file: `0 @I1@ INDI
+1 NAME Luis
+1 SEX M
+0 @I2@ INDI
+1 NAME Dayse
+1 SEX F
+0 @I3@ INDI
+1 NAME Tunico
+1 SEX M
+0 @I4@ INDI
+1 NAME Claudia
+1 SEX F
+0 @I5@ INDI
+1 NAME Caio
+1 SEX M
+1 FAMC @F1@
+1 FAMC @F2@
+2 PEDI ADOPTED
+1 FAMC @F3@
+2 PEDI OTHER
+3 PHRASE step
+1 FAMC @F4@
+2 PEDI sealing
+0 @F1@ FAM
+1 HUSB @I1@
+1 WIFE @I2@
+1 CHIL @I5@
+0 @F2@ FAM
+1 HUSB @I3@
+1 CHIL @I5@
+0 @F3@ FAM
+1 HUSB @I1@
+1 WIFE @I4@
+1 CHIL @I5@
+0 @F4@ FAM
+1 WIFE @I4@
+1 CHIL @I5@
+`,
+			expectedBatch: domain.PersonBatch{
+				{TempID: "I1", Person: domain.Person{Name: "Luis", Gender: domain.Male}},
+				{TempID: "I2", Person: domain.Person{Name: "Dayse", Gender: domain.Female}},
+				{TempID: "I3", Person: domain.Person{Name: "Tunico", Gender: domain.Male}},
+				{TempID: "I4", Person: domain.Person{Name: "Claudia", Gender: domain.Female}},
+				{TempID: "I5", Person: domain.Person{
+					Name:        "Caio",
+					Gender:      domain.Male,
+					Parents:     []*domain.Person{ref("I1"), ref("I2"), ref("I3"), ref("I4")},
+					ParentRoles: map[string]domain.ParentRoleType{"I1": domain.FatherParentRole, "I2": domain.MotherParentRole, "I3": domain.FatherParentRole, "I4": domain.MotherParentRole},
+					ParentLinkTypes: map[string]domain.LinkType{
+						"I3": domain.AdoptiveLinkType,
+						"I4": domain.StepLinkType,
+					},
+				}},
+			},
+			expectedFamilies:        4,
+			expectedUnsupportedTags: map[string]int{"INDI.FAMC.PEDI": 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(tt testArgs) func(t *testing.T) {
+			return func(t *testing.T) {
+				records, err := Parse(strings.NewReader(tt.file))
+				assert.NoError(t, err)
+
+				batch, report := BuildPersonBatch(records)
+
+				assert.Equal(t, tt.expectedBatch, batch)
+				assert.Equal(t, len(tt.expectedBatch), report.Persons)
+				assert.Equal(t, tt.expectedFamilies, report.Families)
+				assert.Equal(t, tt.expectedUnsupportedTags, report.UnsupportedTags)
+
+				var skippedXRefs []string
+				for _, skipped := range report.Skipped {
+					skippedXRefs = append(skippedXRefs, skipped.XRef)
+				}
+				assert.Equal(t, tt.expectedSkippedXRefs, skippedXRefs)
+			}
+		}(tt))
+	}
+}
@@ -0,0 +1,131 @@
+package gedcom
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/CaioBittencourt/arvore-genealogica/errors"
+)
+
+// Record is a GEDCOM line with the lines of higher level that follow it. CONC and CONT lines are already merged
+// into Value.
+type Record struct {
+	Line     int
+	Level    int
+	XRef     string
+	Tag      string
+	Value    string
+	Children []*Record
+}
+
+// Child returns the first sub record with tag.
+func (r Record) Child(tag string) *Record {
+	for _, child := range r.Children {
+		if child.Tag == tag {
+			return child
+		}
+	}
+
+	return nil
+}
+
+func newInvalidGedcomError(lineNumber int, message string) error {
+	return errors.NewApplicationError(fmt.Sprintf("gedcom line %d: %s", lineNumber, message), errors.InvalidGedcomErrorCode)
+}
+
+// parseLine splits a line in the "level [@xref@] tag [value]" format.
+func parseLine(lineNumber int, line string) (*Record, error) {
+	fields := strings.SplitN(line, " ", 2)
+	level, err := strconv.Atoi(fields[0])
+	if err != nil || level < 0 {
+		return nil, newInvalidGedcomError(lineNumber, fmt.Sprintf("invalid level %q", fields[0]))
+	}
+
+	if len(fields) < 2 || fields[1] == "" {
+		return nil, newInvalidGedcomError(lineNumber, "missing tag")
+	}
+
+	record := &Record{Line: lineNumber, Level: level}
+	rest := fields[1]
+	if strings.HasPrefix(rest, "@") {
+		fields = strings.SplitN(rest, " ", 2)
+		if len(fields) < 2 || len(fields[0]) < 3 || !strings.HasSuffix(fields[0], "@") {
+			return nil, newInvalidGedcomError(lineNumber, fmt.Sprintf("invalid cross reference %q", fields[0]))
+		}
+
+		record.XRef = strings.Trim(fields[0], "@")
+		rest = fields[1]
+	}
+
+	fields = strings.SplitN(rest, " ", 2)
+	record.Tag = strings.ToUpper(fields[0])
+	if len(fields) == 2 {
+		record.Value = fields[1]
+	}
+
+	return record, nil
+}
+
+// Parse reads a GEDCOM 5.5.1 file and returns its level 0 records.
+func Parse(reader io.Reader) ([]*Record, error) {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var records []*Record
+	var openRecords []*Record
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if lineNumber == 1 {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+		line = strings.TrimLeft(line, " \t")
+		if line == "" {
+			continue
+		}
+
+		record, err := parseLine(lineNumber, line)
+		if err != nil {
+			return nil, err
+		}
+
+		if record.Level > len(openRecords) {
+			return nil, newInvalidGedcomError(lineNumber, fmt.Sprintf("level %d skips a level", record.Level))
+		}
+		openRecords = openRecords[:record.Level]
+
+		if record.Level == 0 {
+			records = append(records, record)
+			openRecords = append(openRecords, record)
+			continue
+		}
+
+		parent := openRecords[record.Level-1]
+		switch record.Tag {
+		case "CONC":
+			parent.Value += record.Value
+			continue
+		case "CONT":
+			parent.Value += "\n" + record.Value
+			continue
+		}
+
+		parent.Children = append(parent.Children, record)
+		openRecords = append(openRecords, record)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(records) == 0 {
+		return nil, errors.NewApplicationError("gedcom file has no records", errors.InvalidGedcomErrorCode)
+	}
+
+	return records, nil
+}
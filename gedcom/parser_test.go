@@ -0,0 +1,70 @@
+package gedcom
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CaioBittencourt/arvore-genealogica/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	type testArgs struct {
+		testName          string
+		file              string
+		expectedRecords   []*Record
+		expectedErrorCode errors.ApplicationErrorCode
+	}
+
+	tests := []testArgs{
+		{
+			testName: "should build the records tree with cross references",
+			file:     "0 HEAD\n1 CHAR UTF-8\n0 @I1@ INDI\n1 NAME Caio /Bittencourt/\n1 BIRT\n2 DATE 1 JAN 1990\n0 TRLR\n",
+			expectedRecords: []*Record{
+				{Line: 1, Level: 0, Tag: "HEAD", Children: []*Record{{Line: 2, Level: 1, Tag: "CHAR", Value: "UTF-8"}}},
+				{Line: 3, Level: 0, XRef: "I1", Tag: "INDI", Children: []*Record{
+					{Line: 4, Level: 1, Tag: "NAME", Value: "Caio /Bittencourt/"},
+					{Line: 5, Level: 1, Tag: "BIRT", Children: []*Record{{Line: 6, Level: 2, Tag: "DATE", Value: "1 JAN 1990"}}},
+				}},
+				{Line: 7, Level: 0, Tag: "TRLR"},
+			},
+		},
+		{
+			testName: "should merge CONC and CONT lines and ignore byte order mark and carriage returns",
+			file:     "\ufeff0 @N1@ NOTE first\r\n1 CONC  part\r\n1 CONT second line\r\n",
+			expectedRecords: []*Record{
+				{Line: 1, Level: 0, XRef: "N1", Tag: "NOTE", Value: "first part\nsecond line"},
+			},
+		},
+		{
+			testName:          "should fail on invalid level",
+			file:              "0 HEAD\nX CHAR UTF-8\n",
+			expectedErrorCode: errors.InvalidGedcomErrorCode,
+		},
+		{
+			testName:          "should fail when a level is skipped",
+			file:              "0 HEAD\n2 CHAR UTF-8\n",
+			expectedErrorCode: errors.InvalidGedcomErrorCode,
+		},
+		{
+			testName:          "should fail on empty file",
+			file:              "\n\n",
+			expectedErrorCode: errors.InvalidGedcomErrorCode,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(tt testArgs) func(t *testing.T) {
+			return func(t *testing.T) {
+				records, err := Parse(strings.NewReader(tt.file))
+				if tt.expectedErrorCode != "" {
+					assert.True(t, errors.ErrorHasCode(err, tt.expectedErrorCode))
+					return
+				}
+
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedRecords, records)
+			}
+		}(tt))
+	}
+}
@@ -0,0 +1,388 @@
+package gedcomx
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+	"github.com/CaioBittencourt/arvore-genealogica/errors"
+	"github.com/CaioBittencourt/arvore-genealogica/gedcom"
+)
+
+const MediaType = "application/x-gedcomx-v1+json"
+
+const (
+	maleType        = "http://gedcomx.org/Male"
+	femaleType      = "http://gedcomx.org/Female"
+	birthType       = "http://gedcomx.org/Birth"
+	deathType       = "http://gedcomx.org/Death"
+	parentChildType = "http://gedcomx.org/ParentChild"
+	coupleType      = "http://gedcomx.org/Couple"
+)
+
+// lineageTypeByLinkType is the lineage fact of the ParentChild relationships of each link type. GEDCOM X has no
+// lineage for donors, so it is written as a data URI, the way GEDCOM X extends its enumerations.
+var lineageTypeByLinkType = map[domain.LinkType]string{
+	domain.BiologicalLinkType: "http://gedcomx.org/BiologicalParent",
+	domain.AdoptiveLinkType:   "http://gedcomx.org/AdoptiveParent",
+	domain.FosterLinkType:     "http://gedcomx.org/FosterParent",
+	domain.StepLinkType:       "http://gedcomx.org/StepParent",
+	domain.SurrogateLinkType:  "http://gedcomx.org/SurrogateParent",
+	domain.DonorLinkType:      "data:,DonorParent",
+}
+
+var linkTypeByLineageType = func() map[string]domain.LinkType {
+	linkTypeByLineageType := make(map[string]domain.LinkType, len(lineageTypeByLinkType))
+	for linkType, lineageType := range lineageTypeByLinkType {
+		linkTypeByLineageType[lineageType] = linkType
+	}
+
+	return linkTypeByLineageType
+}()
+
+type ResourceReference struct {
+	Resource string `json:"resource"`
+}
+
+type Gender struct {
+	Type string `json:"type"`
+}
+
+type NameForm struct {
+	FullText string `json:"fullText"`
+}
+
+type Name struct {
+	NameForms []NameForm `json:"nameForms"`
+}
+
+type Date struct {
+	Original string `json:"original"`
+}
+
+type Place struct {
+	Original string `json:"original"`
+}
+
+type Fact struct {
+	Type  string `json:"type"`
+	Date  *Date  `json:"date,omitempty"`
+	Place *Place `json:"place,omitempty"`
+}
+
+type Person struct {
+	ID     string  `json:"id"`
+	Gender *Gender `json:"gender,omitempty"`
+	Names  []Name  `json:"names,omitempty"`
+	Facts  []Fact  `json:"facts,omitempty"`
+}
+
+type Relationship struct {
+	Type    string            `json:"type"`
+	Person1 ResourceReference `json:"person1"`
+	Person2 ResourceReference `json:"person2"`
+	Facts   []Fact            `json:"facts,omitempty"`
+}
+
+// Document is the subset of a GEDCOM X JSON document this application knows how to read and write.
+type Document struct {
+	Persons       []Person       `json:"persons"`
+	Relationships []Relationship `json:"relationships"`
+}
+
+func referenceTo(personID string) ResourceReference {
+	return ResourceReference{Resource: "#" + personID}
+}
+
+func buildFact(factType string, lifeEvent *domain.LifeEvent) Fact {
+	fact := Fact{Type: factType}
+	if lifeEvent.Date != "" {
+		fact.Date = &Date{Original: lifeEvent.Date}
+	}
+	if lifeEvent.Place != "" {
+		fact.Place = &Place{Original: lifeEvent.Place}
+	}
+
+	return fact
+}
+
+var genderTypeByGender = map[domain.GenderType]string{domain.Male: maleType, domain.Female: femaleType}
+
+// Encode writes persons as a GEDCOM X JSON document, keeping their ids. Links that are not biological get the lineage
+// fact of their link type. Couples are written for the biological parents of each child and for the spouses of each
+// person.
+func Encode(writer io.Writer, persons []domain.Person) error {
+	persons = append([]domain.Person{}, persons...)
+	sort.Slice(persons, func(i, j int) bool {
+		return persons[i].ID < persons[j].ID
+	})
+
+	exported := make(map[string]bool, len(persons))
+	for _, person := range persons {
+		exported[person.ID] = true
+	}
+
+	document := Document{Persons: []Person{}, Relationships: []Relationship{}}
+	couplesAdded := make(map[string]bool)
+	addCouple := func(personAID string, personBID string) {
+		if personAID > personBID {
+			personAID, personBID = personBID, personAID
+		}
+
+		if couplesAdded[personAID+"+"+personBID] {
+			return
+		}
+
+		couplesAdded[personAID+"+"+personBID] = true
+		document.Relationships = append(document.Relationships, Relationship{Type: coupleType, Person1: referenceTo(personAID), Person2: referenceTo(personBID)})
+	}
+
+	for _, person := range persons {
+		gedcomxPerson := Person{ID: person.ID, Names: []Name{{NameForms: []NameForm{{FullText: person.Name}}}}}
+		if genderType, ok := genderTypeByGender[person.Gender]; ok {
+			gedcomxPerson.Gender = &Gender{Type: genderType}
+		}
+		if person.Birth != nil {
+			gedcomxPerson.Facts = append(gedcomxPerson.Facts, buildFact(birthType, person.Birth))
+		}
+		if person.Death != nil {
+			gedcomxPerson.Facts = append(gedcomxPerson.Facts, buildFact(deathType, person.Death))
+		}
+		document.Persons = append(document.Persons, gedcomxPerson)
+
+		var parentIDS []string
+		for _, parent := range person.Parents {
+			if exported[parent.ID] {
+				parentIDS = append(parentIDS, parent.ID)
+			}
+		}
+		sort.Strings(parentIDS)
+
+		var biologicalParentIDS []string
+		for _, parentID := range parentIDS {
+			relationship := Relationship{Type: parentChildType, Person1: referenceTo(parentID), Person2: referenceTo(person.ID)}
+			if linkType := person.ParentLinkType(parentID); linkType == domain.BiologicalLinkType {
+				biologicalParentIDS = append(biologicalParentIDS, parentID)
+			} else {
+				relationship.Facts = []Fact{{Type: lineageTypeByLinkType[linkType]}}
+			}
+
+			document.Relationships = append(document.Relationships, relationship)
+		}
+		if len(biologicalParentIDS) == 2 {
+			addCouple(biologicalParentIDS[0], biologicalParentIDS[1])
+		}
+	}
+
+	for _, person := range persons {
+		for _, spouse := range person.Spouses {
+			if exported[spouse.ID] {
+				addCouple(person.ID, spouse.ID)
+			}
+		}
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(document)
+}
+
+func Decode(reader io.Reader) (*Document, error) {
+	var document Document
+	if err := json.NewDecoder(reader).Decode(&document); err != nil {
+		return nil, errors.NewApplicationError(fmt.Sprintf("invalid gedcomx document: %s", err.Error()), errors.InvalidGedcomErrorCode)
+	}
+
+	return &document, nil
+}
+
+func parseLifeEvent(fact Fact) *domain.LifeEvent {
+	lifeEvent := domain.LifeEvent{}
+	if fact.Date != nil {
+		lifeEvent.Date = strings.TrimSpace(fact.Date.Original)
+	}
+	if fact.Place != nil {
+		lifeEvent.Place = strings.TrimSpace(fact.Place.Original)
+	}
+
+	if lifeEvent.Date == "" && lifeEvent.Place == "" {
+		return nil
+	}
+
+	return &lifeEvent
+}
+
+func buildDomainPerson(gedcomxPerson Person, report *gedcom.ImportReport) domain.Person {
+	person := domain.Person{}
+	if len(gedcomxPerson.Names) > 0 && len(gedcomxPerson.Names[0].NameForms) > 0 {
+		person.Name = strings.Join(strings.Fields(gedcomxPerson.Names[0].NameForms[0].FullText), " ")
+	}
+
+	if gedcomxPerson.Gender != nil {
+		switch gedcomxPerson.Gender.Type {
+		case maleType:
+			person.Gender = domain.Male
+		case femaleType:
+			person.Gender = domain.Female
+		}
+	}
+
+	for _, fact := range gedcomxPerson.Facts {
+		switch fact.Type {
+		case birthType:
+			person.Birth = parseLifeEvent(fact)
+		case deathType:
+			person.Death = parseLifeEvent(fact)
+		default:
+			report.UnsupportedTags["facts."+fact.Type]++
+		}
+	}
+
+	return person
+}
+
+// parseLinkType reads the link type from the lineage facts of a ParentChild relationship, biological when there is
+// none. Other facts are reported.
+func parseLinkType(relationship Relationship, report *gedcom.ImportReport) domain.LinkType {
+	linkType := domain.BiologicalLinkType
+	for _, fact := range relationship.Facts {
+		factLinkType, ok := linkTypeByLineageType[fact.Type]
+		if !ok {
+			report.UnsupportedTags["relationships.facts."+fact.Type]++
+			continue
+		}
+		linkType = factLinkType
+	}
+
+	return linkType
+}
+
+func biologicalParentsCount(person domain.Person) int {
+	count := 0
+	for _, parent := range person.Parents {
+		if person.ParentLinkType(parent.ID) == domain.BiologicalLinkType {
+			count++
+		}
+	}
+
+	return count
+}
+
+func skip(report *gedcom.ImportReport, id string, tag string, reason string) {
+	report.Skipped = append(report.Skipped, gedcom.SkippedRecord{XRef: id, Tag: tag, Reason: reason})
+}
+
+// BuildPersonBatch maps the persons of the document to a batch with their ids as temporary ids. ParentChild
+// relationships become parent links with the link type of their lineage fact, and only biological ones count for the
+// two parents limit. Couple relationships are only reported when the couple has no child in common.
+func BuildPersonBatch(document Document) (domain.PersonBatch, gedcom.ImportReport) {
+	report := gedcom.ImportReport{Version: "gedcomx", UnsupportedTags: map[string]int{}}
+
+	var personIDS []string
+	personsByID := make(map[string]*domain.Person)
+	for _, gedcomxPerson := range document.Persons {
+		if gedcomxPerson.ID == "" {
+			skip(&report, "", "person", "person has no id")
+			continue
+		}
+
+		if _, ok := personsByID[gedcomxPerson.ID]; ok {
+			skip(&report, gedcomxPerson.ID, "person", fmt.Sprintf("person %s is duplicated", gedcomxPerson.ID))
+			continue
+		}
+
+		person := buildDomainPerson(gedcomxPerson, &report)
+		if len(person.Name) < 2 {
+			skip(&report, gedcomxPerson.ID, "person", "name must have more than 1 character")
+			continue
+		}
+
+		if !person.Gender.IsValid() {
+			skip(&report, gedcomxPerson.ID, "person", "gender must be male or female")
+			continue
+		}
+
+		personsByID[gedcomxPerson.ID] = &person
+		personIDS = append(personIDS, gedcomxPerson.ID)
+	}
+
+	var couples []Relationship
+	for _, relationship := range document.Relationships {
+		person1ID := strings.TrimPrefix(relationship.Person1.Resource, "#")
+		person2ID := strings.TrimPrefix(relationship.Person2.Resource, "#")
+
+		switch relationship.Type {
+		case parentChildType, coupleType:
+		default:
+			report.UnsupportedTags["relationships."+relationship.Type]++
+			continue
+		}
+
+		_, parentOK := personsByID[person1ID]
+		children, childrenOK := personsByID[person2ID]
+		if !parentOK || !childrenOK {
+			skip(&report, person1ID+"+"+person2ID, relationship.Type, "relationship references a person that was not imported")
+			continue
+		}
+
+		if relationship.Type == coupleType {
+			couples = append(couples, relationship)
+			continue
+		}
+
+		alreadyParent := person1ID == person2ID
+		for _, currentParent := range children.Parents {
+			alreadyParent = alreadyParent || currentParent.ID == person1ID
+		}
+		if alreadyParent {
+			continue
+		}
+
+		linkType := parseLinkType(relationship, &report)
+		if linkType == domain.BiologicalLinkType && biologicalParentsCount(*children) == 2 {
+			skip(&report, person1ID+"+"+person2ID, relationship.Type, fmt.Sprintf("person %s already has two biological parents", person2ID))
+			continue
+		}
+
+		children.Parents = append(children.Parents, &domain.Person{ID: person1ID})
+		children.SetParentLinkType(person1ID, linkType)
+	}
+
+	for _, couple := range couples {
+		person1ID := strings.TrimPrefix(couple.Person1.Resource, "#")
+		person2ID := strings.TrimPrefix(couple.Person2.Resource, "#")
+		if !haveChildInCommon(personsByID, person1ID, person2ID) {
+			skip(&report, person1ID+"+"+person2ID, couple.Type, "couple has no imported children, spouses are only linked through their children")
+			continue
+		}
+
+		report.Families++
+	}
+
+	var batch domain.PersonBatch
+	for _, personID := range personIDS {
+		batch = append(batch, domain.BatchPerson{TempID: personID, Person: *personsByID[personID]})
+	}
+	report.Persons = len(batch)
+
+	return batch, report
+}
+
+func haveChildInCommon(personsByID map[string]*domain.Person, personAID string, personBID string) bool {
+	for _, person := range personsByID {
+		isChildOfA, isChildOfB := false, false
+		for _, parent := range person.Parents {
+			isChildOfA = isChildOfA || parent.ID == personAID
+			isChildOfB = isChildOfB || parent.ID == personBID
+		}
+
+		if isChildOfA && isChildOfB {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,146 @@
+package gedcomx
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoundTrip(t *testing.T) {
+	luis := &domain.Person{ID: "IDLuis", Name: "Luis Bittencourt", Gender: domain.Male, Death: &domain.LifeEvent{Date: "2020"}}
+	dayse := &domain.Person{ID: "IDDayse", Name: "Dayse Bittencourt", Gender: domain.Female, Birth: &domain.LifeEvent{Date: "ABT 1962", Place: "Porto Alegre"}}
+	caio := &domain.Person{ID: "IDCaio", Name: "Caio Bittencourt", Gender: domain.Male, Parents: []*domain.Person{luis, dayse}}
+	luis.Children = []*domain.Person{caio}
+	dayse.Children = []*domain.Person{caio}
+	luis.Spouses = []*domain.Person{dayse}
+	dayse.Spouses = []*domain.Person{luis}
+
+	var document bytes.Buffer
+	assert.NoError(t, Encode(&document, []domain.Person{*caio, *luis, *dayse}))
+
+	decoded, err := Decode(&document)
+	assert.NoError(t, err)
+	assert.Len(t, decoded.Persons, 3)
+	// one ParentChild for each parent and a single Couple for the parents
+	assert.Len(t, decoded.Relationships, 3)
+
+	batch, report := BuildPersonBatch(*decoded)
+	assert.Empty(t, report.Skipped)
+	assert.Empty(t, report.UnsupportedTags)
+	assert.Equal(t, 1, report.Families)
+	assert.Equal(t, domain.PersonBatch{
+		{TempID: "IDCaio", Person: domain.Person{Name: caio.Name, Gender: caio.Gender, Parents: []*domain.Person{{ID: "IDDayse"}, {ID: "IDLuis"}}}},
+		{TempID: "IDDayse", Person: domain.Person{Name: dayse.Name, Gender: dayse.Gender, Birth: dayse.Birth}},
+		{TempID: "IDLuis", Person: domain.Person{Name: luis.Name, Gender: luis.Gender, Death: luis.Death}},
+	}, batch)
+}
+
+func TestRoundTripWithLinkTypes(t *testing.T) {
+	luis := &domain.Person{ID: "IDLuis", Name: "Luis Bittencourt", Gender: domain.Male}
+	dayse := &domain.Person{ID: "IDDayse", Name: "Dayse Bittencourt", Gender: domain.Female}
+	tunico := &domain.Person{ID: "IDTunico", Name: "Tunico", Gender: domain.Male}
+	ana := &domain.Person{ID: "IDAna", Name: "Ana", Gender: domain.Female}
+	caio := &domain.Person{ID: "IDCaio", Name: "Caio Bittencourt", Gender: domain.Male, Parents: []*domain.Person{luis, dayse, tunico, ana}}
+	caio.SetParentLinkType(tunico.ID, domain.AdoptiveLinkType)
+	caio.SetParentLinkType(ana.ID, domain.DonorLinkType)
+
+	var document bytes.Buffer
+	assert.NoError(t, Encode(&document, []domain.Person{*caio, *luis, *dayse, *tunico, *ana}))
+
+	decoded, err := Decode(&document)
+	assert.NoError(t, err)
+	// one ParentChild for each parent and a single Couple for the biological parents
+	assert.Len(t, decoded.Relationships, 5)
+
+	batch, report := BuildPersonBatch(*decoded)
+	assert.Empty(t, report.Skipped)
+	assert.Empty(t, report.UnsupportedTags)
+	assert.Equal(t, domain.Person{
+		Name:            caio.Name,
+		Gender:          caio.Gender,
+		Parents:         []*domain.Person{{ID: "IDAna"}, {ID: "IDDayse"}, {ID: "IDLuis"}, {ID: "IDTunico"}},
+		ParentLinkTypes: map[string]domain.LinkType{"IDTunico": domain.AdoptiveLinkType, "IDAna": domain.DonorLinkType},
+	}, batch[1].Person)
+}
+
+func TestBuildPersonBatch(t *testing.T) {
+	type testArgs struct {
+		testName                string
+		document                string
+		expectedPersons         int
+		expectedSkippedIDs      []string
+		expectedUnsupportedTags map[string]int
+	}
+
+	tests := []testArgs{
+		{
+			testName: "should skip persons without gender and their relationships",
+			document: `{"persons": [
+				{"id": "P1", "names": [{"nameForms": [{"fullText": "Luis"}]}]},
+				{"id": "P2", "gender": {"type": "http://gedcomx.org/Male"}, "names": [{"nameForms": [{"fullText": "Caio"}]}]}
+			], "relationships": [
+				{"type": "http://gedcomx.org/ParentChild", "person1": {"resource": "#P1"}, "person2": {"resource": "#P2"}}
+			]}`,
+			expectedPersons:         1,
+			expectedSkippedIDs:      []string{"P1", "P1+P2"},
+			expectedUnsupportedTags: map[string]int{},
+		},
+		{
+			testName: "should report unsupported facts and relationships and couples without children",
+			document: `{"persons": [
+				{"id": "P1", "gender": {"type": "http://gedcomx.org/Male"}, "names": [{"nameForms": [{"fullText": "Luis"}]}], "facts": [{"type": "http://gedcomx.org/Occupation"}]},
+				{"id": "P2", "gender": {"type": "http://gedcomx.org/Female"}, "names": [{"nameForms": [{"fullText": "Dayse"}]}]}
+			], "relationships": [
+				{"type": "http://gedcomx.org/Couple", "person1": {"resource": "#P1"}, "person2": {"resource": "#P2"}},
+				{"type": "http://gedcomx.org/EnslavedBy", "person1": {"resource": "#P1"}, "person2": {"resource": "#P2"}}
+			]}`,
+			expectedPersons:    2,
+			expectedSkippedIDs: []string{"P1+P2"},
+			expectedUnsupportedTags: map[string]int{
+				"facts.http://gedcomx.org/Occupation":         1,
+				"relationships.http://gedcomx.org/EnslavedBy": 1,
+			},
+		},
+		{
+			testName: "should skip a third biological parent and report unknown lineages",
+			document: `{"persons": [
+				{"id": "P1", "gender": {"type": "http://gedcomx.org/Male"}, "names": [{"nameForms": [{"fullText": "Luis"}]}]},
+				{"id": "P2", "gender": {"type": "http://gedcomx.org/Female"}, "names": [{"nameForms": [{"fullText": "Dayse"}]}]},
+				{"id": "P3", "gender": {"type": "http://gedcomx.org/Male"}, "names": [{"nameForms": [{"fullText": "Tunico"}]}]},
+				{"id": "P4", "gender": {"type": "http://gedcomx.org/Male"}, "names": [{"nameForms": [{"fullText": "Caio"}]}]}
+			], "relationships": [
+				{"type": "http://gedcomx.org/ParentChild", "person1": {"resource": "#P1"}, "person2": {"resource": "#P4"}},
+				{"type": "http://gedcomx.org/ParentChild", "person1": {"resource": "#P2"}, "person2": {"resource": "#P4"}, "facts": [{"type": "http://gedcomx.org/BiologicalParent"}]},
+				{"type": "http://gedcomx.org/ParentChild", "person1": {"resource": "#P3"}, "person2": {"resource": "#P4"}, "facts": [{"type": "http://gedcomx.org/GuardianParent"}]}
+			]}`,
+			expectedPersons:    4,
+			expectedSkippedIDs: []string{"P3+P4"},
+			expectedUnsupportedTags: map[string]int{
+				"relationships.facts.http://gedcomx.org/GuardianParent": 1,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(tt testArgs) func(t *testing.T) {
+			return func(t *testing.T) {
+				document, err := Decode(strings.NewReader(tt.document))
+				assert.NoError(t, err)
+
+				batch, report := BuildPersonBatch(*document)
+
+				assert.Len(t, batch, tt.expectedPersons)
+				assert.Equal(t, tt.expectedUnsupportedTags, report.UnsupportedTags)
+
+				var skippedIDs []string
+				for _, skipped := range report.Skipped {
+					skippedIDs = append(skippedIDs, skipped.XRef)
+				}
+				assert.Equal(t, tt.expectedSkippedIDs, skippedIDs)
+			}
+		}(tt))
+	}
+}
@@ -0,0 +1,57 @@
+package graph
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/CaioBittencourt/arvore-genealogica/errors"
+	"github.com/CaioBittencourt/arvore-genealogica/service"
+	"github.com/graph-gophers/graphql-go"
+	log "github.com/sirupsen/logrus"
+)
+
+//go:embed schema.graphql
+var Schema string
+
+// MaxDepth bounds nested traversal, so a single query can't walk a whole tree one generation at a time.
+const MaxDepth = 32
+
+// NewSchema parses Schema with resolvers backed by personService. Queries must run on a context returned by
+// NewRequestContext.
+func NewSchema(personService service.PersonService) *graphql.Schema {
+	return graphql.MustParseSchema(Schema, &rootResolver{personService: personService}, graphql.MaxDepth(MaxDepth))
+}
+
+// NewRequestContext gives each request its own person cache, so persons are never served across requests or trees.
+func NewRequestContext(ctx context.Context, personService service.PersonService) context.Context {
+	return contextWithPersonLoader(ctx, newPersonLoader(personService))
+}
+
+// resolverError exposes the code of application errors on the extensions of the GraphQL error, other errors are
+// logged and hidden like on the REST routes.
+type resolverError struct {
+	message string
+	code    errors.ApplicationErrorCode
+}
+
+func (re resolverError) Error() string {
+	return re.message
+}
+
+func (re resolverError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": string(re.code)}
+}
+
+func buildResolverError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	applicationError, ok := errors.CastToApplicationError(err)
+	if !ok {
+		log.WithError(err).Error("graph: resolver failed")
+		return resolverError{message: "Internal Server Error"}
+	}
+
+	return resolverError{message: applicationError.Messsage, code: applicationError.Code}
+}
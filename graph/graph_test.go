@@ -0,0 +1,274 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+	"github.com/CaioBittencourt/arvore-genealogica/errors"
+	"github.com/CaioBittencourt/arvore-genealogica/service"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakePersonService keeps persons with only the IDs of their relatives, like the person collection, and counts
+// every batch read.
+type fakePersonService struct {
+	service.PersonService
+	personsByID   map[string]domain.Person
+	loadedBatches [][]string
+}
+
+func newFakePersonService(persons ...domain.Person) *fakePersonService {
+	personsByID := map[string]domain.Person{}
+	for _, person := range persons {
+		personsByID[person.ID] = person
+	}
+
+	return &fakePersonService{personsByID: personsByID}
+}
+
+func (fps *fakePersonService) GetPersonsByIDS(ctx context.Context, personIDS []string) ([]domain.Person, error) {
+	fps.loadedBatches = append(fps.loadedBatches, personIDS)
+
+	var persons []domain.Person
+	for _, personID := range personIDS {
+		person, ok := fps.personsByID[personID]
+		if !ok {
+			continue
+		}
+
+		withRelatives := person
+		withRelatives.Parents, withRelatives.Children = nil, nil
+		for _, parent := range person.Parents {
+			storedParent := fps.personsByID[parent.ID]
+			withRelatives.Parents = append(withRelatives.Parents, &storedParent)
+		}
+		for _, children := range person.Children {
+			storedChildren := fps.personsByID[children.ID]
+			withRelatives.Children = append(withRelatives.Children, &storedChildren)
+		}
+
+		persons = append(persons, withRelatives)
+	}
+
+	return persons, nil
+}
+
+func (fps *fakePersonService) Store(ctx context.Context, person domain.Person) (*domain.Person, error) {
+	for _, relative := range append(append([]*domain.Person{}, person.Parents...), person.Children...) {
+		if fps.personsByID[relative.ID].Version != relative.Version {
+			return nil, errors.NewApplicationError("version mismatch", errors.PersonVersionMismatchErrorCode)
+		}
+	}
+
+	person.ID = "IDNew"
+	person.Version = 1
+	fps.personsByID[person.ID] = person
+	for _, parent := range person.Parents {
+		storedParent := fps.personsByID[parent.ID]
+		storedParent.Children = append(storedParent.Children, &domain.Person{ID: person.ID})
+		storedParent.Version++
+		fps.personsByID[parent.ID] = storedParent
+	}
+
+	return &person, nil
+}
+
+// linkFollowed tells whether the parent link from personAID to personBID is one of linkTypes.
+func (fps *fakePersonService) linkFollowed(personAID string, personBID string, linkTypes []domain.LinkType) bool {
+	if len(linkTypes) == 0 {
+		return true
+	}
+
+	for _, linkType := range linkTypes {
+		if fps.personsByID[personAID].ParentLinkType(personBID) == linkType {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (fps *fakePersonService) BaconsNumber(ctx context.Context, personAID string, personBID string, options domain.BaconsNumberOptions) (*uint, error) {
+	if personBID == "IDUnexisting" || !fps.linkFollowed(personAID, personBID, options.LinkTypes) {
+		return nil, errors.NewApplicationError("persons dont belong to eachothers graph", errors.PersonNotFoundInGraph)
+	}
+
+	baconsNumber := uint(2)
+	return &baconsNumber, nil
+}
+
+func (fps *fakePersonService) GetRelationshipBetweenPersons(ctx context.Context, personAID string, personBID string, linkTypes []domain.LinkType) (*domain.Person, error) {
+	if !fps.linkFollowed(personAID, personBID, linkTypes) {
+		return nil, errors.NewApplicationError("persons dont belong to eachothers graph", errors.PersonNotFoundInGraph)
+	}
+
+	personB := fps.personsByID[personBID]
+	return &domain.Person{
+		ID: personAID,
+		Relationships: map[string]domain.Relationship{
+			personBID: {
+				Person:       domain.RelationshipPerson{ID: personB.ID, Name: personB.Name, Gender: personB.Gender},
+				Relationship: domain.ParentRelashionship,
+				LinkType:     fps.personsByID[personAID].ParentLinkTypes[personBID],
+			},
+		},
+	}, nil
+}
+
+func stub(id string) *domain.Person {
+	return &domain.Person{ID: id}
+}
+
+// family has four generations: Zeze is the mother of Luis, who has Caio and Vini with Dayse, and Caio is the father
+// of Bento. Only Luis is stored with the father role, for Caio, and Bento is adopted by Caio.
+func family() *fakePersonService {
+	return newFakePersonService(
+		domain.Person{ID: "IDZeze", Name: "Zeze", Gender: domain.Female, Children: []*domain.Person{stub("IDLuis")}, Version: 1},
+		domain.Person{ID: "IDLuis", Name: "Luis", Gender: domain.Male, Parents: []*domain.Person{stub("IDZeze")}, Children: []*domain.Person{stub("IDCaio"), stub("IDVini")}, Version: 1},
+		domain.Person{ID: "IDDayse", Name: "Dayse", Gender: domain.Female, Children: []*domain.Person{stub("IDCaio"), stub("IDVini")}, Version: 1},
+		domain.Person{ID: "IDCaio", Name: "Caio", Gender: domain.Male, Birth: &domain.LifeEvent{Date: "1 JAN 1990"}, Parents: []*domain.Person{stub("IDLuis"), stub("IDDayse")}, ParentRoles: map[string]domain.ParentRoleType{"IDLuis": domain.FatherParentRole}, Children: []*domain.Person{stub("IDBento")}, Version: 3},
+		domain.Person{ID: "IDVini", Name: "Vini", Gender: domain.Male, Parents: []*domain.Person{stub("IDLuis"), stub("IDDayse")}, Version: 1},
+		domain.Person{ID: "IDBento", Name: "Bento", Gender: domain.Male, Parents: []*domain.Person{stub("IDCaio")}, ParentLinkTypes: map[string]domain.LinkType{"IDCaio": domain.AdoptiveLinkType}, Version: 1},
+	)
+}
+
+func exec(t *testing.T, personService *fakePersonService, query string, variables map[string]interface{}) (map[string]interface{}, []string) {
+	schema := NewSchema(personService)
+	response := schema.Exec(NewRequestContext(context.Background(), personService), query, "", variables)
+
+	var errorCodes []string
+	for _, queryError := range response.Errors {
+		code, _ := queryError.Extensions["code"].(string)
+		errorCodes = append(errorCodes, code)
+	}
+
+	var data map[string]interface{}
+	if len(response.Data) > 0 {
+		assert.NoError(t, json.Unmarshal(response.Data, &data))
+	}
+
+	return data, errorCodes
+}
+
+func TestQuery(t *testing.T) {
+	type testArgs struct {
+		testName           string
+		query              string
+		expectedData       string
+		expectedErrorCodes []string
+		expectedBatches    int
+	}
+
+	tests := []testArgs{
+		{
+			testName:        "should resolve a person with its life events",
+			query:           `{ person(id: "IDCaio") { id name gender version birth { date place } death { date } } }`,
+			expectedData:    `{"person": {"id": "IDCaio", "name": "Caio", "gender": "male", "version": 3, "birth": {"date": "1 JAN 1990", "place": null}, "death": null}}`,
+			expectedBatches: 1,
+		},
+		{
+			testName:        "should resolve null for an unexisting person",
+			query:           `{ person(id: "IDUnexisting") { id } }`,
+			expectedData:    `{"person": null}`,
+			expectedBatches: 1,
+		},
+		{
+			testName:        "should resolve parents and children from the first read",
+			query:           `{ person(id: "IDCaio") { parents { name } children { name } } }`,
+			expectedData:    `{"person": {"parents": [{"name": "Luis"}, {"name": "Dayse"}], "children": [{"name": "Bento"}]}}`,
+			expectedBatches: 1,
+		},
+		{
+			testName:        "should resolve only the parents with the mother or father role",
+			query:           `{ person(id: "IDCaio") { mother { name } father { name } } }`,
+			expectedData:    `{"person": {"mother": null, "father": {"name": "Luis"}}}`,
+			expectedBatches: 1,
+		},
+		{
+			testName:        "should read a whole generation in a single batch",
+			query:           `{ person(id: "IDBento") { parents { parents { parents { name } children { name } } } } }`,
+			expectedData:    `{"person": {"parents": [{"parents": [{"parents": [{"name": "Zeze"}], "children": [{"name": "Caio"}, {"name": "Vini"}]}, {"parents": [], "children": [{"name": "Caio"}, {"name": "Vini"}]}]}]}}`,
+			expectedBatches: 2,
+		},
+		{
+			testName:        "should resolve spouses from the other parents of the children",
+			query:           `{ person(id: "IDLuis") { spouses { name } } }`,
+			expectedData:    `{"person": {"spouses": [{"name": "Dayse"}]}}`,
+			expectedBatches: 2,
+		},
+		{
+			testName:        "should resolve many persons at once",
+			query:           `{ persons(ids: ["IDVini", "IDUnexisting", "IDZeze"]) { name } }`,
+			expectedData:    `{"persons": [{"name": "Vini"}, {"name": "Zeze"}]}`,
+			expectedBatches: 1,
+		},
+		{
+			testName:        "should resolve relationships and bacon's number",
+			query:           `{ person(id: "IDCaio") { relationships(to: "IDLuis") { relationship person { name } } baconNumber(to: "IDLuis") } }`,
+			expectedData:    `{"person": {"relationships": [{"relationship": "parent", "person": {"name": "Luis"}}], "baconNumber": 2}}`,
+			expectedBatches: 1,
+		},
+		{
+			testName:        "should resolve the link type of relationships and follow only the link types asked",
+			query:           `{ person(id: "IDBento") { relationships(to: "IDCaio") { relationship linkType } baconNumber(to: "IDCaio", linkTypes: [adoptive, foster]) } }`,
+			expectedData:    `{"person": {"relationships": [{"relationship": "parent", "linkType": "adoptive"}], "baconNumber": 2}}`,
+			expectedBatches: 1,
+		},
+		{
+			testName:           "should not find relationships through link types that were not asked",
+			query:              `{ person(id: "IDBento") { relationships(to: "IDCaio", linkTypes: [biological]) { relationship } } }`,
+			expectedData:       `{"person": null}`,
+			expectedErrorCodes: []string{string(errors.PersonNotFoundInGraph)},
+			expectedBatches:    1,
+		},
+		{
+			testName:           "should expose the application error code",
+			query:              `{ person(id: "IDCaio") { baconNumber(to: "IDUnexisting") } }`,
+			expectedData:       `{"person": null}`,
+			expectedErrorCodes: []string{string(errors.PersonNotFoundInGraph)},
+			expectedBatches:    1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(tt testArgs) func(t *testing.T) {
+			return func(t *testing.T) {
+				personService := family()
+				data, errorCodes := exec(t, personService, tt.query, nil)
+
+				encodedData, err := json.Marshal(data)
+				assert.NoError(t, err)
+				assert.JSONEq(t, tt.expectedData, string(encodedData))
+				assert.Equal(t, tt.expectedErrorCodes, errorCodes)
+				assert.Len(t, personService.loadedBatches, tt.expectedBatches)
+			}
+		}(tt))
+	}
+}
+
+func TestCreatePerson(t *testing.T) {
+	query := `mutation($input: CreatePersonInput!) { createPerson(input: $input) { id name father { name } parents { name version children { name } } } }`
+
+	t.Run("should store the person and read its parents again", func(t *testing.T) {
+		personService := family()
+		data, errorCodes := exec(t, personService, query, map[string]interface{}{
+			"input": map[string]interface{}{"name": "Maria", "gender": "female", "father": map[string]interface{}{"id": "IDCaio", "version": 3}},
+		})
+
+		assert.Empty(t, errorCodes)
+		encodedData, err := json.Marshal(data)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"createPerson": {"id": "IDNew", "name": "Maria", "father": {"name": "Caio"}, "parents": [{"name": "Caio", "version": 4, "children": [{"name": "Bento"}, {"name": "Maria"}]}]}}`, string(encodedData))
+	})
+
+	t.Run("should fail when a relative is not on the version sent", func(t *testing.T) {
+		personService := family()
+		_, errorCodes := exec(t, personService, query, map[string]interface{}{
+			"input": map[string]interface{}{"name": "Maria", "gender": "female", "father": map[string]interface{}{"id": "IDCaio", "version": 1}},
+		})
+
+		assert.Equal(t, []string{string(errors.PersonVersionMismatchErrorCode)}, errorCodes)
+	})
+}
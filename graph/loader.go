@@ -0,0 +1,146 @@
+package graph
+
+import (
+	"context"
+	"sync"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+	"github.com/CaioBittencourt/arvore-genealogica/service"
+)
+
+// personLoader caches every person read during a request. Persons come with their parents and children, so those
+// are cached too, and the relatives of every person handed to a resolver are queued, so the next cache miss loads
+// the whole next generation with a single GetPersonsByIDS call instead of one call per person.
+type personLoader struct {
+	personService service.PersonService
+
+	mutex         sync.Mutex
+	personsByID   map[string]*domain.Person
+	notFoundIDS   map[string]bool
+	queuedIDS     map[string]bool
+	queuedIDOrder []string
+}
+
+func newPersonLoader(personService service.PersonService) *personLoader {
+	return &personLoader{
+		personService: personService,
+		personsByID:   map[string]*domain.Person{},
+		notFoundIDS:   map[string]bool{},
+		queuedIDS:     map[string]bool{},
+	}
+}
+
+type personLoaderContextKey struct{}
+
+func contextWithPersonLoader(ctx context.Context, loader *personLoader) context.Context {
+	return context.WithValue(ctx, personLoaderContextKey{}, loader)
+}
+
+func personLoaderFromContext(ctx context.Context) *personLoader {
+	loader, _ := ctx.Value(personLoaderContextKey{}).(*personLoader)
+	return loader
+}
+
+func (pl *personLoader) isKnown(personID string) bool {
+	_, cached := pl.personsByID[personID]
+	return cached || pl.notFoundIDS[personID]
+}
+
+func (pl *personLoader) cache(person domain.Person) {
+	pl.personsByID[person.ID] = &person
+	delete(pl.queuedIDS, person.ID)
+}
+
+// queueRelatives must be called with the mutex held.
+func (pl *personLoader) queueRelatives(person domain.Person) {
+	for _, relative := range append(append([]*domain.Person{}, person.Parents...), person.Children...) {
+		if pl.isKnown(relative.ID) || pl.queuedIDS[relative.ID] {
+			continue
+		}
+
+		pl.queuedIDS[relative.ID] = true
+		pl.queuedIDOrder = append(pl.queuedIDOrder, relative.ID)
+	}
+}
+
+// Load returns the persons found among personIDS, in the same order. Persons with only their ID filled on Parents
+// and Children are enough for resolvers, the relatives are loaded when asked for.
+func (pl *personLoader) Load(ctx context.Context, personIDS []string) ([]domain.Person, error) {
+	pl.mutex.Lock()
+	defer pl.mutex.Unlock()
+
+	var idsToLoad []string
+	missing := map[string]bool{}
+	for _, personID := range personIDS {
+		if !pl.isKnown(personID) && !missing[personID] {
+			missing[personID] = true
+			idsToLoad = append(idsToLoad, personID)
+		}
+	}
+
+	if len(idsToLoad) > 0 {
+		for _, personID := range pl.queuedIDOrder {
+			if pl.queuedIDS[personID] && !missing[personID] {
+				missing[personID] = true
+				idsToLoad = append(idsToLoad, personID)
+			}
+		}
+
+		persons, err := pl.personService.GetPersonsByIDS(ctx, idsToLoad)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, person := range persons {
+			for _, relative := range append(append([]*domain.Person{}, person.Parents...), person.Children...) {
+				if relative.Name != "" && !pl.isKnown(relative.ID) {
+					pl.cache(*relative)
+				}
+			}
+
+			pl.cache(person)
+		}
+
+		for _, personID := range idsToLoad {
+			if _, ok := pl.personsByID[personID]; !ok {
+				pl.notFoundIDS[personID] = true
+			}
+		}
+
+		pl.queuedIDS = map[string]bool{}
+		pl.queuedIDOrder = nil
+	}
+
+	var persons []domain.Person
+	for _, personID := range personIDS {
+		person, ok := pl.personsByID[personID]
+		if !ok {
+			continue
+		}
+
+		pl.queueRelatives(*person)
+		persons = append(persons, *person)
+	}
+
+	return persons, nil
+}
+
+func (pl *personLoader) LoadOne(ctx context.Context, personID string) (*domain.Person, error) {
+	persons, err := pl.Load(ctx, []string{personID})
+	if err != nil || len(persons) == 0 {
+		return nil, err
+	}
+
+	return &persons[0], nil
+}
+
+// Forget drops persons changed by a mutation, so they are read again with their new relatives and version.
+func (pl *personLoader) Forget(personIDS []string) {
+	pl.mutex.Lock()
+	defer pl.mutex.Unlock()
+
+	for _, personID := range personIDS {
+		delete(pl.personsByID, personID)
+		delete(pl.notFoundIDS, personID)
+	}
+}
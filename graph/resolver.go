@@ -0,0 +1,358 @@
+package graph
+
+import (
+	"context"
+	"sort"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+	"github.com/CaioBittencourt/arvore-genealogica/service"
+	"github.com/graph-gophers/graphql-go"
+)
+
+type rootResolver struct {
+	personService service.PersonService
+}
+
+func (r *rootResolver) Person(ctx context.Context, args struct{ ID graphql.ID }) (*personResolver, error) {
+	person, err := personLoaderFromContext(ctx).LoadOne(ctx, string(args.ID))
+	if err != nil || person == nil {
+		return nil, buildResolverError(err)
+	}
+
+	return &personResolver{person: *person, personService: r.personService}, nil
+}
+
+func (r *rootResolver) Persons(ctx context.Context, args struct{ IDS []graphql.ID }) ([]*personResolver, error) {
+	var personIDS []string
+	for _, id := range args.IDS {
+		personIDS = append(personIDS, string(id))
+	}
+
+	return loadPersonResolvers(ctx, r.personService, personIDS)
+}
+
+type lifeEventInput struct {
+	Date  *string
+	Place *string
+}
+
+type relativeInput struct {
+	ID      graphql.ID
+	Version int32
+}
+
+type createPersonInput struct {
+	Name     string
+	Gender   string
+	Birth    *lifeEventInput
+	Death    *lifeEventInput
+	Mother   *relativeInput
+	Father   *relativeInput
+	Parents  *[]relativeInput
+	Children *[]relativeInput
+}
+
+func buildDomainLifeEventFromInput(lifeEvent *lifeEventInput) *domain.LifeEvent {
+	if lifeEvent == nil {
+		return nil
+	}
+
+	domainLifeEvent := &domain.LifeEvent{}
+	if lifeEvent.Date != nil {
+		domainLifeEvent.Date = *lifeEvent.Date
+	}
+	if lifeEvent.Place != nil {
+		domainLifeEvent.Place = *lifeEvent.Place
+	}
+
+	return domainLifeEvent
+}
+
+// buildDomainRelativeFromInput checks version 0, the version of persons stored before versions existed, like any
+// other version.
+func buildDomainRelativeFromInput(relative relativeInput) *domain.Person {
+	version := int64(relative.Version)
+	if version == 0 {
+		version = domain.UnversionedPersonVersion
+	}
+
+	return &domain.Person{ID: string(relative.ID), Version: version}
+}
+
+func buildPersonFromCreatePersonInput(input createPersonInput) domain.Person {
+	person := domain.Person{
+		Name:   input.Name,
+		Gender: domain.GenderType(input.Gender),
+		Birth:  buildDomainLifeEventFromInput(input.Birth),
+		Death:  buildDomainLifeEventFromInput(input.Death),
+	}
+
+	if input.Father != nil {
+		person.AddParent(buildDomainRelativeFromInput(*input.Father), domain.FatherParentRole)
+	}
+
+	if input.Mother != nil {
+		person.AddParent(buildDomainRelativeFromInput(*input.Mother), domain.MotherParentRole)
+	}
+
+	if input.Parents != nil {
+		for _, parent := range *input.Parents {
+			person.AddParent(buildDomainRelativeFromInput(parent), domain.GenericParentRole)
+		}
+	}
+
+	if input.Children != nil {
+		for _, children := range *input.Children {
+			person.Children = append(person.Children, buildDomainRelativeFromInput(children))
+		}
+	}
+
+	return person
+}
+
+// CreatePerson links the new person to its relatives only if they are still on the version sent, like the
+// If-Match header of POST /person.
+func (r *rootResolver) CreatePerson(ctx context.Context, args struct{ Input createPersonInput }) (*personResolver, error) {
+	person, err := r.personService.Store(ctx, buildPersonFromCreatePersonInput(args.Input))
+	if err != nil {
+		return nil, buildResolverError(err)
+	}
+
+	loader := personLoaderFromContext(ctx)
+	loader.Forget(append(relativeIDS(person.Parents), relativeIDS(person.Children)...))
+
+	storedPerson, err := loader.LoadOne(ctx, person.ID)
+	if err != nil {
+		return nil, buildResolverError(err)
+	}
+
+	if storedPerson == nil {
+		return &personResolver{person: *person, personService: r.personService}, nil
+	}
+
+	return &personResolver{person: *storedPerson, personService: r.personService}, nil
+}
+
+func relativeIDS(relatives []*domain.Person) []string {
+	var ids []string
+	for _, relative := range relatives {
+		ids = append(ids, relative.ID)
+	}
+
+	return ids
+}
+
+func loadPersonResolvers(ctx context.Context, personService service.PersonService, personIDS []string) ([]*personResolver, error) {
+	persons, err := personLoaderFromContext(ctx).Load(ctx, personIDS)
+	if err != nil {
+		return nil, buildResolverError(err)
+	}
+
+	resolvers := []*personResolver{}
+	for _, person := range persons {
+		resolvers = append(resolvers, &personResolver{person: person, personService: personService})
+	}
+
+	return resolvers, nil
+}
+
+type lifeEventResolver struct {
+	lifeEvent domain.LifeEvent
+}
+
+func newLifeEventResolver(lifeEvent *domain.LifeEvent) *lifeEventResolver {
+	if lifeEvent == nil {
+		return nil
+	}
+
+	return &lifeEventResolver{lifeEvent: *lifeEvent}
+}
+
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+
+	return &value
+}
+
+func (r *lifeEventResolver) Date() *string {
+	return optionalString(r.lifeEvent.Date)
+}
+
+func (r *lifeEventResolver) Place() *string {
+	return optionalString(r.lifeEvent.Place)
+}
+
+type personResolver struct {
+	person        domain.Person
+	personService service.PersonService
+}
+
+func (r *personResolver) ID() graphql.ID {
+	return graphql.ID(r.person.ID)
+}
+
+func (r *personResolver) Name() string {
+	return r.person.Name
+}
+
+func (r *personResolver) Gender() string {
+	return string(r.person.Gender)
+}
+
+func (r *personResolver) Birth() *lifeEventResolver {
+	return newLifeEventResolver(r.person.Birth)
+}
+
+func (r *personResolver) Death() *lifeEventResolver {
+	return newLifeEventResolver(r.person.Death)
+}
+
+func (r *personResolver) Version() int32 {
+	return int32(r.person.Version)
+}
+
+func (r *personResolver) Parents(ctx context.Context) ([]*personResolver, error) {
+	return loadPersonResolvers(ctx, r.personService, relativeIDS(r.person.Parents))
+}
+
+func (r *personResolver) parentWithRole(ctx context.Context, role domain.ParentRoleType) (*personResolver, error) {
+	for _, parent := range r.person.Parents {
+		if r.person.ParentRole(parent.ID) != role {
+			continue
+		}
+
+		parents, err := loadPersonResolvers(ctx, r.personService, []string{parent.ID})
+		if err != nil || len(parents) == 0 {
+			return nil, err
+		}
+
+		return parents[0], nil
+	}
+
+	return nil, nil
+}
+
+func (r *personResolver) Mother(ctx context.Context) (*personResolver, error) {
+	return r.parentWithRole(ctx, domain.MotherParentRole)
+}
+
+func (r *personResolver) Father(ctx context.Context) (*personResolver, error) {
+	return r.parentWithRole(ctx, domain.FatherParentRole)
+}
+
+func (r *personResolver) Children(ctx context.Context) ([]*personResolver, error) {
+	return loadPersonResolvers(ctx, r.personService, relativeIDS(r.person.Children))
+}
+
+func (r *personResolver) Spouses(ctx context.Context) ([]*personResolver, error) {
+	children, err := personLoaderFromContext(ctx).Load(ctx, relativeIDS(r.person.Children))
+	if err != nil {
+		return nil, buildResolverError(err)
+	}
+
+	var spouseIDS []string
+	seen := map[string]bool{r.person.ID: true}
+	for _, children := range children {
+		for _, parentID := range relativeIDS(children.Parents) {
+			if !seen[parentID] {
+				seen[parentID] = true
+				spouseIDS = append(spouseIDS, parentID)
+			}
+		}
+	}
+
+	return loadPersonResolvers(ctx, r.personService, spouseIDS)
+}
+
+type relationshipResolver struct {
+	relationship  domain.Relationship
+	personService service.PersonService
+}
+
+func (r *relationshipResolver) Person(ctx context.Context) (*personResolver, error) {
+	person, err := personLoaderFromContext(ctx).LoadOne(ctx, r.relationship.Person.ID)
+	if err != nil {
+		return nil, buildResolverError(err)
+	}
+
+	if person == nil {
+		return &personResolver{
+			person:        domain.Person{ID: r.relationship.Person.ID, Name: r.relationship.Person.Name, Gender: r.relationship.Person.Gender},
+			personService: r.personService,
+		}, nil
+	}
+
+	return &personResolver{person: *person, personService: r.personService}, nil
+}
+
+func (r *relationshipResolver) Relationship() string {
+	return string(r.relationship.Relationship)
+}
+
+func (r *relationshipResolver) Role() *string {
+	if r.relationship.ParentRole == "" {
+		return nil
+	}
+
+	role := string(r.relationship.ParentRole)
+	return &role
+}
+
+func (r *relationshipResolver) LinkType() *string {
+	if r.relationship.LinkType == "" {
+		return nil
+	}
+
+	linkType := string(r.relationship.LinkType)
+	return &linkType
+}
+
+type relatedPersonArgs struct {
+	To        graphql.ID
+	LinkTypes *[]string
+}
+
+func (args relatedPersonArgs) linkTypes() []domain.LinkType {
+	if args.LinkTypes == nil {
+		return nil
+	}
+
+	var linkTypes []domain.LinkType
+	for _, linkType := range *args.LinkTypes {
+		linkTypes = append(linkTypes, domain.LinkType(linkType))
+	}
+
+	return linkTypes
+}
+
+// Relationships returns the same relationships as GET /person/:id/relationship/:id2.
+func (r *personResolver) Relationships(ctx context.Context, args relatedPersonArgs) ([]*relationshipResolver, error) {
+	personWithRelationship, err := r.personService.GetRelationshipBetweenPersons(ctx, r.person.ID, string(args.To), args.linkTypes())
+	if err != nil {
+		return nil, buildResolverError(err)
+	}
+
+	var relationshipPersonIDS []string
+	for personID := range personWithRelationship.Relationships {
+		relationshipPersonIDS = append(relationshipPersonIDS, personID)
+	}
+	sort.Strings(relationshipPersonIDS)
+
+	resolvers := []*relationshipResolver{}
+	for _, personID := range relationshipPersonIDS {
+		resolvers = append(resolvers, &relationshipResolver{relationship: personWithRelationship.Relationships[personID], personService: r.personService})
+	}
+
+	return resolvers, nil
+}
+
+func (r *personResolver) BaconNumber(ctx context.Context, args relatedPersonArgs) (int32, error) {
+	baconsNumber, err := r.personService.BaconsNumber(ctx, r.person.ID, string(args.To), domain.BaconsNumberOptions{LinkTypes: args.linkTypes()})
+	if err != nil {
+		return 0, buildResolverError(err)
+	}
+
+	return int32(*baconsNumber), nil
+}
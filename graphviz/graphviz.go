@@ -0,0 +1,185 @@
+package graphviz
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+)
+
+const MediaType = "text/vnd.graphviz"
+
+type Options struct {
+	// RelationshipLabels adds to each member its relationship with the searched person
+	RelationshipLabels bool
+}
+
+type genderStyle struct {
+	fillColor string
+	edgeColor string
+	edgeStyle string
+}
+
+var genderStyles = map[domain.GenderType]genderStyle{
+	domain.Male:   {fillColor: "#cfe2ff", edgeColor: "#1f5fbf", edgeStyle: "solid"},
+	domain.Female: {fillColor: "#f8d7e8", edgeColor: "#bf1f6a", edgeStyle: "dashed"},
+}
+
+var unknownGenderStyle = genderStyle{fillColor: "#e9ecef", edgeColor: "#6c757d", edgeStyle: "dotted"}
+
+func styleForGender(gender domain.GenderType) genderStyle {
+	if style, ok := genderStyles[gender]; ok {
+		return style
+	}
+
+	return unknownGenderStyle
+}
+
+// quote returns s as a DOT quoted string.
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+func marriageNodeID(personAID string, personBID string) string {
+	if personAID > personBID {
+		personAID, personBID = personBID, personAID
+	}
+
+	return "marriage_" + personAID + "_" + personBID
+}
+
+// couple is a pair of spouses drawn through a marriage node, the generation is the one of its first spouse.
+type couple struct {
+	nodeID     string
+	spouseIDS  [2]string
+	generation int
+}
+
+func buildCouples(members []*domain.Person) map[string]couple {
+	couplesByNodeID := make(map[string]couple)
+	for _, member := range members {
+		for _, spouse := range member.Spouses {
+			nodeID := marriageNodeID(member.ID, spouse.ID)
+			if _, ok := couplesByNodeID[nodeID]; ok {
+				continue
+			}
+
+			spouseIDS := [2]string{member.ID, spouse.ID}
+			sort.Strings(spouseIDS[:])
+			couplesByNodeID[nodeID] = couple{nodeID: nodeID, spouseIDS: spouseIDS, generation: member.Generation}
+		}
+	}
+
+	return couplesByNodeID
+}
+
+func sortedMembers(familyGraph domain.FamilyGraph) []*domain.Person {
+	var members []*domain.Person
+	for _, member := range familyGraph.Members {
+		members = append(members, member)
+	}
+
+	sort.Slice(members, func(i, j int) bool {
+		if members[i].Generation != members[j].Generation {
+			return members[i].Generation > members[j].Generation
+		}
+		return members[i].ID < members[j].ID
+	})
+
+	return members
+}
+
+// Encode writes the family graph as a DOT digraph. Each generation is a rank, ancestors on top, spouses are joined
+// by a marriage node their children descend from and the person searched is highlighted.
+func Encode(writer io.Writer, familyGraph domain.FamilyGraph, personID string, options Options) error {
+	members := sortedMembers(familyGraph)
+	couplesByNodeID := buildCouples(members)
+
+	var searchedPerson *domain.Person
+	if person, ok := familyGraph.Members[personID]; ok {
+		searchedPerson = person
+	}
+
+	w := bufio.NewWriter(writer)
+	fmt.Fprintln(w, "digraph family {")
+	fmt.Fprintln(w, "  rankdir=TB;")
+	fmt.Fprintln(w, `  node [shape=box, style="rounded,filled", fontname="Helvetica"];`)
+	fmt.Fprintln(w, "  edge [arrowsize=0.6];")
+
+	var generations []int
+	nodeIDSByGeneration := make(map[int][]string)
+	for _, member := range members {
+		if _, ok := nodeIDSByGeneration[member.Generation]; !ok {
+			generations = append(generations, member.Generation)
+		}
+		nodeIDSByGeneration[member.Generation] = append(nodeIDSByGeneration[member.Generation], quote(member.ID))
+	}
+
+	var coupleNodeIDS []string
+	for nodeID := range couplesByNodeID {
+		coupleNodeIDS = append(coupleNodeIDS, nodeID)
+	}
+	sort.Strings(coupleNodeIDS)
+	for _, nodeID := range coupleNodeIDS {
+		generation := couplesByNodeID[nodeID].generation
+		nodeIDSByGeneration[generation] = append(nodeIDSByGeneration[generation], quote(nodeID))
+	}
+
+	for _, member := range members {
+		label := member.Name
+		if options.RelationshipLabels && searchedPerson != nil {
+			if relationship, ok := searchedPerson.Relationships[member.ID]; ok {
+				label += "\n(" + relationship.Label() + ")"
+			}
+		}
+
+		attributes := fmt.Sprintf("label=%s, fillcolor=%s", quote(label), quote(styleForGender(member.Gender).fillColor))
+		if member.ID == personID {
+			attributes += `, penwidth=3, color="#ff9f1c"`
+		}
+		fmt.Fprintf(w, "  %s [%s];\n", quote(member.ID), attributes)
+	}
+
+	for _, nodeID := range coupleNodeIDS {
+		fmt.Fprintf(w, "  %s [shape=point, width=0.08, label=\"\"];\n", quote(nodeID))
+	}
+
+	for _, generation := range generations {
+		fmt.Fprintf(w, "  subgraph %s { rank=same; %s; }\n", quote(fmt.Sprintf("generation_%d", generation)), strings.Join(nodeIDSByGeneration[generation], "; "))
+	}
+
+	for _, nodeID := range coupleNodeIDS {
+		for _, spouseID := range couplesByNodeID[nodeID].spouseIDS {
+			style := styleForGender(familyGraph.Members[spouseID].Gender)
+			fmt.Fprintf(w, "  %s -> %s [dir=none, color=%s, style=%s];\n", quote(spouseID), quote(nodeID), quote(style.edgeColor), style.edgeStyle)
+		}
+	}
+
+	for _, member := range members {
+		var parents []*domain.Person
+		for _, parent := range member.Parents {
+			if _, ok := familyGraph.Members[parent.ID]; ok {
+				parents = append(parents, parent)
+			}
+		}
+
+		if len(parents) == 2 {
+			if _, ok := couplesByNodeID[marriageNodeID(parents[0].ID, parents[1].ID)]; ok {
+				fmt.Fprintf(w, "  %s -> %s;\n", quote(marriageNodeID(parents[0].ID, parents[1].ID)), quote(member.ID))
+				continue
+			}
+		}
+
+		for _, parent := range parents {
+			style := styleForGender(parent.Gender)
+			fmt.Fprintf(w, "  %s -> %s [color=%s, style=%s];\n", quote(parent.ID), quote(member.ID), quote(style.edgeColor), style.edgeStyle)
+		}
+	}
+
+	fmt.Fprintln(w, "}")
+
+	return w.Flush()
+}
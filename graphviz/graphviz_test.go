@@ -0,0 +1,95 @@
+package graphviz
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func buildFamilyGraph() domain.FamilyGraph {
+	zeze := &domain.Person{ID: "IDZézé", Name: "Zézé \"Vó\"", Gender: domain.Female, Generation: 2}
+	luis := &domain.Person{ID: "IDLuis", Name: "Luis", Gender: domain.Male, Generation: 1}
+	dayse := &domain.Person{ID: "IDDayse", Name: "Dayse", Gender: domain.Female, Generation: 1}
+	caio := &domain.Person{ID: "IDCaio", Name: "Caio", Gender: domain.Male, Generation: 0}
+
+	zeze.Children = []*domain.Person{luis}
+	luis.Parents = []*domain.Person{zeze}
+	luis.Children = []*domain.Person{caio}
+	luis.Spouses = []*domain.Person{dayse}
+	dayse.Children = []*domain.Person{caio}
+	dayse.Spouses = []*domain.Person{luis}
+	caio.Parents = []*domain.Person{luis, dayse}
+
+	return domain.FamilyGraph{Members: map[string]*domain.Person{
+		zeze.ID:  zeze,
+		luis.ID:  luis,
+		dayse.ID: dayse,
+		caio.ID:  caio,
+	}}
+}
+
+func TestEncode(t *testing.T) {
+	type testArgs struct {
+		testName         string
+		options          Options
+		expectedLines    []string
+		notExpectedLines []string
+	}
+
+	tests := []testArgs{
+		{
+			testName: "should rank generations and join spouses through a marriage node",
+			expectedLines: []string{
+				`  subgraph "generation_2" { rank=same; "IDZézé"; }`,
+				`  subgraph "generation_1" { rank=same; "IDDayse"; "IDLuis"; "marriage_IDDayse_IDLuis"; }`,
+				`  subgraph "generation_0" { rank=same; "IDCaio"; }`,
+				`  "IDDayse" -> "marriage_IDDayse_IDLuis" [dir=none, color="#bf1f6a", style=dashed];`,
+				`  "IDLuis" -> "marriage_IDDayse_IDLuis" [dir=none, color="#1f5fbf", style=solid];`,
+				`  "marriage_IDDayse_IDLuis" -> "IDCaio";`,
+				`  "IDZézé" -> "IDLuis" [color="#bf1f6a", style=dashed];`,
+			},
+			notExpectedLines: []string{
+				`  "IDLuis" -> "IDCaio" [color="#1f5fbf", style=solid];`,
+			},
+		},
+		{
+			testName: "should highlight the searched person and escape names",
+			expectedLines: []string{
+				`  "IDCaio" [label="Caio", fillcolor="#cfe2ff", penwidth=3, color="#ff9f1c"];`,
+				`  "IDZézé" [label="Zézé \"Vó\"", fillcolor="#f8d7e8"];`,
+			},
+		},
+		{
+			testName: "should label members with their relationship with the searched person",
+			options:  Options{RelationshipLabels: true},
+			expectedLines: []string{
+				`  "IDLuis" [label="Luis\n(parent)", fillcolor="#cfe2ff"];`,
+				`  "IDCaio" [label="Caio", fillcolor="#cfe2ff", penwidth=3, color="#ff9f1c"];`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(tt testArgs) func(t *testing.T) {
+			return func(t *testing.T) {
+				familyGraph := buildFamilyGraph()
+				assert.NoError(t, familyGraph.PopulateFamilyWithRelationships("IDCaio"))
+
+				var graph bytes.Buffer
+				assert.NoError(t, Encode(&graph, familyGraph, "IDCaio", tt.options))
+
+				lines := strings.Split(graph.String(), "\n")
+				assert.Equal(t, "digraph family {", lines[0])
+				for _, expectedLine := range tt.expectedLines {
+					assert.Contains(t, lines, expectedLine)
+				}
+				for _, notExpectedLine := range tt.notExpectedLines {
+					assert.NotContains(t, lines, notExpectedLine)
+				}
+			}
+		}(tt))
+	}
+}
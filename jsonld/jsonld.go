@@ -0,0 +1,207 @@
+package jsonld
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+)
+
+const MediaType = "application/ld+json"
+
+const schemaContext = "https://schema.org"
+
+var genderByDomainGender = map[domain.GenderType]string{
+	domain.Male:   "https://schema.org/Male",
+	domain.Female: "https://schema.org/Female",
+}
+
+type Reference struct {
+	ID string `json:"@id"`
+}
+
+type Place struct {
+	Type string `json:"@type"`
+	Name string `json:"name"`
+}
+
+type Person struct {
+	ID         string      `json:"@id"`
+	Type       string      `json:"@type"`
+	Identifier string      `json:"identifier"`
+	Name       string      `json:"name"`
+	Gender     string      `json:"gender,omitempty"`
+	BirthDate  string      `json:"birthDate,omitempty"`
+	BirthPlace *Place      `json:"birthPlace,omitempty"`
+	DeathDate  string      `json:"deathDate,omitempty"`
+	DeathPlace *Place      `json:"deathPlace,omitempty"`
+	Parent     []Reference `json:"parent,omitempty"`
+	Children   []Reference `json:"children,omitempty"`
+	Spouse     []Reference `json:"spouse,omitempty"`
+	Sibling    []Reference `json:"sibling,omitempty"`
+	RelatedTo  []Reference `json:"relatedTo,omitempty"`
+}
+
+type Document struct {
+	Context string   `json:"@context"`
+	Graph   []Person `json:"@graph"`
+}
+
+// IRI is the address the person is served at, baseIRI being the address of the API or of the tree.
+func IRI(baseIRI string, personID string) string {
+	return baseIRI + "/person/" + url.PathEscape(personID)
+}
+
+// dateLayouts are the ISO 8601 dates schema.org expects and the GEDCOM dates imported files usually have, with the
+// ISO layout each one is written as.
+var dateLayouts = []struct {
+	layout    string
+	isoLayout string
+}{
+	{layout: "2006-01-02", isoLayout: "2006-01-02"},
+	{layout: "2006-01", isoLayout: "2006-01"},
+	{layout: "2006", isoLayout: "2006"},
+	{layout: "2 Jan 2006", isoLayout: "2006-01-02"},
+	{layout: "Jan 2006", isoLayout: "2006-01"},
+}
+
+// isoDate returns an empty date when it is not exact, like "ABT 1900" or "BET 1900 AND 1910", as schema.org dates
+// can not express that.
+func isoDate(date string) string {
+	for _, dateLayout := range dateLayouts {
+		if parsed, err := time.Parse(dateLayout.layout, date); err == nil {
+			return parsed.Format(dateLayout.isoLayout)
+		}
+	}
+
+	return ""
+}
+
+func lifeEvent(lifeEvent *domain.LifeEvent) (string, *Place) {
+	if lifeEvent == nil {
+		return "", nil
+	}
+
+	var place *Place
+	if lifeEvent.Place != "" {
+		place = &Place{Type: "Place", Name: lifeEvent.Place}
+	}
+
+	return isoDate(lifeEvent.Date), place
+}
+
+var inverseRelationships = map[domain.RelationshipType]domain.RelationshipType{
+	domain.ParentRelashionship: domain.ChildRelashionship,
+	domain.ChildRelashionship:  domain.ParentRelashionship,
+}
+
+// relativeIDSByRelationship returns the relatives of every member by relationship type. Relationships are only
+// computed from one of the persons of each pair, so the inverse one is added to the other person.
+func relativeIDSByRelationship(familyGraph domain.FamilyGraph) map[string]map[domain.RelationshipType]map[string]bool {
+	relativeIDS := make(map[string]map[domain.RelationshipType]map[string]bool, len(familyGraph.Members))
+	add := func(personID string, relationshipType domain.RelationshipType, relativeID string) {
+		if _, ok := relativeIDS[personID]; !ok {
+			relativeIDS[personID] = make(map[domain.RelationshipType]map[string]bool)
+		}
+		if _, ok := relativeIDS[personID][relationshipType]; !ok {
+			relativeIDS[personID][relationshipType] = make(map[string]bool)
+		}
+		relativeIDS[personID][relationshipType][relativeID] = true
+	}
+
+	for _, member := range familyGraph.Members {
+		for relativeID, relationship := range member.Relationships {
+			if _, ok := familyGraph.Members[relativeID]; !ok {
+				continue
+			}
+
+			inverseRelationship, ok := inverseRelationships[relationship.Relationship]
+			if !ok {
+				inverseRelationship = relationship.Relationship
+			}
+
+			add(member.ID, relationship.Relationship, relativeID)
+			add(relativeID, inverseRelationship, member.ID)
+		}
+	}
+
+	return relativeIDS
+}
+
+func references(baseIRI string, relativeIDS map[string]bool) []Reference {
+	var references []Reference
+	for relativeID := range relativeIDS {
+		references = append(references, Reference{ID: IRI(baseIRI, relativeID)})
+	}
+
+	sort.Slice(references, func(i, j int) bool {
+		return references[i].ID < references[j].ID
+	})
+
+	return references
+}
+
+// BuildDocument turns every member of the graph into a schema.org Person, the searched person first. The parent,
+// children, spouse and sibling properties come from the computed relationships of each member, the remaining
+// relationships, like cousins, become relatedTo.
+func BuildDocument(familyGraph domain.FamilyGraph, personID string, baseIRI string) Document {
+	var members []*domain.Person
+	for _, member := range familyGraph.Members {
+		members = append(members, member)
+	}
+
+	sort.Slice(members, func(i, j int) bool {
+		if (members[i].ID == personID) != (members[j].ID == personID) {
+			return members[i].ID == personID
+		}
+		return members[i].ID < members[j].ID
+	})
+
+	relativeIDSByType := relativeIDSByRelationship(familyGraph)
+	document := Document{Context: schemaContext, Graph: []Person{}}
+	for _, member := range members {
+		person := Person{
+			ID:         IRI(baseIRI, member.ID),
+			Type:       "Person",
+			Identifier: member.ID,
+			Name:       member.Name,
+			Gender:     genderByDomainGender[member.Gender],
+		}
+		person.BirthDate, person.BirthPlace = lifeEvent(member.Birth)
+		person.DeathDate, person.DeathPlace = lifeEvent(member.Death)
+
+		relatedIDS := make(map[string]bool)
+		for relationshipType, relativeIDS := range relativeIDSByType[member.ID] {
+			switch relationshipType {
+			case domain.ParentRelashionship:
+				person.Parent = references(baseIRI, relativeIDS)
+			case domain.ChildRelashionship:
+				person.Children = references(baseIRI, relativeIDS)
+			case domain.SpouseRelashionship:
+				person.Spouse = references(baseIRI, relativeIDS)
+			case domain.SiblingRelashionship:
+				person.Sibling = references(baseIRI, relativeIDS)
+			default:
+				for relativeID := range relativeIDS {
+					relatedIDS[relativeID] = true
+				}
+			}
+		}
+		person.RelatedTo = references(baseIRI, relatedIDS)
+
+		document.Graph = append(document.Graph, person)
+	}
+
+	return document
+}
+
+func Encode(writer io.Writer, familyGraph domain.FamilyGraph, personID string, baseIRI string) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+
+	return encoder.Encode(BuildDocument(familyGraph, personID, baseIRI))
+}
@@ -0,0 +1,101 @@
+package jsonld
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const baseIRI = "https://arvore.example"
+
+func buildFamilyGraph() domain.FamilyGraph {
+	luis := &domain.Person{ID: "IDLuis", Name: "Luis", Gender: domain.Male, Generation: 1}
+	dayse := &domain.Person{ID: "IDDayse", Name: "Dayse", Gender: domain.Female, Generation: 1}
+	caio := &domain.Person{ID: "IDCaio", Name: "Caio", Gender: domain.Male, Generation: 0, Birth: &domain.LifeEvent{Date: "1 JAN 1990", Place: "Porto Alegre"}}
+	ana := &domain.Person{ID: "ID Ana", Name: "Zézé", Gender: domain.Female, Generation: 0, Death: &domain.LifeEvent{Date: "ABT 2020"}}
+
+	luis.Children = []*domain.Person{caio, ana}
+	luis.Spouses = []*domain.Person{dayse}
+	dayse.Children = []*domain.Person{caio, ana}
+	dayse.Spouses = []*domain.Person{luis}
+	caio.Parents = []*domain.Person{luis, dayse}
+	ana.Parents = []*domain.Person{luis, dayse}
+
+	return domain.FamilyGraph{Members: map[string]*domain.Person{
+		luis.ID:  luis,
+		dayse.ID: dayse,
+		caio.ID:  caio,
+		ana.ID:   ana,
+	}}
+}
+
+func TestIsoDate(t *testing.T) {
+	type testArgs struct {
+		testName     string
+		date         string
+		expectedDate string
+	}
+
+	tests := []testArgs{
+		{testName: "should keep iso dates", date: "1990-01-31", expectedDate: "1990-01-31"},
+		{testName: "should keep years", date: "1990", expectedDate: "1990"},
+		{testName: "should convert gedcom dates", date: "31 JAN 1990", expectedDate: "1990-01-31"},
+		{testName: "should convert gedcom months", date: "JAN 1990", expectedDate: "1990-01"},
+		{testName: "should drop approximate dates", date: "ABT 1990", expectedDate: ""},
+		{testName: "should drop date ranges", date: "BET 1990 AND 1991", expectedDate: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(tt testArgs) func(t *testing.T) {
+			return func(t *testing.T) {
+				assert.Equal(t, tt.expectedDate, isoDate(tt.date))
+			}
+		}(tt))
+	}
+}
+
+func TestBuildDocument(t *testing.T) {
+	familyGraph := buildFamilyGraph()
+	assert.NoError(t, familyGraph.PopulateFamilyWithRelationships("IDCaio"))
+
+	document := BuildDocument(familyGraph, "IDCaio", baseIRI)
+
+	assert.Equal(t, "https://schema.org", document.Context)
+	assert.Len(t, document.Graph, 4)
+
+	caio := document.Graph[0]
+	assert.Equal(t, "https://arvore.example/person/IDCaio", caio.ID)
+	assert.Equal(t, "Person", caio.Type)
+	assert.Equal(t, "IDCaio", caio.Identifier)
+	assert.Equal(t, "https://schema.org/Male", caio.Gender)
+	assert.Equal(t, "1990-01-01", caio.BirthDate)
+	assert.Equal(t, &Place{Type: "Place", Name: "Porto Alegre"}, caio.BirthPlace)
+	assert.Equal(t, []Reference{{ID: baseIRI + "/person/IDDayse"}, {ID: baseIRI + "/person/IDLuis"}}, caio.Parent)
+	assert.Equal(t, []Reference{{ID: baseIRI + "/person/ID%20Ana"}}, caio.Sibling)
+
+	ana := document.Graph[1]
+	assert.Equal(t, baseIRI+"/person/ID%20Ana", ana.ID)
+	assert.Equal(t, "", ana.DeathDate)
+
+	luis := document.Graph[3]
+	assert.Equal(t, "IDLuis", luis.Identifier)
+	assert.Equal(t, []Reference{{ID: baseIRI + "/person/IDDayse"}}, luis.Spouse)
+	assert.Equal(t, []Reference{{ID: baseIRI + "/person/ID%20Ana"}, {ID: baseIRI + "/person/IDCaio"}}, luis.Children)
+}
+
+func TestEncode(t *testing.T) {
+	familyGraph := buildFamilyGraph()
+	assert.NoError(t, familyGraph.PopulateFamilyWithRelationships("IDCaio"))
+
+	var buffer bytes.Buffer
+	assert.NoError(t, Encode(&buffer, familyGraph, "IDCaio", baseIRI))
+
+	var decoded map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buffer.Bytes(), &decoded))
+	assert.Equal(t, "https://schema.org", decoded["@context"])
+	assert.Contains(t, buffer.String(), `"name": "Zézé"`)
+	assert.NotContains(t, buffer.String(), `"relatedTo"`)
+}
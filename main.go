@@ -3,13 +3,17 @@ package main
 import (
 	"context"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 
+	"github.com/CaioBittencourt/arvore-genealogica/cli"
 	docs "github.com/CaioBittencourt/arvore-genealogica/docs"
 	"github.com/CaioBittencourt/arvore-genealogica/repository/mongodb"
+	"github.com/CaioBittencourt/arvore-genealogica/rpc"
 	"github.com/CaioBittencourt/arvore-genealogica/server/routes"
 	"github.com/CaioBittencourt/arvore-genealogica/service"
 	swaggerfiles "github.com/swaggo/files"
@@ -22,8 +26,28 @@ func main() {
 
 	personRepository := mongodb.NewPersonRepository(*mongoClient, os.Getenv("MONGO_DATABASE"))
 	personService := service.NewPersonService(personRepository)
+	treeRepository := mongodb.NewTreeRepository(*mongoClient, os.Getenv("MONGO_DATABASE"))
+	treeService := service.NewTreeService(treeRepository)
+	backupRepository := mongodb.NewBackupRepository(*mongoClient, os.Getenv("MONGO_DATABASE"))
+	backupService := service.NewBackupService(backupRepository)
 
-	router := routes.SetupRouter(personService)
+	if len(os.Args) > 1 {
+		if err := cli.Run(context.Background(), cli.Services{PersonService: personService, BackupService: backupService}, os.Args[1:], os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if err := personRepository.EnableTreeEvents(context.Background()); err != nil {
+		log.Printf("tree events without pre-images: %s\n", err)
+	}
+
+	var allowedOrigins []string
+	if origins := os.Getenv("ALLOWED_ORIGINS"); origins != "" {
+		allowedOrigins = strings.Split(origins, ",")
+	}
+
+	router := routes.SetupRouter(personService, treeService, allowedOrigins...)
 
 	docs.SwaggerInfo.BasePath = "/person"
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
@@ -33,6 +57,12 @@ func main() {
 		Handler: router,
 	}
 
+	grpcServer := rpc.NewServer(personService, treeService)
+	grpcListener, err := net.Listen("tcp", ":9090")
+	if err != nil {
+		log.Fatalf("listen grpc: %s\n", err)
+	}
+
 	go func() {
 		// service connections
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -40,11 +70,26 @@ func main() {
 		}
 	}()
 
+	go func() {
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			log.Fatalf("serve grpc: %s\n", err)
+		}
+	}()
+
 	shutdown := make(chan os.Signal, 1)
 	signal.Notify(shutdown, syscall.SIGINT, syscall.SIGTERM)
 
 	<-shutdown
+
+	// both servers drain their in flight requests at the same time
+	grpcStopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(grpcStopped)
+	}()
+
 	if err := srv.Shutdown(context.Background()); err != nil {
 		log.Fatal("Error on server shutdown:", err)
 	}
+	<-grpcStopped
 }
@@ -0,0 +1,135 @@
+package mermaid
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+)
+
+const MediaType = "text/vnd.mermaid"
+
+var classByGender = map[domain.GenderType]string{
+	domain.Male:   "male",
+	domain.Female: "female",
+}
+
+// NodeID turns the person id into a Mermaid id. Letters and digits are kept and every other byte is written as its
+// hex code, so two different ids never share a node and exporting twice gives the same ids.
+func NodeID(personID string) string {
+	var builder strings.Builder
+	builder.WriteString("p_")
+	for _, b := range []byte(personID) {
+		if (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || (b >= '0' && b <= '9') {
+			builder.WriteByte(b)
+			continue
+		}
+		fmt.Fprintf(&builder, "_%02x", b)
+	}
+
+	return builder.String()
+}
+
+// label returns s as a quoted Mermaid label. Characters that end the label or are read as markup become entity
+// codes, everything else, accents included, is written as is.
+func label(s string) string {
+	return `"` + strings.NewReplacer(
+		"#", "#35;",
+		`"`, "#quot;",
+		"<", "#lt;",
+		">", "#gt;",
+		"\n", " ",
+		"\r", " ",
+	).Replace(s) + `"`
+}
+
+func generationID(generation int) string {
+	if generation < 0 {
+		return fmt.Sprintf("generation_minus_%d", -generation)
+	}
+
+	return fmt.Sprintf("generation_%d", generation)
+}
+
+func sortedMembers(familyGraph domain.FamilyGraph) []*domain.Person {
+	var members []*domain.Person
+	for _, member := range familyGraph.Members {
+		members = append(members, member)
+	}
+
+	sort.Slice(members, func(i, j int) bool {
+		if members[i].Generation != members[j].Generation {
+			return members[i].Generation > members[j].Generation
+		}
+		return members[i].ID < members[j].ID
+	})
+
+	return members
+}
+
+// Encode writes the family graph as a Mermaid flowchart, ancestors on top. Each generation is a subgraph, spouses
+// are joined by an undirected link and every parent points to its children.
+func Encode(writer io.Writer, familyGraph domain.FamilyGraph, personID string) error {
+	members := sortedMembers(familyGraph)
+
+	w := bufio.NewWriter(writer)
+	fmt.Fprintln(w, "flowchart TB")
+
+	for i, member := range members {
+		if i == 0 || members[i-1].Generation != member.Generation {
+			fmt.Fprintf(w, "  subgraph %s [%s]\n", generationID(member.Generation), label(fmt.Sprintf("Generation %d", member.Generation)))
+		}
+
+		fmt.Fprintf(w, "    %s[%s]\n", NodeID(member.ID), label(member.Name))
+
+		if i == len(members)-1 || members[i+1].Generation != member.Generation {
+			fmt.Fprintln(w, "  end")
+		}
+	}
+
+	for _, member := range members {
+		for _, spouse := range membersOf(familyGraph, member.Spouses) {
+			if member.ID < spouse.ID {
+				fmt.Fprintf(w, "  %s ---|spouse| %s\n", NodeID(member.ID), NodeID(spouse.ID))
+			}
+		}
+	}
+
+	for _, member := range members {
+		for _, children := range membersOf(familyGraph, member.Children) {
+			fmt.Fprintf(w, "  %s --> %s\n", NodeID(member.ID), NodeID(children.ID))
+		}
+	}
+
+	fmt.Fprintln(w, "  classDef male fill:#cfe2ff,stroke:#1f5fbf")
+	fmt.Fprintln(w, "  classDef female fill:#f8d7e8,stroke:#bf1f6a")
+	fmt.Fprintln(w, "  classDef searched stroke:#ff9f1c,stroke-width:3px")
+	for _, member := range members {
+		if class, ok := classByGender[member.Gender]; ok {
+			fmt.Fprintf(w, "  class %s %s\n", NodeID(member.ID), class)
+		}
+	}
+	if _, ok := familyGraph.Members[personID]; ok {
+		fmt.Fprintf(w, "  class %s searched\n", NodeID(personID))
+	}
+
+	return w.Flush()
+}
+
+func membersOf(familyGraph domain.FamilyGraph, persons []*domain.Person) []*domain.Person {
+	var members []*domain.Person
+	for _, person := range persons {
+		if member, ok := familyGraph.Members[person.ID]; ok {
+			members = append(members, member)
+		}
+	}
+
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].ID < members[j].ID
+	})
+
+	return members
+}
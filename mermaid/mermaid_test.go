@@ -0,0 +1,78 @@
+package mermaid
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func buildFamilyGraph() domain.FamilyGraph {
+	zeze := &domain.Person{ID: "IDZézé", Name: "Zézé \"Vó\" #1", Gender: domain.Female, Generation: 2}
+	luis := &domain.Person{ID: "IDLuis", Name: "Luis", Gender: domain.Male, Generation: 1}
+	dayse := &domain.Person{ID: "IDDayse", Name: "Dayse", Gender: domain.Female, Generation: 1}
+	caio := &domain.Person{ID: "IDCaio", Name: "Caio <3", Gender: domain.Male, Generation: 0}
+	pedro := &domain.Person{ID: "IDPedro", Name: "Pedro", Generation: -1}
+
+	zeze.Children = []*domain.Person{luis}
+	luis.Parents = []*domain.Person{zeze}
+	luis.Children = []*domain.Person{caio}
+	luis.Spouses = []*domain.Person{dayse}
+	dayse.Children = []*domain.Person{caio}
+	dayse.Spouses = []*domain.Person{luis}
+	caio.Parents = []*domain.Person{luis, dayse}
+	caio.Children = []*domain.Person{pedro}
+	pedro.Parents = []*domain.Person{caio}
+
+	return domain.FamilyGraph{Members: map[string]*domain.Person{
+		zeze.ID:  zeze,
+		luis.ID:  luis,
+		dayse.ID: dayse,
+		caio.ID:  caio,
+		pedro.ID: pedro,
+	}}
+}
+
+func TestNodeID(t *testing.T) {
+	assert.Equal(t, "p_64138dcbd2a5f3b8e8a24f1a", NodeID("64138dcbd2a5f3b8e8a24f1a"))
+	assert.Equal(t, "p_IDZ_c3_a9z_c3_a9", NodeID("IDZézé"))
+	assert.NotEqual(t, NodeID("a_2d"), NodeID("a-"))
+}
+
+func TestEncode(t *testing.T) {
+	var buffer bytes.Buffer
+	assert.NoError(t, Encode(&buffer, buildFamilyGraph(), "IDCaio"))
+
+	assert.Equal(t, strings.Join([]string{
+		"flowchart TB",
+		`  subgraph generation_2 ["Generation 2"]`,
+		`    p_IDZ_c3_a9z_c3_a9["Zézé #quot;Vó#quot; #35;1"]`,
+		"  end",
+		`  subgraph generation_1 ["Generation 1"]`,
+		`    p_IDDayse["Dayse"]`,
+		`    p_IDLuis["Luis"]`,
+		"  end",
+		`  subgraph generation_0 ["Generation 0"]`,
+		`    p_IDCaio["Caio #lt;3"]`,
+		"  end",
+		`  subgraph generation_minus_1 ["Generation -1"]`,
+		`    p_IDPedro["Pedro"]`,
+		"  end",
+		"  p_IDDayse ---|spouse| p_IDLuis",
+		"  p_IDZ_c3_a9z_c3_a9 --> p_IDLuis",
+		"  p_IDDayse --> p_IDCaio",
+		"  p_IDLuis --> p_IDCaio",
+		"  p_IDCaio --> p_IDPedro",
+		"  classDef male fill:#cfe2ff,stroke:#1f5fbf",
+		"  classDef female fill:#f8d7e8,stroke:#bf1f6a",
+		"  classDef searched stroke:#ff9f1c,stroke-width:3px",
+		"  class p_IDZ_c3_a9z_c3_a9 female",
+		"  class p_IDDayse female",
+		"  class p_IDLuis male",
+		"  class p_IDCaio male",
+		"  class p_IDCaio searched",
+		"",
+	}, "\n"), buffer.String())
+}
@@ -0,0 +1,112 @@
+package plantuml
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+	"github.com/CaioBittencourt/arvore-genealogica/mermaid"
+)
+
+const MediaType = "text/vnd.plantuml"
+
+var colorByGender = map[domain.GenderType]string{
+	domain.Male:   "#cfe2ff",
+	domain.Female: "#f8d7e8",
+}
+
+// quote returns s as a quoted PlantUML name. Quotes can not be escaped inside names, so they are written as their
+// unicode code, everything else, accents included, is written as is.
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`"`, "<U+0022>", "\n", " ", "\r", " ").Replace(s) + `"`
+}
+
+func sortedMembers(familyGraph domain.FamilyGraph) []*domain.Person {
+	var members []*domain.Person
+	for _, member := range familyGraph.Members {
+		members = append(members, member)
+	}
+
+	sort.Slice(members, func(i, j int) bool {
+		if members[i].Generation != members[j].Generation {
+			return members[i].Generation > members[j].Generation
+		}
+		return members[i].ID < members[j].ID
+	})
+
+	return members
+}
+
+func membersOf(familyGraph domain.FamilyGraph, persons []*domain.Person) []*domain.Person {
+	var members []*domain.Person
+	for _, person := range persons {
+		if member, ok := familyGraph.Members[person.ID]; ok {
+			members = append(members, member)
+		}
+	}
+
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].ID < members[j].ID
+	})
+
+	return members
+}
+
+// Encode writes the family graph as a PlantUML diagram with one rectangle per person inside a rectangle per
+// generation, ancestors on top. Node ids are the same as the Mermaid ones, spouses are joined by a dashed line and
+// every parent points to its children.
+func Encode(writer io.Writer, familyGraph domain.FamilyGraph, personID string) error {
+	members := sortedMembers(familyGraph)
+
+	w := bufio.NewWriter(writer)
+	fmt.Fprintln(w, "@startuml")
+	fmt.Fprintln(w, "top to bottom direction")
+	fmt.Fprintln(w, "skinparam rectangle {")
+	fmt.Fprintln(w, "  RoundCorner 10")
+	fmt.Fprintln(w, "}")
+
+	for i, member := range members {
+		if i == 0 || members[i-1].Generation != member.Generation {
+			fmt.Fprintf(w, "rectangle %s {\n", quote(fmt.Sprintf("Generation %d", member.Generation)))
+		}
+
+		style := colorByGender[member.Gender]
+		if member.ID == personID {
+			if style == "" {
+				style = "#"
+			} else {
+				style += ";"
+			}
+			style += "line:ff9f1c;line.bold"
+		}
+		if style != "" {
+			style = " " + style
+		}
+		fmt.Fprintf(w, "  rectangle %s as %s%s\n", quote(member.Name), mermaid.NodeID(member.ID), style)
+
+		if i == len(members)-1 || members[i+1].Generation != member.Generation {
+			fmt.Fprintln(w, "}")
+		}
+	}
+
+	for _, member := range members {
+		for _, spouse := range membersOf(familyGraph, member.Spouses) {
+			if member.ID < spouse.ID {
+				fmt.Fprintf(w, "%s .. %s : spouse\n", mermaid.NodeID(member.ID), mermaid.NodeID(spouse.ID))
+			}
+		}
+	}
+
+	for _, member := range members {
+		for _, children := range membersOf(familyGraph, member.Children) {
+			fmt.Fprintf(w, "%s --> %s\n", mermaid.NodeID(member.ID), mermaid.NodeID(children.ID))
+		}
+	}
+
+	fmt.Fprintln(w, "@enduml")
+
+	return w.Flush()
+}
@@ -0,0 +1,82 @@
+package plantuml
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func buildFamilyGraph() domain.FamilyGraph {
+	zeze := &domain.Person{ID: "IDZézé", Name: "Zézé \"Vó\"", Gender: domain.Female, Generation: 1}
+	luis := &domain.Person{ID: "IDLuis", Name: "Luis", Gender: domain.Male, Generation: 0}
+	dayse := &domain.Person{ID: "IDDayse", Name: "Dayse", Generation: 0}
+
+	zeze.Children = []*domain.Person{luis}
+	luis.Parents = []*domain.Person{zeze}
+	luis.Spouses = []*domain.Person{dayse}
+	dayse.Spouses = []*domain.Person{luis}
+
+	return domain.FamilyGraph{Members: map[string]*domain.Person{
+		zeze.ID:  zeze,
+		luis.ID:  luis,
+		dayse.ID: dayse,
+	}}
+}
+
+func TestEncode(t *testing.T) {
+	type testArgs struct {
+		testName      string
+		personID      string
+		expectedLines []string
+	}
+
+	tests := []testArgs{
+		{
+			testName: "should group generations and link spouses and children",
+			personID: "IDLuis",
+			expectedLines: []string{
+				`rectangle "Generation 1" {`,
+				`  rectangle "Zézé <U+0022>Vó<U+0022>" as p_IDZ_c3_a9z_c3_a9 #f8d7e8`,
+				`rectangle "Generation 0" {`,
+				"p_IDDayse .. p_IDLuis : spouse",
+				"p_IDZ_c3_a9z_c3_a9 --> p_IDLuis",
+			},
+		},
+		{
+			testName: "should highlight the searched person",
+			personID: "IDLuis",
+			expectedLines: []string{
+				`  rectangle "Luis" as p_IDLuis #cfe2ff;line:ff9f1c;line.bold`,
+				`  rectangle "Dayse" as p_IDDayse`,
+			},
+		},
+		{
+			testName: "should highlight the searched person without gender",
+			personID: "IDDayse",
+			expectedLines: []string{
+				`  rectangle "Dayse" as p_IDDayse #line:ff9f1c;line.bold`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(tt testArgs) func(t *testing.T) {
+			return func(t *testing.T) {
+				var buffer bytes.Buffer
+				assert.NoError(t, Encode(&buffer, buildFamilyGraph(), tt.personID))
+
+				diagram := buffer.String()
+				assert.True(t, strings.HasPrefix(diagram, "@startuml\n"))
+				assert.True(t, strings.HasSuffix(diagram, "@enduml\n"))
+
+				lines := strings.Split(diagram, "\n")
+				for _, expectedLine := range tt.expectedLines {
+					assert.Contains(t, lines, expectedLine)
+				}
+			}
+		}(tt))
+	}
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+)
+
+// BackupRepository reads and writes every collection at once, across all trees, so a backup taken from one
+// backend can be restored into another.
+type BackupRepository interface {
+	Dump(ctx context.Context) (*domain.Backup, error)
+	Restore(ctx context.Context, backup domain.Backup) error
+	IsEmpty(ctx context.Context) (bool, error)
+}
@@ -0,0 +1,261 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var backupCollectionNames = []string{treeCollectionName, personCollectionName, personHistoryCollectionName}
+
+// restoreChunkSize is how many documents each insert of a restore sends, far below the limits of a single command.
+const restoreChunkSize = 1000
+
+type BackupRepository struct {
+	client       mongo.Client
+	databaseName string
+}
+
+func NewBackupRepository(client mongo.Client, databaseName string) BackupRepository {
+	return BackupRepository{client: client, databaseName: databaseName}
+}
+
+func objectIDHex(objectID *primitive.ObjectID) string {
+	if objectID == nil {
+		return ""
+	}
+
+	return objectID.Hex()
+}
+
+// objectIDMapper keeps the ids of backups taken from this backend and gives a new ObjectID to every id another
+// backend may have used, always the same one for the same id so links keep pointing to the same person.
+type objectIDMapper map[string]primitive.ObjectID
+
+func (m objectIDMapper) objectID(id string) primitive.ObjectID {
+	if objectID, ok := m[id]; ok {
+		return objectID
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		objectID = primitive.NewObjectID()
+	}
+	m[id] = objectID
+
+	return objectID
+}
+
+func (m objectIDMapper) treeObjectID(treeID string) *primitive.ObjectID {
+	if treeID == "" {
+		return nil
+	}
+
+	objectID := m.objectID(treeID)
+	return &objectID
+}
+
+func (m objectIDMapper) person(treeID string, person domain.Person) Person {
+	repositoryPerson := Person{
+		ID:          m.objectID(person.ID),
+		Name:        person.Name,
+		Gender:      string(person.Gender),
+		Birth:       buildRepositoryLifeEventFromDomainLifeEvent(person.Birth),
+		Death:       buildRepositoryLifeEventFromDomainLifeEvent(person.Death),
+		ParentIDS:   []primitive.ObjectID{},
+		ChildrenIDS: []primitive.ObjectID{},
+		TreeID:      m.treeObjectID(treeID),
+		Version:     person.Version,
+	}
+
+	for _, parent := range person.Parents {
+		parentObjectID := m.objectID(parent.ID)
+		repositoryPerson.ParentIDS = append(repositoryPerson.ParentIDS, parentObjectID)
+		repositoryPerson.setParentRole(parentObjectID, person.ParentRoles[parent.ID])
+		repositoryPerson.setParentLinkType(parentObjectID, person.ParentLinkTypes[parent.ID])
+	}
+
+	for _, children := range person.Children {
+		repositoryPerson.ChildrenIDS = append(repositoryPerson.ChildrenIDS, m.objectID(children.ID))
+	}
+
+	return repositoryPerson
+}
+
+func (m objectIDMapper) personPointer(treeID string, person *domain.Person) *Person {
+	if person == nil {
+		return nil
+	}
+
+	repositoryPerson := m.person(treeID, *person)
+	return &repositoryPerson
+}
+
+func findAll(ctx context.Context, collection *mongo.Collection, results interface{}) error {
+	cursor, err := collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return err
+	}
+
+	if err := cursor.All(ctx, results); err != nil {
+		if err := cursor.Err(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Dump reads every collection without a tree scope, persons keep their version and history entries their id. The
+// collections are read on a snapshot session, so a backup taken during writes has the history of the persons it has.
+func (br BackupRepository) Dump(ctx context.Context) (*domain.Backup, error) {
+	database := br.client.Database(br.databaseName)
+
+	session, err := br.client.StartSession(options.Session().SetSnapshot(true))
+	if err != nil {
+		return nil, err
+	}
+	defer session.EndSession(ctx)
+
+	var trees []Tree
+	var persons []Person
+	var personHistories []PersonHistory
+	err = mongo.WithSession(ctx, session, func(sessCtx mongo.SessionContext) error {
+		if err := findAll(sessCtx, database.Collection(treeCollectionName), &trees); err != nil {
+			return err
+		}
+
+		if err := findAll(sessCtx, database.Collection(personCollectionName), &persons); err != nil {
+			return err
+		}
+
+		return findAll(sessCtx, database.Collection(personHistoryCollectionName), &personHistories)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	backup := domain.Backup{}
+	for _, tree := range trees {
+		backup.Trees = append(backup.Trees, buildDomainTreeFromRepositoryTree(tree))
+	}
+
+	for _, person := range persons {
+		backup.Persons = append(backup.Persons, domain.TreePerson{
+			TreeID: objectIDHex(person.TreeID),
+			Person: buildDomainPersonFromRepositoryPerson(person),
+		})
+	}
+
+	for _, personHistory := range personHistories {
+		backup.History = append(backup.History, domain.TreePersonChange{
+			TreeID: objectIDHex(personHistory.TreeID),
+			Change: buildDomainPersonChangeFromPersonHistory(personHistory),
+		})
+	}
+
+	return &backup, nil
+}
+
+// insertInChunks inserts documents in order, restoreChunkSize at a time.
+func insertInChunks(ctx context.Context, collection *mongo.Collection, documents []interface{}) error {
+	for start := 0; start < len(documents); start += restoreChunkSize {
+		end := start + restoreChunkSize
+		if end > len(documents) {
+			end = len(documents)
+		}
+
+		if _, err := collection.InsertMany(ctx, documents[start:end]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Restore inserts the backup in ordered chunks instead of a single transaction, which large backups would outlive.
+// The database is required to be empty, so a failed restore deletes everything it inserted to leave it empty again.
+func (br BackupRepository) Restore(ctx context.Context, backup domain.Backup) error {
+	database := br.client.Database(br.databaseName)
+	mapper := objectIDMapper{}
+
+	var trees, persons, personHistories []interface{}
+	for _, tree := range backup.Trees {
+		trees = append(trees, Tree{
+			ID:        mapper.objectID(tree.ID),
+			Name:      tree.Name,
+			Owner:     tree.Owner,
+			CreatedAt: tree.CreatedAt,
+		})
+	}
+
+	for _, treePerson := range backup.Persons {
+		persons = append(persons, mapper.person(treePerson.TreeID, treePerson.Person))
+	}
+
+	for _, treePersonChange := range backup.History {
+		change := treePersonChange.Change
+		personHistories = append(personHistories, PersonHistory{
+			ID:           mapper.objectID(change.ID),
+			PersonID:     mapper.objectID(change.PersonID),
+			ChangeType:   string(change.Type),
+			Before:       mapper.personPointer(treePersonChange.TreeID, change.Before),
+			After:        mapper.personPointer(treePersonChange.TreeID, change.After),
+			ClaimedActor: change.ClaimedActor,
+			Source:       change.Source,
+			Reason:       change.Reason,
+			Timestamp:    change.Timestamp,
+			TreeID:       mapper.treeObjectID(treePersonChange.TreeID),
+		})
+	}
+
+	for _, collection := range []struct {
+		name      string
+		documents []interface{}
+	}{
+		{treeCollectionName, trees},
+		{personCollectionName, persons},
+		{personHistoryCollectionName, personHistories},
+	} {
+		if err := insertInChunks(ctx, database.Collection(collection.name), collection.documents); err != nil {
+			return br.clean(err)
+		}
+	}
+
+	return nil
+}
+
+// clean empties the collections of a failed restore and returns its error. It does not use the context of the
+// restore, which may be the reason it failed.
+func (br BackupRepository) clean(restoreErr error) error {
+	database := br.client.Database(br.databaseName)
+	for _, collectionName := range backupCollectionNames {
+		if _, err := database.Collection(collectionName).DeleteMany(context.Background(), bson.M{}); err != nil {
+			return fmt.Errorf("%w, and cleaning the restored %s failed: %s", restoreErr, collectionName, err)
+		}
+	}
+
+	return restoreErr
+}
+
+func (br BackupRepository) IsEmpty(ctx context.Context) (bool, error) {
+	database := br.client.Database(br.databaseName)
+
+	for _, collectionName := range backupCollectionNames {
+		count, err := database.Collection(collectionName).CountDocuments(ctx, bson.M{}, options.Count().SetLimit(1))
+		if err != nil {
+			return false, err
+		}
+
+		if count > 0 {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
@@ -0,0 +1,15 @@
+package mongodb
+
+import (
+	"context"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+)
+
+// SearchBaconsNumber loads the persons around both persons on demand, one frontier per query, until the searches
+// from each of them meet. Unlike GetPersonFamilyGraphByID it is not bounded by the ascendants of a single person.
+func (pr PersonRepository) SearchBaconsNumber(ctx context.Context, personAID string, personBID string, options domain.BaconsNumberOptions, maxPersons int) (*uint, error) {
+	return domain.SearchBaconsNumber(personAID, personBID, options, maxPersons, func(personIDS []string) ([]domain.Person, error) {
+		return pr.GetPersonWithImmediateRelativesByIDS(ctx, personIDS)
+	})
+}
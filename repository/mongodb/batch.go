@@ -0,0 +1,216 @@
+package mongodb
+
+import (
+	"context"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func resolveBatchObjectID(personID string, objectIDByTempID map[string]primitive.ObjectID) (primitive.ObjectID, bool, error) {
+	if objectID, ok := objectIDByTempID[personID]; ok {
+		return objectID, true, nil
+	}
+
+	objectIDS, err := convertIDStringToObjectsIDS([]string{personID})
+	if err != nil {
+		return primitive.NilObjectID, false, err
+	}
+
+	return objectIDS[0], false, nil
+}
+
+func appendObjectIDIfMissing(objectIDS []primitive.ObjectID, objectID primitive.ObjectID) []primitive.ObjectID {
+	for _, currentObjectID := range objectIDS {
+		if currentObjectID == objectID {
+			return objectIDS
+		}
+	}
+
+	return append(objectIDS, objectID)
+}
+
+// buildRepositoryPersonsFromBatch assigns the ids up front, so links between persons of the same batch are written
+// on both documents no matter the order they were sent. Links to stored persons are returned to be updated on them.
+func buildRepositoryPersonsFromBatch(batch domain.PersonBatch) ([]Person, map[primitive.ObjectID][]primitive.ObjectID, map[primitive.ObjectID][]primitive.ObjectID, error) {
+	objectIDByTempID := make(map[string]primitive.ObjectID, len(batch))
+	for _, batchPerson := range batch {
+		objectIDByTempID[batchPerson.TempID] = primitive.NewObjectID()
+	}
+
+	personsByObjectID := make(map[primitive.ObjectID]*Person, len(batch))
+	var persons []*Person
+	for _, batchPerson := range batch {
+		person := &Person{
+			ID:          objectIDByTempID[batchPerson.TempID],
+			Name:        batchPerson.Person.Name,
+			Gender:      string(batchPerson.Person.Gender),
+			Birth:       buildRepositoryLifeEventFromDomainLifeEvent(batchPerson.Person.Birth),
+			Death:       buildRepositoryLifeEventFromDomainLifeEvent(batchPerson.Person.Death),
+			ParentIDS:   []primitive.ObjectID{},
+			ChildrenIDS: []primitive.ObjectID{},
+		}
+		personsByObjectID[person.ID] = person
+		persons = append(persons, person)
+	}
+
+	storedParentsToAddByChildren := make(map[primitive.ObjectID][]primitive.ObjectID)
+	storedChildrenToAddByParent := make(map[primitive.ObjectID][]primitive.ObjectID)
+	for _, batchPerson := range batch {
+		person := personsByObjectID[objectIDByTempID[batchPerson.TempID]]
+
+		for _, parent := range batchPerson.Person.Parents {
+			parentObjectID, inBatch, err := resolveBatchObjectID(parent.ID, objectIDByTempID)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+
+			person.ParentIDS = appendObjectIDIfMissing(person.ParentIDS, parentObjectID)
+			person.setParentRole(parentObjectID, batchPerson.Person.ParentRoles[parent.ID])
+			person.setParentLinkType(parentObjectID, batchPerson.Person.ParentLinkTypes[parent.ID])
+			if inBatch {
+				personsByObjectID[parentObjectID].ChildrenIDS = appendObjectIDIfMissing(personsByObjectID[parentObjectID].ChildrenIDS, person.ID)
+			} else {
+				storedChildrenToAddByParent[parentObjectID] = append(storedChildrenToAddByParent[parentObjectID], person.ID)
+			}
+		}
+
+		for _, children := range batchPerson.Person.Children {
+			childrenObjectID, inBatch, err := resolveBatchObjectID(children.ID, objectIDByTempID)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+
+			person.ChildrenIDS = appendObjectIDIfMissing(person.ChildrenIDS, childrenObjectID)
+			if inBatch {
+				personsByObjectID[childrenObjectID].ParentIDS = appendObjectIDIfMissing(personsByObjectID[childrenObjectID].ParentIDS, person.ID)
+			} else {
+				storedParentsToAddByChildren[childrenObjectID] = append(storedParentsToAddByChildren[childrenObjectID], person.ID)
+			}
+		}
+	}
+
+	var repositoryPersons []Person
+	for _, person := range persons {
+		repositoryPersons = append(repositoryPersons, *person)
+	}
+
+	return repositoryPersons, storedChildrenToAddByParent, storedParentsToAddByChildren, nil
+}
+
+func (pr PersonRepository) storeBatch(
+	sessCtx mongo.SessionContext,
+	persons []Person,
+	storedChildrenToAddByParent map[primitive.ObjectID][]primitive.ObjectID,
+	storedParentsToAddByChildren map[primitive.ObjectID][]primitive.ObjectID,
+	expectedVersionByObjectID map[primitive.ObjectID]int64) error {
+
+	personCollection := pr.client.Database(pr.databaseName).Collection(personCollectionName)
+	checks := newVersionChecks(expectedVersionByObjectID)
+
+	treeObjectID, err := treeScope(sessCtx)
+	if err != nil {
+		return err
+	}
+
+	var documents []interface{}
+	for _, person := range persons {
+		document := bson.M{
+			"_id":         person.ID,
+			"treeId":      treeObjectID,
+			"name":        person.Name,
+			"gender":      person.Gender,
+			"birth":       person.Birth,
+			"death":       person.Death,
+			"parentIds":   person.ParentIDS,
+			"childrenIds": person.ChildrenIDS,
+			"version":     1,
+		}
+		if len(person.ParentRoles) > 0 {
+			document["parentRoles"] = person.ParentRoles
+		}
+		if len(person.ParentLinkTypes) > 0 {
+			document["parentLinkTypes"] = person.ParentLinkTypes
+		}
+
+		documents = append(documents, document)
+	}
+
+	if len(documents) > 0 {
+		if _, err := personCollection.InsertMany(sessCtx, documents); err != nil {
+			return err
+		}
+	}
+
+	for parentObjectID, childrenObjectIDS := range storedChildrenToAddByParent {
+		if err := pr.updatePersonCheckingVersion(sessCtx,
+			parentObjectID,
+			checks.take(parentObjectID),
+			bson.M{"$addToSet": bson.M{"childrenIds": bson.M{"$each": childrenObjectIDS}}},
+		); err != nil {
+			return err
+		}
+	}
+
+	for childrenObjectID, parentObjectIDS := range storedParentsToAddByChildren {
+		if err := pr.updatePersonCheckingVersion(sessCtx,
+			childrenObjectID,
+			checks.take(childrenObjectID),
+			bson.M{"$addToSet": bson.M{"parentIds": bson.M{"$each": parentObjectIDS}}},
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (pr PersonRepository) StoreBatch(ctx context.Context, batch domain.PersonBatch) (map[string]string, error) {
+	persons, storedChildrenToAddByParent, storedParentsToAddByChildren, err := buildRepositoryPersonsFromBatch(batch)
+	if err != nil {
+		return nil, err
+	}
+
+	expectedVersionByObjectID := make(map[primitive.ObjectID]int64)
+	for _, batchPerson := range batch {
+		for objectID, version := range buildExpectedVersionsFromRelatives(batchPerson.Person) {
+			expectedVersionByObjectID[objectID] = version
+		}
+	}
+
+	var affectedObjectIDS []primitive.ObjectID
+	for _, person := range persons {
+		affectedObjectIDS = append(affectedObjectIDS, person.ID)
+	}
+	for parentObjectID := range storedChildrenToAddByParent {
+		affectedObjectIDS = append(affectedObjectIDS, parentObjectID)
+	}
+	for childrenObjectID := range storedParentsToAddByChildren {
+		affectedObjectIDS = append(affectedObjectIDS, childrenObjectID)
+	}
+
+	callback := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return nil, pr.withPersonHistory(sessCtx, affectedObjectIDS, func() error {
+			return pr.storeBatch(sessCtx, persons, storedChildrenToAddByParent, storedParentsToAddByChildren, expectedVersionByObjectID)
+		})
+	}
+
+	session, err := pr.client.StartSession()
+	if err != nil {
+		return nil, err
+	}
+	defer session.EndSession(ctx)
+
+	if _, err := session.WithTransaction(ctx, callback); err != nil {
+		return nil, err
+	}
+
+	idByTempID := make(map[string]string, len(batch))
+	for i, batchPerson := range batch {
+		idByTempID[batchPerson.TempID] = persons[i].ID.Hex()
+	}
+
+	return idByTempID, nil
+}
@@ -0,0 +1,143 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+	"github.com/CaioBittencourt/arvore-genealogica/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// namespaceNotFoundErrorCode is returned by collMod when the collection was not created yet.
+const namespaceNotFoundErrorCode = 26
+
+// personChangeEvent is a change stream event of the person collection.
+type personChangeEvent struct {
+	OperationType            string    `bson:"operationType"`
+	FullDocument             *Person   `bson:"fullDocument,omitempty"`
+	FullDocumentBeforeChange *Person   `bson:"fullDocumentBeforeChange,omitempty"`
+	WallTime                 time.Time `bson:"wallTime,omitempty"`
+}
+
+// EnableTreeEvents keeps the person documents as they were before each change, so WatchTreeEvents can tell which
+// links were removed and which tree a deleted person belonged to.
+func (pr PersonRepository) EnableTreeEvents(ctx context.Context) error {
+	database := pr.client.Database(pr.databaseName)
+	preAndPostImages := bson.M{"enabled": true}
+
+	err := database.RunCommand(ctx, bson.D{
+		{Key: "collMod", Value: personCollectionName},
+		{Key: "changeStreamPreAndPostImages", Value: preAndPostImages},
+	}).Err()
+
+	if commandError, ok := err.(mongo.CommandError); ok && commandError.Code == namespaceNotFoundErrorCode {
+		return database.CreateCollection(ctx, personCollectionName, options.CreateCollection().SetChangeStreamPreAndPostImages(preAndPostImages))
+	}
+
+	return err
+}
+
+func buildTreeEventsFromPersonChangeEvent(changeEvent personChangeEvent) []domain.TreeEvent {
+	var before, after *domain.Person
+	if changeEvent.FullDocumentBeforeChange != nil {
+		person := buildDomainPersonFromRepositoryPerson(*changeEvent.FullDocumentBeforeChange)
+		before = &person
+	}
+
+	if changeEvent.FullDocument != nil && changeEvent.OperationType != "delete" {
+		person := buildDomainPersonFromRepositoryPerson(*changeEvent.FullDocument)
+		after = &person
+	}
+
+	timestamp := changeEvent.WallTime
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	//NOTE: without the document before the change, when pre-images are not enabled, it can not be told what changed
+	if before == nil && changeEvent.OperationType != "insert" {
+		if after == nil {
+			return nil
+		}
+
+		return []domain.TreeEvent{domain.BuildPersonUpdatedTreeEvent(*after, timestamp)}
+	}
+
+	return domain.BuildTreeEvents(before, after, timestamp)
+}
+
+// treeEventStream reads the tree events of a change stream, which may hold more than one for each change.
+type treeEventStream struct {
+	changeStream  *mongo.ChangeStream
+	pendingEvents []domain.TreeEvent
+	event         domain.TreeEvent
+	err           error
+}
+
+func (s *treeEventStream) Next(ctx context.Context) bool {
+	for len(s.pendingEvents) == 0 {
+		if !s.changeStream.Next(ctx) {
+			return false
+		}
+
+		var changeEvent personChangeEvent
+		if err := s.changeStream.Decode(&changeEvent); err != nil {
+			s.err = err
+			return false
+		}
+
+		s.pendingEvents = buildTreeEventsFromPersonChangeEvent(changeEvent)
+	}
+
+	s.event, s.pendingEvents = s.pendingEvents[0], s.pendingEvents[1:]
+	return true
+}
+
+func (s *treeEventStream) Event() domain.TreeEvent {
+	return s.event
+}
+
+func (s *treeEventStream) Err() error {
+	if s.err != nil {
+		return s.err
+	}
+
+	return s.changeStream.Err()
+}
+
+func (s *treeEventStream) Close(ctx context.Context) error {
+	return s.changeStream.Close(ctx)
+}
+
+// WatchTreeEvents opens a change stream on the persons of the tree of the context.
+func (pr PersonRepository) WatchTreeEvents(ctx context.Context) (repository.TreeEventStream, error) {
+	personCollection := pr.client.Database(pr.databaseName).Collection(personCollectionName)
+
+	treeObjectID, err := treeScope(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	fullDocumentTreeID := fmt.Sprintf("fullDocument.%s", treeIDFieldName)
+	fullDocumentBeforeChangeTreeID := fmt.Sprintf("fullDocumentBeforeChange.%s", treeIDFieldName)
+	matchStage := bson.M{"$match": bson.M{
+		"operationType": bson.M{"$in": bson.A{"insert", "update", "replace", "delete"}},
+		"$or": bson.A{
+			bson.M{"fullDocument._id": bson.M{"$exists": true}, fullDocumentTreeID: treeObjectID},
+			bson.M{"fullDocumentBeforeChange._id": bson.M{"$exists": true}, fullDocumentBeforeChangeTreeID: treeObjectID},
+		},
+	}}
+
+	changeStream, err := personCollection.Watch(ctx, bson.A{matchStage}, options.ChangeStream().
+		SetFullDocument(options.UpdateLookup).
+		SetFullDocumentBeforeChange(options.WhenAvailable))
+	if err != nil {
+		return nil, err
+	}
+
+	return &treeEventStream{changeStream: changeStream}, nil
+}
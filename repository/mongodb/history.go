@@ -0,0 +1,510 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const personHistoryCollectionName = "person_history"
+
+type PersonHistory struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty"`
+	PersonID   primitive.ObjectID `bson:"personId"`
+	ChangeType string             `bson:"changeType"`
+	Before     *Person            `bson:"before,omitempty"`
+	After      *Person            `bson:"after,omitempty"`
+	// ClaimedActor keeps the actor key of the entries recorded before the source was.
+	ClaimedActor string              `bson:"actor,omitempty"`
+	Source       string              `bson:"source,omitempty"`
+	Reason       string              `bson:"reason,omitempty"`
+	Timestamp    time.Time           `bson:"timestamp"`
+	TreeID       *primitive.ObjectID `bson:"treeId,omitempty"`
+}
+
+func buildDomainPersonChangeFromPersonHistory(personHistory PersonHistory) domain.PersonChange {
+	personChange := domain.PersonChange{
+		ID:           personHistory.ID.Hex(),
+		PersonID:     personHistory.PersonID.Hex(),
+		Type:         domain.PersonChangeType(personHistory.ChangeType),
+		ClaimedActor: personHistory.ClaimedActor,
+		Source:       personHistory.Source,
+		Reason:       personHistory.Reason,
+		Timestamp:    personHistory.Timestamp,
+	}
+
+	if personHistory.Before != nil {
+		before := buildDomainPersonFromRepositoryPerson(*personHistory.Before)
+		personChange.Before = &before
+	}
+
+	if personHistory.After != nil {
+		after := buildDomainPersonFromRepositoryPerson(*personHistory.After)
+		personChange.After = &after
+	}
+
+	return personChange
+}
+
+func (pr PersonRepository) getPersonsByObjectIDS(ctx context.Context, objectIDS []primitive.ObjectID) (map[primitive.ObjectID]Person, error) {
+	personCollection := pr.client.Database(pr.databaseName).Collection(personCollectionName)
+
+	filter, err := scopedFilter(ctx, bson.M{"_id": bson.M{"$in": objectIDS}})
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := personCollection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var persons []Person
+	if err := cursor.All(ctx, &persons); err != nil {
+		if err := cursor.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	personsByObjectID := make(map[primitive.ObjectID]Person, len(persons))
+	for _, person := range persons {
+		personsByObjectID[person.ID] = person
+	}
+
+	return personsByObjectID, nil
+}
+
+// withPersonHistory must run inside a transaction. It snapshots the affected persons before and after mutate and
+// appends one history entry for each person that changed, with the actor and reason found in the context.
+func (pr PersonRepository) withPersonHistory(sessCtx mongo.SessionContext, affectedObjectIDS []primitive.ObjectID, mutate func() error) error {
+	personsBefore, err := pr.getPersonsByObjectIDS(sessCtx, affectedObjectIDS)
+	if err != nil {
+		return err
+	}
+
+	if err := mutate(); err != nil {
+		return err
+	}
+
+	personsAfter, err := pr.getPersonsByObjectIDS(sessCtx, affectedObjectIDS)
+	if err != nil {
+		return err
+	}
+
+	changeMetadata := domain.ChangeMetadataFromContext(sessCtx)
+	timestamp := time.Now().UTC()
+
+	treeObjectID, err := treeScope(sessCtx)
+	if err != nil {
+		return err
+	}
+
+	var historyEntries []interface{}
+	alreadyRecorded := make(map[primitive.ObjectID]bool, len(affectedObjectIDS))
+	for _, objectID := range affectedObjectIDS {
+		if alreadyRecorded[objectID] {
+			continue
+		}
+		alreadyRecorded[objectID] = true
+
+		before, existedBefore := personsBefore[objectID]
+		after, existsAfter := personsAfter[objectID]
+
+		personHistory := PersonHistory{
+			PersonID:     objectID,
+			ClaimedActor: changeMetadata.ClaimedActor,
+			Source:       changeMetadata.Source,
+			Reason:       changeMetadata.Reason,
+			Timestamp:    timestamp,
+			TreeID:       treeObjectID,
+		}
+
+		switch {
+		case !existedBefore && existsAfter:
+			personHistory.ChangeType = string(domain.PersonCreatedChange)
+			personHistory.After = &after
+		case existedBefore && !existsAfter:
+			personHistory.ChangeType = string(domain.PersonDeletedChange)
+			personHistory.Before = &before
+		case existedBefore && existsAfter:
+			if isSamePersonDocument(before, after) {
+				continue
+			}
+
+			personHistory.ChangeType = string(domain.PersonUpdatedChange)
+			personHistory.Before = &before
+			personHistory.After = &after
+		default:
+			continue
+		}
+
+		historyEntries = append(historyEntries, personHistory)
+	}
+
+	if len(historyEntries) == 0 {
+		return nil
+	}
+
+	personHistoryCollection := pr.client.Database(pr.databaseName).Collection(personHistoryCollectionName)
+	_, err = personHistoryCollection.InsertMany(sessCtx, historyEntries)
+	return err
+}
+
+func isSamePersonDocument(personA Person, personB Person) bool {
+	documentA, errA := bson.Marshal(personA)
+	documentB, errB := bson.Marshal(personB)
+	if errA != nil || errB != nil {
+		return false
+	}
+
+	return string(documentA) == string(documentB)
+}
+
+func (pr PersonRepository) GetPersonHistory(ctx context.Context, personID string) ([]domain.PersonChange, error) {
+	personHistoryCollection := pr.client.Database(pr.databaseName).Collection(personHistoryCollectionName)
+
+	objectIDS, err := convertIDStringToObjectsIDS([]string{personID})
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := scopedFilter(ctx, bson.M{"personId": objectIDS[0]})
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := personHistoryCollection.Find(ctx,
+		filter,
+		options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var personHistories []PersonHistory
+	if err := cursor.All(ctx, &personHistories); err != nil {
+		if err := cursor.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	var personChanges []domain.PersonChange
+	for _, personHistory := range personHistories {
+		personChanges = append(personChanges, buildDomainPersonChangeFromPersonHistory(personHistory))
+	}
+
+	return personChanges, nil
+}
+
+// getFirstPersonChanges returns the first change of each person matched by filter, in the order of direction applied
+// to the timestamps: -1 for the last change and 1 for the first one.
+func (pr PersonRepository) getFirstPersonChanges(ctx context.Context, filter bson.M, direction int) ([]PersonHistory, error) {
+	personHistoryCollection := pr.client.Database(pr.databaseName).Collection(personHistoryCollectionName)
+
+	pipelineStages := bson.A{
+		bson.M{"$match": filter},
+		bson.M{"$sort": bson.D{{Key: "personId", Value: 1}, {Key: "timestamp", Value: direction}, {Key: "_id", Value: direction}}},
+		bson.M{"$group": bson.M{"_id": "$personId", "firstChange": bson.M{"$first": "$$ROOT"}}},
+		bson.M{"$replaceRoot": bson.M{"newRoot": "$firstChange"}},
+	}
+
+	cursor, err := personHistoryCollection.Aggregate(ctx, pipelineStages)
+	if err != nil {
+		return nil, err
+	}
+
+	var personHistories []PersonHistory
+	if err := cursor.All(ctx, &personHistories); err != nil {
+		if err := cursor.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	return personHistories, nil
+}
+
+// getPersonsAsOf returns the persons that existed at the given time as they were then: the document after their last
+// change up to that time or, when their first change came later, the document before it. Persons without any change
+// were stored before the history was recorded and did not change since, so their current document is used when
+// their id was created up to that time.
+func (pr PersonRepository) getPersonsAsOf(ctx context.Context, at time.Time, personObjectIDS []primitive.ObjectID) (map[string]Person, error) {
+	lastChangesFilter, err := scopedFilter(ctx, bson.M{"personId": bson.M{"$in": personObjectIDS}, "timestamp": bson.M{"$lte": at}})
+	if err != nil {
+		return nil, err
+	}
+
+	lastChanges, err := pr.getFirstPersonChanges(ctx, lastChangesFilter, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	personsByID := make(map[string]Person, len(personObjectIDS))
+	hasChanges := make(map[primitive.ObjectID]bool, len(personObjectIDS))
+	for _, personHistory := range lastChanges {
+		hasChanges[personHistory.PersonID] = true
+		if personHistory.After != nil {
+			personsByID[personHistory.PersonID.Hex()] = *personHistory.After
+		}
+	}
+
+	var withoutChangesObjectIDS []primitive.ObjectID
+	for _, objectID := range personObjectIDS {
+		if !hasChanges[objectID] {
+			withoutChangesObjectIDS = append(withoutChangesObjectIDS, objectID)
+		}
+	}
+
+	if len(withoutChangesObjectIDS) == 0 {
+		return personsByID, nil
+	}
+
+	nextChangesFilter, err := scopedFilter(ctx, bson.M{"personId": bson.M{"$in": withoutChangesObjectIDS}, "timestamp": bson.M{"$gt": at}})
+	if err != nil {
+		return nil, err
+	}
+
+	nextChanges, err := pr.getFirstPersonChanges(ctx, nextChangesFilter, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, personHistory := range nextChanges {
+		hasChanges[personHistory.PersonID] = true
+		if personHistory.Before != nil {
+			personsByID[personHistory.PersonID.Hex()] = *personHistory.Before
+		}
+	}
+
+	var unrecordedObjectIDS []primitive.ObjectID
+	for _, objectID := range withoutChangesObjectIDS {
+		if !hasChanges[objectID] && !objectID.Timestamp().After(at) {
+			unrecordedObjectIDS = append(unrecordedObjectIDS, objectID)
+		}
+	}
+
+	if len(unrecordedObjectIDS) == 0 {
+		return personsByID, nil
+	}
+
+	currentPersonsByObjectID, err := pr.getPersonsByObjectIDS(ctx, unrecordedObjectIDS)
+	if err != nil {
+		return nil, err
+	}
+
+	for objectID, person := range currentPersonsByObjectID {
+		personsByID[objectID.Hex()] = person
+	}
+
+	return personsByID, nil
+}
+
+// getRelativesAsOf walks from persons through the ids returned by next, reading each generation as it was at the
+// given time, the way graphLookup walks the current documents. It returns the relatives by depth, starting at 0,
+// and stops after maxDepth when it is set. Persons already read are kept in loaded.
+func (pr PersonRepository) getRelativesAsOf(
+	ctx context.Context,
+	at time.Time,
+	persons []Person,
+	next func(Person) []primitive.ObjectID,
+	maxDepth *int,
+	loaded map[string]Person) ([][]Person, error) {
+
+	visited := make(map[primitive.ObjectID]bool, len(persons))
+	for _, person := range persons {
+		visited[person.ID] = true
+	}
+
+	var relativesByDepth [][]Person
+	generation := persons
+	for depth := 0; maxDepth == nil || depth <= *maxDepth; depth++ {
+		var generationObjectIDS, notLoadedObjectIDS []primitive.ObjectID
+		for _, person := range generation {
+			for _, objectID := range next(person) {
+				if visited[objectID] {
+					continue
+				}
+				visited[objectID] = true
+
+				generationObjectIDS = append(generationObjectIDS, objectID)
+				if _, ok := loaded[objectID.Hex()]; !ok {
+					notLoadedObjectIDS = append(notLoadedObjectIDS, objectID)
+				}
+			}
+		}
+
+		if len(generationObjectIDS) == 0 {
+			break
+		}
+
+		if len(notLoadedObjectIDS) > 0 {
+			personsByID, err := pr.getPersonsAsOf(ctx, at, notLoadedObjectIDS)
+			if err != nil {
+				return nil, err
+			}
+
+			for personID, person := range personsByID {
+				loaded[personID] = person
+			}
+		}
+
+		generation = nil
+		for _, objectID := range generationObjectIDS {
+			if person, ok := loaded[objectID.Hex()]; ok {
+				generation = append(generation, person)
+			}
+		}
+		relativesByDepth = append(relativesByDepth, generation)
+	}
+
+	return relativesByDepth, nil
+}
+
+func withoutPerson(persons []Person, personID primitive.ObjectID) []Person {
+	var filteredPersons []Person
+	for _, person := range persons {
+		if person.ID != personID {
+			filteredPersons = append(filteredPersons, person)
+		}
+	}
+
+	return filteredPersons
+}
+
+func parentIDSOf(person Person) []primitive.ObjectID {
+	return person.ParentIDS
+}
+
+func childrenIDSOf(person Person) []primitive.ObjectID {
+	return person.ChildrenIDS
+}
+
+func (pr PersonRepository) GetPersonAsOf(ctx context.Context, personID string, at time.Time) (*domain.Person, error) {
+	objectIDS, err := convertIDStringToObjectsIDS([]string{personID})
+	if err != nil {
+		return nil, err
+	}
+
+	personsByID, err := pr.getPersonsAsOf(ctx, at, objectIDS)
+	if err != nil {
+		return nil, err
+	}
+
+	person, ok := personsByID[personID]
+	if !ok {
+		return nil, nil
+	}
+
+	relativeObjectIDS := append(append([]primitive.ObjectID{}, person.ParentIDS...), person.ChildrenIDS...)
+	if len(relativeObjectIDS) > 0 {
+		relativesByID, err := pr.getPersonsAsOf(ctx, at, relativeObjectIDS)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, parentObjectID := range person.ParentIDS {
+			if parent, ok := relativesByID[parentObjectID.Hex()]; ok {
+				person.Parents = append(person.Parents, parent)
+			}
+		}
+
+		for _, childrenObjectID := range person.ChildrenIDS {
+			if children, ok := relativesByID[childrenObjectID.Hex()]; ok {
+				person.Children = append(person.Children, children)
+			}
+		}
+	}
+
+	domainPerson := buildDomainPersonFromRepositoryPerson(person)
+	return &domainPerson, nil
+}
+
+// GetPersonFamilyGraphAsOf returns the family graph of personID as it was at the given time, with the same scope
+// GetPersonFamilyGraphByID applies to the current documents. Only the persons reachable within the scope are read.
+func (pr PersonRepository) GetPersonFamilyGraphAsOf(ctx context.Context, personID string, at time.Time, scope domain.FamilyGraphScope) (*domain.FamilyGraph, error) {
+	objectIDS, err := convertIDStringToObjectsIDS([]string{personID})
+	if err != nil {
+		return nil, err
+	}
+
+	loaded, err := pr.getPersonsAsOf(ctx, at, objectIDS)
+	if err != nil {
+		return nil, err
+	}
+
+	searchedPerson, ok := loaded[personID]
+	if !ok {
+		return nil, nil
+	}
+
+	var ancestorsMaxDepth *int
+	if scope.AncestorGenerations != nil {
+		depth := int(*scope.AncestorGenerations) - 1
+		ancestorsMaxDepth = &depth
+	}
+
+	ancestorsByDepth, err := pr.getRelativesAsOf(ctx, at, []Person{searchedPerson}, parentIDSOf, ancestorsMaxDepth, loaded)
+	if err != nil {
+		return nil, err
+	}
+	relativesLists := ancestorsByDepth
+
+	var collateralAncestors []Person
+	for depth, ancestors := range ancestorsByDepth {
+		if uint(depth) < scope.CollateralGenerations {
+			collateralAncestors = append(collateralAncestors, ancestors...)
+		}
+	}
+
+	if len(collateralAncestors) > 0 {
+		depth := 1
+		collateralsByDepth, err := pr.getRelativesAsOf(ctx, at, collateralAncestors, childrenIDSOf, &depth, loaded)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, collateralRelatives := range collateralsByDepth {
+			if scope.DescendantGenerations == 0 {
+				collateralRelatives = withoutChildrenOf(collateralRelatives, searchedPerson.ID)
+			}
+			relativesLists = append(relativesLists, collateralRelatives)
+		}
+	}
+
+	if scope.DescendantGenerations > 0 {
+		depth := int(scope.DescendantGenerations) - 1
+		descendantsByDepth, err := pr.getRelativesAsOf(ctx, at, []Person{searchedPerson}, childrenIDSOf, &depth, loaded)
+		if err != nil {
+			return nil, err
+		}
+		relativesLists = append(relativesLists, descendantsByDepth...)
+	}
+
+	if !scope.ExcludeSpouses && len(searchedPerson.ChildrenIDS) > 0 {
+		depth := 0
+		childrenByDepth, err := pr.getRelativesAsOf(ctx, at, []Person{searchedPerson}, childrenIDSOf, &depth, loaded)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, children := range childrenByDepth {
+			spousesByDepth, err := pr.getRelativesAsOf(ctx, at, children, parentIDSOf, &depth, loaded)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, spouses := range spousesByDepth {
+				relativesLists = append(relativesLists, withoutPerson(spouses, searchedPerson.ID))
+			}
+		}
+	}
+
+	searchedPersonWithRelatives := PersonWithRelatives{Person: searchedPerson, Relatives: mergeRelativesIntoSet(relativesLists)}
+	familyGraph := buildFamilyGraphFromPersonRelatives(searchedPersonWithRelatives)
+
+	return &familyGraph, nil
+}
@@ -0,0 +1,133 @@
+package mongodb
+
+import (
+	"context"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func (pr PersonRepository) GetAll(ctx context.Context) ([]domain.Person, error) {
+	personCollection := pr.client.Database(pr.databaseName).Collection(personCollectionName)
+
+	filter, err := scopedFilter(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := personCollection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var persons []Person
+	if err := cursor.All(ctx, &persons); err != nil {
+		if err := cursor.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	return buildDomainPersonsFromRepositoryPersons(persons), nil
+}
+
+func buildRepairUpdateFromIntegrityIssue(issue domain.IntegrityIssue) (primitive.ObjectID, bson.M, error) {
+	objectIDS, err := convertIDStringToObjectsIDS(append([]string{issue.PersonID}, issue.RelatedPersonIDS...))
+	if err != nil {
+		return primitive.NilObjectID, nil, err
+	}
+
+	personObjectID, relatedObjectID := objectIDS[0], objectIDS[1]
+	switch issue.Type {
+	case domain.AsymmetricParentLinkIssue:
+		return relatedObjectID, bson.M{"$addToSet": bson.M{"childrenIds": personObjectID}}, nil
+	case domain.AsymmetricChildLinkIssue:
+		return relatedObjectID, bson.M{"$addToSet": bson.M{"parentIds": personObjectID}}, nil
+	case domain.DanglingParentIDIssue:
+		return personObjectID, bson.M{"$pull": bson.M{"parentIds": relatedObjectID}}, nil
+	case domain.DanglingChildIDIssue:
+		return personObjectID, bson.M{"$pull": bson.M{"childrenIds": relatedObjectID}}, nil
+	}
+
+	return primitive.NilObjectID, nil, nil
+}
+
+func (pr PersonRepository) getIntegrityIssuesPersons(ctx context.Context, issues []domain.IntegrityIssue) ([]domain.Person, error) {
+	var personIDS []string
+	for _, issue := range issues {
+		personIDS = append(personIDS, issue.PersonID)
+		personIDS = append(personIDS, issue.RelatedPersonIDS...)
+	}
+
+	objectIDS, err := convertIDStringToObjectsIDS(personIDS)
+	if err != nil {
+		return nil, err
+	}
+
+	personsByObjectID, err := pr.getPersonsByObjectIDS(ctx, objectIDS)
+	if err != nil {
+		return nil, err
+	}
+
+	persons := make([]Person, 0, len(personsByObjectID))
+	for _, person := range personsByObjectID {
+		persons = append(persons, person)
+	}
+
+	return buildDomainPersonsFromRepositoryPersons(persons), nil
+}
+
+// RepairIntegrityIssues reads the persons of every issue again inside the transaction and only repairs the issues
+// that still hold, so a link changed after the check is never overwritten.
+func (pr PersonRepository) RepairIntegrityIssues(ctx context.Context, issues []domain.IntegrityIssue) error {
+	callback := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		persons, err := pr.getIntegrityIssuesPersons(sessCtx, issues)
+		if err != nil {
+			return nil, err
+		}
+
+		var affectedObjectIDS []primitive.ObjectID
+		updatesByObjectID := make(map[primitive.ObjectID][]bson.M)
+		for _, issue := range domain.RecheckFixableIssues(issues, persons) {
+			personObjectID, update, err := buildRepairUpdateFromIntegrityIssue(issue)
+			if err != nil {
+				return nil, err
+			}
+
+			if update == nil {
+				continue
+			}
+
+			if _, ok := updatesByObjectID[personObjectID]; !ok {
+				affectedObjectIDS = append(affectedObjectIDS, personObjectID)
+			}
+			updatesByObjectID[personObjectID] = append(updatesByObjectID[personObjectID], update)
+		}
+
+		if len(affectedObjectIDS) == 0 {
+			return nil, nil
+		}
+
+		return nil, pr.withPersonHistory(sessCtx, affectedObjectIDS, func() error {
+			for _, personObjectID := range affectedObjectIDS {
+				for _, update := range updatesByObjectID[personObjectID] {
+					if err := pr.updatePersonCheckingVersion(sessCtx, personObjectID, 0, update); err != nil {
+						return err
+					}
+				}
+			}
+
+			return nil
+		})
+	}
+
+	session, err := pr.client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, callback)
+	return err
+}
@@ -0,0 +1,123 @@
+package mongodb
+
+import (
+	"context"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// updateParentLink applies childUpdate to child and operator ($addToSet or $pull) to the childrenIds of parent
+// inside a single transaction, checking the version of both when it is set and running check, when given, before
+// any write.
+func (pr PersonRepository) updateParentLink(ctx context.Context, child domain.Person, parent domain.Person, operator string, childUpdate bson.M, check func(sessCtx mongo.SessionContext) error) error {
+	objectIDS, err := convertIDStringToObjectsIDS([]string{child.ID, parent.ID})
+	if err != nil {
+		return err
+	}
+	childObjectID, parentObjectID := objectIDS[0], objectIDS[1]
+
+	callback := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		if check != nil {
+			if err := check(sessCtx); err != nil {
+				return nil, err
+			}
+		}
+
+		err := pr.withPersonHistory(sessCtx, objectIDS, func() error {
+			if err := pr.updatePersonCheckingVersion(sessCtx, childObjectID, child.Version, childUpdate); err != nil {
+				return err
+			}
+
+			return pr.updatePersonCheckingVersion(sessCtx, parentObjectID, parent.Version, bson.M{operator: bson.M{"childrenIds": childObjectID}})
+		})
+
+		return nil, err
+	}
+
+	session, err := pr.client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, callback)
+	return err
+}
+
+func parentRoleFieldName(parentID string) string {
+	return "parentRoles." + parentID
+}
+
+func parentLinkTypeFieldName(parentID string) string {
+	return "parentLinkTypes." + parentID
+}
+
+// checkAncestry reads the ancestors of parent again, so a link stored after the service validated this one can not
+// make child its own ancestor.
+func (pr PersonRepository) checkAncestry(ctx context.Context, child domain.Person, parent domain.Person) error {
+	parentFamilyGraph, err := pr.GetPersonFamilyGraphByID(ctx, parent.ID, domain.FamilyGraphScope{ExcludeSpouses: true})
+	if err != nil {
+		return err
+	}
+
+	var parentAncestorIDS map[string]bool
+	if parentFamilyGraph != nil {
+		parentAncestorIDS = parentFamilyGraph.AncestorIDS(parent.ID)
+	}
+
+	return domain.ValidateAncestry(child, parent, parentAncestorIDS)
+}
+
+// LinkParent only stores the role of mothers and fathers and the link type of parents that are not biological. The
+// ancestors of parent are read again inside the transaction when it is a new parent of child. Two links racing to
+// make two persons parents of each other write both of them, so one transaction is retried and sees the other link.
+func (pr PersonRepository) LinkParent(ctx context.Context, child domain.Person, parent domain.Person, role domain.ParentRoleType, linkType domain.LinkType) error {
+	objectIDS, err := convertIDStringToObjectsIDS([]string{parent.ID})
+	if err != nil {
+		return err
+	}
+
+	fieldsToSet, fieldsToUnset := bson.M{}, bson.M{}
+	if role == domain.GenericParentRole {
+		fieldsToUnset[parentRoleFieldName(parent.ID)] = ""
+	} else {
+		fieldsToSet[parentRoleFieldName(parent.ID)] = string(role)
+	}
+
+	if linkType == domain.BiologicalLinkType {
+		fieldsToUnset[parentLinkTypeFieldName(parent.ID)] = ""
+	} else {
+		fieldsToSet[parentLinkTypeFieldName(parent.ID)] = string(linkType)
+	}
+
+	childUpdate := bson.M{"$addToSet": bson.M{"parentIds": objectIDS[0]}}
+	if len(fieldsToSet) > 0 {
+		childUpdate["$set"] = fieldsToSet
+	}
+	if len(fieldsToUnset) > 0 {
+		childUpdate["$unset"] = fieldsToUnset
+	}
+
+	var check func(sessCtx mongo.SessionContext) error
+	if !child.HasParent(parent) {
+		check = func(sessCtx mongo.SessionContext) error {
+			return pr.checkAncestry(sessCtx, child, parent)
+		}
+	}
+
+	return pr.updateParentLink(ctx, child, parent, "$addToSet", childUpdate, check)
+}
+
+func (pr PersonRepository) UnlinkParent(ctx context.Context, child domain.Person, parent domain.Person) error {
+	objectIDS, err := convertIDStringToObjectsIDS([]string{parent.ID})
+	if err != nil {
+		return err
+	}
+
+	return pr.updateParentLink(ctx, child, parent, "$pull", bson.M{
+		"$pull":  bson.M{"parentIds": objectIDS[0]},
+		"$unset": bson.M{parentRoleFieldName(parent.ID): "", parentLinkTypeFieldName(parent.ID): ""},
+	}, nil)
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/CaioBittencourt/arvore-genealogica/domain"
+	applicationErrors "github.com/CaioBittencourt/arvore-genealogica/errors"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -23,15 +24,32 @@ type PersonWithRelatives struct {
 	Relatives []Person `bson:"relatives,omitempty"`
 }
 
+// personWithRelative is a PersonWithRelatives unwound, one document per relative.
+type personWithRelative struct {
+	Person   `bson:"inline"`
+	Relative *Person `bson:"relatives,omitempty"`
+}
+
+type LifeEvent struct {
+	Date  string `bson:"date,omitempty"`
+	Place string `bson:"place,omitempty"`
+}
+
 type Person struct {
-	ID          primitive.ObjectID   `bson:"_id,omitempty"`
-	Name        string               `bson:"name,omitempty"`
-	Gender      string               `bson:"gender,omitempty"`
-	ParentIDS   []primitive.ObjectID `bson:"parentIds,omitempty"`
-	ChildrenIDS []primitive.ObjectID `bson:"childrenIds,omitempty"`
-	Parents     []Person             `bson:"parents,omitempty"`
-	Children    []Person             `bson:"children,omitempty"`
-	DepthField  uint                 `bson:"depthField,omitempty"`
+	ID              primitive.ObjectID   `bson:"_id,omitempty"`
+	Name            string               `bson:"name,omitempty"`
+	Gender          string               `bson:"gender,omitempty"`
+	Birth           *LifeEvent           `bson:"birth,omitempty"`
+	Death           *LifeEvent           `bson:"death,omitempty"`
+	ParentIDS       []primitive.ObjectID `bson:"parentIds,omitempty"`
+	ParentRoles     map[string]string    `bson:"parentRoles,omitempty"`
+	ParentLinkTypes map[string]string    `bson:"parentLinkTypes,omitempty"`
+	ChildrenIDS     []primitive.ObjectID `bson:"childrenIds,omitempty"`
+	Parents         []Person             `bson:"parents,omitempty"`
+	Children        []Person             `bson:"children,omitempty"`
+	TreeID          *primitive.ObjectID  `bson:"treeId,omitempty"`
+	DepthField      uint                 `bson:"depthField,omitempty"`
+	Version         int64                `bson:"version,omitempty"`
 }
 
 type PersonRepository struct {
@@ -43,10 +61,80 @@ func NewPersonRepository(client mongo.Client, databaseName string) PersonReposit
 	return PersonRepository{client: client, databaseName: databaseName}
 }
 
+func buildRepositoryLifeEventFromDomainLifeEvent(lifeEvent *domain.LifeEvent) *LifeEvent {
+	if lifeEvent == nil {
+		return nil
+	}
+
+	return &LifeEvent{Date: lifeEvent.Date, Place: lifeEvent.Place}
+}
+
+func buildDomainLifeEventFromRepositoryLifeEvent(lifeEvent *LifeEvent) *domain.LifeEvent {
+	if lifeEvent == nil {
+		return nil
+	}
+
+	return &domain.LifeEvent{Date: lifeEvent.Date, Place: lifeEvent.Place}
+}
+
+// buildDomainParentRoles keeps a nil map for persons whose parents have no role.
+func buildDomainParentRoles(parentRoles map[string]string) map[string]domain.ParentRoleType {
+	if len(parentRoles) == 0 {
+		return nil
+	}
+
+	domainParentRoles := make(map[string]domain.ParentRoleType, len(parentRoles))
+	for parentID, role := range parentRoles {
+		domainParentRoles[parentID] = domain.ParentRoleType(role)
+	}
+
+	return domainParentRoles
+}
+
+// setParentRole only keeps the roles of mothers and fathers, parents without a role are generic parents.
+func (p *Person) setParentRole(parentObjectID primitive.ObjectID, role domain.ParentRoleType) {
+	if role == "" || role == domain.GenericParentRole {
+		return
+	}
+
+	if p.ParentRoles == nil {
+		p.ParentRoles = make(map[string]string)
+	}
+	p.ParentRoles[parentObjectID.Hex()] = string(role)
+}
+
+// buildDomainParentLinkTypes keeps a nil map for persons whose parents are all biological.
+func buildDomainParentLinkTypes(parentLinkTypes map[string]string) map[string]domain.LinkType {
+	if len(parentLinkTypes) == 0 {
+		return nil
+	}
+
+	domainParentLinkTypes := make(map[string]domain.LinkType, len(parentLinkTypes))
+	for parentID, linkType := range parentLinkTypes {
+		domainParentLinkTypes[parentID] = domain.LinkType(linkType)
+	}
+
+	return domainParentLinkTypes
+}
+
+// setParentLinkType only keeps the link types of parents that are not biological.
+func (p *Person) setParentLinkType(parentObjectID primitive.ObjectID, linkType domain.LinkType) {
+	if linkType == "" || linkType == domain.BiologicalLinkType {
+		return
+	}
+
+	if p.ParentLinkTypes == nil {
+		p.ParentLinkTypes = make(map[string]string)
+	}
+	p.ParentLinkTypes[parentObjectID.Hex()] = string(linkType)
+}
+
 func buildRepositoryPersonFromDomainPerson(domainPerson domain.Person) Person {
 	repositoryPerson := Person{
 		Name:        domainPerson.Name,
 		Gender:      string(domainPerson.Gender),
+		Birth:       buildRepositoryLifeEventFromDomainLifeEvent(domainPerson.Birth),
+		Death:       buildRepositoryLifeEventFromDomainLifeEvent(domainPerson.Death),
 		ChildrenIDS: []primitive.ObjectID{},
 		ParentIDS:   []primitive.ObjectID{},
 	}
@@ -62,6 +150,8 @@ func buildRepositoryPersonFromDomainPerson(domainPerson domain.Person) Person {
 		objectID, err := primitive.ObjectIDFromHex(parent.ID)
 		if err == nil {
 			repositoryPerson.ParentIDS = append(repositoryPerson.ParentIDS, objectID)
+			repositoryPerson.setParentRole(objectID, domainPerson.ParentRoles[parent.ID])
+			repositoryPerson.setParentLinkType(objectID, domainPerson.ParentLinkTypes[parent.ID])
 		}
 	}
 
@@ -77,9 +167,15 @@ func buildRepositoryPersonFromDomainPerson(domainPerson domain.Person) Person {
 
 func buildDomainPersonFromRepositoryPerson(personRepository Person) domain.Person {
 	person := domain.Person{
-		ID:     personRepository.ID.Hex(),
-		Name:   personRepository.Name,
-		Gender: domain.GenderType(personRepository.Gender),
+		ID:      personRepository.ID.Hex(),
+		Name:    personRepository.Name,
+		Gender:  domain.GenderType(personRepository.Gender),
+		Birth:   buildDomainLifeEventFromRepositoryLifeEvent(personRepository.Birth),
+		Death:   buildDomainLifeEventFromRepositoryLifeEvent(personRepository.Death),
+		Version: personRepository.Version,
+
+		ParentRoles:     buildDomainParentRoles(personRepository.ParentRoles),
+		ParentLinkTypes: buildDomainParentLinkTypes(personRepository.ParentLinkTypes),
 	}
 
 	if len(personRepository.Parents) > 0 {
@@ -121,11 +217,16 @@ func buildFamilyGraphFromPersonRelatives(personWithRelatives PersonWithRelatives
 
 	searchedPersonID := personWithRelatives.ID.Hex()
 	personRelativesMap[searchedPersonID] = Person{
-		ID:          personWithRelatives.ID,
-		Name:        personWithRelatives.Name,
-		Gender:      personWithRelatives.Gender,
-		ParentIDS:   personWithRelatives.ParentIDS,
-		ChildrenIDS: personWithRelatives.ChildrenIDS,
+		ID:              personWithRelatives.ID,
+		Name:            personWithRelatives.Name,
+		Gender:          personWithRelatives.Gender,
+		Birth:           personWithRelatives.Birth,
+		Death:           personWithRelatives.Death,
+		Version:         personWithRelatives.Version,
+		ParentIDS:       personWithRelatives.ParentIDS,
+		ParentRoles:     personWithRelatives.ParentRoles,
+		ParentLinkTypes: personWithRelatives.ParentLinkTypes,
+		ChildrenIDS:     personWithRelatives.ChildrenIDS,
 	}
 	for _, relatedPerson := range personWithRelatives.Relatives {
 		personRelativesMap[relatedPerson.ID.Hex()] = relatedPerson
@@ -152,7 +253,13 @@ func buildFamilyRelationshipsFromPersonRelatives(
 		ID:         personRepository.ID.Hex(),
 		Name:       personRepository.Name,
 		Gender:     domain.GenderType(personRepository.Gender),
+		Birth:      buildDomainLifeEventFromRepositoryLifeEvent(personRepository.Birth),
+		Death:      buildDomainLifeEventFromRepositoryLifeEvent(personRepository.Death),
 		Generation: generation,
+		Version:    personRepository.Version,
+
+		ParentRoles:     buildDomainParentRoles(personRepository.ParentRoles),
+		ParentLinkTypes: buildDomainParentLinkTypes(personRepository.ParentLinkTypes),
 	}
 
 	graphMembersMapped[person.ID] = &person
@@ -207,7 +314,7 @@ func convertIDStringToObjectsIDS(personIDS []string) ([]primitive.ObjectID, erro
 	for _, personID := range personIDS {
 		personObjectId, err := primitive.ObjectIDFromHex(personID)
 		if err != nil {
-			return nil, err
+			return nil, applicationErrors.NewApplicationError(fmt.Sprintf("invalid person id %s", personID), applicationErrors.InvalidPersonIDErrorCode)
 		}
 		objectIDS = append(objectIDS, personObjectId)
 	}
@@ -223,14 +330,20 @@ func (pr PersonRepository) graphlookupGetPersonRelativesByPersonIDS(ctx context.
 		return nil, err
 	}
 
-	matchStage := bson.M{"$match": bson.M{"_id": bson.M{"$in": objectIDS}}}
+	treeObjectID, err := treeScope(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	matchStage := bson.M{"$match": bson.M{"_id": bson.M{"$in": objectIDS}, treeIDFieldName: treeObjectID}}
 	graphLookupParameters := bson.M{
-		"from":             personCollectionName,
-		"startWith":        fmt.Sprintf("$%s", connectFromField),
-		"connectFromField": connectFromField,
-		"connectToField":   "_id",
-		"as":               relativesFieldName,
-		"depthField":       depthFieldName,
+		"from":                    personCollectionName,
+		"startWith":               fmt.Sprintf("$%s", connectFromField),
+		"connectFromField":        connectFromField,
+		"connectToField":          "_id",
+		"as":                      relativesFieldName,
+		"depthField":              depthFieldName,
+		"restrictSearchWithMatch": bson.M{treeIDFieldName: treeObjectID},
 	}
 
 	if maxDepth != nil {
@@ -238,21 +351,43 @@ func (pr PersonRepository) graphlookupGetPersonRelativesByPersonIDS(ctx context.
 	}
 
 	graphLookupStage := bson.M{"$graphLookup": graphLookupParameters}
+	//NOTE: unwinding right after the graphLookup keeps large families from hitting the document size limit, and the
+	// relatives are decoded one at a time instead of all at once
+	unwindRelativesStage := bson.M{"$unwind": bson.M{"path": fmt.Sprintf("$%s", relativesFieldName), "preserveNullAndEmptyArrays": true}}
 	pipelineStages := bson.A{
 		matchStage,
 		graphLookupStage,
+		unwindRelativesStage,
 	}
 
 	cursor, err := personCollection.Aggregate(ctx, pipelineStages)
 	if err != nil {
 		return nil, err
 	}
+	defer cursor.Close(ctx)
 
 	var personRelatives []PersonWithRelatives
-	if err := cursor.All(ctx, &personRelatives); err != nil {
-		if err := cursor.Err(); err != nil {
+	indexByPersonID := map[primitive.ObjectID]int{}
+	for cursor.Next(ctx) {
+		var unwoundPerson personWithRelative
+		if err := cursor.Decode(&unwoundPerson); err != nil {
 			return nil, err
 		}
+
+		index, ok := indexByPersonID[unwoundPerson.ID]
+		if !ok {
+			index = len(personRelatives)
+			indexByPersonID[unwoundPerson.ID] = index
+			personRelatives = append(personRelatives, PersonWithRelatives{Person: unwoundPerson.Person})
+		}
+
+		if unwoundPerson.Relative != nil {
+			personRelatives[index].Relatives = append(personRelatives[index].Relatives, *unwoundPerson.Relative)
+		}
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, err
 	}
 
 	return personRelatives, nil
@@ -277,44 +412,66 @@ func mergeRelativesIntoSet(relativesLists [][]Person) []Person {
 	return relatives
 }
 
-func (pr PersonRepository) getDescendantsFromPersonWithAscendants(ctx context.Context, personWithAscendants PersonWithRelatives, maxDepth uint) ([][]Person, error) {
+// getCollateralRelatives reads the children and grandchildren of the ancestors less than generations above the
+// person, so parents bring siblings and nephews and grandparents bring uncles and cousins.
+func (pr PersonRepository) getCollateralRelatives(ctx context.Context, personWithAscendants PersonWithRelatives, generations uint) ([][]Person, error) {
 	var relativesLists [][]Person
 
-	//NOTE: find brothers, cousins and etc. If we wanted to find more relations we could go higher on the ascendant. but since the challenge goes only to that level...
 	var ascendantsIDS []string
 	for _, person := range personWithAscendants.Relatives {
-		if person.DepthField <= maxDepth {
+		if person.DepthField < generations {
 			ascendantsIDS = append(ascendantsIDS, person.ID.Hex())
 		}
 	}
 
-	if len(ascendantsIDS) > 0 {
-		depth := 1 //NOTE: get brothers, cousins, childrens, this could be configurable
-		parentAndGrandParentsWithRelatives, err := pr.graphlookupGetPersonRelativesByPersonIDS(ctx, ascendantsIDS, "childrenIds", &depth)
-		if err != nil {
-			return nil, err
-		}
+	if len(ascendantsIDS) == 0 {
+		return nil, nil
+	}
 
-		for _, parentAndGrandparent := range parentAndGrandParentsWithRelatives {
-			relativesLists = append(relativesLists, parentAndGrandparent.Relatives)
-		}
-	} else {
-		depth := 0 //NOTE: didnt find any ascendant, use my id to fetch childrens
-		myRelatives, err := pr.graphlookupGetPersonRelativesByPersonIDS(ctx, []string{personWithAscendants.ID.Hex()}, "childrenIds", &depth)
-		if err != nil {
-			return nil, err
+	depth := 1
+	ascendantsWithRelatives, err := pr.graphlookupGetPersonRelativesByPersonIDS(ctx, ascendantsIDS, "childrenIds", &depth)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ascendantWithRelatives := range ascendantsWithRelatives {
+		relativesLists = append(relativesLists, ascendantWithRelatives.Relatives)
+	}
+
+	return relativesLists, nil
+}
+
+// withoutChildrenOf drops the children of personID that came along with the collateral relatives.
+func withoutChildrenOf(relatives []Person, personID primitive.ObjectID) []Person {
+	var filteredRelatives []Person
+	for _, relative := range relatives {
+		isChildren := false
+		for _, parentID := range relative.ParentIDS {
+			if parentID == personID {
+				isChildren = true
+			}
 		}
 
-		for _, myRelatives := range myRelatives {
-			relativesLists = append(relativesLists, myRelatives.Relatives)
+		if !isChildren {
+			filteredRelatives = append(filteredRelatives, relative)
 		}
 	}
 
-	return relativesLists, nil
+	return filteredRelatives
 }
 
-func (pr PersonRepository) GetPersonFamilyGraphByID(ctx context.Context, personID string, maxDepth *int64) (*domain.FamilyGraph, error) {
-	result, err := pr.graphlookupGetPersonRelativesByPersonIDS(ctx, []string{personID}, "parentIds", nil)
+func (pr PersonRepository) GetPersonFamilyGraphByID(ctx context.Context, personID string, scope domain.FamilyGraphScope) (*domain.FamilyGraph, error) {
+	var ascendantsMaxDepth *int
+	if scope.AncestorGenerations != nil {
+		//NOTE: graphLookup always reads the first generation, the parents are dropped below when no ancestor is wanted
+		depth := 0
+		if *scope.AncestorGenerations > 0 {
+			depth = int(*scope.AncestorGenerations) - 1
+		}
+		ascendantsMaxDepth = &depth
+	}
+
+	result, err := pr.graphlookupGetPersonRelativesByPersonIDS(ctx, []string{personID}, "parentIds", ascendantsMaxDepth)
 	if err != nil {
 		return nil, err
 	}
@@ -324,21 +481,40 @@ func (pr PersonRepository) GetPersonFamilyGraphByID(ctx context.Context, personI
 	}
 
 	personWithAscendants := result[0]
+	if scope.AncestorGenerations != nil && *scope.AncestorGenerations == 0 {
+		personWithAscendants.Relatives = nil
+	}
 
 	var relativesLists [][]Person
 	relativesLists = append(relativesLists, personWithAscendants.Relatives)
 
-	//NOTE: max depth 1, means that is only going to search for parents and grandparents
-	listOfDescendantsRelatives, err := pr.getDescendantsFromPersonWithAscendants(ctx, personWithAscendants, 1)
+	collateralRelativesLists, err := pr.getCollateralRelatives(ctx, personWithAscendants, scope.CollateralGenerations)
 	if err != nil {
 		return nil, err
 	}
 
-	relativesLists = append(relativesLists, listOfDescendantsRelatives...)
+	for _, collateralRelatives := range collateralRelativesLists {
+		if scope.DescendantGenerations == 0 {
+			collateralRelatives = withoutChildrenOf(collateralRelatives, personWithAscendants.ID)
+		}
+		relativesLists = append(relativesLists, collateralRelatives)
+	}
+
+	if scope.DescendantGenerations > 0 {
+		depth := int(scope.DescendantGenerations) - 1
+		personWithDescendants, err := pr.graphlookupGetPersonRelativesByPersonIDS(ctx, []string{personID}, "childrenIds", &depth)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, descendants := range personWithDescendants {
+			relativesLists = append(relativesLists, descendants.Relatives)
+		}
+	}
 
 	personWithRelatives := personWithAscendants
 
-	if len(personWithRelatives.ChildrenIDS) > 0 {
+	if !scope.ExcludeSpouses && len(personWithRelatives.ChildrenIDS) > 0 {
 		personsByChildrenID, err := pr.getPersonsByChildrenIDS(ctx, personWithRelatives.ChildrenIDS)
 		if err != nil {
 			return nil, err
@@ -360,14 +536,15 @@ func (pr PersonRepository) GetPersonFamilyGraphByID(ctx context.Context, personI
 	return &familyGraph, nil
 }
 
-func (pr PersonRepository) updateParentsForInsertedPerson(ctx context.Context, parentsObjectIDS []primitive.ObjectID, childrenObjectID primitive.ObjectID) error {
-	personCollection := pr.client.Database(pr.databaseName).Collection(personCollectionName)
-
-	if _, err := personCollection.UpdateMany(ctx,
-		bson.M{"_id": bson.M{"$in": parentsObjectIDS}},
-		bson.M{"$addToSet": bson.M{"childrenIds": childrenObjectID}},
-	); err != nil {
-		return err
+func (pr PersonRepository) updateParentsForInsertedPerson(ctx context.Context, parentsObjectIDS []primitive.ObjectID, childrenObjectID primitive.ObjectID, checks versionChecks) error {
+	for _, parentObjectID := range parentsObjectIDS {
+		if err := pr.updatePersonCheckingVersion(ctx,
+			parentObjectID,
+			checks.take(parentObjectID),
+			bson.M{"$addToSet": bson.M{"childrenIds": childrenObjectID}},
+		); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -376,12 +553,30 @@ func (pr PersonRepository) updateParentsForInsertedPerson(ctx context.Context, p
 func (pr PersonRepository) storePerson(ctx context.Context, person Person) (*primitive.ObjectID, error) {
 	personCollection := pr.client.Database(pr.databaseName).Collection(personCollectionName)
 
-	insertedPerson, err := personCollection.InsertOne(ctx, bson.M{
+	treeObjectID, err := treeScope(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	document := bson.M{
+		"_id":         person.ID,
+		"treeId":      treeObjectID,
 		"name":        person.Name,
 		"gender":      person.Gender,
+		"birth":       person.Birth,
+		"death":       person.Death,
 		"parentIds":   person.ParentIDS,
 		"childrenIds": person.ChildrenIDS,
-	})
+		"version":     1,
+	}
+	if len(person.ParentRoles) > 0 {
+		document["parentRoles"] = person.ParentRoles
+	}
+	if len(person.ParentLinkTypes) > 0 {
+		document["parentLinkTypes"] = person.ParentLinkTypes
+	}
+
+	insertedPerson, err := personCollection.InsertOne(ctx, document)
 	if err != nil {
 		return nil, err
 	}
@@ -402,12 +597,18 @@ func (pr PersonRepository) getPersonWithImmediateRelativesByIDS(ctx context.Cont
 		return nil, err
 	}
 
-	matchStage := bson.M{"$match": bson.M{"_id": bson.M{"$in": objectIDS}}}
+	treeObjectID, err := treeScope(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	matchStage := bson.M{"$match": bson.M{"_id": bson.M{"$in": objectIDS}, treeIDFieldName: treeObjectID}}
 
 	lookupParentsStage := bson.M{"$lookup": bson.M{
 		"from":         personCollectionName,
 		"localField":   "parentIds",
 		"foreignField": "_id",
+		"pipeline":     bson.A{bson.M{"$match": bson.M{treeIDFieldName: treeObjectID}}},
 		"as":           "parents",
 	}}
 
@@ -415,6 +616,7 @@ func (pr PersonRepository) getPersonWithImmediateRelativesByIDS(ctx context.Cont
 		"from":         personCollectionName,
 		"localField":   "childrenIds",
 		"foreignField": "_id",
+		"pipeline":     bson.A{bson.M{"$match": bson.M{treeIDFieldName: treeObjectID}}},
 		"as":           "children",
 	}}
 
@@ -451,7 +653,12 @@ func (pr PersonRepository) GetPersonWithImmediateRelativesByIDS(ctx context.Cont
 func (pr PersonRepository) getPersonsByChildrenIDS(ctx context.Context, objectIDS []primitive.ObjectID) ([]Person, error) {
 	personCollection := pr.client.Database(pr.databaseName).Collection(personCollectionName)
 
-	cursor, err := personCollection.Find(ctx, bson.M{"childrenIds": bson.M{"$in": objectIDS}})
+	filter, err := scopedFilter(ctx, bson.M{"childrenIds": bson.M{"$in": objectIDS}})
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := personCollection.Find(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
@@ -466,21 +673,11 @@ func (pr PersonRepository) getPersonsByChildrenIDS(ctx context.Context, objectID
 	return persons, nil
 }
 
-func (pr PersonRepository) updateChildrenForInsertedPerson(ctx context.Context, childrenObjectIDS []primitive.ObjectID, parentObjectID primitive.ObjectID) error {
-	personCollection := pr.client.Database(pr.databaseName).Collection(personCollectionName)
-
-	if len(childrenObjectIDS) > 1 {
-		_, err := personCollection.UpdateMany(ctx,
-			bson.M{"_id": bson.M{"$in": childrenObjectIDS}},
-			bson.M{"$addToSet": bson.M{"parentIds": parentObjectID}},
-		)
-
-		if err != nil {
-			return err
-		}
-	} else {
-		if _, err := personCollection.UpdateOne(ctx,
-			bson.M{"_id": childrenObjectIDS[0]},
+func (pr PersonRepository) updateChildrenForInsertedPerson(ctx context.Context, childrenObjectIDS []primitive.ObjectID, parentObjectID primitive.ObjectID, checks versionChecks) error {
+	for _, childrenObjectID := range childrenObjectIDS {
+		if err := pr.updatePersonCheckingVersion(ctx,
+			childrenObjectID,
+			checks.take(childrenObjectID),
 			bson.M{"$addToSet": bson.M{"parentIds": parentObjectID}},
 		); err != nil {
 			return err
@@ -492,23 +689,41 @@ func (pr PersonRepository) updateChildrenForInsertedPerson(ctx context.Context,
 
 func (pr PersonRepository) Store(ctx context.Context, person domain.Person) (*domain.Person, error) {
 	repositoryPerson := buildRepositoryPersonFromDomainPerson(person)
+	if repositoryPerson.ID.IsZero() {
+		repositoryPerson.ID = primitive.NewObjectID()
+	}
+
+	expectedVersionByObjectID := buildExpectedVersionsFromRelatives(person)
+
+	affectedObjectIDS := append([]primitive.ObjectID{repositoryPerson.ID}, repositoryPerson.ParentIDS...)
+	affectedObjectIDS = append(affectedObjectIDS, repositoryPerson.ChildrenIDS...)
 
 	callback := func(sessCtx mongo.SessionContext) (interface{}, error) {
-		insertedPersonObjectID, err := pr.storePerson(sessCtx, repositoryPerson)
-		if err != nil {
-			return nil, err
-		}
+		var insertedPersonObjectID *primitive.ObjectID
+		checks := newVersionChecks(expectedVersionByObjectID)
+		err := pr.withPersonHistory(sessCtx, affectedObjectIDS, func() error {
+			var err error
+			insertedPersonObjectID, err = pr.storePerson(sessCtx, repositoryPerson)
+			if err != nil {
+				return err
+			}
 
-		if len(repositoryPerson.ParentIDS) > 0 {
-			if err := pr.updateParentsForInsertedPerson(sessCtx, repositoryPerson.ParentIDS, *insertedPersonObjectID); err != nil {
-				return nil, err
+			if len(repositoryPerson.ParentIDS) > 0 {
+				if err := pr.updateParentsForInsertedPerson(sessCtx, repositoryPerson.ParentIDS, *insertedPersonObjectID, checks); err != nil {
+					return err
+				}
 			}
-		}
 
-		if len(repositoryPerson.ChildrenIDS) > 0 {
-			if err := pr.updateChildrenForInsertedPerson(sessCtx, repositoryPerson.ChildrenIDS, *insertedPersonObjectID); err != nil {
-				return nil, err
+			if len(repositoryPerson.ChildrenIDS) > 0 {
+				if err := pr.updateChildrenForInsertedPerson(sessCtx, repositoryPerson.ChildrenIDS, *insertedPersonObjectID, checks); err != nil {
+					return err
+				}
 			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
 		}
 
 		return insertedPersonObjectID, nil
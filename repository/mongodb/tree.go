@@ -0,0 +1,178 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+	applicationErrors "github.com/CaioBittencourt/arvore-genealogica/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	treeCollectionName = "tree"
+	treeIDFieldName    = "treeId"
+)
+
+type Tree struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	Name      string             `bson:"name"`
+	Owner     string             `bson:"owner,omitempty"`
+	CreatedAt time.Time          `bson:"createdAt"`
+}
+
+// treeScope returns the tree id every person query and write must be restricted to. It is nil for the default
+// tree, which matches persons stored without a tree.
+func treeScope(ctx context.Context) (*primitive.ObjectID, error) {
+	treeID := domain.TreeIDFromContext(ctx)
+	if treeID == "" {
+		return nil, nil
+	}
+
+	treeObjectID, err := primitive.ObjectIDFromHex(treeID)
+	if err != nil {
+		return nil, applicationErrors.NewApplicationError(fmt.Sprintf("invalid tree id %s", treeID), applicationErrors.TreeNotFoundErrorCode)
+	}
+
+	return &treeObjectID, nil
+}
+
+// scopedFilter adds the tree of the context to filter, so no query ever reaches persons of other trees.
+func scopedFilter(ctx context.Context, filter bson.M) (bson.M, error) {
+	treeObjectID, err := treeScope(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	scoped := bson.M{treeIDFieldName: treeObjectID}
+	for field, value := range filter {
+		scoped[field] = value
+	}
+
+	return scoped, nil
+}
+
+func buildDomainTreeFromRepositoryTree(tree Tree) domain.Tree {
+	return domain.Tree{
+		ID:        tree.ID.Hex(),
+		Name:      tree.Name,
+		Owner:     tree.Owner,
+		CreatedAt: tree.CreatedAt,
+	}
+}
+
+type TreeRepository struct {
+	client       mongo.Client
+	databaseName string
+}
+
+func NewTreeRepository(client mongo.Client, databaseName string) TreeRepository {
+	return TreeRepository{client: client, databaseName: databaseName}
+}
+
+func (tr TreeRepository) Store(ctx context.Context, tree domain.Tree) (*domain.Tree, error) {
+	treeCollection := tr.client.Database(tr.databaseName).Collection(treeCollectionName)
+
+	repositoryTree := Tree{
+		ID:        primitive.NewObjectID(),
+		Name:      tree.Name,
+		Owner:     tree.Owner,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	if _, err := treeCollection.InsertOne(ctx, repositoryTree); err != nil {
+		return nil, err
+	}
+
+	storedTree := buildDomainTreeFromRepositoryTree(repositoryTree)
+	return &storedTree, nil
+}
+
+func (tr TreeRepository) GetByID(ctx context.Context, treeID string) (*domain.Tree, error) {
+	treeCollection := tr.client.Database(tr.databaseName).Collection(treeCollectionName)
+
+	treeObjectID, err := primitive.ObjectIDFromHex(treeID)
+	if err != nil {
+		return nil, nil
+	}
+
+	var tree Tree
+	if err := treeCollection.FindOne(ctx, bson.M{"_id": treeObjectID}).Decode(&tree); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	domainTree := buildDomainTreeFromRepositoryTree(tree)
+	return &domainTree, nil
+}
+
+func (tr TreeRepository) GetAll(ctx context.Context, owner string) ([]domain.Tree, error) {
+	treeCollection := tr.client.Database(tr.databaseName).Collection(treeCollectionName)
+
+	filter := bson.M{}
+	if owner != "" {
+		filter["owner"] = owner
+	}
+
+	cursor, err := treeCollection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+
+	var trees []Tree
+	if err := cursor.All(ctx, &trees); err != nil {
+		if err := cursor.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	var domainTrees []domain.Tree
+	for _, tree := range trees {
+		domainTrees = append(domainTrees, buildDomainTreeFromRepositoryTree(tree))
+	}
+
+	return domainTrees, nil
+}
+
+// Delete removes the tree with every person and history entry that belongs to it.
+func (tr TreeRepository) Delete(ctx context.Context, treeID string) error {
+	database := tr.client.Database(tr.databaseName)
+
+	treeObjectID, err := primitive.ObjectIDFromHex(treeID)
+	if err != nil {
+		return applicationErrors.NewApplicationError(fmt.Sprintf("invalid tree id %s", treeID), applicationErrors.TreeNotFoundErrorCode)
+	}
+
+	callback := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		if _, err := database.Collection(personCollectionName).DeleteMany(sessCtx, bson.M{treeIDFieldName: treeObjectID}); err != nil {
+			return nil, err
+		}
+
+		if _, err := database.Collection(personHistoryCollectionName).DeleteMany(sessCtx, bson.M{treeIDFieldName: treeObjectID}); err != nil {
+			return nil, err
+		}
+
+		if _, err := database.Collection(treeCollectionName).DeleteOne(sessCtx, bson.M{"_id": treeObjectID}); err != nil {
+			return nil, err
+		}
+
+		return nil, nil
+	}
+
+	session, err := tr.client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, callback)
+	return err
+}
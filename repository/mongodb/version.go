@@ -0,0 +1,101 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+	applicationErrors "github.com/CaioBittencourt/arvore-genealogica/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const versionFieldName = "version"
+
+// buildExpectedVersionsFromRelatives maps the versions the caller read for the parents and children of person.
+// Relatives without a version are updated without checking it.
+func buildExpectedVersionsFromRelatives(person domain.Person) map[primitive.ObjectID]int64 {
+	expectedVersionByObjectID := make(map[primitive.ObjectID]int64)
+	for _, relative := range append(append([]*domain.Person{}, person.Parents...), person.Children...) {
+		if relative.Version == 0 {
+			continue
+		}
+
+		objectID, err := primitive.ObjectIDFromHex(relative.ID)
+		if err == nil {
+			expectedVersionByObjectID[objectID] = relative.Version
+		}
+	}
+
+	return expectedVersionByObjectID
+}
+
+// updatePersonCheckingVersion applies update only if the person is still on expectedVersion and bumps its version.
+// An expectedVersion of 0 skips the check and domain.UnversionedPersonVersion matches the persons stored before
+// versions existed, whose missing version is read as 0.
+func (pr PersonRepository) updatePersonCheckingVersion(ctx context.Context, personObjectID primitive.ObjectID, expectedVersion int64, update bson.M) error {
+	personCollection := pr.client.Database(pr.databaseName).Collection(personCollectionName)
+
+	filter, err := scopedFilter(ctx, bson.M{"_id": personObjectID})
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case expectedVersion == domain.UnversionedPersonVersion:
+		filter["$or"] = bson.A{bson.M{versionFieldName: 0}, bson.M{versionFieldName: bson.M{"$exists": false}}}
+	case expectedVersion > 0:
+		filter[versionFieldName] = expectedVersion
+	}
+
+	versionedUpdate := bson.M{"$inc": bson.M{versionFieldName: 1}}
+	for operator, fields := range update {
+		versionedUpdate[operator] = fields
+	}
+
+	result, err := personCollection.UpdateOne(ctx, filter, versionedUpdate)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount > 0 {
+		return nil
+	}
+
+	count, err := personCollection.CountDocuments(ctx, bson.M{"_id": personObjectID, treeIDFieldName: filter[treeIDFieldName]})
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return applicationErrors.NewApplicationError(fmt.Sprintf("person with id %s not found", personObjectID.Hex()), applicationErrors.PersonNotFoundErrorCode)
+	}
+
+	if expectedVersion == domain.UnversionedPersonVersion {
+		expectedVersion = 0
+	}
+
+	return applicationErrors.NewApplicationError(
+		fmt.Sprintf("person with id %s was modified since version %d", personObjectID.Hex(), expectedVersion),
+		applicationErrors.PersonVersionMismatchErrorCode,
+	)
+}
+
+// versionChecks hands out the expected version of each person only once, since after the first update inside the
+// transaction the version was already bumped by us.
+type versionChecks map[primitive.ObjectID]int64
+
+func newVersionChecks(expectedVersionByObjectID map[primitive.ObjectID]int64) versionChecks {
+	checks := make(versionChecks, len(expectedVersionByObjectID))
+	for objectID, version := range expectedVersionByObjectID {
+		checks[objectID] = version
+	}
+
+	return checks
+}
+
+func (vc versionChecks) take(objectID primitive.ObjectID) int64 {
+	version := vc[objectID]
+	delete(vc, objectID)
+	return version
+}
@@ -2,12 +2,31 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/CaioBittencourt/arvore-genealogica/domain"
 )
 
+// TreeEventStream yields the changes to a tree one at a time, like a cursor. Once Next returns false Err tells why.
+type TreeEventStream interface {
+	Next(ctx context.Context) bool
+	Event() domain.TreeEvent
+	Err() error
+	Close(ctx context.Context) error
+}
+
 type PersonRepository interface {
-	GetPersonFamilyGraphByID(ctx context.Context, personID string, maxDepth *int64) (*domain.FamilyGraph, error)
+	GetPersonFamilyGraphByID(ctx context.Context, personID string, scope domain.FamilyGraphScope) (*domain.FamilyGraph, error)
 	Store(ctx context.Context, person domain.Person) (*domain.Person, error)
+	StoreBatch(ctx context.Context, batch domain.PersonBatch) (map[string]string, error)
 	GetPersonWithImmediateRelativesByIDS(ctx context.Context, personIDS []string) ([]domain.Person, error)
+	SearchBaconsNumber(ctx context.Context, personAID string, personBID string, options domain.BaconsNumberOptions, maxPersons int) (*uint, error)
+	GetAll(ctx context.Context) ([]domain.Person, error)
+	RepairIntegrityIssues(ctx context.Context, issues []domain.IntegrityIssue) error
+	GetPersonHistory(ctx context.Context, personID string) ([]domain.PersonChange, error)
+	GetPersonAsOf(ctx context.Context, personID string, at time.Time) (*domain.Person, error)
+	GetPersonFamilyGraphAsOf(ctx context.Context, personID string, at time.Time, scope domain.FamilyGraphScope) (*domain.FamilyGraph, error)
+	LinkParent(ctx context.Context, child domain.Person, parent domain.Person, role domain.ParentRoleType, linkType domain.LinkType) error
+	UnlinkParent(ctx context.Context, child domain.Person, parent domain.Person) error
+	WatchTreeEvents(ctx context.Context) (TreeEventStream, error)
 }
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+)
+
+type TreeRepository interface {
+	Store(ctx context.Context, tree domain.Tree) (*domain.Tree, error)
+	GetByID(ctx context.Context, treeID string) (*domain.Tree, error)
+	GetAll(ctx context.Context, owner string) ([]domain.Tree, error)
+	Delete(ctx context.Context, treeID string) error
+}
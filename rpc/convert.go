@@ -0,0 +1,237 @@
+package rpc
+
+import (
+	"sort"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+	"github.com/CaioBittencourt/arvore-genealogica/familytreepb"
+)
+
+var genderByDomainGender = map[domain.GenderType]familytreepb.Gender{
+	domain.Male:   familytreepb.Gender_GENDER_MALE,
+	domain.Female: familytreepb.Gender_GENDER_FEMALE,
+}
+
+var relationshipTypeByDomainRelationshipType = map[domain.RelationshipType]familytreepb.RelationshipType{
+	domain.ParentRelashionship:    familytreepb.RelationshipType_RELATIONSHIP_TYPE_PARENT,
+	domain.ChildRelashionship:     familytreepb.RelationshipType_RELATIONSHIP_TYPE_CHILD,
+	domain.SiblingRelashionship:   familytreepb.RelationshipType_RELATIONSHIP_TYPE_SIBLING,
+	domain.NephewRelashionship:    familytreepb.RelationshipType_RELATIONSHIP_TYPE_NEPHEW,
+	domain.CousinRelashionship:    familytreepb.RelationshipType_RELATIONSHIP_TYPE_COUSIN,
+	domain.SpouseRelashionship:    familytreepb.RelationshipType_RELATIONSHIP_TYPE_SPOUSE,
+	domain.AuntUncleRelashionship: familytreepb.RelationshipType_RELATIONSHIP_TYPE_AUNT_UNCLE,
+}
+
+var parentRoleByDomainParentRole = map[domain.ParentRoleType]familytreepb.ParentRole{
+	domain.MotherParentRole:  familytreepb.ParentRole_PARENT_ROLE_MOTHER,
+	domain.FatherParentRole:  familytreepb.ParentRole_PARENT_ROLE_FATHER,
+	domain.GenericParentRole: familytreepb.ParentRole_PARENT_ROLE_PARENT,
+}
+
+var linkTypeByDomainLinkType = map[domain.LinkType]familytreepb.LinkType{
+	domain.BiologicalLinkType: familytreepb.LinkType_LINK_TYPE_BIOLOGICAL,
+	domain.AdoptiveLinkType:   familytreepb.LinkType_LINK_TYPE_ADOPTIVE,
+	domain.FosterLinkType:     familytreepb.LinkType_LINK_TYPE_FOSTER,
+	domain.StepLinkType:       familytreepb.LinkType_LINK_TYPE_STEP,
+	domain.DonorLinkType:      familytreepb.LinkType_LINK_TYPE_DONOR,
+	domain.SurrogateLinkType:  familytreepb.LinkType_LINK_TYPE_SURROGATE,
+}
+
+var pathDirectionByDomainPathDirection = map[domain.PathDirection]familytreepb.PathDirection{
+	domain.AnyPathDirection:         familytreepb.PathDirection_PATH_DIRECTION_UNSPECIFIED,
+	domain.AncestorsPathDirection:   familytreepb.PathDirection_PATH_DIRECTION_ANCESTORS,
+	domain.DescendantsPathDirection: familytreepb.PathDirection_PATH_DIRECTION_DESCENDANTS,
+}
+
+// buildDomainLinkTypes keeps LINK_TYPE_UNSPECIFIED as an empty link type, so it is refused as not valid.
+func buildDomainLinkTypes(linkTypes []familytreepb.LinkType) ([]domain.LinkType, error) {
+	var values []string
+	for _, linkType := range linkTypes {
+		value := ""
+		for domainLinkType, protoLinkType := range linkTypeByDomainLinkType {
+			if protoLinkType == linkType {
+				value = string(domainLinkType)
+			}
+		}
+		values = append(values, value)
+	}
+
+	return domain.ParseLinkTypes(values)
+}
+
+// buildDomainFamilyGraphScope starts from the default scope, so only the generations set on the request change it.
+func buildDomainFamilyGraphScope(req *familytreepb.GetFamilyTreeRequest) domain.FamilyGraphScope {
+	scope := domain.DefaultFamilyGraphScope()
+	if req.AncestorGenerations != nil {
+		ancestorGenerations := uint(req.GetAncestorGenerations())
+		scope.AncestorGenerations = &ancestorGenerations
+	}
+	if req.DescendantGenerations != nil {
+		scope.DescendantGenerations = uint(req.GetDescendantGenerations())
+	}
+	if req.CollateralGenerations != nil {
+		scope.CollateralGenerations = uint(req.GetCollateralGenerations())
+	}
+	scope.ExcludeSpouses = req.ExcludeSpouses
+
+	return scope
+}
+
+// buildDomainBaconsNumberOptions keeps unknown directions by their number, so they are refused as not valid.
+func buildDomainBaconsNumberOptions(req *familytreepb.GetBaconsNumberRequest) (domain.BaconsNumberOptions, error) {
+	linkTypes, err := buildDomainLinkTypes(req.LinkTypes)
+	if err != nil {
+		return domain.BaconsNumberOptions{}, err
+	}
+
+	direction := domain.PathDirection(req.Direction.String())
+	for domainDirection, protoDirection := range pathDirectionByDomainPathDirection {
+		if protoDirection == req.Direction {
+			direction = domainDirection
+		}
+	}
+
+	return domain.BaconsNumberOptions{
+		LinkTypes:      linkTypes,
+		ExcludeSpouses: req.ExcludeSpouses,
+		Direction:      direction,
+		SpouseWeight:   uint(req.SpouseWeight),
+		ParentWeight:   uint(req.ParentWeight),
+		ChildWeight:    uint(req.ChildWeight),
+		MaxDepth:       uint(req.MaxDepth),
+	}, nil
+}
+
+func buildDomainGender(gender familytreepb.Gender) domain.GenderType {
+	for domainGender, protoGender := range genderByDomainGender {
+		if protoGender == gender {
+			return domainGender
+		}
+	}
+
+	return ""
+}
+
+func buildDomainLifeEvent(lifeEvent *familytreepb.LifeEvent) *domain.LifeEvent {
+	if lifeEvent == nil {
+		return nil
+	}
+
+	return &domain.LifeEvent{Date: lifeEvent.Date, Place: lifeEvent.Place}
+}
+
+func buildLifeEvent(lifeEvent *domain.LifeEvent) *familytreepb.LifeEvent {
+	if lifeEvent == nil {
+		return nil
+	}
+
+	return &familytreepb.LifeEvent{Date: lifeEvent.Date, Place: lifeEvent.Place}
+}
+
+// buildDomainRelative leaves the version at 0 when it was not sent, and maps the version 0 of persons stored before
+// versions existed to domain.UnversionedPersonVersion, like the If-Match header does.
+func buildDomainRelative(relative *familytreepb.RelativeReference) *domain.Person {
+	person := &domain.Person{ID: relative.Id}
+	if relative.Version != nil {
+		person.Version = *relative.Version
+		if person.Version == 0 {
+			person.Version = domain.UnversionedPersonVersion
+		}
+	}
+
+	return person
+}
+
+func buildPersonFromStorePersonRequest(req *familytreepb.StorePersonRequest) domain.Person {
+	person := domain.Person{
+		Name:   req.Name,
+		Gender: buildDomainGender(req.Gender),
+		Birth:  buildDomainLifeEvent(req.Birth),
+		Death:  buildDomainLifeEvent(req.Death),
+	}
+
+	if req.Father != nil {
+		person.AddParent(buildDomainRelative(req.Father), domain.FatherParentRole)
+	}
+
+	if req.Mother != nil {
+		person.AddParent(buildDomainRelative(req.Mother), domain.MotherParentRole)
+	}
+
+	for _, parent := range req.Parents {
+		person.AddParent(buildDomainRelative(parent), domain.GenericParentRole)
+	}
+
+	for _, children := range req.Children {
+		person.Children = append(person.Children, buildDomainRelative(children))
+	}
+
+	return person
+}
+
+func buildPersonReference(person domain.Person) *familytreepb.PersonReference {
+	return &familytreepb.PersonReference{
+		Id:     person.ID,
+		Name:   person.Name,
+		Gender: genderByDomainGender[person.Gender],
+	}
+}
+
+// buildPerson skips relatives without a name, which are only references to persons that were not read.
+func buildPerson(person domain.Person) *familytreepb.Person {
+	protoPerson := &familytreepb.Person{
+		Id:      person.ID,
+		Name:    person.Name,
+		Gender:  genderByDomainGender[person.Gender],
+		Birth:   buildLifeEvent(person.Birth),
+		Death:   buildLifeEvent(person.Death),
+		Version: person.Version,
+	}
+
+	for _, parent := range person.Parents {
+		if parent.Name == "" {
+			continue
+		}
+
+		protoPerson.Parents = append(protoPerson.Parents, buildPersonReference(*parent))
+		switch person.ParentRole(parent.ID) {
+		case domain.MotherParentRole:
+			protoPerson.Mother = buildPersonReference(*parent)
+		case domain.FatherParentRole:
+			protoPerson.Father = buildPersonReference(*parent)
+		}
+	}
+
+	for _, children := range person.Children {
+		if children.Name != "" {
+			protoPerson.Children = append(protoPerson.Children, buildPersonReference(*children))
+		}
+	}
+
+	return protoPerson
+}
+
+func buildRelationships(relationships map[string]domain.Relationship) []*familytreepb.Relationship {
+	var personIDS []string
+	for personID := range relationships {
+		personIDS = append(personIDS, personID)
+	}
+	sort.Strings(personIDS)
+
+	var protoRelationships []*familytreepb.Relationship
+	for _, personID := range personIDS {
+		relationship := relationships[personID]
+		protoRelationships = append(protoRelationships, &familytreepb.Relationship{
+			Person: &familytreepb.PersonReference{
+				Id:     relationship.Person.ID,
+				Name:   relationship.Person.Name,
+				Gender: genderByDomainGender[relationship.Person.Gender],
+			},
+			Relationship: relationshipTypeByDomainRelationshipType[relationship.Relationship],
+			ParentRole:   parentRoleByDomainParentRole[relationship.ParentRole],
+			LinkType:     linkTypeByDomainLinkType[relationship.LinkType],
+		})
+	}
+
+	return protoRelationships
+}
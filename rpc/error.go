@@ -0,0 +1,47 @@
+package rpc
+
+import (
+	"github.com/CaioBittencourt/arvore-genealogica/errors"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var statusCodeByApplicationErrorCode = map[errors.ApplicationErrorCode]codes.Code{
+	errors.PersonNotFoundErrorCode:          codes.NotFound,
+	errors.PersonNotFoundInGraph:            codes.NotFound,
+	errors.InvalidPersonNameErrorCode:       codes.InvalidArgument,
+	errors.TooManyParentsForPersonErrorCode: codes.InvalidArgument,
+	errors.InvalidPersonGenderErrorCode:     codes.InvalidArgument,
+	errors.ChildrenAlreadyHasTwoParents:     codes.InvalidArgument,
+	errors.InvalidPersonIDErrorCode:         codes.InvalidArgument,
+	errors.InvalidBatchErrorCode:            codes.InvalidArgument,
+	errors.AncestryCycleErrorCode:           codes.InvalidArgument,
+	errors.InvalidQueryParameterErrorCode:   codes.InvalidArgument,
+	errors.PersonVersionMismatchErrorCode:   codes.Aborted,
+	errors.PersonVersionRequiredErrorCode:   codes.FailedPrecondition,
+	errors.TreeNotFoundErrorCode:            codes.NotFound,
+	errors.InvalidTreeNameErrorCode:         codes.InvalidArgument,
+	errors.ParentRoleConflictErrorCode:      codes.InvalidArgument,
+	errors.InvalidParentRoleErrorCode:       codes.InvalidArgument,
+	errors.InvalidParentLinkTypeErrorCode:   codes.InvalidArgument,
+	errors.SearchLimitReachedErrorCode:      codes.ResourceExhausted,
+}
+
+// buildStatusFromError keeps the message of application errors, with their code as the status message prefix so
+// clients can tell them apart, and hides every other error like the HTTP API does.
+func buildStatusFromError(err error) error {
+	applicationError, ok := errors.CastToApplicationError(err)
+	if !ok {
+		log.WithError(err).Error("rpc: unexpected error")
+		return status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	code, ok := statusCodeByApplicationErrorCode[applicationError.Code]
+	if !ok {
+		log.WithError(err).Error("rpc: unmapped application error")
+		return status.Error(codes.Internal, "Internal Server Error")
+	}
+
+	return status.Errorf(code, "%s: %s", applicationError.Code, applicationError.Messsage)
+}
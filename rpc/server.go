@@ -0,0 +1,219 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+	"github.com/CaioBittencourt/arvore-genealogica/errors"
+	"github.com/CaioBittencourt/arvore-genealogica/familytreepb"
+	"github.com/CaioBittencourt/arvore-genealogica/service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+const (
+	actorMetadataKey        = "x-actor"
+	changeReasonMetadataKey = "x-change-reason"
+)
+
+type familyTreeServer struct {
+	familytreepb.UnimplementedFamilyTreeServiceServer
+	personService service.PersonService
+	treeService   service.TreeService
+}
+
+// NewServer registers FamilyTreeService on a new gRPC server.
+func NewServer(personService service.PersonService, treeService service.TreeService) *grpc.Server {
+	grpcServer := grpc.NewServer()
+	familytreepb.RegisterFamilyTreeServiceServer(grpcServer, familyTreeServer{personService: personService, treeService: treeService})
+
+	return grpcServer
+}
+
+func firstMetadataValue(md metadata.MD, key string) string {
+	values := md.Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
+
+// requestContext scopes ctx to treeID, checking that the tree exists, and adds the change metadata sent by the
+// client, like TreeScopeMiddleware and ChangeMetadataMiddleware do for HTTP.
+func (fts familyTreeServer) requestContext(ctx context.Context, treeID string) (context.Context, error) {
+	if treeID != "" {
+		tree, err := fts.treeService.GetByID(ctx, treeID)
+		if err != nil {
+			return nil, err
+		}
+		ctx = domain.ContextWithTreeID(ctx, tree.ID)
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	return domain.ContextWithChangeMetadata(ctx, domain.ChangeMetadata{
+		ClaimedActor: firstMetadataValue(md, actorMetadataKey),
+		Source:       peerAddress(ctx),
+		Reason:       firstMetadataValue(md, changeReasonMetadataKey),
+	}), nil
+}
+
+// peerAddress is the host of the connection of the client, which its metadata can not override.
+func peerAddress(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+
+	return host
+}
+
+// StorePerson requires the version of every relative, like the If-Match header of POST /person, 0 for persons
+// stored before versions existed.
+func (fts familyTreeServer) StorePerson(ctx context.Context, req *familytreepb.StorePersonRequest) (*familytreepb.Person, error) {
+	ctx, err := fts.requestContext(ctx, req.TreeId)
+	if err != nil {
+		return nil, buildStatusFromError(err)
+	}
+
+	person := buildPersonFromStorePersonRequest(req)
+	for _, relative := range append(append([]*domain.Person{}, person.Parents...), person.Children...) {
+		if relative.Version == 0 {
+			return nil, buildStatusFromError(errors.NewApplicationError(
+				fmt.Sprintf("version of person %s is required", relative.ID),
+				errors.PersonVersionRequiredErrorCode,
+			))
+		}
+	}
+
+	storedPerson, err := fts.personService.Store(ctx, person)
+	if err != nil {
+		return nil, buildStatusFromError(err)
+	}
+
+	return buildPerson(*storedPerson), nil
+}
+
+func (fts familyTreeServer) GetFamilyTree(ctx context.Context, req *familytreepb.GetFamilyTreeRequest) (*familytreepb.FamilyGraph, error) {
+	ctx, err := fts.requestContext(ctx, req.TreeId)
+	if err != nil {
+		return nil, buildStatusFromError(err)
+	}
+
+	familyGraph, err := fts.personService.GetFamilyGraphByPersonID(ctx, req.PersonId, buildDomainFamilyGraphScope(req))
+	if err != nil {
+		return nil, buildStatusFromError(err)
+	}
+
+	var memberIDS []string
+	for memberID := range familyGraph.Members {
+		memberIDS = append(memberIDS, memberID)
+	}
+	sort.Strings(memberIDS)
+
+	protoFamilyGraph := &familytreepb.FamilyGraph{PersonId: req.PersonId}
+	for _, memberID := range memberIDS {
+		member := familyGraph.Members[memberID]
+		protoFamilyGraph.Members = append(protoFamilyGraph.Members, &familytreepb.FamilyGraphMember{
+			Person:        buildPersonReference(*member),
+			Relationships: buildRelationships(member.Relationships),
+		})
+	}
+
+	return protoFamilyGraph, nil
+}
+
+func (fts familyTreeServer) GetRelationship(ctx context.Context, req *familytreepb.GetRelationshipRequest) (*familytreepb.GetRelationshipResponse, error) {
+	ctx, err := fts.requestContext(ctx, req.TreeId)
+	if err != nil {
+		return nil, buildStatusFromError(err)
+	}
+
+	linkTypes, err := buildDomainLinkTypes(req.LinkTypes)
+	if err != nil {
+		return nil, buildStatusFromError(err)
+	}
+
+	personWithRelationship, err := fts.personService.GetRelationshipBetweenPersons(ctx, req.PersonId, req.RelatedPersonId, linkTypes)
+	if err != nil {
+		return nil, buildStatusFromError(err)
+	}
+
+	return &familytreepb.GetRelationshipResponse{
+		Person:        buildPersonReference(*personWithRelationship),
+		Relationships: buildRelationships(personWithRelationship.Relationships),
+	}, nil
+}
+
+func (fts familyTreeServer) GetBaconsNumber(ctx context.Context, req *familytreepb.GetBaconsNumberRequest) (*familytreepb.GetBaconsNumberResponse, error) {
+	ctx, err := fts.requestContext(ctx, req.TreeId)
+	if err != nil {
+		return nil, buildStatusFromError(err)
+	}
+
+	options, err := buildDomainBaconsNumberOptions(req)
+	if err != nil {
+		return nil, buildStatusFromError(err)
+	}
+
+	baconsNumber, err := fts.personService.BaconsNumber(ctx, req.PersonId, req.RelatedPersonId, options)
+	if err != nil {
+		return nil, buildStatusFromError(err)
+	}
+
+	return &familytreepb.GetBaconsNumberResponse{BaconsNumber: uint32(*baconsNumber)}, nil
+}
+
+// StreamDescendants reads a whole generation at a time and sends it before reading the next one, so clients start
+// receiving descendants of large trees right away. Persons reached twice, through a cycle, are only sent once.
+func (fts familyTreeServer) StreamDescendants(req *familytreepb.StreamDescendantsRequest, stream familytreepb.FamilyTreeService_StreamDescendantsServer) error {
+	ctx, err := fts.requestContext(stream.Context(), req.TreeId)
+	if err != nil {
+		return buildStatusFromError(err)
+	}
+
+	person, err := fts.personService.GetPersonByID(ctx, req.PersonId)
+	if err != nil {
+		return buildStatusFromError(err)
+	}
+
+	visited := map[string]bool{person.ID: true}
+	generation := []domain.Person{*person}
+	for generationNumber := uint32(1); len(generation) > 0; generationNumber++ {
+		var childrenIDS []string
+		for _, person := range generation {
+			for _, children := range person.Children {
+				if !visited[children.ID] {
+					visited[children.ID] = true
+					childrenIDS = append(childrenIDS, children.ID)
+				}
+			}
+		}
+
+		if len(childrenIDS) == 0 {
+			return nil
+		}
+
+		generation, err = fts.personService.GetPersonsByIDS(ctx, childrenIDS)
+		if err != nil {
+			return buildStatusFromError(err)
+		}
+
+		for _, descendant := range generation {
+			if err := stream.Send(&familytreepb.Descendant{Person: buildPerson(descendant), Generation: generationNumber}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
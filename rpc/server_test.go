@@ -0,0 +1,422 @@
+package rpc
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+	"github.com/CaioBittencourt/arvore-genealogica/errors"
+	"github.com/CaioBittencourt/arvore-genealogica/familytreepb"
+	"github.com/CaioBittencourt/arvore-genealogica/service"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+type fakePersonService struct {
+	service.PersonService
+	personsByID   map[string]domain.Person
+	storedPersons []domain.Person
+	treeIDS       []string
+	actors        []string
+	sources       []string
+	scopes        []domain.FamilyGraphScope
+	options       []domain.BaconsNumberOptions
+}
+
+func (fps *fakePersonService) withRelatives(person domain.Person) domain.Person {
+	withRelatives := person
+	withRelatives.Parents, withRelatives.Children = nil, nil
+	for _, parent := range person.Parents {
+		storedParent := fps.personsByID[parent.ID]
+		withRelatives.Parents = append(withRelatives.Parents, &storedParent)
+	}
+	for _, children := range person.Children {
+		storedChildren := fps.personsByID[children.ID]
+		withRelatives.Children = append(withRelatives.Children, &storedChildren)
+	}
+
+	return withRelatives
+}
+
+func (fps *fakePersonService) GetPersonByID(ctx context.Context, personID string) (*domain.Person, error) {
+	person, ok := fps.personsByID[personID]
+	if !ok {
+		return nil, errors.NewApplicationError("person not found", errors.PersonNotFoundErrorCode)
+	}
+
+	withRelatives := fps.withRelatives(person)
+	return &withRelatives, nil
+}
+
+func (fps *fakePersonService) GetPersonsByIDS(ctx context.Context, personIDS []string) ([]domain.Person, error) {
+	var persons []domain.Person
+	for _, personID := range personIDS {
+		if person, ok := fps.personsByID[personID]; ok {
+			persons = append(persons, fps.withRelatives(person))
+		}
+	}
+
+	return persons, nil
+}
+
+func (fps *fakePersonService) Store(ctx context.Context, person domain.Person) (*domain.Person, error) {
+	fps.storedPersons = append(fps.storedPersons, person)
+	fps.treeIDS = append(fps.treeIDS, domain.TreeIDFromContext(ctx))
+	fps.actors = append(fps.actors, domain.ChangeMetadataFromContext(ctx).ClaimedActor)
+	fps.sources = append(fps.sources, domain.ChangeMetadataFromContext(ctx).Source)
+
+	person.ID = "IDNew"
+	person.Version = 1
+	return &person, nil
+}
+
+func (fps *fakePersonService) GetFamilyGraphByPersonID(ctx context.Context, personID string, scope domain.FamilyGraphScope) (*domain.FamilyGraph, error) {
+	fps.scopes = append(fps.scopes, scope)
+
+	luis := fps.personsByID["IDLuis"]
+	caio := fps.personsByID["IDCaio"]
+	luis.Relationships = map[string]domain.Relationship{
+		"IDCaio": {Person: domain.RelationshipPerson{ID: caio.ID, Name: caio.Name, Gender: caio.Gender}, Relationship: domain.ChildRelashionship},
+	}
+	caio.Relationships = map[string]domain.Relationship{
+		"IDLuis": {Person: domain.RelationshipPerson{ID: luis.ID, Name: luis.Name, Gender: luis.Gender}, Relationship: domain.ParentRelashionship, ParentRole: domain.FatherParentRole},
+	}
+
+	return &domain.FamilyGraph{Members: map[string]*domain.Person{"IDLuis": &luis, "IDCaio": &caio}}, nil
+}
+
+func (fps *fakePersonService) BaconsNumber(ctx context.Context, personAID string, personBID string, options domain.BaconsNumberOptions) (*uint, error) {
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+	fps.options = append(fps.options, options)
+
+	return nil, errors.NewApplicationError("persons dont belong to eachothers graph", errors.PersonNotFoundInGraph)
+}
+
+type fakeTreeService struct {
+	service.TreeService
+}
+
+func (fts fakeTreeService) GetByID(ctx context.Context, treeID string) (*domain.Tree, error) {
+	if treeID != "IDTree" {
+		return nil, errors.NewApplicationError("tree not found", errors.TreeNotFoundErrorCode)
+	}
+
+	return &domain.Tree{ID: treeID}, nil
+}
+
+func stub(id string) *domain.Person {
+	return &domain.Person{ID: id}
+}
+
+// family has Zeze as the mother of Luis, who is the father of Caio and Vini, and Caio is the father of Bento, whose
+// child link back to Luis forms a cycle.
+func family() *fakePersonService {
+	persons := []domain.Person{
+		{ID: "IDZeze", Name: "Zeze", Gender: domain.Female, Children: []*domain.Person{stub("IDLuis")}},
+		{ID: "IDLuis", Name: "Luis", Gender: domain.Male, Parents: []*domain.Person{stub("IDZeze")}, Children: []*domain.Person{stub("IDCaio"), stub("IDVini")}},
+		{ID: "IDCaio", Name: "Caio", Gender: domain.Male, Parents: []*domain.Person{stub("IDLuis")}, Children: []*domain.Person{stub("IDBento")}},
+		{ID: "IDVini", Name: "Vini", Gender: domain.Male, Parents: []*domain.Person{stub("IDLuis")}},
+		{ID: "IDBento", Name: "Bento", Gender: domain.Male, Parents: []*domain.Person{stub("IDCaio")}, Children: []*domain.Person{stub("IDLuis")}},
+	}
+
+	personsByID := map[string]domain.Person{}
+	for _, person := range persons {
+		personsByID[person.ID] = person
+	}
+
+	return &fakePersonService{personsByID: personsByID}
+}
+
+func newClient(t *testing.T, personService service.PersonService) familytreepb.FamilyTreeServiceClient {
+	listener := bufconn.Listen(1024 * 1024)
+	grpcServer := NewServer(personService, fakeTreeService{})
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return familytreepb.NewFamilyTreeServiceClient(conn)
+}
+
+func TestStorePerson(t *testing.T) {
+	type testArgs struct {
+		testName        string
+		req             *familytreepb.StorePersonRequest
+		expectedCode    codes.Code
+		expectedParents []*domain.Person
+	}
+
+	tests := []testArgs{
+		{
+			testName: "should store the person on the tree with the actor of the metadata",
+			req: &familytreepb.StorePersonRequest{
+				TreeId: "IDTree",
+				Name:   "Maria",
+				Gender: familytreepb.Gender_GENDER_FEMALE,
+				Birth:  &familytreepb.LifeEvent{Date: "1 JAN 2020"},
+				Father: &familytreepb.RelativeReference{Id: "IDCaio", Version: proto.Int64(2)},
+			},
+			expectedCode:    codes.OK,
+			expectedParents: []*domain.Person{{ID: "IDCaio", Version: 2}},
+		},
+		{
+			testName: "should link relatives stored before versions existed",
+			req: &familytreepb.StorePersonRequest{
+				TreeId: "IDTree",
+				Name:   "Maria",
+				Gender: familytreepb.Gender_GENDER_FEMALE,
+				Birth:  &familytreepb.LifeEvent{Date: "1 JAN 2020"},
+				Father: &familytreepb.RelativeReference{Id: "IDCaio", Version: proto.Int64(0)},
+			},
+			expectedCode:    codes.OK,
+			expectedParents: []*domain.Person{{ID: "IDCaio", Version: domain.UnversionedPersonVersion}},
+		},
+		{
+			testName:     "should require the version of relatives",
+			req:          &familytreepb.StorePersonRequest{Name: "Maria", Gender: familytreepb.Gender_GENDER_FEMALE, Father: &familytreepb.RelativeReference{Id: "IDCaio"}},
+			expectedCode: codes.FailedPrecondition,
+		},
+		{
+			testName:     "should fail on an unexisting tree",
+			req:          &familytreepb.StorePersonRequest{TreeId: "IDUnexisting", Name: "Maria", Gender: familytreepb.Gender_GENDER_FEMALE},
+			expectedCode: codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(tt testArgs) func(t *testing.T) {
+			return func(t *testing.T) {
+				personService := family()
+				client := newClient(t, personService)
+
+				ctx := metadata.AppendToOutgoingContext(context.Background(), actorMetadataKey, "caio")
+				person, err := client.StorePerson(ctx, tt.req)
+				assert.Equal(t, tt.expectedCode, status.Code(err))
+				if tt.expectedCode != codes.OK {
+					assert.Empty(t, personService.storedPersons)
+					return
+				}
+
+				assert.Equal(t, "IDNew", person.Id)
+				assert.Equal(t, familytreepb.Gender_GENDER_FEMALE, person.Gender)
+				assert.Equal(t, []string{"IDTree"}, personService.treeIDS)
+				assert.Equal(t, []string{"caio"}, personService.actors)
+				assert.Equal(t, []string{"bufconn"}, personService.sources)
+				assert.Equal(t, domain.Female, personService.storedPersons[0].Gender)
+				assert.Equal(t, &domain.LifeEvent{Date: "1 JAN 2020"}, personService.storedPersons[0].Birth)
+				assert.Equal(t, tt.expectedParents, personService.storedPersons[0].Parents)
+				assert.Equal(t, domain.FatherParentRole, personService.storedPersons[0].ParentRole("IDCaio"))
+			}
+		}(tt))
+	}
+}
+
+func TestGetFamilyTree(t *testing.T) {
+	client := newClient(t, family())
+
+	familyGraph, err := client.GetFamilyTree(context.Background(), &familytreepb.GetFamilyTreeRequest{PersonId: "IDCaio"})
+	assert.NoError(t, err)
+	assert.Equal(t, "IDCaio", familyGraph.PersonId)
+	assert.Len(t, familyGraph.Members, 2)
+	assert.Equal(t, "IDCaio", familyGraph.Members[0].Person.Id)
+	assert.Equal(t, familytreepb.RelationshipType_RELATIONSHIP_TYPE_PARENT, familyGraph.Members[0].Relationships[0].Relationship)
+	assert.Equal(t, "Luis", familyGraph.Members[0].Relationships[0].Person.Name)
+	assert.Equal(t, familytreepb.ParentRole_PARENT_ROLE_FATHER, familyGraph.Members[0].Relationships[0].ParentRole)
+}
+
+func TestGetBaconsNumber(t *testing.T) {
+	client := newClient(t, family())
+
+	_, err := client.GetBaconsNumber(context.Background(), &familytreepb.GetBaconsNumberRequest{PersonId: "IDCaio", RelatedPersonId: "IDZeze"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), string(errors.PersonNotFoundInGraph))
+
+	_, err = client.GetBaconsNumber(context.Background(), &familytreepb.GetBaconsNumberRequest{
+		PersonId:        "IDCaio",
+		RelatedPersonId: "IDZeze",
+		LinkTypes:       []familytreepb.LinkType{familytreepb.LinkType_LINK_TYPE_BIOLOGICAL, familytreepb.LinkType_LINK_TYPE_UNSPECIFIED},
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), string(errors.InvalidParentLinkTypeErrorCode))
+}
+
+func TestGetFamilyTreeScope(t *testing.T) {
+	type testArgs struct {
+		testName      string
+		request       *familytreepb.GetFamilyTreeRequest
+		expectedScope domain.FamilyGraphScope
+	}
+
+	noAncestors := uint(0)
+	tests := []testArgs{
+		{
+			testName:      "should read the default scope when no generation is set",
+			request:       &familytreepb.GetFamilyTreeRequest{PersonId: "IDCaio"},
+			expectedScope: domain.DefaultFamilyGraphScope(),
+		},
+		{
+			testName: "should read only the generations set on the request",
+			request: &familytreepb.GetFamilyTreeRequest{
+				PersonId:              "IDCaio",
+				AncestorGenerations:   proto.Uint32(0),
+				DescendantGenerations: proto.Uint32(3),
+				ExcludeSpouses:        true,
+			},
+			expectedScope: domain.FamilyGraphScope{AncestorGenerations: &noAncestors, DescendantGenerations: 3, CollateralGenerations: 2, ExcludeSpouses: true},
+		},
+		{
+			testName:      "should keep a collateral generation of 0 set on the request",
+			request:       &familytreepb.GetFamilyTreeRequest{PersonId: "IDCaio", CollateralGenerations: proto.Uint32(0)},
+			expectedScope: domain.FamilyGraphScope{DescendantGenerations: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(tt testArgs) func(t *testing.T) {
+			return func(t *testing.T) {
+				personService := family()
+				client := newClient(t, personService)
+
+				_, err := client.GetFamilyTree(context.Background(), tt.request)
+				assert.NoError(t, err)
+				assert.Equal(t, []domain.FamilyGraphScope{tt.expectedScope}, personService.scopes)
+			}
+		}(tt))
+	}
+}
+
+func TestGetBaconsNumberOptions(t *testing.T) {
+	type testArgs struct {
+		testName        string
+		request         *familytreepb.GetBaconsNumberRequest
+		expectedOptions []domain.BaconsNumberOptions
+		expectedCode    codes.Code
+	}
+
+	tests := []testArgs{
+		{
+			testName: "should pass every option of the request",
+			request: &familytreepb.GetBaconsNumberRequest{
+				PersonId:        "IDCaio",
+				RelatedPersonId: "IDZeze",
+				LinkTypes:       []familytreepb.LinkType{familytreepb.LinkType_LINK_TYPE_ADOPTIVE},
+				ExcludeSpouses:  true,
+				Direction:       familytreepb.PathDirection_PATH_DIRECTION_ANCESTORS,
+				SpouseWeight:    2,
+				ParentWeight:    3,
+				ChildWeight:     4,
+				MaxDepth:        5,
+			},
+			expectedOptions: []domain.BaconsNumberOptions{{
+				LinkTypes:      []domain.LinkType{domain.AdoptiveLinkType},
+				ExcludeSpouses: true,
+				Direction:      domain.AncestorsPathDirection,
+				SpouseWeight:   2,
+				ParentWeight:   3,
+				ChildWeight:    4,
+				MaxDepth:       5,
+			}},
+			expectedCode: codes.NotFound,
+		},
+		{
+			testName:        "should jump in any direction when the direction is unspecified",
+			request:         &familytreepb.GetBaconsNumberRequest{PersonId: "IDCaio", RelatedPersonId: "IDZeze"},
+			expectedOptions: []domain.BaconsNumberOptions{{Direction: domain.AnyPathDirection}},
+			expectedCode:    codes.NotFound,
+		},
+		{
+			testName:     "should refuse an unknown direction",
+			request:      &familytreepb.GetBaconsNumberRequest{PersonId: "IDCaio", RelatedPersonId: "IDZeze", Direction: familytreepb.PathDirection(7)},
+			expectedCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(tt testArgs) func(t *testing.T) {
+			return func(t *testing.T) {
+				personService := family()
+				client := newClient(t, personService)
+
+				_, err := client.GetBaconsNumber(context.Background(), tt.request)
+				assert.Equal(t, tt.expectedCode, status.Code(err))
+				assert.Equal(t, tt.expectedOptions, personService.options)
+			}
+		}(tt))
+	}
+}
+
+func TestStreamDescendants(t *testing.T) {
+	type testArgs struct {
+		testName            string
+		personID            string
+		expectedDescendants map[uint32][]string
+		expectedCode        codes.Code
+	}
+
+	tests := []testArgs{
+		{
+			testName: "should stream every generation once, even through cycles",
+			personID: "IDZeze",
+			expectedDescendants: map[uint32][]string{
+				1: {"Luis"},
+				2: {"Caio", "Vini"},
+				3: {"Bento"},
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			testName:            "should stream nothing for a person without children",
+			personID:            "IDVini",
+			expectedDescendants: map[uint32][]string{},
+			expectedCode:        codes.OK,
+		},
+		{
+			testName:            "should fail on an unexisting person",
+			personID:            "IDUnexisting",
+			expectedDescendants: map[uint32][]string{},
+			expectedCode:        codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(tt testArgs) func(t *testing.T) {
+			return func(t *testing.T) {
+				client := newClient(t, family())
+
+				stream, err := client.StreamDescendants(context.Background(), &familytreepb.StreamDescendantsRequest{PersonId: tt.personID})
+				assert.NoError(t, err)
+
+				descendants := map[uint32][]string{}
+				for {
+					descendant, err := stream.Recv()
+					if err == io.EOF {
+						break
+					}
+					if err != nil {
+						assert.Equal(t, tt.expectedCode, status.Code(err))
+						break
+					}
+
+					descendants[descendant.Generation] = append(descendants[descendant.Generation], descendant.Person.Name)
+				}
+
+				assert.Equal(t, tt.expectedDescendants, descendants)
+			}
+		}(tt))
+	}
+}
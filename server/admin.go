@@ -0,0 +1,46 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/CaioBittencourt/arvore-genealogica/service"
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary      Check referential integrity of persons
+// @Description  Scans every person for asymmetric links, dangling ids, persons with more than 2 parents and ancestry cycles
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}   domain.IntegrityReport
+// @Failure      500  {object}   ErrorResponse
+// @Router       /admin/integrity [get]
+func CheckIntegrity(personService service.PersonService) gin.HandlerFunc {
+	return gin.HandlerFunc(func(ctx *gin.Context) {
+		report, err := personService.CheckIntegrity(ctx, false)
+		if err != nil {
+			createServerResponseFromError(ctx, err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, report)
+	})
+}
+
+// @Summary      Repair referential integrity of persons
+// @Description  Repairs asymmetric links and dangling ids inside a transaction, skipping the ones changed since the check. Returns the issues found before the repair
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}   domain.IntegrityReport
+// @Failure      500  {object}   ErrorResponse
+// @Router       /admin/integrity/repair [post]
+func RepairIntegrity(personService service.PersonService) gin.HandlerFunc {
+	return gin.HandlerFunc(func(ctx *gin.Context) {
+		report, err := personService.CheckIntegrity(ctx, true)
+		if err != nil {
+			createServerResponseFromError(ctx, err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, report)
+	})
+}
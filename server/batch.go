@@ -0,0 +1,129 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+	"github.com/CaioBittencourt/arvore-genealogica/errors"
+	"github.com/CaioBittencourt/arvore-genealogica/service"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+// MotherID, FatherID and ChildrenIDs accept the tempId of another person of the batch or the id of a stored person
+type StorePersonBatchItemRequest struct {
+	TempID string `json:"tempId"`
+	StorePersonRequest
+}
+
+type StorePersonBatchRequest struct {
+	Persons []StorePersonBatchItemRequest `json:"persons"`
+}
+
+type StorePersonBatchResponse struct {
+	IDs map[string]string `json:"ids"`
+}
+
+type BatchItemErrorResponse struct {
+	Index        int    `json:"index"`
+	TempID       string `json:"tempId"`
+	ErrorMessage string `json:"errorMessage"`
+	ErrorCode    string `json:"errorCode"`
+}
+
+type StorePersonBatchErrorResponse struct {
+	ErrorResponse
+	Items []BatchItemErrorResponse `json:"items"`
+}
+
+func buildPersonBatchFromStorePersonBatchRequest(batchReq StorePersonBatchRequest) domain.PersonBatch {
+	var batch domain.PersonBatch
+	for _, personReq := range batchReq.Persons {
+		batch = append(batch, domain.BatchPerson{
+			TempID: personReq.TempID,
+			Person: buildPersonFromStorePersonRequest(personReq.StorePersonRequest),
+		})
+	}
+
+	return batch
+}
+
+func BuildBatchItemErrorResponses(itemErrors []domain.BatchItemError) []BatchItemErrorResponse {
+	itemErrorResponses := []BatchItemErrorResponse{}
+	for _, itemError := range itemErrors {
+		itemErrorResponse := BatchItemErrorResponse{
+			Index:        itemError.Index,
+			TempID:       itemError.TempID,
+			ErrorMessage: itemError.Err.Error(),
+		}
+
+		if applicationError, ok := errors.CastToApplicationError(itemError.Err); ok {
+			itemErrorResponse.ErrorCode = string(applicationError.Code)
+		}
+
+		itemErrorResponses = append(itemErrorResponses, itemErrorResponse)
+	}
+
+	return itemErrorResponses
+}
+
+// @Summary      Store persons in batch
+// @Description  Store persons in batch inside a single transaction. Persons reference each other through their tempId. Returns the id stored for each tempId
+// @Accept       json
+// @Produce      json
+// @Param        persons   body       StorePersonBatchRequest  true  "Persons to store"
+// @Param        If-Match  header     string  false  "ETags of the stored persons referenced by the batch"
+// @Success      200  {object}   StorePersonBatchResponse
+// @Failure      400  {object}   StorePersonBatchErrorResponse
+// @Failure      412  {object}   ErrorResponse
+// @Failure      428  {object}   ErrorResponse
+// @Failure      500  {object}   ErrorResponse
+// @Router       /persons/batch [post]
+func StoreBatch(personService service.PersonService) gin.HandlerFunc {
+	return gin.HandlerFunc(func(ctx *gin.Context) {
+		var req StorePersonBatchRequest
+
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			log.WithError(err).Error("server person: store batch: invalid request")
+			ctx.JSON(http.StatusBadRequest, ErrorResponse{
+				ErrorMessage: err.Error(),
+			})
+			return
+		}
+
+		batch := buildPersonBatchFromStorePersonBatchRequest(req)
+
+		indexByTempID := batch.TempIDS()
+		var batchPersons []*domain.Person
+		for i := range batch {
+			batchPersons = append(batchPersons, &batch[i].Person)
+		}
+
+		isStoredPersonID := func(personID string) bool {
+			_, isTempID := indexByTempID[personID]
+			return !isTempID
+		}
+
+		if err := applyIfMatchToPersons(ctx, batchPersons, isStoredPersonID); err != nil {
+			createServerResponseFromError(ctx, err)
+			return
+		}
+
+		idByTempID, itemErrors, err := personService.StoreBatch(ctx, batch)
+		if len(itemErrors) > 0 {
+			errResponse := BuildErrorResponseFromError(err)
+			ctx.JSON(errResponse.StatusCode, StorePersonBatchErrorResponse{
+				ErrorResponse: errResponse,
+				Items:         BuildBatchItemErrorResponses(itemErrors),
+			})
+			return
+		}
+
+		if err != nil {
+			createServerResponseFromError(ctx, err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, StorePersonBatchResponse{IDs: idByTempID})
+	})
+}
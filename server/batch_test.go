@@ -0,0 +1,107 @@
+package server_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/CaioBittencourt/arvore-genealogica/errors"
+	"github.com/CaioBittencourt/arvore-genealogica/repository/mongodb"
+	"github.com/CaioBittencourt/arvore-genealogica/server"
+	"github.com/CaioBittencourt/arvore-genealogica/server/routes"
+	"github.com/CaioBittencourt/arvore-genealogica/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func doStorePersonBatchRequest(router *gin.Engine, req server.StorePersonBatchRequest) (*server.StorePersonBatchResponse, *server.StorePersonBatchErrorResponse, int, error) {
+	var buf bytes.Buffer
+	err := json.NewEncoder(&buf).Encode(req)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("POST", "/persons/batch", &buf)
+	router.ServeHTTP(w, httpReq)
+
+	if w.Code > 200 {
+		res := server.StorePersonBatchErrorResponse{}
+		err = json.Unmarshal(w.Body.Bytes(), &res)
+		if err != nil {
+			return nil, nil, w.Code, err
+		}
+
+		return nil, &res, w.Code, nil
+	}
+
+	res := server.StorePersonBatchResponse{}
+	err = json.Unmarshal(w.Body.Bytes(), &res)
+	if err != nil {
+		return nil, nil, w.Code, err
+	}
+
+	return &res, nil, w.Code, nil
+}
+
+func TestStoreBatch(t *testing.T) {
+	personRepository := mongodb.NewPersonRepository(*mongoClient, os.Getenv("MONGO_DATABASE"))
+	personService := service.NewPersonService(personRepository)
+	treeRepository := mongodb.NewTreeRepository(*mongoClient, os.Getenv("MONGO_DATABASE"))
+	treeService := service.NewTreeService(treeRepository)
+	router := routes.SetupRouter(personService, treeService)
+
+	tunico := "tunico"
+	luis := "luis"
+	dayse := "dayse"
+
+	t.Run("should return per item errors and store nothing when batch is invalid", func(t *testing.T) {
+		successRes, errorRes, statusCode, err := doStorePersonBatchRequest(router, server.StorePersonBatchRequest{
+			Persons: []server.StorePersonBatchItemRequest{
+				{TempID: luis, StorePersonRequest: server.StorePersonRequest{Name: "Luis", Gender: "male"}},
+				{TempID: dayse, StorePersonRequest: server.StorePersonRequest{Name: "D", Gender: "female"}},
+			},
+		})
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(t, 400, statusCode)
+		assert.Nil(t, successRes)
+		assert.Equal(t, string(errors.InvalidBatchErrorCode), errorRes.ErrorCode)
+		assert.Equal(t, []server.BatchItemErrorResponse{
+			{Index: 1, TempID: dayse, ErrorMessage: "name must have more than 1 character", ErrorCode: string(errors.InvalidPersonNameErrorCode)},
+		}, errorRes.Items)
+	})
+
+	t.Run("should store persons referenced before being declared", func(t *testing.T) {
+		successRes, errorRes, statusCode, err := doStorePersonBatchRequest(router, server.StorePersonBatchRequest{
+			Persons: []server.StorePersonBatchItemRequest{
+				{TempID: "caio", StorePersonRequest: server.StorePersonRequest{Name: "Caio", Gender: "male", FatherID: &luis, MotherID: &dayse}},
+				{TempID: luis, StorePersonRequest: server.StorePersonRequest{Name: "Luis", Gender: "male", FatherID: &tunico}},
+				{TempID: dayse, StorePersonRequest: server.StorePersonRequest{Name: "Dayse", Gender: "female"}},
+				{TempID: tunico, StorePersonRequest: server.StorePersonRequest{Name: "Tunico", Gender: "male"}},
+			},
+		})
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(t, 200, statusCode)
+		assert.Nil(t, errorRes)
+		assert.Len(t, successRes.IDs, 4)
+
+		treeRes, _, statusCode, err := doGetPersonFamilyRelationshipsRequest(router, successRes.IDs["caio"], "")
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(t, 200, statusCode)
+		assert.Len(t, treeRes.Members, 4)
+	})
+
+	teardownTest()
+}
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/CaioBittencourt/arvore-genealogica/chart"
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+	"github.com/CaioBittencourt/arvore-genealogica/service"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+// @Summary      Draw family tree of person as SVG
+// @Description  Draw the family tree of a person as an SVG chart. Pedigree charts show the ancestors of the person, descendants charts its descendants beside the co-parents they were had with and hourglass charts show both
+// @Produce      image/svg+xml
+// @Param        id   path       string  true  "Person ID"
+// @Param        type query      string  false "pedigree, descendants or hourglass, defaults to hourglass"
+// @Success      200  {string}   string
+// @Failure      400  {object}   ErrorResponse
+// @Failure      404  {object}   ErrorResponse
+// @Failure      500  {object}   ErrorResponse
+// @Router       /person/:id/chart.svg [get]
+func GetPersonFamilyTreeChart(personService service.PersonService) gin.HandlerFunc {
+	return gin.HandlerFunc(func(ctx *gin.Context) {
+		personID := ctx.Param("id")
+
+		chartType, err := chart.ParseChartType(ctx.Query("type"))
+		if err != nil {
+			createServerResponseFromError(ctx, err)
+			return
+		}
+
+		familyGraph, err := personService.GetFamilyGraphByPersonID(ctx, personID, domain.DefaultFamilyGraphScope())
+		if err != nil {
+			createServerResponseFromError(ctx, err)
+			return
+		}
+
+		layout, err := chart.BuildLayout(*familyGraph, personID, chartType)
+		if err != nil {
+			createServerResponseFromError(ctx, err)
+			return
+		}
+
+		ctx.Header("Content-Type", chart.MediaType+"; charset=utf-8")
+		ctx.Status(http.StatusOK)
+
+		if err := chart.EncodeSVG(ctx.Writer, layout, personID); err != nil {
+			log.WithError(err).Error("server chart: failed to draw family tree")
+		}
+	})
+}
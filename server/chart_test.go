@@ -0,0 +1,70 @@
+package server_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/CaioBittencourt/arvore-genealogica/repository/mongodb"
+	"github.com/CaioBittencourt/arvore-genealogica/server"
+	"github.com/CaioBittencourt/arvore-genealogica/server/routes"
+	"github.com/CaioBittencourt/arvore-genealogica/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPersonFamilyTreeChart(t *testing.T) {
+	personRepository := mongodb.NewPersonRepository(*mongoClient, os.Getenv("MONGO_DATABASE"))
+	personService := service.NewPersonService(personRepository)
+	treeRepository := mongodb.NewTreeRepository(*mongoClient, os.Getenv("MONGO_DATABASE"))
+	treeService := service.NewTreeService(treeRepository)
+	router := routes.SetupRouter(personService, treeService)
+
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("POST", "/import/gedcom", strings.NewReader(familyGedcom))
+	router.ServeHTTP(w, httpReq)
+
+	var report server.ImportReportResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+		t.Error(err)
+	}
+
+	t.Run("should draw the pedigree of a person", func(t *testing.T) {
+		svg, statusCode := doGetWithAcceptRequest(router, "/person/"+report.IDs["I3"]+"/chart.svg?type=pedigree", "")
+
+		assert.Equal(t, 200, statusCode)
+		assert.True(t, strings.HasPrefix(svg, "<svg "))
+		assert.Equal(t, 3, strings.Count(svg, "<rect "))
+		assert.Contains(t, svg, "Caio Bittencourt")
+	})
+
+	t.Run("should draw only the person on the descendants chart of a person without children", func(t *testing.T) {
+		svg, statusCode := doGetWithAcceptRequest(router, "/person/"+report.IDs["I3"]+"/chart.svg?type=descendants", "")
+
+		assert.Equal(t, 200, statusCode)
+		assert.Equal(t, 1, strings.Count(svg, "<rect "))
+	})
+
+	t.Run("should draw an hourglass by default", func(t *testing.T) {
+		svg, statusCode := doGetWithAcceptRequest(router, "/person/"+report.IDs["I1"]+"/chart.svg", "")
+
+		assert.Equal(t, 200, statusCode)
+		assert.Equal(t, 3, strings.Count(svg, "<rect "))
+	})
+
+	t.Run("should return bad request for unsupported chart type", func(t *testing.T) {
+		_, statusCode := doGetWithAcceptRequest(router, "/person/"+report.IDs["I3"]+"/chart.svg?type=fan", "")
+
+		assert.Equal(t, 400, statusCode)
+	})
+
+	t.Run("should return not found for unexisting person", func(t *testing.T) {
+		_, statusCode := doGetWithAcceptRequest(router, "/person/64138dcbd2a5f3b8e8a24f1a/chart.svg", "")
+
+		assert.Equal(t, 404, statusCode)
+	})
+
+	teardownTest()
+}
@@ -0,0 +1,130 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+	"github.com/CaioBittencourt/arvore-genealogica/errors"
+	"github.com/CaioBittencourt/arvore-genealogica/service"
+	"github.com/CaioBittencourt/arvore-genealogica/spreadsheet"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+type RowErrorResponse struct {
+	Line         int    `json:"line"`
+	Key          string `json:"key"`
+	Column       string `json:"column,omitempty"`
+	ErrorMessage string `json:"errorMessage"`
+	ErrorCode    string `json:"errorCode"`
+}
+
+type CSVImportReportResponse struct {
+	Rows   int                `json:"rows"`
+	DryRun bool               `json:"dryRun"`
+	IDs    map[string]string  `json:"ids"`
+	Errors []RowErrorResponse `json:"errors"`
+}
+
+type CSVImportErrorResponse struct {
+	ErrorResponse
+	CSVImportReportResponse
+}
+
+func BuildCSVImportReportResponseFromImportReport(report spreadsheet.ImportReport) CSVImportReportResponse {
+	reportResponse := CSVImportReportResponse{
+		Rows:   report.Rows,
+		DryRun: report.DryRun,
+		IDs:    report.IDByKey,
+		Errors: []RowErrorResponse{},
+	}
+
+	for _, rowError := range report.Errors {
+		rowErrorResponse := RowErrorResponse{
+			Line:         rowError.Line,
+			Key:          rowError.Key,
+			Column:       rowError.Column,
+			ErrorMessage: rowError.Err.Error(),
+		}
+
+		if applicationError, ok := errors.CastToApplicationError(rowError.Err); ok {
+			rowErrorResponse.ErrorCode = string(applicationError.Code)
+		}
+
+		reportResponse.Errors = append(reportResponse.Errors, rowErrorResponse)
+	}
+
+	return reportResponse
+}
+
+// @Summary      Import CSV file
+// @Description  Import the rows of a CSV file inside a single transaction. The header names the columns: id, name, gender, mother, father, other_parents, birth_date, birth_place, death_date and death_place, only name and gender are required. Mother and father reference the id of another row or of a stored person, and other_parents has the parents that are not the biological mother or father as id:link type or id:link type:role entries separated by semicolons. Every invalid row is reported with its line, with dryRun nothing is stored
+// @Accept       plain
+// @Accept       mpfd
+// @Produce      json
+// @Param        file    formData   file     false  "CSV file, when not sent as the request body"
+// @Param        dryRun  query      boolean  false  "only check the rows"
+// @Success      200  {object}   CSVImportReportResponse
+// @Failure      400  {object}   CSVImportErrorResponse
+// @Failure      500  {object}   ErrorResponse
+// @Router       /import/csv [post]
+func ImportCSV(importService service.ImportService) gin.HandlerFunc {
+	return gin.HandlerFunc(func(ctx *gin.Context) {
+		file, err := uploadedFileReader(ctx)
+		if err != nil {
+			log.WithError(err).Error("server import: invalid request")
+			ctx.JSON(http.StatusBadRequest, ErrorResponse{
+				ErrorMessage: err.Error(),
+			})
+			return
+		}
+		defer file.Close()
+
+		report, err := importService.ImportCSV(ctx, file, ctx.Query("dryRun") == "true")
+		if report != nil && len(report.Errors) > 0 {
+			errResponse := BuildErrorResponseFromError(err)
+			ctx.JSON(errResponse.StatusCode, CSVImportErrorResponse{
+				ErrorResponse:           errResponse,
+				CSVImportReportResponse: BuildCSVImportReportResponseFromImportReport(*report),
+			})
+			return
+		}
+
+		if err != nil {
+			createServerResponseFromError(ctx, err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, BuildCSVImportReportResponseFromImportReport(*report))
+	})
+}
+
+// @Summary      Export every person as CSV
+// @Description  Export every person of the tree as a CSV file with the columns accepted by the CSV import
+// @Produce      text/csv
+// @Success      200  {string}   string
+// @Failure      500  {object}   ErrorResponse
+// @Router       /export/csv [get]
+func ExportCSV(personService service.PersonService) gin.HandlerFunc {
+	return gin.HandlerFunc(func(ctx *gin.Context) {
+		persons, err := personService.GetAllPersons(ctx)
+		if err != nil {
+			createServerResponseFromError(ctx, err)
+			return
+		}
+
+		fileName := "export.csv"
+		if treeID := domain.TreeIDFromContext(ctx); treeID != "" {
+			fileName = treeID + ".csv"
+		}
+
+		ctx.Header("Content-Type", spreadsheet.MediaType+"; charset=utf-8")
+		ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+		ctx.Status(http.StatusOK)
+
+		if err := spreadsheet.Encode(ctx.Writer, persons); err != nil {
+			log.WithError(err).Error("server export: failed to write csv")
+		}
+	})
+}
@@ -0,0 +1,93 @@
+package server_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/CaioBittencourt/arvore-genealogica/repository/mongodb"
+	"github.com/CaioBittencourt/arvore-genealogica/server"
+	"github.com/CaioBittencourt/arvore-genealogica/server/routes"
+	"github.com/CaioBittencourt/arvore-genealogica/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+const familyCSV = `id,name,gender,mother,father,birth_date,birth_place
+luis,Luis Bittencourt,male,,,,
+dayse,Dayse Bittencourt,female,,,,
+caio,Caio Bittencourt,male,dayse,luis,1 JAN 1990,Porto Alegre
+`
+
+func doImportCSVRequest(router *gin.Engine, url string, file string) (*server.CSVImportReportResponse, int) {
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("POST", url, strings.NewReader(file))
+	httpReq.Header.Set("Content-Type", "text/csv")
+	router.ServeHTTP(w, httpReq)
+
+	var report server.CSVImportReportResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+		return nil, w.Code
+	}
+
+	return &report, w.Code
+}
+
+func TestImportCSV(t *testing.T) {
+	personRepository := mongodb.NewPersonRepository(*mongoClient, os.Getenv("MONGO_DATABASE"))
+	personService := service.NewPersonService(personRepository)
+	treeRepository := mongodb.NewTreeRepository(*mongoClient, os.Getenv("MONGO_DATABASE"))
+	treeService := service.NewTreeService(treeRepository)
+	router := routes.SetupRouter(personService, treeService)
+
+	t.Run("should only check the rows on dry run", func(t *testing.T) {
+		report, statusCode := doImportCSVRequest(router, "/import/csv?dryRun=true", familyCSV)
+
+		assert.Equal(t, 200, statusCode)
+		assert.True(t, report.DryRun)
+		assert.Equal(t, 3, report.Rows)
+		assert.Empty(t, report.IDs)
+
+		exported, statusCode := doGetWithAcceptRequest(router, "/export/csv", "")
+		assert.Equal(t, 200, statusCode)
+		assert.Equal(t, 1, strings.Count(exported, "\n"))
+	})
+
+	t.Run("should report every invalid row without storing any", func(t *testing.T) {
+		file := familyCSV + "zeze,Z,female,,\nana,Ana,female,nobody,caio\n"
+		report, statusCode := doImportCSVRequest(router, "/import/csv", file)
+
+		assert.Equal(t, 400, statusCode)
+		assert.Len(t, report.Errors, 2)
+		assert.Equal(t, 5, report.Errors[0].Line)
+		assert.Equal(t, "INVALID_PERSON_NAME", report.Errors[0].ErrorCode)
+		assert.Equal(t, 6, report.Errors[1].Line)
+		assert.Equal(t, "PERSON_NOT_FOUND", report.Errors[1].ErrorCode)
+	})
+
+	t.Run("should import the rows and export them back", func(t *testing.T) {
+		report, statusCode := doImportCSVRequest(router, "/import/csv", familyCSV)
+
+		assert.Equal(t, 200, statusCode)
+		assert.Len(t, report.IDs, 3)
+
+		exported, statusCode := doGetWithAcceptRequest(router, "/export/csv", "")
+		assert.Equal(t, 200, statusCode)
+		assert.Contains(t, exported, report.IDs["caio"]+",Caio Bittencourt,male,"+report.IDs["dayse"]+","+report.IDs["luis"]+",,1 JAN 1990,Porto Alegre,,\n")
+
+		tree, statusCode := doGetWithAcceptRequest(router, "/person/"+report.IDs["caio"]+"/tree?format=csv", "")
+		assert.Equal(t, 200, statusCode)
+		assert.Equal(t, 4, strings.Count(tree, "\n"))
+	})
+
+	t.Run("should reference stored persons", func(t *testing.T) {
+		_, statusCode := doImportCSVRequest(router, "/import/csv", "name,gender,father\nPedro,male,64138dcbd2a5f3b8e8a24f1a\n")
+
+		assert.Equal(t, 400, statusCode)
+	})
+
+	teardownTest()
+}
@@ -24,6 +24,21 @@ var errorResponseConfigurationByApplicationErrorCode = map[errors.ApplicationErr
 	errors.TooManyParentsForPersonErrorCode: {ErrorShouldBeVisible: true, ErrorStatusCode: 400},
 	errors.InvalidPersonGenderErrorCode:     {ErrorShouldBeVisible: true, ErrorStatusCode: 400},
 	errors.ChildrenAlreadyHasTwoParents:     {ErrorShouldBeVisible: true, ErrorStatusCode: 400},
+	errors.InvalidPersonIDErrorCode:         {ErrorShouldBeVisible: true, ErrorStatusCode: 400},
+	errors.InvalidBatchErrorCode:            {ErrorShouldBeVisible: true, ErrorStatusCode: 400},
+	errors.AncestryCycleErrorCode:           {ErrorShouldBeVisible: true, ErrorStatusCode: 400},
+	errors.InvalidQueryParameterErrorCode:   {ErrorShouldBeVisible: true, ErrorStatusCode: 400},
+	errors.PersonVersionMismatchErrorCode:   {ErrorShouldBeVisible: true, ErrorStatusCode: 412},
+	errors.PersonVersionRequiredErrorCode:   {ErrorShouldBeVisible: true, ErrorStatusCode: 428},
+	errors.TreeNotFoundErrorCode:            {ErrorShouldBeVisible: true, ErrorStatusCode: 404},
+	errors.InvalidTreeNameErrorCode:         {ErrorShouldBeVisible: true, ErrorStatusCode: 400},
+	errors.InvalidGedcomErrorCode:           {ErrorShouldBeVisible: true, ErrorStatusCode: 400},
+	errors.InvalidCSVErrorCode:              {ErrorShouldBeVisible: true, ErrorStatusCode: 400},
+	errors.ParentRoleConflictErrorCode:      {ErrorShouldBeVisible: true, ErrorStatusCode: 400},
+	errors.InvalidParentRoleErrorCode:       {ErrorShouldBeVisible: true, ErrorStatusCode: 400},
+	errors.InvalidParentLinkTypeErrorCode:   {ErrorShouldBeVisible: true, ErrorStatusCode: 400},
+	errors.SearchLimitReachedErrorCode:      {ErrorShouldBeVisible: true, ErrorStatusCode: 422},
+	errors.InvalidHeaderErrorCode:           {ErrorShouldBeVisible: true, ErrorStatusCode: 400},
 }
 
 func (er ErrorResponse) Error() string {
@@ -0,0 +1,142 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+	"github.com/CaioBittencourt/arvore-genealogica/service"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/websocket"
+)
+
+// treeEventsKeepAliveInterval keeps proxies from closing idle event streams.
+const treeEventsKeepAliveInterval = 30 * time.Second
+
+type TreeEventResponse struct {
+	Type            string                  `json:"type"`
+	PersonID        string                  `json:"personId"`
+	RelatedPersonID string                  `json:"relatedPersonId,omitempty"`
+	Person          *PersonSnapshotResponse `json:"person"`
+	Timestamp       time.Time               `json:"timestamp"`
+}
+
+func buildTreeEventResponseFromTreeEvent(treeEvent domain.TreeEvent) TreeEventResponse {
+	return TreeEventResponse{
+		Type:            string(treeEvent.Type),
+		PersonID:        treeEvent.PersonID,
+		RelatedPersonID: treeEvent.RelatedPersonID,
+		Person:          buildPersonSnapshotResponseFromDomainPerson(treeEvent.Person),
+		Timestamp:       treeEvent.Timestamp,
+	}
+}
+
+func streamTreeEventsAsServerSentEvents(ctx *gin.Context, treeEvents <-chan domain.TreeEvent) {
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("X-Accel-Buffering", "no")
+
+	keepAlive := time.NewTicker(treeEventsKeepAliveInterval)
+	defer keepAlive.Stop()
+
+	ctx.Stream(func(w io.Writer) bool {
+		select {
+		case treeEvent, ok := <-treeEvents:
+			if !ok {
+				return false
+			}
+			ctx.SSEvent(string(treeEvent.Type), buildTreeEventResponseFromTreeEvent(treeEvent))
+			return true
+		case <-keepAlive.C:
+			_, err := fmt.Fprint(w, ": keep-alive\n\n")
+			return err == nil
+		}
+	})
+}
+
+// checkWebSocketOrigin accepts handshakes from the origin of the api itself or from the origins allowed by
+// CORSMiddleware. Browsers always send Origin, so a handshake without it is refused as well.
+func checkWebSocketOrigin(allowedOrigins []string) func(*websocket.Config, *http.Request) error {
+	return func(config *websocket.Config, req *http.Request) error {
+		origin, err := websocket.Origin(config, req)
+		if err != nil {
+			return err
+		}
+
+		if origin == nil {
+			return fmt.Errorf("websocket handshake without origin")
+		}
+
+		if !strings.EqualFold(origin.Host, req.Host) && !isAllowedOrigin(allowedOrigins, origin.String()) {
+			return fmt.Errorf("websocket handshake from origin %s is not allowed", origin)
+		}
+
+		config.Origin = origin
+		return nil
+	}
+}
+
+// streamTreeEventsThroughWebSocket sends every event as a JSON message. Messages from the client are discarded,
+// reading them is only how a closed connection is noticed. Handshakes from origins that are not allowed get a 403.
+func streamTreeEventsThroughWebSocket(ctx *gin.Context, treeEvents <-chan domain.TreeEvent, stopWatching context.CancelFunc) {
+	websocketServer := websocket.Server{Handshake: checkWebSocketOrigin(ctx.GetStringSlice(allowedOriginsContextKey)), Handler: func(conn *websocket.Conn) {
+		go func() {
+			io.Copy(io.Discard, conn)
+			stopWatching()
+		}()
+
+		for treeEvent := range treeEvents {
+			if err := websocket.JSON.Send(conn, buildTreeEventResponseFromTreeEvent(treeEvent)); err != nil {
+				log.WithError(err).Error("server: failed to send tree event")
+				return
+			}
+		}
+	}}
+
+	websocketServer.ServeHTTP(ctx.Writer, ctx.Request)
+}
+
+// @Summary      Subscribe to changes of the family tree of person
+// @Description  Pushes the persons created, updated or deleted and the parent links added or removed that affect the family tree of the person, as Server-Sent Events named after the type of the event or, when the request upgrades to a WebSocket, as JSON messages. Takes the same ancestors, descendants, collaterals and spouses parameters as /person/:id/tree
+// @Produce      text/event-stream
+// @Param        id   path       string  true  "Person ID"
+// @Param        ancestors query      integer false "generations of ancestors, all of them by default"
+// @Param        descendants query    integer false "generations of descendants, defaults to 1"
+// @Param        collaterals query    integer false "generations of ancestors whose children and grandchildren are included, defaults to 2"
+// @Param        spouses query        boolean false "include the other parents of the children of the person, defaults to true"
+// @Success      200  {object}   TreeEventResponse
+// @Failure      400  {object}   ErrorResponse
+// @Failure      404  {object}   ErrorResponse
+// @Failure      500  {object}   ErrorResponse
+// @Router       /person/:id/tree/events [get]
+func GetPersonFamilyTreeEvents(personService service.PersonService) gin.HandlerFunc {
+	return gin.HandlerFunc(func(ctx *gin.Context) {
+		personID := ctx.Param("id")
+
+		scope, err := parseFamilyGraphScope(ctx)
+		if err != nil {
+			createServerResponseFromError(ctx, err)
+			return
+		}
+
+		watchCtx, stopWatching := context.WithCancel(ctx.Request.Context())
+		defer stopWatching()
+
+		treeEvents, err := personService.WatchFamilyGraphEvents(watchCtx, personID, scope)
+		if err != nil {
+			createServerResponseFromError(ctx, err)
+			return
+		}
+
+		if ctx.IsWebsocket() {
+			streamTreeEventsThroughWebSocket(ctx, treeEvents, stopWatching)
+			return
+		}
+
+		streamTreeEventsAsServerSentEvents(ctx, treeEvents)
+	})
+}
@@ -0,0 +1,131 @@
+package server_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/CaioBittencourt/arvore-genealogica/repository/mongodb"
+	"github.com/CaioBittencourt/arvore-genealogica/server"
+	"github.com/CaioBittencourt/arvore-genealogica/server/routes"
+	"github.com/CaioBittencourt/arvore-genealogica/service"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/websocket"
+)
+
+func receiveTreeEvent(conn *websocket.Conn) (*server.TreeEventResponse, error) {
+	if err := conn.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		return nil, err
+	}
+
+	res := server.TreeEventResponse{}
+	if err := websocket.JSON.Receive(conn, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func TestGetPersonFamilyTreeEventsHandler(t *testing.T) {
+	personRepository := mongodb.NewPersonRepository(*mongoClient, os.Getenv("MONGO_DATABASE"))
+	personService := service.NewPersonService(personRepository)
+	treeRepository := mongodb.NewTreeRepository(*mongoClient, os.Getenv("MONGO_DATABASE"))
+	treeService := service.NewTreeService(treeRepository)
+	router := routes.SetupRouter(personService, treeService)
+
+	httpServer := httptest.NewServer(router)
+	defer httpServer.Close()
+
+	t.Run("unknown person", func(t *testing.T) {
+		res, err := http.Get(fmt.Sprintf("%s/person/%s/tree/events", httpServer.URL, "6434a7a3c3b2d8a4a1f1a111"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+
+		assert.Equal(t, 404, res.StatusCode)
+	})
+
+	t.Run("events of the family tree through websocket", func(t *testing.T) {
+		insertedPersonByName := map[string]server.PersonResponse{}
+		if err := storePerson(router, server.StorePersonRequest{Name: "Tunico", Gender: "male"}, insertedPersonByName); err != nil {
+			t.Fatal(err)
+		}
+		tunicoID := insertedPersonByName["Tunico"].ID
+
+		wsURL := strings.Replace(httpServer.URL, "http", "ws", 1)
+		conn, err := websocket.Dial(fmt.Sprintf("%s/person/%s/tree/events", wsURL, tunicoID), "", httpServer.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+
+		if err := storePerson(router, server.StorePersonRequest{Name: "Joana", Gender: "female"}, insertedPersonByName); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := storePerson(router, server.StorePersonRequest{Name: "Luis", Gender: "male", FatherID: &tunicoID}, insertedPersonByName); err != nil {
+			t.Fatal(err)
+		}
+		luisID := insertedPersonByName["Luis"].ID
+
+		created, err := receiveTreeEvent(conn)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "person_created", created.Type)
+		assert.Equal(t, luisID, created.PersonID)
+		assert.Equal(t, "Luis", created.Person.Name)
+
+		linked, err := receiveTreeEvent(conn)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "link_added", linked.Type)
+		assert.Equal(t, luisID, linked.PersonID)
+		assert.Equal(t, tunicoID, linked.RelatedPersonID)
+	})
+
+	t.Run("websocket handshakes from other origins", func(t *testing.T) {
+		insertedPersonByName := map[string]server.PersonResponse{}
+		if err := storePerson(router, server.StorePersonRequest{Name: "Dayse", Gender: "female"}, insertedPersonByName); err != nil {
+			t.Fatal(err)
+		}
+		dayseID := insertedPersonByName["Dayse"].ID
+
+		wsURL := strings.Replace(httpServer.URL, "http", "ws", 1)
+		if _, err := websocket.Dial(fmt.Sprintf("%s/person/%s/tree/events", wsURL, dayseID), "", "http://evil.example"); err == nil {
+			t.Fatal("expected handshake from an origin that is not allowed to fail")
+		}
+
+		allowedOriginsServer := httptest.NewServer(routes.SetupRouter(personService, treeService, "http://family.example"))
+		defer allowedOriginsServer.Close()
+
+		wsURL = strings.Replace(allowedOriginsServer.URL, "http", "ws", 1)
+		conn, err := websocket.Dial(fmt.Sprintf("%s/person/%s/tree/events", wsURL, dayseID), "", "http://family.example")
+		if err != nil {
+			t.Fatal(err)
+		}
+		conn.Close()
+
+		req, _ := http.NewRequest(http.MethodOptions, fmt.Sprintf("%s/person/%s", allowedOriginsServer.URL, dayseID), nil)
+		req.Header.Set("Origin", "http://family.example")
+		req.Header.Set("Access-Control-Request-Method", http.MethodPut)
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+
+		assert.Equal(t, http.StatusNoContent, res.StatusCode)
+		assert.Equal(t, "http://family.example", res.Header.Get("Access-Control-Allow-Origin"))
+	})
+
+	teardownTest()
+}
@@ -0,0 +1,184 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+	"github.com/CaioBittencourt/arvore-genealogica/errors"
+	"github.com/CaioBittencourt/arvore-genealogica/gedcom"
+	"github.com/CaioBittencourt/arvore-genealogica/gedcomx"
+	"github.com/CaioBittencourt/arvore-genealogica/graphviz"
+	"github.com/CaioBittencourt/arvore-genealogica/jsonld"
+	"github.com/CaioBittencourt/arvore-genealogica/mermaid"
+	"github.com/CaioBittencourt/arvore-genealogica/plantuml"
+	"github.com/CaioBittencourt/arvore-genealogica/service"
+	"github.com/CaioBittencourt/arvore-genealogica/spreadsheet"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	log "github.com/sirupsen/logrus"
+)
+
+type familyGraphEncodeOptions struct {
+	RelationshipLabels bool
+	// BaseIRI is the address persons are served at, used to build the JSON-LD ids
+	BaseIRI string
+}
+
+type familyGraphEncoder func(writer io.Writer, familyGraph domain.FamilyGraph, personID string, options familyGraphEncodeOptions) error
+
+func personsEncoder(encode func(writer io.Writer, persons []domain.Person) error) familyGraphEncoder {
+	return func(writer io.Writer, familyGraph domain.FamilyGraph, personID string, options familyGraphEncodeOptions) error {
+		return encode(writer, gedcom.PersonsFromFamilyGraph(familyGraph))
+	}
+}
+
+func encodeGraphviz(writer io.Writer, familyGraph domain.FamilyGraph, personID string, options familyGraphEncodeOptions) error {
+	return graphviz.Encode(writer, familyGraph, personID, graphviz.Options{RelationshipLabels: options.RelationshipLabels})
+}
+
+func encodeMermaid(writer io.Writer, familyGraph domain.FamilyGraph, personID string, options familyGraphEncodeOptions) error {
+	return mermaid.Encode(writer, familyGraph, personID)
+}
+
+func encodePlantUML(writer io.Writer, familyGraph domain.FamilyGraph, personID string, options familyGraphEncodeOptions) error {
+	return plantuml.Encode(writer, familyGraph, personID)
+}
+
+func encodeJSONLD(writer io.Writer, familyGraph domain.FamilyGraph, personID string, options familyGraphEncodeOptions) error {
+	return jsonld.Encode(writer, familyGraph, personID, options.BaseIRI)
+}
+
+// familyGraphMediaTypes are the formats the family tree can be negotiated in through the Accept header, JSON being
+// the default.
+var familyGraphMediaTypes = []string{binding.MIMEJSON, gedcom.MediaType, gedcom.V7MediaType, gedcomx.MediaType, graphviz.MediaType, mermaid.MediaType, plantuml.MediaType, spreadsheet.MediaType, jsonld.MediaType, ndjsonMediaType}
+
+// familyGraphMediaTypesByFormat lets clients that can not set the Accept header pick the format with ?format=.
+var familyGraphMediaTypesByFormat = map[string]string{
+	"json":     binding.MIMEJSON,
+	"gedcom":   gedcom.MediaType,
+	"gedcom7":  gedcom.V7MediaType,
+	"gedcomx":  gedcomx.MediaType,
+	"dot":      graphviz.MediaType,
+	"mermaid":  mermaid.MediaType,
+	"plantuml": plantuml.MediaType,
+	"csv":      spreadsheet.MediaType,
+	"jsonld":   jsonld.MediaType,
+	"ndjson":   ndjsonMediaType,
+}
+
+var familyGraphEncodersByMediaType = map[string]familyGraphEncoder{
+	gedcom.MediaType:      personsEncoder(gedcom.Encode),
+	gedcom.V7MediaType:    personsEncoder(gedcom.EncodeVersion7),
+	gedcomx.MediaType:     personsEncoder(gedcomx.Encode),
+	graphviz.MediaType:    encodeGraphviz,
+	mermaid.MediaType:     encodeMermaid,
+	plantuml.MediaType:    encodePlantUML,
+	spreadsheet.MediaType: personsEncoder(spreadsheet.Encode),
+	jsonld.MediaType:      encodeJSONLD,
+}
+
+// negotiateFamilyGraphMediaType returns an empty media type when none of the accepted formats is supported.
+func negotiateFamilyGraphMediaType(ctx *gin.Context) (string, error) {
+	format := ctx.Query("format")
+	if format == "" {
+		return ctx.NegotiateFormat(familyGraphMediaTypes...), nil
+	}
+
+	mediaType, ok := familyGraphMediaTypesByFormat[format]
+	if !ok {
+		return "", errors.NewApplicationError(fmt.Sprintf("format %s is not supported", format), errors.InvalidQueryParameterErrorCode)
+	}
+
+	return mediaType, nil
+}
+
+// requestBaseIRI is the address of the API the request was sent to, or of the tree for tree scoped requests.
+func requestBaseIRI(ctx *gin.Context) string {
+	scheme := "http"
+	if ctx.Request.TLS != nil {
+		scheme = "https"
+	}
+	if forwardedProto := ctx.GetHeader("X-Forwarded-Proto"); forwardedProto != "" {
+		scheme = forwardedProto
+	}
+
+	baseIRI := scheme + "://" + ctx.Request.Host
+	if treeID := domain.TreeIDFromContext(ctx); treeID != "" {
+		baseIRI += "/trees/" + url.PathEscape(treeID)
+	}
+
+	return baseIRI
+}
+
+func writeEncodedFamilyGraph(ctx *gin.Context, mediaType string, familyGraph domain.FamilyGraph, personID string) {
+	options := familyGraphEncodeOptions{
+		RelationshipLabels: ctx.Query("labels") == "true",
+		BaseIRI:            requestBaseIRI(ctx),
+	}
+
+	ctx.Header("Content-Type", mediaType+"; charset=utf-8")
+	ctx.Status(http.StatusOK)
+
+	if err := familyGraphEncodersByMediaType[mediaType](ctx.Writer, familyGraph, personID, options); err != nil {
+		log.WithError(err).Error("server export: failed to encode family graph")
+	}
+}
+
+func writeGedcom(ctx *gin.Context, fileName string, persons []domain.Person) {
+	ctx.Header("Content-Type", gedcom.MediaType+"; charset=utf-8")
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+	ctx.Status(http.StatusOK)
+
+	if err := gedcom.Encode(ctx.Writer, persons); err != nil {
+		log.WithError(err).Error("server export: failed to write gedcom")
+	}
+}
+
+// @Summary      Export family tree of person as GEDCOM
+// @Description  Export the family tree of a person as a GEDCOM 5.5.1 file. Co-parents become FAM records and the xrefs are derived from the person ids, so exporting twice gives the same ids
+// @Produce      plain
+// @Param        id   path       string  true  "Person ID"
+// @Success      200  {string}   string
+// @Failure      400  {object}   ErrorResponse
+// @Failure      404  {object}   ErrorResponse
+// @Failure      500  {object}   ErrorResponse
+// @Router       /person/:id/tree.ged [get]
+func GetPersonFamilyTreeGedcom(personService service.PersonService) gin.HandlerFunc {
+	return gin.HandlerFunc(func(ctx *gin.Context) {
+		personID := ctx.Param("id")
+
+		familyGraph, err := personService.GetFamilyGraphByPersonID(ctx, personID, domain.DefaultFamilyGraphScope())
+		if err != nil {
+			createServerResponseFromError(ctx, err)
+			return
+		}
+
+		writeGedcom(ctx, personID+".ged", gedcom.PersonsFromFamilyGraph(*familyGraph))
+	})
+}
+
+// @Summary      Export every person as GEDCOM
+// @Description  Export every person of the tree as a GEDCOM 5.5.1 file
+// @Produce      plain
+// @Success      200  {string}   string
+// @Failure      500  {object}   ErrorResponse
+// @Router       /export/gedcom [get]
+func ExportGedcom(personService service.PersonService) gin.HandlerFunc {
+	return gin.HandlerFunc(func(ctx *gin.Context) {
+		persons, err := personService.GetAllPersons(ctx)
+		if err != nil {
+			createServerResponseFromError(ctx, err)
+			return
+		}
+
+		fileName := "export.ged"
+		if treeID := domain.TreeIDFromContext(ctx); treeID != "" {
+			fileName = treeID + ".ged"
+		}
+
+		writeGedcom(ctx, fileName, persons)
+	})
+}
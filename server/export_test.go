@@ -0,0 +1,140 @@
+package server_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/CaioBittencourt/arvore-genealogica/gedcom"
+	"github.com/CaioBittencourt/arvore-genealogica/gedcomx"
+	"github.com/CaioBittencourt/arvore-genealogica/graphviz"
+	"github.com/CaioBittencourt/arvore-genealogica/jsonld"
+	"github.com/CaioBittencourt/arvore-genealogica/mermaid"
+	"github.com/CaioBittencourt/arvore-genealogica/plantuml"
+	"github.com/CaioBittencourt/arvore-genealogica/repository/mongodb"
+	"github.com/CaioBittencourt/arvore-genealogica/server"
+	"github.com/CaioBittencourt/arvore-genealogica/server/routes"
+	"github.com/CaioBittencourt/arvore-genealogica/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func doGetGedcomRequest(router *gin.Engine, url string) (string, int) {
+	return doGetWithAcceptRequest(router, url, "")
+}
+
+func doGetWithAcceptRequest(router *gin.Engine, url string, accept string) (string, int) {
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("GET", url, nil)
+	if accept != "" {
+		httpReq.Header.Set("Accept", accept)
+	}
+	router.ServeHTTP(w, httpReq)
+
+	return w.Body.String(), w.Code
+}
+
+func TestExportGedcom(t *testing.T) {
+	personRepository := mongodb.NewPersonRepository(*mongoClient, os.Getenv("MONGO_DATABASE"))
+	personService := service.NewPersonService(personRepository)
+	treeRepository := mongodb.NewTreeRepository(*mongoClient, os.Getenv("MONGO_DATABASE"))
+	treeService := service.NewTreeService(treeRepository)
+	router := routes.SetupRouter(personService, treeService)
+
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("POST", "/import/gedcom", strings.NewReader(familyGedcom))
+	router.ServeHTTP(w, httpReq)
+
+	var report server.ImportReportResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+		t.Error(err)
+	}
+
+	t.Run("should export the family tree of a person with the parents as a family", func(t *testing.T) {
+		file, statusCode := doGetGedcomRequest(router, "/person/"+report.IDs["I3"]+"/tree.ged")
+
+		assert.Equal(t, 200, statusCode)
+		assert.True(t, strings.HasPrefix(file, "0 HEAD\r\n"))
+		assert.True(t, strings.HasSuffix(file, "0 TRLR\r\n"))
+		assert.Equal(t, 3, strings.Count(file, " INDI\r\n"))
+		assert.Equal(t, 1, strings.Count(file, " FAM\r\n"))
+		assert.Contains(t, file, "2 PLAC Porto Alegre\r\n")
+	})
+
+	t.Run("should export every person", func(t *testing.T) {
+		file, statusCode := doGetGedcomRequest(router, "/export/gedcom")
+
+		assert.Equal(t, 200, statusCode)
+		assert.Equal(t, 3, strings.Count(file, " INDI\r\n"))
+	})
+
+	t.Run("should negotiate the family tree format with the accept header", func(t *testing.T) {
+		treeURL := "/person/" + report.IDs["I3"] + "/tree"
+
+		file, statusCode := doGetWithAcceptRequest(router, treeURL, gedcom.V7MediaType)
+		assert.Equal(t, 200, statusCode)
+		assert.Contains(t, file, "2 VERS 7.0\r\n")
+
+		document, statusCode := doGetWithAcceptRequest(router, treeURL, gedcomx.MediaType)
+		assert.Equal(t, 200, statusCode)
+		decoded, err := gedcomx.Decode(strings.NewReader(document))
+		assert.NoError(t, err)
+		assert.Len(t, decoded.Persons, 3)
+
+		_, statusCode = doGetWithAcceptRequest(router, treeURL, "application/xml")
+		assert.Equal(t, 406, statusCode)
+	})
+
+	t.Run("should export the family tree as a dot graph", func(t *testing.T) {
+		treeURL := "/person/" + report.IDs["I3"] + "/tree"
+
+		graph, statusCode := doGetWithAcceptRequest(router, treeURL, graphviz.MediaType)
+		assert.Equal(t, 200, statusCode)
+		assert.True(t, strings.HasPrefix(graph, "digraph family {\n"))
+
+		labeledGraph, statusCode := doGetWithAcceptRequest(router, treeURL+"?format=dot&labels=true", "")
+		assert.Equal(t, 200, statusCode)
+		assert.Contains(t, labeledGraph, `\n(parent)"`)
+
+		_, statusCode = doGetWithAcceptRequest(router, treeURL+"?format=xml", "")
+		assert.Equal(t, 400, statusCode)
+	})
+
+	t.Run("should export the family tree as mermaid and plantuml diagrams", func(t *testing.T) {
+		treeURL := "/person/" + report.IDs["I3"] + "/tree"
+
+		flowchart, statusCode := doGetWithAcceptRequest(router, treeURL+"?format=mermaid", "")
+		assert.Equal(t, 200, statusCode)
+		assert.True(t, strings.HasPrefix(flowchart, "flowchart TB\n"))
+		assert.Contains(t, flowchart, mermaid.NodeID(report.IDs["I1"])+" --> "+mermaid.NodeID(report.IDs["I3"]))
+		assert.Equal(t, 1, strings.Count(flowchart, "---|spouse|"))
+
+		diagram, statusCode := doGetWithAcceptRequest(router, treeURL, plantuml.MediaType)
+		assert.Equal(t, 200, statusCode)
+		assert.True(t, strings.HasPrefix(diagram, "@startuml\n"))
+	})
+
+	t.Run("should export the family tree as schema.org persons", func(t *testing.T) {
+		treeURL := "/person/" + report.IDs["I3"] + "/tree"
+
+		body, statusCode := doGetWithAcceptRequest(router, treeURL, jsonld.MediaType)
+		assert.Equal(t, 200, statusCode)
+
+		var document jsonld.Document
+		assert.NoError(t, json.Unmarshal([]byte(body), &document))
+		assert.Len(t, document.Graph, 3)
+		assert.True(t, strings.HasSuffix(document.Graph[0].ID, "/person/"+report.IDs["I3"]))
+		assert.Len(t, document.Graph[0].Parent, 2)
+	})
+
+	t.Run("should return not found for unexisting person", func(t *testing.T) {
+		_, statusCode := doGetGedcomRequest(router, "/person/64138dcbd2a5f3b8e8a24f1a/tree.ged")
+
+		assert.Equal(t, 404, statusCode)
+	})
+
+	teardownTest()
+}
@@ -0,0 +1,67 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/CaioBittencourt/arvore-genealogica/graph"
+	"github.com/CaioBittencourt/arvore-genealogica/service"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+type GraphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+type GraphQLErrorResponse struct {
+	Message    string                 `json:"message"`
+	Path       []interface{}          `json:"path,omitempty"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+type GraphQLResponse struct {
+	Data   json.RawMessage        `json:"data,omitempty" swaggertype:"object"`
+	Errors []GraphQLErrorResponse `json:"errors,omitempty"`
+}
+
+// @Summary      GraphQL query
+// @Description  Runs a GraphQL query or mutation over persons. A Person exposes its parents, children and spouses as persons, so relatives can be traversed to any depth, along with relationships(to:) and baconNumber(to:). Persons are read in batches, one call per generation instead of one per person. createPerson links the new person only to relatives still on the version sent. The schema is at graph/schema.graphql
+// @Accept       json
+// @Produce      json
+// @Param        request body    GraphQLRequest  true  "Query, operation name and variables"
+// @Success      200  {object}   GraphQLResponse
+// @Failure      400  {object}   ErrorResponse
+// @Router       /graphql [post]
+func GraphQL(personService service.PersonService) gin.HandlerFunc {
+	schema := graph.NewSchema(personService)
+
+	return gin.HandlerFunc(func(ctx *gin.Context) {
+		var req GraphQLRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			log.WithError(err).Error("server graphql: invalid request")
+			ctx.JSON(http.StatusBadRequest, ErrorResponse{ErrorMessage: err.Error()})
+			return
+		}
+
+		if req.Query == "" {
+			ctx.JSON(http.StatusBadRequest, ErrorResponse{ErrorMessage: "query is required"})
+			return
+		}
+
+		response := schema.Exec(graph.NewRequestContext(ctx.Request.Context(), personService), req.Query, req.OperationName, req.Variables)
+
+		graphQLResponse := GraphQLResponse{Data: response.Data}
+		for _, queryError := range response.Errors {
+			graphQLResponse.Errors = append(graphQLResponse.Errors, GraphQLErrorResponse{
+				Message:    queryError.Message,
+				Path:       queryError.Path,
+				Extensions: queryError.Extensions,
+			})
+		}
+
+		ctx.JSON(http.StatusOK, graphQLResponse)
+	})
+}
@@ -0,0 +1,124 @@
+package server_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/CaioBittencourt/arvore-genealogica/repository/mongodb"
+	"github.com/CaioBittencourt/arvore-genealogica/server"
+	"github.com/CaioBittencourt/arvore-genealogica/server/routes"
+	"github.com/CaioBittencourt/arvore-genealogica/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func doGraphQLRequest(router *gin.Engine, query string, variables map[string]interface{}) (map[string]interface{}, []server.GraphQLErrorResponse, int) {
+	body, _ := json.Marshal(server.GraphQLRequest{Query: query, Variables: variables})
+
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("POST", "/graphql", bytes.NewReader(body))
+	router.ServeHTTP(w, httpReq)
+
+	var response struct {
+		Data   map[string]interface{}        `json:"data"`
+		Errors []server.GraphQLErrorResponse `json:"errors"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+
+	return response.Data, response.Errors, w.Code
+}
+
+func TestGraphQL(t *testing.T) {
+	personRepository := mongodb.NewPersonRepository(*mongoClient, os.Getenv("MONGO_DATABASE"))
+	personService := service.NewPersonService(personRepository)
+	treeRepository := mongodb.NewTreeRepository(*mongoClient, os.Getenv("MONGO_DATABASE"))
+	treeService := service.NewTreeService(treeRepository)
+	router := routes.SetupRouter(personService, treeService)
+
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("POST", "/import/gedcom", strings.NewReader(familyGedcom))
+	router.ServeHTTP(w, httpReq)
+
+	var report server.ImportReportResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+		t.Error(err)
+	}
+
+	t.Run("should traverse parents, children and spouses", func(t *testing.T) {
+		data, errors, statusCode := doGraphQLRequest(router,
+			`query($id: ID!) { person(id: $id) { name parents { name spouses { name } children { name } } } }`,
+			map[string]interface{}{"id": report.IDs["I3"]},
+		)
+
+		assert.Equal(t, 200, statusCode)
+		assert.Empty(t, errors)
+
+		person := data["person"].(map[string]interface{})
+		assert.Equal(t, "Caio Bittencourt", person["name"])
+		assert.Len(t, person["parents"], 2)
+		for _, parent := range person["parents"].([]interface{}) {
+			assert.Len(t, parent.(map[string]interface{})["spouses"], 1)
+			assert.Equal(t, []interface{}{map[string]interface{}{"name": "Caio Bittencourt"}}, parent.(map[string]interface{})["children"])
+		}
+	})
+
+	t.Run("should return relationships and bacon's number", func(t *testing.T) {
+		data, errors, statusCode := doGraphQLRequest(router,
+			`query($id: ID!, $to: ID!) { person(id: $id) { relationships(to: $to) { relationship person { name } } baconNumber(to: $to) } }`,
+			map[string]interface{}{"id": report.IDs["I3"], "to": report.IDs["I1"]},
+		)
+
+		assert.Equal(t, 200, statusCode)
+		assert.Empty(t, errors)
+
+		person := data["person"].(map[string]interface{})
+		assert.Equal(t, float64(1), person["baconNumber"])
+		assert.Len(t, person["relationships"], 1)
+	})
+
+	t.Run("should create a person linked to a parent on the version read", func(t *testing.T) {
+		data, errors, _ := doGraphQLRequest(router,
+			`query($id: ID!) { person(id: $id) { version } }`,
+			map[string]interface{}{"id": report.IDs["I3"]},
+		)
+		assert.Empty(t, errors)
+		version := data["person"].(map[string]interface{})["version"]
+
+		data, errors, statusCode := doGraphQLRequest(router,
+			`mutation($input: CreatePersonInput!) { createPerson(input: $input) { name parents { name } } }`,
+			map[string]interface{}{"input": map[string]interface{}{
+				"name":   "Maria Bittencourt",
+				"gender": "female",
+				"father": map[string]interface{}{"id": report.IDs["I3"], "version": version},
+			}},
+		)
+
+		assert.Equal(t, 200, statusCode)
+		assert.Empty(t, errors)
+		assert.Equal(t, map[string]interface{}{
+			"name":    "Maria Bittencourt",
+			"parents": []interface{}{map[string]interface{}{"name": "Caio Bittencourt"}},
+		}, data["createPerson"])
+	})
+
+	t.Run("should return the error code of invalid ids", func(t *testing.T) {
+		_, errors, statusCode := doGraphQLRequest(router, `{ person(id: "invalid") { name } }`, nil)
+
+		assert.Equal(t, 200, statusCode)
+		assert.Len(t, errors, 1)
+		assert.Equal(t, "INVALID_PERSON_ID", errors[0].Extensions["code"])
+	})
+
+	t.Run("should return bad request without a query", func(t *testing.T) {
+		_, _, statusCode := doGraphQLRequest(router, "", nil)
+
+		assert.Equal(t, 400, statusCode)
+	})
+
+	teardownTest()
+}
@@ -0,0 +1,153 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+	"github.com/CaioBittencourt/arvore-genealogica/errors"
+	"github.com/CaioBittencourt/arvore-genealogica/service"
+	"github.com/gin-gonic/gin"
+)
+
+type PersonSnapshotResponse struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Gender      string   `json:"gender"`
+	ParentIDs   []string `json:"parentIds"`
+	ChildrenIDs []string `json:"childrenIds"`
+}
+
+type PersonChangeResponse struct {
+	ID           string                  `json:"id"`
+	PersonID     string                  `json:"personId"`
+	Type         string                  `json:"type"`
+	Before       *PersonSnapshotResponse `json:"before"`
+	After        *PersonSnapshotResponse `json:"after"`
+	ClaimedActor string                  `json:"claimedActor"`
+	Source       string                  `json:"source"`
+	Reason       string                  `json:"reason"`
+	Timestamp    time.Time               `json:"timestamp"`
+}
+
+type PersonHistoryResponse struct {
+	Changes []PersonChangeResponse `json:"changes"`
+}
+
+func parseAsOfQueryParameter(ctx *gin.Context, name string) (*time.Time, error) {
+	value := ctx.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	at, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, errors.NewApplicationError(fmt.Sprintf("%s must be a RFC3339 timestamp", name), errors.InvalidQueryParameterErrorCode)
+	}
+
+	return &at, nil
+}
+
+func buildPersonSnapshotResponseFromDomainPerson(domainPerson *domain.Person) *PersonSnapshotResponse {
+	if domainPerson == nil {
+		return nil
+	}
+
+	snapshot := PersonSnapshotResponse{
+		ID:          domainPerson.ID,
+		Name:        domainPerson.Name,
+		Gender:      string(domainPerson.Gender),
+		ParentIDs:   []string{},
+		ChildrenIDs: []string{},
+	}
+
+	for _, parent := range domainPerson.Parents {
+		snapshot.ParentIDs = append(snapshot.ParentIDs, parent.ID)
+	}
+
+	for _, children := range domainPerson.Children {
+		snapshot.ChildrenIDs = append(snapshot.ChildrenIDs, children.ID)
+	}
+
+	return &snapshot
+}
+
+func buildPersonHistoryResponseFromPersonChanges(personChanges []domain.PersonChange) PersonHistoryResponse {
+	historyResponse := PersonHistoryResponse{Changes: []PersonChangeResponse{}}
+	for _, personChange := range personChanges {
+		historyResponse.Changes = append(historyResponse.Changes, PersonChangeResponse{
+			ID:           personChange.ID,
+			PersonID:     personChange.PersonID,
+			Type:         string(personChange.Type),
+			Before:       buildPersonSnapshotResponseFromDomainPerson(personChange.Before),
+			After:        buildPersonSnapshotResponseFromDomainPerson(personChange.After),
+			ClaimedActor: personChange.ClaimedActor,
+			Source:       personChange.Source,
+			Reason:       personChange.Reason,
+			Timestamp:    personChange.Timestamp,
+		})
+	}
+
+	return historyResponse
+}
+
+// @Summary      Get change history of person
+// @Description  Get every change recorded for a person, oldest first. The claimedActor and reason come from the X-Actor and X-Change-Reason headers of the mutation and are not checked, anyone can send them. The source is set by the server, the address of the HTTP or gRPC client or cli for the commands, and can not be forged through headers
+// @Accept       json
+// @Produce      json
+// @Param        id   path       string  true  "Person ID"
+// @Success      200  {object}   PersonHistoryResponse
+// @Failure      400  {object}   ErrorResponse
+// @Failure      404  {object}   ErrorResponse
+// @Failure      500  {object}   ErrorResponse
+// @Router       /person/:id/history [get]
+func GetPersonHistory(personService service.PersonService) gin.HandlerFunc {
+	return gin.HandlerFunc(func(ctx *gin.Context) {
+		personID := ctx.Param("id")
+
+		personChanges, err := personService.GetPersonHistory(ctx, personID)
+		if err != nil {
+			createServerResponseFromError(ctx, err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, buildPersonHistoryResponseFromPersonChanges(personChanges))
+	})
+}
+
+// @Summary      Get person as of a point in time
+// @Description  Rebuilds a person and its parents and children from the change history
+// @Accept       json
+// @Produce      json
+// @Param        id   path       string  true  "Person ID"
+// @Param        at   query      string  true  "RFC3339 timestamp"
+// @Success      200  {object}   PersonResponse
+// @Failure      400  {object}   ErrorResponse
+// @Failure      404  {object}   ErrorResponse
+// @Failure      500  {object}   ErrorResponse
+// @Router       /person/:id/snapshot [get]
+func GetPersonSnapshot(personService service.PersonService) gin.HandlerFunc {
+	return gin.HandlerFunc(func(ctx *gin.Context) {
+		personID := ctx.Param("id")
+
+		at, err := parseAsOfQueryParameter(ctx, "at")
+		if err != nil {
+			createServerResponseFromError(ctx, err)
+			return
+		}
+
+		if at == nil {
+			createServerResponseFromError(ctx, errors.NewApplicationError("at is required", errors.InvalidQueryParameterErrorCode))
+			return
+		}
+
+		person, err := personService.GetPersonAsOf(ctx, personID, *at)
+		if err != nil {
+			createServerResponseFromError(ctx, err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, buildPersonResponseFromDomainPerson(*person))
+	})
+}
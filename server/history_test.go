@@ -0,0 +1,149 @@
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/CaioBittencourt/arvore-genealogica/repository/mongodb"
+	"github.com/CaioBittencourt/arvore-genealogica/server"
+	"github.com/CaioBittencourt/arvore-genealogica/server/routes"
+	"github.com/CaioBittencourt/arvore-genealogica/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func doGetPersonHistoryRequest(router *gin.Engine, personID string) (*server.PersonHistoryResponse, int, error) {
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("GET", fmt.Sprintf("/person/%s/history", personID), nil)
+	router.ServeHTTP(w, httpReq)
+
+	res := server.PersonHistoryResponse{}
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		return nil, w.Code, err
+	}
+
+	return &res, w.Code, nil
+}
+
+func TestGetPersonHistory(t *testing.T) {
+	personRepository := mongodb.NewPersonRepository(*mongoClient, os.Getenv("MONGO_DATABASE"))
+	personService := service.NewPersonService(personRepository)
+	treeRepository := mongodb.NewTreeRepository(*mongoClient, os.Getenv("MONGO_DATABASE"))
+	treeService := service.NewTreeService(treeRepository)
+	router := routes.SetupRouter(personService, treeService)
+
+	insertedPersonByName := map[string]server.PersonResponse{}
+	if err := storePerson(router, server.StorePersonRequest{Name: "Tunico", Gender: "male"}, insertedPersonByName); err != nil {
+		t.Fatal(err)
+	}
+	tunicoID := insertedPersonByName["Tunico"].ID
+
+	if err := storePerson(router, server.StorePersonRequest{Name: "Luis", Gender: "male", FatherID: &tunicoID}, insertedPersonByName); err != nil {
+		t.Fatal(err)
+	}
+	luisID := insertedPersonByName["Luis"].ID
+
+	historyRes, statusCode, err := doGetPersonHistoryRequest(router, tunicoID)
+	if err != nil {
+		t.Error(err)
+	}
+
+	assert.Equal(t, 200, statusCode)
+	assert.Len(t, historyRes.Changes, 2)
+	if len(historyRes.Changes) == 2 {
+		created, updated := historyRes.Changes[0], historyRes.Changes[1]
+
+		assert.Equal(t, "created", created.Type)
+		assert.Nil(t, created.Before)
+		assert.Equal(t, "Tunico", created.After.Name)
+		// without X-Actor nothing is claimed, the source is the address of the connection of httptest requests
+		assert.Empty(t, created.ClaimedActor)
+		assert.Equal(t, "192.0.2.1", created.Source)
+
+		assert.Equal(t, "updated", updated.Type)
+		assert.Empty(t, updated.Before.ChildrenIDs)
+		assert.Equal(t, []string{luisID}, updated.After.ChildrenIDs)
+	}
+
+	teardownTest()
+}
+
+func TestGetPersonFamilyGraphAsOf(t *testing.T) {
+	personRepository := mongodb.NewPersonRepository(*mongoClient, os.Getenv("MONGO_DATABASE"))
+	personService := service.NewPersonService(personRepository)
+	treeRepository := mongodb.NewTreeRepository(*mongoClient, os.Getenv("MONGO_DATABASE"))
+	treeService := service.NewTreeService(treeRepository)
+	router := routes.SetupRouter(personService, treeService)
+
+	// a person stored before the history was recorded has no history entries
+	zezeObjectID := primitive.NewObjectID()
+	if _, err := mongoClient.Database(os.Getenv("MONGO_DATABASE")).Collection("person").InsertOne(context.Background(), bson.M{"_id": zezeObjectID, "name": "Zézé", "gender": "female"}); err != nil {
+		t.Fatal(err)
+	}
+	zezeID := zezeObjectID.Hex()
+	beforeTunico := time.Now().UTC()
+	time.Sleep(10 * time.Millisecond)
+
+	insertedPersonByName := map[string]server.PersonResponse{}
+	if err := storePerson(router, server.StorePersonRequest{Name: "Tunico", Gender: "male", MotherID: &zezeID}, insertedPersonByName); err != nil {
+		t.Fatal(err)
+	}
+	tunicoID := insertedPersonByName["Tunico"].ID
+
+	beforeLuis := time.Now().UTC()
+	time.Sleep(10 * time.Millisecond)
+
+	if err := storePerson(router, server.StorePersonRequest{Name: "Luis", Gender: "male", FatherID: &tunicoID}, insertedPersonByName); err != nil {
+		t.Fatal(err)
+	}
+	luisID := insertedPersonByName["Luis"].ID
+
+	asOf := func(at time.Time, query string) string {
+		return "?asOf=" + url.QueryEscape(at.Format(time.RFC3339Nano)) + query
+	}
+
+	t.Run("should rebuild the tree before the child was stored", func(t *testing.T) {
+		treeRes, _, statusCode, err := doGetPersonFamilyRelationshipsRequest(router, tunicoID, asOf(beforeLuis, ""))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, 200, statusCode)
+		assert.Contains(t, treeRes.Members, tunicoID)
+		assert.Contains(t, treeRes.Members, zezeID)
+		assert.NotContains(t, treeRes.Members, luisID)
+	})
+
+	t.Run("should read persons before their first recorded change", func(t *testing.T) {
+		treeRes, _, statusCode, err := doGetPersonFamilyRelationshipsRequest(router, zezeID, asOf(beforeTunico, ""))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, 200, statusCode)
+		assert.Contains(t, treeRes.Members, zezeID)
+		assert.NotContains(t, treeRes.Members, tunicoID)
+	})
+
+	t.Run("should apply the scope to the rebuilt tree", func(t *testing.T) {
+		treeRes, _, statusCode, err := doGetPersonFamilyRelationshipsRequest(router, tunicoID, asOf(time.Now().UTC(), "&ancestors=0"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, 200, statusCode)
+		assert.Contains(t, treeRes.Members, luisID)
+		assert.NotContains(t, treeRes.Members, zezeID)
+	})
+
+	teardownTest()
+}
@@ -0,0 +1,128 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+	"github.com/CaioBittencourt/arvore-genealogica/gedcom"
+	"github.com/CaioBittencourt/arvore-genealogica/service"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+type SkippedRecordResponse struct {
+	Line   int    `json:"line"`
+	XRef   string `json:"xref"`
+	Tag    string `json:"tag"`
+	Reason string `json:"reason"`
+}
+
+type ImportReportResponse struct {
+	Version         string                  `json:"version"`
+	Persons         int                     `json:"persons"`
+	Families        int                     `json:"families"`
+	IDs             map[string]string       `json:"ids"`
+	Skipped         []SkippedRecordResponse `json:"skipped"`
+	UnsupportedTags map[string]int          `json:"unsupportedTags"`
+}
+
+func BuildImportReportResponseFromImportReport(report gedcom.ImportReport) ImportReportResponse {
+	reportResponse := ImportReportResponse{
+		Version:         report.Version,
+		Persons:         report.Persons,
+		Families:        report.Families,
+		IDs:             report.IDByXRef,
+		Skipped:         []SkippedRecordResponse{},
+		UnsupportedTags: report.UnsupportedTags,
+	}
+
+	for _, skipped := range report.Skipped {
+		reportResponse.Skipped = append(reportResponse.Skipped, SkippedRecordResponse{
+			Line:   skipped.Line,
+			XRef:   skipped.XRef,
+			Tag:    skipped.Tag,
+			Reason: skipped.Reason,
+		})
+	}
+
+	return reportResponse
+}
+
+// uploadedFileReader accepts the file as the raw request body or as the "file" field of a multipart form.
+func uploadedFileReader(ctx *gin.Context) (io.ReadCloser, error) {
+	if ctx.ContentType() != "multipart/form-data" {
+		return ctx.Request.Body, nil
+	}
+
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		return nil, err
+	}
+
+	return fileHeader.Open()
+}
+
+type importFunc func(ctx context.Context, reader io.Reader) (*gedcom.ImportReport, []domain.BatchItemError, error)
+
+func importUploadedFile(ctx *gin.Context, importFile importFunc) {
+	file, err := uploadedFileReader(ctx)
+	if err != nil {
+		log.WithError(err).Error("server import: invalid request")
+		ctx.JSON(http.StatusBadRequest, ErrorResponse{
+			ErrorMessage: err.Error(),
+		})
+		return
+	}
+	defer file.Close()
+
+	report, itemErrors, err := importFile(ctx, file)
+	if len(itemErrors) > 0 {
+		errResponse := BuildErrorResponseFromError(err)
+		ctx.JSON(errResponse.StatusCode, StorePersonBatchErrorResponse{
+			ErrorResponse: errResponse,
+			Items:         BuildBatchItemErrorResponses(itemErrors),
+		})
+		return
+	}
+
+	if err != nil {
+		createServerResponseFromError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, BuildImportReportResponseFromImportReport(*report))
+}
+
+// @Summary      Import GEDCOM file
+// @Description  Import the individuals and families of a GEDCOM 5.5.1 or GEDCOM 7 file inside a single transaction. FAM records become parent links, spouses are inferred from common children. Returns the id stored for each individual cross reference and the skipped records and unsupported tags
+// @Accept       plain
+// @Accept       mpfd
+// @Produce      json
+// @Param        file  formData   file  false  "GEDCOM file, when not sent as the request body"
+// @Success      200  {object}   ImportReportResponse
+// @Failure      400  {object}   StorePersonBatchErrorResponse
+// @Failure      500  {object}   ErrorResponse
+// @Router       /import/gedcom [post]
+func ImportGedcom(importService service.ImportService) gin.HandlerFunc {
+	return gin.HandlerFunc(func(ctx *gin.Context) {
+		importUploadedFile(ctx, importService.ImportGedcom)
+	})
+}
+
+// @Summary      Import GEDCOM X document
+// @Description  Import the persons and relationships of a GEDCOM X JSON document inside a single transaction. ParentChild relationships become parent links, Couple relationships are inferred from common children. Returns the id stored for each person id and the skipped persons and relationships and unsupported fact types
+// @Accept       json
+// @Accept       mpfd
+// @Produce      json
+// @Param        file  formData   file  false  "GEDCOM X document, when not sent as the request body"
+// @Success      200  {object}   ImportReportResponse
+// @Failure      400  {object}   StorePersonBatchErrorResponse
+// @Failure      500  {object}   ErrorResponse
+// @Router       /import/gedcomx [post]
+func ImportGedcomX(importService service.ImportService) gin.HandlerFunc {
+	return gin.HandlerFunc(func(ctx *gin.Context) {
+		importUploadedFile(ctx, importService.ImportGedcomX)
+	})
+}
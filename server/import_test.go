@@ -0,0 +1,87 @@
+package server_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/CaioBittencourt/arvore-genealogica/repository/mongodb"
+	"github.com/CaioBittencourt/arvore-genealogica/server"
+	"github.com/CaioBittencourt/arvore-genealogica/server/routes"
+	"github.com/CaioBittencourt/arvore-genealogica/service"
+	"github.com/stretchr/testify/assert"
+)
+
+const familyGedcom = `0 HEAD
+1 GEDC
+2 VERS 5.5.1
+0 @I1@ INDI
+1 NAME Luis /Bittencourt/
+1 SEX M
+1 OCCU Engineer
+0 @I2@ INDI
+1 NAME Dayse /Bittencourt/
+1 SEX F
+0 @I3@ INDI
+1 NAME Caio /Bittencourt/
+1 SEX M
+1 BIRT
+2 DATE 1 JAN 1990
+2 PLAC Porto Alegre
+0 @F1@ FAM
+1 HUSB @I1@
+1 WIFE @I2@
+1 CHIL @I3@
+0 TRLR
+`
+
+func TestImportGedcom(t *testing.T) {
+	personRepository := mongodb.NewPersonRepository(*mongoClient, os.Getenv("MONGO_DATABASE"))
+	personService := service.NewPersonService(personRepository)
+	treeRepository := mongodb.NewTreeRepository(*mongoClient, os.Getenv("MONGO_DATABASE"))
+	treeService := service.NewTreeService(treeRepository)
+	router := routes.SetupRouter(personService, treeService)
+
+	t.Run("should import individuals and families and report unsupported tags", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		httpReq, _ := http.NewRequest("POST", "/import/gedcom", strings.NewReader(familyGedcom))
+		httpReq.Header.Set("Content-Type", "text/plain")
+		router.ServeHTTP(w, httpReq)
+
+		assert.Equal(t, 200, w.Code)
+
+		var report server.ImportReportResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(t, 3, report.Persons)
+		assert.Equal(t, 1, report.Families)
+		assert.Len(t, report.IDs, 3)
+		assert.Equal(t, map[string]int{"INDI.OCCU": 1}, report.UnsupportedTags)
+
+		var person server.PersonResponse
+		statusCode, err := doJSONRequest(router, "GET", "/person/"+report.IDs["I3"], nil, &person)
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(t, 200, statusCode)
+		assert.Equal(t, "Caio Bittencourt", person.Name)
+		assert.Equal(t, &server.LifeEvent{Date: "1 JAN 1990", Place: "Porto Alegre"}, person.Birth)
+		assert.Len(t, person.Parents, 2)
+	})
+
+	t.Run("should not import invalid gedcom", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		httpReq, _ := http.NewRequest("POST", "/import/gedcom", strings.NewReader("0 HEAD\n2 CHAR UTF-8\n"))
+		router.ServeHTTP(w, httpReq)
+
+		assert.Equal(t, 400, w.Code)
+	})
+
+	teardownTest()
+}
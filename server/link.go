@@ -0,0 +1,155 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+	"github.com/CaioBittencourt/arvore-genealogica/errors"
+	"github.com/CaioBittencourt/arvore-genealogica/service"
+	"github.com/gin-gonic/gin"
+)
+
+// buildPersonsToLinkFromIfMatch requires the entity tag of both persons on If-Match, unless it is "*".
+func buildPersonsToLinkFromIfMatch(ctx *gin.Context, childID string, parentID string) (domain.Person, domain.Person, error) {
+	child, parent := domain.Person{ID: childID}, domain.Person{ID: parentID}
+
+	versionByPersonID, anyVersion, err := parseIfMatchHeader(ctx)
+	if err != nil || anyVersion {
+		return child, parent, err
+	}
+
+	for _, person := range []*domain.Person{&child, &parent} {
+		version, ok := versionByPersonID[person.ID]
+		if !ok {
+			return child, parent, errors.NewApplicationError(
+				fmt.Sprintf("If-Match header must have the entity tag of person %s", person.ID),
+				errors.PersonVersionRequiredErrorCode,
+			)
+		}
+		person.Version = version
+	}
+
+	return child, parent, nil
+}
+
+// parseLinkTypesQueryParameter reads the comma separated linkTypes, nil when it is not set.
+func parseLinkTypesQueryParameter(ctx *gin.Context) ([]domain.LinkType, error) {
+	value := ctx.Query("linkTypes")
+	if value == "" {
+		return nil, nil
+	}
+
+	return domain.ParseLinkTypes(strings.Split(value, ","))
+}
+
+// updateParentLink links or unlinks childID and parentID and responds with the person of the :id path parameter.
+func updateParentLink(ctx *gin.Context, personService service.PersonService, childID string, parentID string, link bool) {
+	child, parent, err := buildPersonsToLinkFromIfMatch(ctx, childID, parentID)
+	if err != nil {
+		createServerResponseFromError(ctx, err)
+		return
+	}
+
+	if link {
+		role := domain.ParentRoleType(ctx.DefaultQuery("role", string(domain.GenericParentRole)))
+		linkType := domain.LinkType(ctx.DefaultQuery("linkType", string(domain.BiologicalLinkType)))
+		err = personService.LinkParent(ctx, child, parent, role, linkType)
+	} else {
+		err = personService.UnlinkParent(ctx, child, parent)
+	}
+	if err != nil {
+		createServerResponseFromError(ctx, err)
+		return
+	}
+
+	person, err := personService.GetPersonByID(ctx, ctx.Param("id"))
+	if err != nil {
+		createServerResponseFromError(ctx, err)
+		return
+	}
+
+	setPersonETag(ctx, *person)
+	ctx.JSON(http.StatusOK, buildPersonResponseFromDomainPerson(*person))
+}
+
+// @Summary      Link parent
+// @Description  Add parentId to the parents of the person with role and linkType, or change the role and link type of a current parent. A person has at most two biological parents, a single mother and a single father of each link type, and can not become an ancestor of itself. Mothers must be female and fathers male, generic parents allow two parents of the same gender. If-Match must hold the ETag of both persons, or "*"
+// @Produce      json
+// @Param        id        path      string  true   "Person ID"
+// @Param        parentId  path      string  true   "Parent ID"
+// @Param        role      query     string  false  "Role of the parent"  Enums(mother, father, parent)  default(parent)
+// @Param        linkType  query     string  false  "Link type of the parent"  Enums(biological, adoptive, foster, step, donor, surrogate)  default(biological)
+// @Param        If-Match  header    string  false  "ETags of the person and of the parent"
+// @Success      200  {object}   PersonResponse
+// @Failure      400  {object}   ErrorResponse
+// @Failure      404  {object}   ErrorResponse
+// @Failure      412  {object}   ErrorResponse
+// @Failure      428  {object}   ErrorResponse
+// @Failure      500  {object}   ErrorResponse
+// @Router       /person/{id}/parents/{parentId} [put]
+func LinkParent(personService service.PersonService) gin.HandlerFunc {
+	return gin.HandlerFunc(func(ctx *gin.Context) {
+		updateParentLink(ctx, personService, ctx.Param("id"), ctx.Param("parentId"), true)
+	})
+}
+
+// @Summary      Unlink parent
+// @Description  Remove parentId from the parents of the person. If-Match must hold the ETag of both persons, or "*"
+// @Produce      json
+// @Param        id        path      string  true   "Person ID"
+// @Param        parentId  path      string  true   "Parent ID"
+// @Param        If-Match  header    string  false  "ETags of the person and of the parent"
+// @Success      200  {object}   PersonResponse
+// @Failure      400  {object}   ErrorResponse
+// @Failure      404  {object}   ErrorResponse
+// @Failure      412  {object}   ErrorResponse
+// @Failure      428  {object}   ErrorResponse
+// @Failure      500  {object}   ErrorResponse
+// @Router       /person/{id}/parents/{parentId} [delete]
+func UnlinkParent(personService service.PersonService) gin.HandlerFunc {
+	return gin.HandlerFunc(func(ctx *gin.Context) {
+		updateParentLink(ctx, personService, ctx.Param("id"), ctx.Param("parentId"), false)
+	})
+}
+
+// @Summary      Link child
+// @Description  Add childId to the children of the person with role and linkType, with the same checks of linking a parent. If-Match must hold the ETag of both persons, or "*"
+// @Produce      json
+// @Param        id        path      string  true   "Person ID"
+// @Param        childId   path      string  true   "Child ID"
+// @Param        role      query     string  false  "Role of the person for the child"  Enums(mother, father, parent)  default(parent)
+// @Param        linkType  query     string  false  "Link type of the person for the child"  Enums(biological, adoptive, foster, step, donor, surrogate)  default(biological)
+// @Param        If-Match  header    string  false  "ETags of the person and of the child"
+// @Success      200  {object}   PersonResponse
+// @Failure      400  {object}   ErrorResponse
+// @Failure      404  {object}   ErrorResponse
+// @Failure      412  {object}   ErrorResponse
+// @Failure      428  {object}   ErrorResponse
+// @Failure      500  {object}   ErrorResponse
+// @Router       /person/{id}/children/{childId} [put]
+func LinkChild(personService service.PersonService) gin.HandlerFunc {
+	return gin.HandlerFunc(func(ctx *gin.Context) {
+		updateParentLink(ctx, personService, ctx.Param("childId"), ctx.Param("id"), true)
+	})
+}
+
+// @Summary      Unlink child
+// @Description  Remove childId from the children of the person. If-Match must hold the ETag of both persons, or "*"
+// @Produce      json
+// @Param        id        path      string  true   "Person ID"
+// @Param        childId   path      string  true   "Child ID"
+// @Param        If-Match  header    string  false  "ETags of the person and of the child"
+// @Success      200  {object}   PersonResponse
+// @Failure      400  {object}   ErrorResponse
+// @Failure      404  {object}   ErrorResponse
+// @Failure      412  {object}   ErrorResponse
+// @Failure      428  {object}   ErrorResponse
+// @Failure      500  {object}   ErrorResponse
+// @Router       /person/{id}/children/{childId} [delete]
+func UnlinkChild(personService service.PersonService) gin.HandlerFunc {
+	return gin.HandlerFunc(func(ctx *gin.Context) {
+		updateParentLink(ctx, personService, ctx.Param("childId"), ctx.Param("id"), false)
+	})
+}
@@ -0,0 +1,238 @@
+package server_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/CaioBittencourt/arvore-genealogica/errors"
+	"github.com/CaioBittencourt/arvore-genealogica/repository/mongodb"
+	"github.com/CaioBittencourt/arvore-genealogica/server"
+	"github.com/CaioBittencourt/arvore-genealogica/server/routes"
+	"github.com/CaioBittencourt/arvore-genealogica/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func doLinkRequest(router *gin.Engine, method string, path string, personIDS []string) (*server.PersonResponse, *server.ErrorResponse, int, error) {
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest(method, path, nil)
+	httpReq.Header.Set("If-Match", buildIfMatchHeader(router, personIDS))
+	router.ServeHTTP(w, httpReq)
+
+	if w.Code != 200 {
+		res := server.ErrorResponse{}
+		if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+			return nil, nil, w.Code, err
+		}
+
+		return nil, &res, w.Code, nil
+	}
+
+	res := server.PersonResponse{}
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		return nil, nil, w.Code, err
+	}
+
+	return &res, nil, w.Code, nil
+}
+
+func TestLinkParentsAndChildren(t *testing.T) {
+	personRepository := mongodb.NewPersonRepository(*mongoClient, os.Getenv("MONGO_DATABASE"))
+	personService := service.NewPersonService(personRepository)
+	treeRepository := mongodb.NewTreeRepository(*mongoClient, os.Getenv("MONGO_DATABASE"))
+	treeService := service.NewTreeService(treeRepository)
+	router := routes.SetupRouter(personService, treeService)
+
+	insertedPersonByName := map[string]server.PersonResponse{}
+	for _, req := range []server.StorePersonRequest{
+		{Name: "Luis", Gender: "male"},
+		{Name: "Dayse", Gender: "female"},
+		{Name: "Zeze", Gender: "female"},
+		{Name: "Caio", Gender: "male"},
+		{Name: "Ana", Gender: "female"},
+		{Name: "Bia", Gender: "female"},
+		{Name: "Davi", Gender: "male"},
+	} {
+		if err := storePerson(router, req, insertedPersonByName); err != nil {
+			t.Fatal(err)
+		}
+	}
+	luisID, dayseID, zezeID, caioID := insertedPersonByName["Luis"].ID, insertedPersonByName["Dayse"].ID, insertedPersonByName["Zeze"].ID, insertedPersonByName["Caio"].ID
+	anaID, biaID, daviID := insertedPersonByName["Ana"].ID, insertedPersonByName["Bia"].ID, insertedPersonByName["Davi"].ID
+
+	t.Run("should link a parent to both persons", func(t *testing.T) {
+		res, _, statusCode, err := doLinkRequest(router, "PUT", fmt.Sprintf("/person/%s/parents/%s?role=father", caioID, luisID), []string{caioID, luisID})
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(t, 200, statusCode)
+		assert.Equal(t, []server.PersonRelativesResponse{{ID: luisID, Name: "Luis", Gender: "male", Role: "father", LinkType: "biological"}}, res.Parents)
+
+		luis := server.PersonResponse{}
+		if _, err := doJSONRequest(router, "GET", fmt.Sprintf("/person/%s", luisID), nil, &luis); err != nil {
+			t.Error(err)
+		}
+		assert.Equal(t, []server.PersonRelativesResponse{{ID: caioID, Name: "Caio", Gender: "male"}}, luis.Children)
+	})
+
+	t.Run("should link a child", func(t *testing.T) {
+		res, _, statusCode, err := doLinkRequest(router, "PUT", fmt.Sprintf("/person/%s/children/%s?role=mother", dayseID, caioID), []string{caioID, dayseID})
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(t, 200, statusCode)
+		assert.Equal(t, []server.PersonRelativesResponse{{ID: caioID, Name: "Caio", Gender: "male"}}, res.Children)
+	})
+
+	t.Run("should not link a third parent", func(t *testing.T) {
+		_, errorRes, statusCode, err := doLinkRequest(router, "PUT", fmt.Sprintf("/person/%s/parents/%s", caioID, zezeID), []string{caioID, zezeID})
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(t, 400, statusCode)
+		assert.Equal(t, string(errors.ChildrenAlreadyHasTwoParents), errorRes.ErrorCode)
+	})
+
+	t.Run("should not link a descendant as a parent", func(t *testing.T) {
+		_, errorRes, statusCode, err := doLinkRequest(router, "PUT", fmt.Sprintf("/person/%s/parents/%s", luisID, caioID), []string{luisID, caioID})
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(t, 400, statusCode)
+		assert.Equal(t, string(errors.AncestryCycleErrorCode), errorRes.ErrorCode)
+	})
+
+	t.Run("should link only one of two persons racing to be the parent of each other", func(t *testing.T) {
+		var wg sync.WaitGroup
+		recorders := []*httptest.ResponseRecorder{httptest.NewRecorder(), httptest.NewRecorder()}
+		for i, path := range []string{
+			fmt.Sprintf("/person/%s/parents/%s", biaID, daviID),
+			fmt.Sprintf("/person/%s/parents/%s", daviID, biaID),
+		} {
+			wg.Add(1)
+			go func(w *httptest.ResponseRecorder, path string) {
+				defer wg.Done()
+				httpReq, _ := http.NewRequest("PUT", path, nil)
+				httpReq.Header.Set("If-Match", "*")
+				router.ServeHTTP(w, httpReq)
+			}(recorders[i], path)
+		}
+		wg.Wait()
+
+		assert.ElementsMatch(t, []int{200, 400}, []int{recorders[0].Code, recorders[1].Code})
+		for _, w := range recorders {
+			if w.Code == 400 {
+				errorRes := server.ErrorResponse{}
+				if err := json.Unmarshal(w.Body.Bytes(), &errorRes); err != nil {
+					t.Error(err)
+				}
+				assert.Equal(t, string(errors.AncestryCycleErrorCode), errorRes.ErrorCode)
+			}
+		}
+	})
+
+	t.Run("should unlink a child", func(t *testing.T) {
+		res, _, statusCode, err := doLinkRequest(router, "DELETE", fmt.Sprintf("/person/%s/children/%s", dayseID, caioID), []string{caioID, dayseID})
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(t, 200, statusCode)
+		assert.Empty(t, res.Children)
+	})
+
+	t.Run("should not link a mother that is not female", func(t *testing.T) {
+		_, errorRes, statusCode, err := doLinkRequest(router, "PUT", fmt.Sprintf("/person/%s/parents/%s?role=mother", dayseID, luisID), []string{dayseID, luisID})
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(t, 400, statusCode)
+		assert.Equal(t, string(errors.InvalidParentRoleErrorCode), errorRes.ErrorCode)
+	})
+
+	t.Run("should not link two mothers", func(t *testing.T) {
+		_, _, statusCode, err := doLinkRequest(router, "PUT", fmt.Sprintf("/person/%s/parents/%s?role=mother", luisID, zezeID), []string{luisID, zezeID})
+		if err != nil {
+			t.Error(err)
+		}
+		assert.Equal(t, 200, statusCode)
+
+		_, errorRes, statusCode, err := doLinkRequest(router, "PUT", fmt.Sprintf("/person/%s/parents/%s?role=mother", luisID, dayseID), []string{luisID, dayseID})
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(t, 400, statusCode)
+		assert.Equal(t, string(errors.ParentRoleConflictErrorCode), errorRes.ErrorCode)
+	})
+
+	t.Run("should link two parents with the same gender without the mother role", func(t *testing.T) {
+		res, _, statusCode, err := doLinkRequest(router, "PUT", fmt.Sprintf("/person/%s/parents/%s", luisID, dayseID), []string{luisID, dayseID})
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(t, 200, statusCode)
+		assert.ElementsMatch(t, []server.PersonRelativesResponse{
+			{ID: zezeID, Name: "Zeze", Gender: "female", Role: "mother", LinkType: "biological"},
+			{ID: dayseID, Name: "Dayse", Gender: "female", Role: "parent", LinkType: "biological"},
+		}, res.Parents)
+	})
+
+	t.Run("should link an adoptive mother to a person with two biological parents", func(t *testing.T) {
+		res, _, statusCode, err := doLinkRequest(router, "PUT", fmt.Sprintf("/person/%s/parents/%s?role=mother&linkType=adoptive", luisID, anaID), []string{luisID, anaID})
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(t, 200, statusCode)
+		assert.Contains(t, res.Parents, server.PersonRelativesResponse{ID: anaID, Name: "Ana", Gender: "female", Role: "mother", LinkType: "adoptive"})
+	})
+
+	t.Run("should only follow the link types asked", func(t *testing.T) {
+		baconsNumber := server.GetBaconsNumberBetweenTwoPersonsResponse{}
+		statusCode, err := doJSONRequest(router, "GET", fmt.Sprintf("/person/%s/baconNumber/%s", luisID, anaID), nil, &baconsNumber)
+		if err != nil {
+			t.Error(err)
+		}
+		assert.Equal(t, 200, statusCode)
+		assert.Equal(t, uint(1), baconsNumber.BaconsNumber)
+
+		errorRes := server.ErrorResponse{}
+		statusCode, err = doJSONRequest(router, "GET", fmt.Sprintf("/person/%s/baconNumber/%s?linkTypes=biological,donor", luisID, anaID), nil, &errorRes)
+		if err != nil {
+			t.Error(err)
+		}
+		assert.Equal(t, 404, statusCode)
+		assert.Equal(t, string(errors.PersonNotFoundInGraph), errorRes.ErrorCode)
+
+		statusCode, err = doJSONRequest(router, "GET", fmt.Sprintf("/person/%s/relationship/%s?linkTypes=godparent", luisID, anaID), nil, &errorRes)
+		if err != nil {
+			t.Error(err)
+		}
+		assert.Equal(t, 400, statusCode)
+		assert.Equal(t, string(errors.InvalidParentLinkTypeErrorCode), errorRes.ErrorCode)
+	})
+
+	t.Run("should not unlink a person that is not a parent", func(t *testing.T) {
+		_, errorRes, statusCode, err := doLinkRequest(router, "DELETE", fmt.Sprintf("/person/%s/parents/%s", caioID, zezeID), []string{caioID, zezeID})
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(t, 404, statusCode)
+		assert.Equal(t, string(errors.PersonNotFoundErrorCode), errorRes.ErrorCode)
+	})
+
+	teardownTest()
+}
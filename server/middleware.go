@@ -0,0 +1,76 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	actorHeader        = "X-Actor"
+	changeReasonHeader = "X-Change-Reason"
+)
+
+const allowedOriginsContextKey = "allowedOrigins"
+
+func isAllowedOrigin(allowedOrigins []string, origin string) bool {
+	origin = strings.TrimSuffix(origin, "/")
+	for _, allowedOrigin := range allowedOrigins {
+		allowedOrigin = strings.TrimSpace(allowedOrigin)
+		if allowedOrigin == "*" || strings.EqualFold(strings.TrimSuffix(allowedOrigin, "/"), origin) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CORSMiddleware lets browser pages served from allowedOrigins call the api, "*" allows any origin. Pages served
+// by the api itself never need it. WebSocket handshakes check their Origin header against the same allowedOrigins.
+func CORSMiddleware(allowedOrigins []string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Set(allowedOriginsContextKey, allowedOrigins)
+
+		origin := ctx.GetHeader("Origin")
+		if origin == "" {
+			ctx.Next()
+			return
+		}
+
+		ctx.Writer.Header().Add("Vary", "Origin")
+		if !isAllowedOrigin(allowedOrigins, origin) {
+			ctx.Next()
+			return
+		}
+
+		ctx.Header("Access-Control-Allow-Origin", origin)
+		ctx.Header("Access-Control-Expose-Headers", "ETag")
+		if ctx.Request.Method == http.MethodOptions && ctx.GetHeader("Access-Control-Request-Method") != "" {
+			ctx.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE")
+			ctx.Header("Access-Control-Allow-Headers", strings.Join([]string{"Content-Type", "If-Match", actorHeader, changeReasonHeader}, ", "))
+			ctx.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// ChangeMetadataMiddleware puts the actor and reason sent by the client on the request context so mutations can be
+// recorded on the person history, with the address of the connection as their source. RemoteIP is used instead of
+// ClientIP because forwarding headers are as easy to forge as X-Actor. The router must be built with
+// ContextWithFallback for handlers to see it.
+func ChangeMetadataMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		changeMetadata := domain.ChangeMetadata{
+			ClaimedActor: ctx.GetHeader(actorHeader),
+			Source:       ctx.RemoteIP(),
+			Reason:       ctx.GetHeader(changeReasonHeader),
+		}
+
+		ctx.Request = ctx.Request.WithContext(domain.ContextWithChangeMetadata(ctx.Request.Context(), changeMetadata))
+		ctx.Next()
+	}
+}
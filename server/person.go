@@ -1,36 +1,58 @@
 package server
 
 import (
+	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/CaioBittencourt/arvore-genealogica/domain"
+	"github.com/CaioBittencourt/arvore-genealogica/errors"
 	"github.com/CaioBittencourt/arvore-genealogica/service"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	log "github.com/sirupsen/logrus"
 )
 
+type LifeEvent struct {
+	Date  string `json:"date"`
+	Place string `json:"place"`
+}
+
 // make fields required
+// ParentIDs are parents without the mother or father role, which allows two parents of the same gender
+// ParentLinkTypes holds the link type of the parents by their id, parents without one are biological
 type StorePersonRequest struct {
-	Name        string   `json:"name"`
-	Gender      string   `json:"gender"`
-	MotherID    *string  `json:"motherId"`
-	FatherID    *string  `json:"fatherId"`
-	ChildrenIDs []string `json:"childrenIds"`
+	Name            string            `json:"name"`
+	Gender          string            `json:"gender"`
+	Birth           *LifeEvent        `json:"birth,omitempty"`
+	Death           *LifeEvent        `json:"death,omitempty"`
+	MotherID        *string           `json:"motherId"`
+	FatherID        *string           `json:"fatherId"`
+	ParentIDs       []string          `json:"parentIds"`
+	ParentLinkTypes map[string]string `json:"parentLinkTypes,omitempty"`
+	ChildrenIDs     []string          `json:"childrenIds"`
 }
 
 type GetBaconsNumberBetweenTwoPersonsResponse struct {
 	BaconsNumber uint `json:"baconsNumber"`
 }
 
+// Role and LinkType are only set for parents: mother, father or parent and biological, adoptive, foster, step, donor
+// or surrogate
 type PersonRelativesResponse struct {
-	ID     string `json:"id"`
-	Name   string `json:"name"`
-	Gender string `json:"gender"`
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Gender   string `json:"gender"`
+	Role     string `json:"role,omitempty"`
+	LinkType string `json:"linkType,omitempty"`
 }
 type PersonResponse struct {
 	ID       string                    `json:"id"`
 	Name     string                    `json:"name"`
 	Gender   string                    `json:"gender"`
+	Birth    *LifeEvent                `json:"birth,omitempty"`
+	Death    *LifeEvent                `json:"death,omitempty"`
+	Version  int64                     `json:"version"`
 	Parents  []PersonRelativesResponse `json:"parents"`
 	Children []PersonRelativesResponse `json:"children"`
 }
@@ -41,9 +63,13 @@ type RelationshipPerson struct {
 	Gender string `json:"gender"`
 }
 
+// Role is set when the related person is the mother or the father and LinkType when it is a parent that is not
+// biological
 type Relationship struct {
 	Person       RelationshipPerson `json:"person"`
 	Relationship string             `json:"relationship"`
+	Role         string             `json:"role,omitempty"`
+	LinkType     string             `json:"linkType,omitempty"`
 }
 
 type PersonWithRelationship struct {
@@ -74,6 +100,8 @@ func buildPersonsWithRelationshipFromPerson(person domain.Person) PersonWithRela
 			personWithRelationship.Relationships,
 			Relationship{
 				Relationship: string(relationship.Relationship),
+				Role:         string(relationship.ParentRole),
+				LinkType:     string(relationship.LinkType),
 				Person: RelationshipPerson{
 					Name:   relationship.Person.Name,
 					ID:     relationship.Person.ID,
@@ -96,38 +124,212 @@ func buildPersonsWithRelationshipFromFamilyGraph(familyGraph domain.FamilyGraph)
 }
 
 // @Summary      Get family tree with relationships for person
-// @Description  Get family tree with relationships for person
+// @Description  Get family tree with relationships for person. The Accept header, or the format query parameter, selects the format: JSON by default, application/x-gedcom (gedcom) for GEDCOM 5.5.1, text/vnd.familysearch.gedcom (gedcom7) for GEDCOM 7, application/x-gedcomx-v1+json (gedcomx) for GEDCOM X, text/vnd.graphviz (dot) for a Graphviz DOT graph, text/vnd.mermaid (mermaid) for a Mermaid flowchart, text/vnd.plantuml (plantuml) for a PlantUML diagram, text/csv (csv) for a CSV file, application/ld+json (jsonld) for schema.org persons whose ids are their /person/:id address or application/x-ndjson (ndjson) to write one member per line. Every format, ndjson and the pages included, resolves the whole tree before writing the response
 // @Accept       json
 // @Produce      json
+// @Produce      application/x-gedcom
+// @Produce      text/vnd.familysearch.gedcom
+// @Produce      application/x-gedcomx-v1+json
+// @Produce      text/vnd.graphviz
+// @Produce      text/vnd.mermaid
+// @Produce      text/vnd.plantuml
+// @Produce      text/csv
+// @Produce      application/ld+json
+// @Produce      application/x-ndjson
 // @Param        id   path       string  true  "Person ID"
+// @Param        format query    string  false "json, ndjson, gedcom, gedcom7, gedcomx, dot, mermaid, plantuml, csv or jsonld, overrides the Accept header"
+// @Param        labels query    boolean false "add the relationship with the person to each member of the dot graph"
+// @Param        asOf query      string  false "RFC3339 timestamp to rebuild the tree from the change history as it was then, with the same scope"
+// @Param        ancestors query      integer false "generations of ancestors, all of them by default"
+// @Param        descendants query    integer false "generations of descendants, defaults to 1"
+// @Param        collaterals query    integer false "generations of ancestors whose children and grandchildren are included, 1 for siblings and nephews, 2 for uncles and cousins too, defaults to 2"
+// @Param        spouses query        boolean false "include the other parents of the children of the person, defaults to true"
+// @Param        limit query     integer false "members per page, at most 1000, from the oldest generation to the youngest, then by name. The response becomes a PersonTreePageResponse. Generations are relative to the person, so the whole tree is still resolved for each page"
+// @Param        cursor query    string  false "nextCursor of the previous page"
 // @Success      200  {object}   PersonTreeResponse
 // @Failure      400  {object}   ErrorResponse
 // @Failure      404  {object}   ErrorResponse
+// @Failure      406  {object}   ErrorResponse
 // @Failure      500  {object}   ErrorResponse
 // @Router       /person/:id/tree [get]
 func GetPersonFamilyRelationships(personService service.PersonService) gin.HandlerFunc {
 	return gin.HandlerFunc(func(ctx *gin.Context) {
 		personID := ctx.Param("id")
 
-		familyGraph, err := personService.GetFamilyGraphByPersonID(ctx, personID)
+		mediaType, err := negotiateFamilyGraphMediaType(ctx)
+		if err != nil {
+			createServerResponseFromError(ctx, err)
+			return
+		}
+
+		if mediaType == "" {
+			ctx.JSON(http.StatusNotAcceptable, ErrorResponse{ErrorMessage: "Not Acceptable"})
+			return
+		}
+
+		asOf, err := parseAsOfQueryParameter(ctx, "asOf")
+		if err != nil {
+			createServerResponseFromError(ctx, err)
+			return
+		}
+
+		scope, err := parseFamilyGraphScope(ctx)
+		if err != nil {
+			createServerResponseFromError(ctx, err)
+			return
+		}
+
+		pageRequest, err := parseMembersPageRequest(ctx)
 		if err != nil {
 			createServerResponseFromError(ctx, err)
 			return
 		}
 
-		ctx.JSON(http.StatusOK, PersonTreeResponse{Members: buildPersonsWithRelationshipFromFamilyGraph(*familyGraph)})
+		if pageRequest != nil && mediaType != binding.MIMEJSON && mediaType != ndjsonMediaType {
+			createServerResponseFromError(ctx, errors.NewApplicationError("only json and ndjson trees can be paginated", errors.InvalidQueryParameterErrorCode))
+			return
+		}
+
+		var familyGraph *domain.FamilyGraph
+		if asOf != nil {
+			familyGraph, err = personService.GetFamilyGraphByPersonIDAsOf(ctx, personID, *asOf, scope)
+		} else {
+			familyGraph, err = personService.GetFamilyGraphByPersonID(ctx, personID, scope)
+		}
+		if err != nil {
+			createServerResponseFromError(ctx, err)
+			return
+		}
+
+		switch {
+		case mediaType == ndjsonMediaType:
+			writeFamilyGraphMembersAsNDJSON(ctx, *familyGraph, pageRequest)
+		case mediaType != binding.MIMEJSON:
+			writeEncodedFamilyGraph(ctx, mediaType, *familyGraph, personID)
+		case pageRequest != nil:
+			writeFamilyGraphMembersPage(ctx, *familyGraph, *pageRequest)
+		default:
+			ctx.JSON(http.StatusOK, PersonTreeResponse{Members: buildPersonsWithRelationshipFromFamilyGraph(*familyGraph)})
+		}
 	})
 }
 
+// parsePositiveUintQueryParameter returns 0 when name is not set.
+func parsePositiveUintQueryParameter(ctx *gin.Context, name string) (uint, error) {
+	value := ctx.Query(name)
+	if value == "" {
+		return 0, nil
+	}
+
+	number, err := strconv.ParseUint(value, 10, 32)
+	if err != nil || number == 0 {
+		return 0, errors.NewApplicationError(fmt.Sprintf("%s must be a positive integer", name), errors.InvalidQueryParameterErrorCode)
+	}
+
+	return uint(number), nil
+}
+
+// parseUintQueryParameter returns nil when name is not set.
+func parseUintQueryParameter(ctx *gin.Context, name string) (*uint, error) {
+	value := ctx.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	number, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return nil, errors.NewApplicationError(fmt.Sprintf("%s must be a non negative integer", name), errors.InvalidQueryParameterErrorCode)
+	}
+
+	generations := uint(number)
+	return &generations, nil
+}
+
+// parseFamilyGraphScope starts from the default scope and overrides the generations and spouses asked for.
+func parseFamilyGraphScope(ctx *gin.Context) (domain.FamilyGraphScope, error) {
+	scope := domain.DefaultFamilyGraphScope()
+
+	var err error
+	if scope.AncestorGenerations, err = parseUintQueryParameter(ctx, "ancestors"); err != nil {
+		return scope, err
+	}
+
+	for _, parameter := range []struct {
+		name        string
+		generations *uint
+	}{
+		{name: "descendants", generations: &scope.DescendantGenerations},
+		{name: "collaterals", generations: &scope.CollateralGenerations},
+	} {
+		generations, err := parseUintQueryParameter(ctx, parameter.name)
+		if err != nil {
+			return scope, err
+		}
+
+		if generations != nil {
+			*parameter.generations = *generations
+		}
+	}
+
+	if value := ctx.Query("spouses"); value != "" {
+		includeSpouses, err := strconv.ParseBool(value)
+		if err != nil {
+			return scope, errors.NewApplicationError("spouses must be true or false", errors.InvalidQueryParameterErrorCode)
+		}
+		scope.ExcludeSpouses = !includeSpouses
+	}
+
+	return scope, nil
+}
+
+func parseBaconsNumberOptions(ctx *gin.Context) (domain.BaconsNumberOptions, error) {
+	options := domain.BaconsNumberOptions{Direction: domain.PathDirection(ctx.Query("direction"))}
+
+	var err error
+	if options.LinkTypes, err = parseLinkTypesQueryParameter(ctx); err != nil {
+		return options, err
+	}
+
+	if value := ctx.Query("excludeSpouses"); value != "" {
+		if options.ExcludeSpouses, err = strconv.ParseBool(value); err != nil {
+			return options, errors.NewApplicationError("excludeSpouses must be true or false", errors.InvalidQueryParameterErrorCode)
+		}
+	}
+
+	for _, parameter := range []struct {
+		name  string
+		value *uint
+	}{
+		{name: "spouseWeight", value: &options.SpouseWeight},
+		{name: "parentWeight", value: &options.ParentWeight},
+		{name: "childWeight", value: &options.ChildWeight},
+		{name: "maxDepth", value: &options.MaxDepth},
+	} {
+		if *parameter.value, err = parsePositiveUintQueryParameter(ctx, parameter.name); err != nil {
+			return options, err
+		}
+	}
+
+	return options, nil
+}
+
 // @Summary      Get bacons number between two persons
-// @Description  Get bacons number between two persons
+// @Description  Get the weight of the lightest path between two persons, by default the minimum number of jumps through spouses, parents and children
 // @Accept       json
 // @Produce      json
 // @Param        id   path       string  true  "Person ID"
 // @Param        id2   path      string  true  "Person 2 ID"
+// @Param        linkTypes       query  string  false  "Comma separated link types to follow, like biological,donor"
+// @Param        excludeSpouses  query  bool    false  "Do not jump through spouses, for blood only distances"
+// @Param        direction       query  string  false  "Only jump to parents or only jump to children"  Enums(ancestors, descendants)
+// @Param        spouseWeight    query  int     false  "Weight of jumping to a spouse"  default(1)
+// @Param        parentWeight    query  int     false  "Weight of jumping to a parent"  default(1)
+// @Param        childWeight     query  int     false  "Weight of jumping to a child"  default(1)
+// @Param        maxDepth        query  int     false  "Maximum number of jumps"
 // @Success      200  {object}   GetBaconsNumberBetweenTwoPersonsResponse
 // @Failure      400  {object}   ErrorResponse
 // @Failure      404  {object}   ErrorResponse
+// @Failure      422  {object}   ErrorResponse
 // @Failure      500  {object}   ErrorResponse
 // @Router       /person/:id/baconNumber/:id2 [get]
 func GetBaconsNumberBetweenTwoPersons(personService service.PersonService) gin.HandlerFunc {
@@ -135,7 +337,13 @@ func GetBaconsNumberBetweenTwoPersons(personService service.PersonService) gin.H
 		personAID := ctx.Param("id")
 		personBID := ctx.Param("id2")
 
-		baconsNumber, err := personService.BaconsNumber(ctx, personAID, personBID)
+		options, err := parseBaconsNumberOptions(ctx)
+		if err != nil {
+			createServerResponseFromError(ctx, err)
+			return
+		}
+
+		baconsNumber, err := personService.BaconsNumber(ctx, personAID, personBID, options)
 		if err != nil {
 			createServerResponseFromError(ctx, err)
 			return
@@ -146,11 +354,12 @@ func GetBaconsNumberBetweenTwoPersons(personService service.PersonService) gin.H
 }
 
 // @Summary      Get relationship between two persons
-// @Description  Get relationship between two persons
+// @Description  Get relationship between two persons, only following the parent links of linkTypes when it is set
 // @Accept       json
 // @Produce      json
 // @Param        id   path       string  true  "Person ID"
 // @Param        id2   path      string  true  "Person 2 ID"
+// @Param        linkTypes  query  string  false  "Comma separated link types to follow, like biological,donor"
 // @Success      200  {object}   PersonWithRelationship
 // @Failure      400  {object}   ErrorResponse
 // @Failure      404  {object}   ErrorResponse
@@ -161,7 +370,13 @@ func GetRelationshipBetweenPersons(personService service.PersonService) gin.Hand
 		personAID := ctx.Param("id")
 		personBID := ctx.Param("id2")
 
-		personWithRelationship, err := personService.GetRelationshipBetweenPersons(ctx, personAID, personBID)
+		linkTypes, err := parseLinkTypesQueryParameter(ctx)
+		if err != nil {
+			createServerResponseFromError(ctx, err)
+			return
+		}
+
+		personWithRelationship, err := personService.GetRelationshipBetweenPersons(ctx, personAID, personBID, linkTypes)
 		if err != nil {
 			createServerResponseFromError(ctx, err)
 			return
@@ -171,17 +386,45 @@ func GetRelationshipBetweenPersons(personService service.PersonService) gin.Hand
 	})
 }
 
+func buildDomainLifeEventFromLifeEvent(lifeEvent *LifeEvent) *domain.LifeEvent {
+	if lifeEvent == nil {
+		return nil
+	}
+
+	return &domain.LifeEvent{Date: lifeEvent.Date, Place: lifeEvent.Place}
+}
+
+func buildLifeEventFromDomainLifeEvent(lifeEvent *domain.LifeEvent) *LifeEvent {
+	if lifeEvent == nil {
+		return nil
+	}
+
+	return &LifeEvent{Date: lifeEvent.Date, Place: lifeEvent.Place}
+}
+
 func buildPersonFromStorePersonRequest(personReq StorePersonRequest) domain.Person {
-	person := domain.Person{Name: personReq.Name}
+	person := domain.Person{
+		Name:  personReq.Name,
+		Birth: buildDomainLifeEventFromLifeEvent(personReq.Birth),
+		Death: buildDomainLifeEventFromLifeEvent(personReq.Death),
+	}
 
 	person.Gender = domain.GenderType(personReq.Gender)
 
 	if personReq.FatherID != nil {
-		person.Parents = append(person.Parents, &domain.Person{ID: *personReq.FatherID})
+		person.AddParent(&domain.Person{ID: *personReq.FatherID}, domain.FatherParentRole)
 	}
 
 	if personReq.MotherID != nil {
-		person.Parents = append(person.Parents, &domain.Person{ID: *personReq.MotherID})
+		person.AddParent(&domain.Person{ID: *personReq.MotherID}, domain.MotherParentRole)
+	}
+
+	for _, parentID := range personReq.ParentIDs {
+		person.AddParent(&domain.Person{ID: parentID}, domain.GenericParentRole)
+	}
+
+	for parentID, linkType := range personReq.ParentLinkTypes {
+		person.SetParentLinkType(parentID, domain.LinkType(linkType))
 	}
 
 	if len(personReq.ChildrenIDs) > 0 {
@@ -206,6 +449,9 @@ func buildPersonResponseFromDomainPerson(domainPerson domain.Person) PersonRespo
 		ID:       domainPerson.ID,
 		Name:     domainPerson.Name,
 		Gender:   string(domainPerson.Gender),
+		Birth:    buildLifeEventFromDomainLifeEvent(domainPerson.Birth),
+		Death:    buildLifeEventFromDomainLifeEvent(domainPerson.Death),
+		Version:  domainPerson.Version,
 		Children: []PersonRelativesResponse{},
 		Parents:  []PersonRelativesResponse{},
 	}
@@ -225,19 +471,50 @@ func buildPersonResponseFromDomainPerson(domainPerson domain.Person) PersonRespo
 		}
 
 		domainParent := buildPersonRelativesResponseFromDomainPerson(*parent)
+		domainParent.Role = string(domainPerson.ParentRole(parent.ID))
+		domainParent.LinkType = string(domainPerson.ParentLinkType(parent.ID))
 		personResponse.Parents = append(personResponse.Parents, domainParent)
 	}
 
 	return personResponse
 }
 
+// @Summary      Get person
+// @Description  Get person with its parents and children. The ETag header holds the version to send on If-Match when linking to this person
+// @Accept       json
+// @Produce      json
+// @Param        id   path       string  true  "Person ID"
+// @Success      200  {object}   PersonResponse
+// @Failure      400  {object}   ErrorResponse
+// @Failure      404  {object}   ErrorResponse
+// @Failure      500  {object}   ErrorResponse
+// @Router       /person/:id [get]
+func GetPerson(personService service.PersonService) gin.HandlerFunc {
+	return gin.HandlerFunc(func(ctx *gin.Context) {
+		personID := ctx.Param("id")
+
+		person, err := personService.GetPersonByID(ctx, personID)
+		if err != nil {
+			createServerResponseFromError(ctx, err)
+			return
+		}
+
+		setPersonETag(ctx, *person)
+		ctx.JSON(http.StatusOK, buildPersonResponseFromDomainPerson(*person))
+	})
+}
+
 // @Summary      Store person
-// @Description  Store person
+// @Description  Store person. If-Match must hold the ETag of every parent and child referenced, or "*"
 // @Accept       json
 // @Produce      json
 // @Param        person   body       StorePersonRequest  true  "Person to store"
+// @Param        If-Match header     string  false  "ETags of the referenced parents and children"
 // @Success      200  {object}   PersonResponse
 // @Failure      400  {object}   ErrorResponse
+// @Failure      404  {object}   ErrorResponse
+// @Failure      412  {object}   ErrorResponse
+// @Failure      428  {object}   ErrorResponse
 // @Failure      500  {object}   ErrorResponse
 // @Router       /person [post]
 func Store(personService service.PersonService) gin.HandlerFunc {
@@ -253,12 +530,18 @@ func Store(personService service.PersonService) gin.HandlerFunc {
 		}
 
 		personToStore := buildPersonFromStorePersonRequest(req)
+		if err := applyIfMatchToPersons(ctx, []*domain.Person{&personToStore}, func(string) bool { return true }); err != nil {
+			createServerResponseFromError(ctx, err)
+			return
+		}
+
 		person, err := personService.Store(ctx, personToStore)
 		if err != nil {
 			createServerResponseFromError(ctx, err)
 			return
 		}
 
+		setPersonETag(ctx, *person)
 		ctx.JSON(http.StatusOK, buildPersonResponseFromDomainPerson(*person))
 	})
 }
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/CaioBittencourt/arvore-genealogica/domain"
@@ -34,6 +35,8 @@ func TestMain(m *testing.M) {
 
 func teardownTest() {
 	mongoClient.Database(os.Getenv("MONGO_DATABASE")).Collection("person").Drop(context.Background())
+	mongoClient.Database(os.Getenv("MONGO_DATABASE")).Collection("person_history").Drop(context.Background())
+	mongoClient.Database(os.Getenv("MONGO_DATABASE")).Collection("tree").Drop(context.Background())
 }
 
 func addPersonIDToExpectedResponse(expected *server.PersonResponse, personInsertedIdByName map[string]string) {
@@ -48,6 +51,22 @@ func addPersonIDToExpectedResponse(expected *server.PersonResponse, personInsert
 	}
 }
 
+// buildIfMatchHeader reads the current ETag of every referenced person, like a client would before linking to them
+func buildIfMatchHeader(router *gin.Engine, personIDS []string) string {
+	var etags []string
+	for _, personID := range personIDS {
+		w := httptest.NewRecorder()
+		httpReq, _ := http.NewRequest("GET", fmt.Sprintf("/person/%s", personID), nil)
+		router.ServeHTTP(w, httpReq)
+
+		if etag := w.Header().Get("ETag"); etag != "" {
+			etags = append(etags, etag)
+		}
+	}
+
+	return strings.Join(etags, ", ")
+}
+
 func doStorePersonRequest(router *gin.Engine, req server.StorePersonRequest) (*server.PersonResponse, *server.ErrorResponse, int, error) {
 	var buf bytes.Buffer
 	err := json.NewEncoder(&buf).Encode(req)
@@ -55,8 +74,17 @@ func doStorePersonRequest(router *gin.Engine, req server.StorePersonRequest) (*s
 		return nil, nil, 0, err
 	}
 
+	referencedPersonIDS := append(append([]string{}, req.ChildrenIDs...), req.ParentIDs...)
+	if req.FatherID != nil {
+		referencedPersonIDS = append(referencedPersonIDS, *req.FatherID)
+	}
+	if req.MotherID != nil {
+		referencedPersonIDS = append(referencedPersonIDS, *req.MotherID)
+	}
+
 	w := httptest.NewRecorder()
 	httpReq, _ := http.NewRequest("POST", "/person", &buf)
+	httpReq.Header.Set("If-Match", buildIfMatchHeader(router, referencedPersonIDS))
 	router.ServeHTTP(w, httpReq)
 
 	if w.Code > 200 {
@@ -79,11 +107,11 @@ func doStorePersonRequest(router *gin.Engine, req server.StorePersonRequest) (*s
 }
 
 // refactor this method to be generic
-func doGetPersonFamilyRelationshipsRequest(router *gin.Engine, personID string) (*server.PersonTreeResponse, *server.ErrorResponse, int, error) {
+func doGetPersonFamilyRelationshipsRequest(router *gin.Engine, personID string, query string) (*server.PersonTreeResponse, *server.ErrorResponse, int, error) {
 	var buf bytes.Buffer
 
 	w := httptest.NewRecorder()
-	httpReq, _ := http.NewRequest("GET", fmt.Sprintf("/person/%s/tree", personID), &buf)
+	httpReq, _ := http.NewRequest("GET", fmt.Sprintf("/person/%s/tree%s", personID, query), &buf)
 	router.ServeHTTP(w, httpReq)
 
 	if w.Code > 200 {
@@ -105,11 +133,11 @@ func doGetPersonFamilyRelationshipsRequest(router *gin.Engine, personID string)
 	return &res, nil, w.Code, nil
 }
 
-func doGetBaconsNumberBetweenTwoPersonsRequest(router *gin.Engine, personAID string, personBID string) (*server.GetBaconsNumberBetweenTwoPersonsResponse, *server.ErrorResponse, int, error) {
+func doGetBaconsNumberBetweenTwoPersonsRequest(router *gin.Engine, personAID string, personBID string, query string) (*server.GetBaconsNumberBetweenTwoPersonsResponse, *server.ErrorResponse, int, error) {
 	var buf bytes.Buffer
 
 	w := httptest.NewRecorder()
-	httpReq, _ := http.NewRequest("GET", fmt.Sprintf("/person/%s/baconNumber/%s", personAID, personBID), &buf)
+	httpReq, _ := http.NewRequest("GET", fmt.Sprintf("/person/%s/baconNumber/%s%s", personAID, personBID, query), &buf)
 	router.ServeHTTP(w, httpReq)
 
 	if w.Code > 200 {
@@ -161,7 +189,9 @@ func TestStore(t *testing.T) {
 	//NOTE: Leaving this settup per test in case any tests want to introduce a mock for services or repository.
 	personRepository := mongodb.NewPersonRepository(*mongoClient, os.Getenv("MONGO_DATABASE"))
 	personService := service.NewPersonService(personRepository)
-	router := routes.SetupRouter(personService)
+	treeRepository := mongodb.NewTreeRepository(*mongoClient, os.Getenv("MONGO_DATABASE"))
+	treeService := service.NewTreeService(treeRepository)
+	router := routes.SetupRouter(personService, treeService)
 
 	// map to get ids to build relationships once that person is inserted
 	personInsertedIdByName := map[string]string{}
@@ -235,7 +265,7 @@ func TestStore(t *testing.T) {
 			expectedResponse: &server.PersonResponse{
 				Name:     dayse.Name,
 				Gender:   dayse.Gender,
-				Parents:  []server.PersonRelativesResponse{{Name: alfredo.Name, Gender: alfredo.Gender}},
+				Parents:  []server.PersonRelativesResponse{{Name: alfredo.Name, Gender: alfredo.Gender, Role: "father", LinkType: "biological"}},
 				Children: []server.PersonRelativesResponse{},
 			},
 		},
@@ -260,7 +290,7 @@ func TestStore(t *testing.T) {
 			expectedResponse: &server.PersonResponse{
 				Name:     denise.Name,
 				Gender:   denise.Gender,
-				Parents:  []server.PersonRelativesResponse{{Name: helena.Name, Gender: helena.Gender}, {Name: alfredo.Name, Gender: alfredo.Gender}},
+				Parents:  []server.PersonRelativesResponse{{Name: helena.Name, Gender: helena.Gender, Role: "mother", LinkType: "biological"}, {Name: alfredo.Name, Gender: alfredo.Gender, Role: "father", LinkType: "biological"}},
 				Children: []server.PersonRelativesResponse{},
 			},
 		},
@@ -274,6 +304,16 @@ func TestStore(t *testing.T) {
 			expectedStatusCode:    400,
 			expectedErrorResponse: &server.ErrorResponse{ErrorMessage: "children already have two parents", ErrorCode: string(errors.ChildrenAlreadyHasTwoParents)},
 		},
+		{
+			testName:   "should return bad request when the mother is not female",
+			motherName: alfredo.Name,
+			personToStore: server.StorePersonRequest{
+				Name:   "Caio",
+				Gender: "male",
+			},
+			expectedStatusCode:    400,
+			expectedErrorResponse: &server.ErrorResponse{ErrorMessage: "mother must be female", ErrorCode: string(errors.InvalidParentRoleErrorCode)},
+		},
 	}
 
 	for _, tt := range tests {
@@ -385,7 +425,9 @@ func TestGetPersonFamilyGraphHandler(t *testing.T) {
 	//NOTE: Leaving this settup per test in case any tests want to introduce a mock for services or repository.
 	personRepository := mongodb.NewPersonRepository(*mongoClient, os.Getenv("MONGO_DATABASE"))
 	personService := service.NewPersonService(personRepository)
-	router := routes.SetupRouter(personService)
+	treeRepository := mongodb.NewTreeRepository(*mongoClient, os.Getenv("MONGO_DATABASE"))
+	treeService := service.NewTreeService(treeRepository)
+	router := routes.SetupRouter(personService, treeService)
 
 	type testArgs struct {
 		testName              string
@@ -690,7 +732,7 @@ func TestGetPersonFamilyGraphHandler(t *testing.T) {
 					t.Errorf("failed to build family tree: %s", err.Error())
 				}
 
-				successRes, errorRes, statusCode, err := doGetPersonFamilyRelationshipsRequest(router, insertedPersonByName[tt.personToSearchName].ID)
+				successRes, errorRes, statusCode, err := doGetPersonFamilyRelationshipsRequest(router, insertedPersonByName[tt.personToSearchName].ID, "")
 				if err != nil {
 					t.Error(err)
 				}
@@ -719,17 +761,211 @@ func TestGetPersonFamilyGraphHandler(t *testing.T) {
 	}
 }
 
+func TestGetPersonFamilyGraphScopeHandler(t *testing.T) {
+	personRepository := mongodb.NewPersonRepository(*mongoClient, os.Getenv("MONGO_DATABASE"))
+	personService := service.NewPersonService(personRepository)
+	treeRepository := mongodb.NewTreeRepository(*mongoClient, os.Getenv("MONGO_DATABASE"))
+	treeService := service.NewTreeService(treeRepository)
+	router := routes.SetupRouter(personService, treeService)
+
+	type testArgs struct {
+		testName              string
+		personToSearchName    string
+		query                 string
+		expectedStatusCode    int
+		expectedMemberNames   []string
+		expectedErrorResponse *server.ErrorResponse
+	}
+
+	tests := []testArgs{
+		{
+			testName:            "should return every ancestor, the children, collaterals and spouses by default",
+			personToSearchName:  "Vivian",
+			expectedStatusCode:  200,
+			expectedMemberNames: []string{"Vivian", "Luis", "Dayse", "Tunico", "Caio", "Claudia", "Livia", "Cauã", "Caio Regis"},
+		},
+		{
+			testName:            "should only return the parents and siblings",
+			personToSearchName:  "Vivian",
+			query:               "?ancestors=1&descendants=0&collaterals=1&spouses=false",
+			expectedStatusCode:  200,
+			expectedMemberNames: []string{"Vivian", "Luis", "Dayse", "Caio"},
+		},
+		{
+			testName:            "should return the children without the spouses",
+			personToSearchName:  "Vivian",
+			query:               "?ancestors=0&spouses=false",
+			expectedStatusCode:  200,
+			expectedMemberNames: []string{"Vivian", "Cauã"},
+		},
+		{
+			testName:            "should return the grandchildren",
+			personToSearchName:  "Tunico",
+			query:               "?descendants=3",
+			expectedStatusCode:  200,
+			expectedMemberNames: []string{"Tunico", "Luis", "Claudia", "Caio", "Vivian", "Livia", "Cauã"},
+		},
+		{
+			testName:              "should return 400 bad request when generations are not a number",
+			personToSearchName:    "Vivian",
+			query:                 "?ancestors=all",
+			expectedStatusCode:    400,
+			expectedErrorResponse: &server.ErrorResponse{ErrorMessage: "ancestors must be a non negative integer", ErrorCode: "INVALID_QUERY_PARAMETER"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(tt testArgs) func(t *testing.T) {
+			return func(t *testing.T) {
+				insertedPersonByName := map[string]server.PersonResponse{}
+				if err := buildFamily(router, insertedPersonByName); err != nil {
+					t.Errorf("failed to build family tree: %s", err.Error())
+				}
+
+				successRes, errorRes, statusCode, err := doGetPersonFamilyRelationshipsRequest(router, insertedPersonByName[tt.personToSearchName].ID, tt.query)
+				if err != nil {
+					t.Error(err)
+				}
+
+				assert.Equal(t, tt.expectedStatusCode, statusCode)
+
+				if tt.expectedErrorResponse != nil {
+					assert.Equal(t, tt.expectedErrorResponse, errorRes)
+				} else {
+					var memberNames []string
+					for _, member := range successRes.Members {
+						memberNames = append(memberNames, member.Name)
+					}
+					assert.ElementsMatch(t, tt.expectedMemberNames, memberNames)
+				}
+
+				teardownTest()
+			}
+		}(tt))
+	}
+}
+
+func doGetPersonFamilyTreeRequest(router *gin.Engine, personID string, query string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("GET", fmt.Sprintf("/person/%s/tree%s", personID, query), nil)
+	router.ServeHTTP(w, httpReq)
+
+	return w
+}
+
+func TestGetPersonFamilyGraphPagesHandler(t *testing.T) {
+	personRepository := mongodb.NewPersonRepository(*mongoClient, os.Getenv("MONGO_DATABASE"))
+	personService := service.NewPersonService(personRepository)
+	treeRepository := mongodb.NewTreeRepository(*mongoClient, os.Getenv("MONGO_DATABASE"))
+	treeService := service.NewTreeService(treeRepository)
+	router := routes.SetupRouter(personService, treeService)
+
+	expectedMemberNames := []string{"Tunico", "Claudia", "Dayse", "Luis", "Caio", "Caio Regis", "Livia", "Vivian", "Cauã"}
+
+	t.Run("should page through the members from the oldest generation to the youngest, then by name", func(t *testing.T) {
+		defer teardownTest()
+
+		insertedPersonByName := map[string]server.PersonResponse{}
+		if err := buildFamily(router, insertedPersonByName); err != nil {
+			t.Fatalf("failed to build family tree: %s", err.Error())
+		}
+
+		var memberNames []string
+		query := "?limit=4"
+		for pages := 0; pages < len(expectedMemberNames); pages++ {
+			w := doGetPersonFamilyTreeRequest(router, insertedPersonByName["Vivian"].ID, query)
+			assert.Equal(t, 200, w.Code)
+
+			var page server.PersonTreePageResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+				t.Fatal(err)
+			}
+
+			assert.LessOrEqual(t, len(page.Members), 4)
+			for _, member := range page.Members {
+				memberNames = append(memberNames, member.Name)
+			}
+
+			if page.NextCursor == "" {
+				break
+			}
+			query = "?limit=4&cursor=" + page.NextCursor
+		}
+
+		assert.Equal(t, expectedMemberNames, memberNames)
+	})
+
+	t.Run("should stream one member per line as ndjson", func(t *testing.T) {
+		defer teardownTest()
+
+		insertedPersonByName := map[string]server.PersonResponse{}
+		if err := buildFamily(router, insertedPersonByName); err != nil {
+			t.Fatalf("failed to build family tree: %s", err.Error())
+		}
+
+		w := doGetPersonFamilyTreeRequest(router, insertedPersonByName["Vivian"].ID, "?format=ndjson")
+		assert.Equal(t, 200, w.Code)
+		assert.Equal(t, "application/x-ndjson; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Empty(t, w.Header().Get("X-Next-Cursor"))
+
+		var memberNames []string
+		decoder := json.NewDecoder(w.Body)
+		for decoder.More() {
+			var member server.PersonWithRelationship
+			if err := decoder.Decode(&member); err != nil {
+				t.Fatal(err)
+			}
+			memberNames = append(memberNames, member.Name)
+		}
+		assert.Equal(t, expectedMemberNames, memberNames)
+
+		w = doGetPersonFamilyTreeRequest(router, insertedPersonByName["Vivian"].ID, "?format=ndjson&limit=2")
+		assert.Equal(t, 200, w.Code)
+		assert.NotEmpty(t, w.Header().Get("X-Next-Cursor"))
+		assert.Equal(t, 2, strings.Count(w.Body.String(), "\n"))
+	})
+
+	t.Run("should return 400 bad request when the cursor is not valid", func(t *testing.T) {
+		defer teardownTest()
+
+		insertedPersonByName := map[string]server.PersonResponse{}
+		if err := buildFamily(router, insertedPersonByName); err != nil {
+			t.Fatalf("failed to build family tree: %s", err.Error())
+		}
+
+		w := doGetPersonFamilyTreeRequest(router, insertedPersonByName["Vivian"].ID, "?cursor=invalid")
+		assert.Equal(t, 400, w.Code)
+		assert.Contains(t, w.Body.String(), "INVALID_QUERY_PARAMETER")
+	})
+
+	t.Run("should return 400 bad request when the limit is above the maximum page size", func(t *testing.T) {
+		defer teardownTest()
+
+		insertedPersonByName := map[string]server.PersonResponse{}
+		if err := buildFamily(router, insertedPersonByName); err != nil {
+			t.Fatalf("failed to build family tree: %s", err.Error())
+		}
+
+		w := doGetPersonFamilyTreeRequest(router, insertedPersonByName["Vivian"].ID, "?limit=4294967295")
+		assert.Equal(t, 400, w.Code)
+		assert.Contains(t, w.Body.String(), "INVALID_QUERY_PARAMETER")
+	})
+}
+
 func TestGetBaconsNumberBetweenTwoPersons(t *testing.T) {
 	//NOTE: Leaving this settup per test in case any tests want to introduce a mock for services or repository.
 	personRepository := mongodb.NewPersonRepository(*mongoClient, os.Getenv("MONGO_DATABASE"))
 	personService := service.NewPersonService(personRepository)
-	router := routes.SetupRouter(personService)
+	treeRepository := mongodb.NewTreeRepository(*mongoClient, os.Getenv("MONGO_DATABASE"))
+	treeService := service.NewTreeService(treeRepository)
+	router := routes.SetupRouter(personService, treeService)
 
 	type testArgs struct {
 		testName              string
 		buildFamilyTreeFunc   func() error
 		personAToSearchName   string
 		personBToSearchName   string
+		query                 string
 		expectedStatusCode    int
 		expectedResponse      *server.GetBaconsNumberBetweenTwoPersonsResponse
 		expectedErrorResponse *server.ErrorResponse
@@ -797,6 +1033,18 @@ func TestGetBaconsNumberBetweenTwoPersons(t *testing.T) {
 				BaconsNumber: 4,
 			},
 		},
+		{
+			testName: "should return bacons number for persons outside of eachothers graph connected through a marriage",
+			buildFamilyTreeFunc: func() error {
+				return buildFamily(router, insertedPersonByName)
+			},
+			personAToSearchName: "Caio Regis",
+			personBToSearchName: "Tunico",
+			expectedStatusCode:  200,
+			expectedResponse: &server.GetBaconsNumberBetweenTwoPersonsResponse{
+				BaconsNumber: 3,
+			},
+		},
 		{
 			testName: "should return bacons number for spouses and should be from graph relationship spouse",
 			buildFamilyTreeFunc: func() error {
@@ -812,6 +1060,56 @@ func TestGetBaconsNumberBetweenTwoPersons(t *testing.T) {
 				BaconsNumber: 1,
 			},
 		},
+		{
+			testName: "should return bacons number for spouses through their children when spouses are excluded",
+			buildFamilyTreeFunc: func() error {
+				return buildFamily(router, insertedPersonByName)
+			},
+			personAToSearchName: "Luis",
+			personBToSearchName: "Dayse",
+			query:               "?excludeSpouses=true",
+			expectedStatusCode:  200,
+			expectedResponse: &server.GetBaconsNumberBetweenTwoPersonsResponse{
+				BaconsNumber: 2,
+			},
+		},
+		{
+			testName: "should return the weight of the lightest path",
+			buildFamilyTreeFunc: func() error {
+				return buildFamily(router, insertedPersonByName)
+			},
+			personAToSearchName: "Tunico",
+			personBToSearchName: "Caio",
+			query:               "?direction=descendants&childWeight=3",
+			expectedStatusCode:  200,
+			expectedResponse: &server.GetBaconsNumberBetweenTwoPersonsResponse{
+				BaconsNumber: 6,
+			},
+		},
+		{
+			testName: "should return the weight of the lightest path searching from the other person",
+			buildFamilyTreeFunc: func() error {
+				return buildFamily(router, insertedPersonByName)
+			},
+			personAToSearchName: "Caio",
+			personBToSearchName: "Tunico",
+			query:               "?direction=ancestors&parentWeight=3&maxDepth=2",
+			expectedStatusCode:  200,
+			expectedResponse: &server.GetBaconsNumberBetweenTwoPersonsResponse{
+				BaconsNumber: 6,
+			},
+		},
+		{
+			testName: "should return 400 bad request when a weight is not a positive integer",
+			buildFamilyTreeFunc: func() error {
+				return storePerson(router, server.StorePersonRequest{Name: "Loner", Gender: "male"}, insertedPersonByName)
+			},
+			personAToSearchName:   "Loner",
+			personBToSearchName:   "Loner",
+			query:                 "?spouseWeight=0",
+			expectedStatusCode:    400,
+			expectedErrorResponse: &server.ErrorResponse{ErrorMessage: "spouseWeight must be a positive integer", ErrorCode: "INVALID_QUERY_PARAMETER"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -825,6 +1123,7 @@ func TestGetBaconsNumberBetweenTwoPersons(t *testing.T) {
 					router,
 					insertedPersonByName[tt.personAToSearchName].ID,
 					insertedPersonByName[tt.personBToSearchName].ID,
+					tt.query,
 				)
 				if err != nil {
 					t.Error(err)
@@ -850,7 +1149,9 @@ func TestGetPersonFamilyRelationships(t *testing.T) {
 	//NOTE: Leaving this settup per test in case any tests want to introduce a mock for services or repository.
 	personRepository := mongodb.NewPersonRepository(*mongoClient, os.Getenv("MONGO_DATABASE"))
 	personService := service.NewPersonService(personRepository)
-	router := routes.SetupRouter(personService)
+	treeRepository := mongodb.NewTreeRepository(*mongoClient, os.Getenv("MONGO_DATABASE"))
+	treeService := service.NewTreeService(treeRepository)
+	router := routes.SetupRouter(personService, treeService)
 
 	type testArgs struct {
 		testName              string
@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"github.com/CaioBittencourt/arvore-genealogica/server"
+	"github.com/CaioBittencourt/arvore-genealogica/service"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterAdminRoutes(router gin.IRouter, personService service.PersonService) {
+	router.GET("/admin/integrity", server.CheckIntegrity(personService))
+	router.POST("/admin/integrity/repair", server.RepairIntegrity(personService))
+}
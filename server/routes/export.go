@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"github.com/CaioBittencourt/arvore-genealogica/server"
+	"github.com/CaioBittencourt/arvore-genealogica/service"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterExportRoutes(router gin.IRouter, personService service.PersonService) {
+	router.GET("/export/gedcom", server.ExportGedcom(personService))
+	router.GET("/export/csv", server.ExportCSV(personService))
+}
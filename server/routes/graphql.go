@@ -0,0 +1,11 @@
+package routes
+
+import (
+	"github.com/CaioBittencourt/arvore-genealogica/server"
+	"github.com/CaioBittencourt/arvore-genealogica/service"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterGraphQLRoutes(router gin.IRouter, personService service.PersonService) {
+	router.POST("/graphql", server.GraphQL(personService))
+}
@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"github.com/CaioBittencourt/arvore-genealogica/server"
+	"github.com/CaioBittencourt/arvore-genealogica/service"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterImportRoutes(router gin.IRouter, importService service.ImportService) {
+	router.POST("/import/gedcom", server.ImportGedcom(importService))
+	router.POST("/import/gedcomx", server.ImportGedcomX(importService))
+	router.POST("/import/csv", server.ImportCSV(importService))
+}
@@ -6,9 +6,20 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func RegisterPersonRoutes(router *gin.Engine, personService service.PersonService) {
+func RegisterPersonRoutes(router gin.IRouter, personService service.PersonService) {
 	router.POST("/person", server.Store(personService))
+	router.POST("/persons/batch", server.StoreBatch(personService))
+	router.GET("/person/:id", server.GetPerson(personService))
 	router.GET("/person/:id/tree", server.GetPersonFamilyRelationships(personService))
+	router.GET("/person/:id/tree/events", server.GetPersonFamilyTreeEvents(personService))
+	router.GET("/person/:id/tree.ged", server.GetPersonFamilyTreeGedcom(personService))
+	router.GET("/person/:id/chart.svg", server.GetPersonFamilyTreeChart(personService))
+	router.GET("/person/:id/history", server.GetPersonHistory(personService))
+	router.GET("/person/:id/snapshot", server.GetPersonSnapshot(personService))
+	router.PUT("/person/:id/parents/:parentId", server.LinkParent(personService))
+	router.DELETE("/person/:id/parents/:parentId", server.UnlinkParent(personService))
+	router.PUT("/person/:id/children/:childId", server.LinkChild(personService))
+	router.DELETE("/person/:id/children/:childId", server.UnlinkChild(personService))
 	router.GET("/person/:id/relationship/:id2", server.GetRelationshipBetweenPersons(personService))
 	router.GET("/person/:id/baconNumber/:id2", server.GetBaconsNumberBetweenTwoPersons(personService))
 }
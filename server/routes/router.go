@@ -1,13 +1,25 @@
 package routes
 
 import (
+	"github.com/CaioBittencourt/arvore-genealogica/server"
 	"github.com/CaioBittencourt/arvore-genealogica/service"
 	"github.com/gin-gonic/gin"
 )
 
-func SetupRouter(personService service.PersonService) *gin.Engine {
+// SetupRouter builds the api. Browser pages from allowedOrigins may call it and subscribe to tree events.
+func SetupRouter(personService service.PersonService, treeService service.TreeService, allowedOrigins ...string) *gin.Engine {
 	router := gin.Default()
+	router.ContextWithFallback = true
+	router.Use(server.CORSMiddleware(allowedOrigins))
+	router.Use(server.ChangeMetadataMiddleware())
+
 	RegisterPersonRoutes(router, personService)
+	RegisterAdminRoutes(router, personService)
+	RegisterImportRoutes(router, service.NewImportService(personService))
+	RegisterExportRoutes(router, personService)
+	RegisterGraphQLRoutes(router, personService)
+	RegisterTreeRoutes(router, treeService, personService)
+	RegisterUIRoutes(router)
 
 	return router
 }
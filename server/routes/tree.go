@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/CaioBittencourt/arvore-genealogica/server"
+	"github.com/CaioBittencourt/arvore-genealogica/service"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterTreeRoutes serves every person route again under /trees/:treeId, scoped to that tree.
+func RegisterTreeRoutes(router *gin.Engine, treeService service.TreeService, personService service.PersonService) {
+	router.POST("/trees", server.StoreTree(treeService))
+	router.GET("/trees", server.GetTrees(treeService))
+	router.GET("/trees/:treeId", server.GetTree(treeService))
+	router.DELETE("/trees/:treeId", server.DeleteTree(treeService))
+
+	treeRouter := router.Group("/trees/:treeId", server.TreeScopeMiddleware(treeService))
+	RegisterPersonRoutes(treeRouter, personService)
+	RegisterAdminRoutes(treeRouter, personService)
+	RegisterImportRoutes(treeRouter, service.NewImportService(personService))
+	RegisterExportRoutes(treeRouter, personService)
+	RegisterGraphQLRoutes(treeRouter, personService)
+}
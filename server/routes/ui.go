@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/CaioBittencourt/arvore-genealogica/ui"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterUIRoutes serves the family tree viewer at /ui, /ui without the trailing slash is redirected to it.
+func RegisterUIRoutes(router gin.IRouter) {
+	router.StaticFS("/ui", http.FS(ui.FS()))
+}
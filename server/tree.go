@@ -0,0 +1,148 @@
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+	"github.com/CaioBittencourt/arvore-genealogica/service"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+type StoreTreeRequest struct {
+	Name  string `json:"name"`
+	Owner string `json:"owner"`
+}
+
+type TreeResponse struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Owner     string    `json:"owner"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type TreesResponse struct {
+	Trees []TreeResponse `json:"trees"`
+}
+
+func buildTreeResponseFromDomainTree(tree domain.Tree) TreeResponse {
+	return TreeResponse{
+		ID:        tree.ID,
+		Name:      tree.Name,
+		Owner:     tree.Owner,
+		CreatedAt: tree.CreatedAt,
+	}
+}
+
+// TreeScopeMiddleware makes every person route under /trees/:treeId read and write only persons of that tree.
+func TreeScopeMiddleware(treeService service.TreeService) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tree, err := treeService.GetByID(ctx, ctx.Param("treeId"))
+		if err != nil {
+			createServerResponseFromError(ctx, err)
+			ctx.Abort()
+			return
+		}
+
+		ctx.Request = ctx.Request.WithContext(domain.ContextWithTreeID(ctx.Request.Context(), tree.ID))
+		ctx.Next()
+	}
+}
+
+// @Summary      Store tree
+// @Description  Store a family tree. Persons are stored on a tree through the /trees/:treeId/person routes
+// @Accept       json
+// @Produce      json
+// @Param        tree   body       StoreTreeRequest  true  "Tree to store"
+// @Success      200  {object}   TreeResponse
+// @Failure      400  {object}   ErrorResponse
+// @Failure      500  {object}   ErrorResponse
+// @Router       /trees [post]
+func StoreTree(treeService service.TreeService) gin.HandlerFunc {
+	return gin.HandlerFunc(func(ctx *gin.Context) {
+		var req StoreTreeRequest
+
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			log.WithError(err).Error("server tree: store: invalid request")
+			ctx.JSON(http.StatusBadRequest, ErrorResponse{
+				ErrorMessage: err.Error(),
+			})
+			return
+		}
+
+		tree, err := treeService.Store(ctx, domain.Tree{Name: req.Name, Owner: req.Owner})
+		if err != nil {
+			createServerResponseFromError(ctx, err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, buildTreeResponseFromDomainTree(*tree))
+	})
+}
+
+// @Summary      Get trees
+// @Description  Get trees, optionally only the ones of an owner
+// @Accept       json
+// @Produce      json
+// @Param        owner   query      string  false  "Owner of the trees"
+// @Success      200  {object}   TreesResponse
+// @Failure      500  {object}   ErrorResponse
+// @Router       /trees [get]
+func GetTrees(treeService service.TreeService) gin.HandlerFunc {
+	return gin.HandlerFunc(func(ctx *gin.Context) {
+		trees, err := treeService.GetAll(ctx, ctx.Query("owner"))
+		if err != nil {
+			createServerResponseFromError(ctx, err)
+			return
+		}
+
+		treesResponse := TreesResponse{Trees: []TreeResponse{}}
+		for _, tree := range trees {
+			treesResponse.Trees = append(treesResponse.Trees, buildTreeResponseFromDomainTree(tree))
+		}
+
+		ctx.JSON(http.StatusOK, treesResponse)
+	})
+}
+
+// @Summary      Get tree
+// @Description  Get tree
+// @Accept       json
+// @Produce      json
+// @Param        treeId   path       string  true  "Tree ID"
+// @Success      200  {object}   TreeResponse
+// @Failure      404  {object}   ErrorResponse
+// @Failure      500  {object}   ErrorResponse
+// @Router       /trees/:treeId [get]
+func GetTree(treeService service.TreeService) gin.HandlerFunc {
+	return gin.HandlerFunc(func(ctx *gin.Context) {
+		tree, err := treeService.GetByID(ctx, ctx.Param("treeId"))
+		if err != nil {
+			createServerResponseFromError(ctx, err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, buildTreeResponseFromDomainTree(*tree))
+	})
+}
+
+// @Summary      Delete tree
+// @Description  Delete tree with every person and change history of it
+// @Accept       json
+// @Produce      json
+// @Param        treeId   path       string  true  "Tree ID"
+// @Success      204
+// @Failure      404  {object}   ErrorResponse
+// @Failure      500  {object}   ErrorResponse
+// @Router       /trees/:treeId [delete]
+func DeleteTree(treeService service.TreeService) gin.HandlerFunc {
+	return gin.HandlerFunc(func(ctx *gin.Context) {
+		if err := treeService.Delete(ctx, ctx.Param("treeId")); err != nil {
+			createServerResponseFromError(ctx, err)
+			return
+		}
+
+		ctx.Status(http.StatusNoContent)
+	})
+}
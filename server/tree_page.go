@@ -0,0 +1,137 @@
+package server
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+	"github.com/CaioBittencourt/arvore-genealogica/errors"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+// ndjsonMediaType writes the members of the family tree one JSON person with relationships per line. Only the body is
+// written incrementally, the whole tree is resolved, with the relationships of every member, before the first line.
+const ndjsonMediaType = "application/x-ndjson"
+
+// nextCursorHeader carries the cursor of the next page of NDJSON responses, whose body only has members.
+const nextCursorHeader = "X-Next-Cursor"
+
+// defaultTreePageLimit is the size of the pages asked for with a cursor but no limit.
+const defaultTreePageLimit = 100
+
+// maxTreePageLimit bounds the limit asked by clients, larger trees have to be walked with the cursor.
+const maxTreePageLimit = 1000
+
+type PersonTreePageResponse struct {
+	Members    []PersonWithRelationship `json:"members"`
+	NextCursor string                   `json:"nextCursor,omitempty"`
+}
+
+// membersPageRequest is nil when the whole tree is wanted.
+type membersPageRequest struct {
+	after *domain.MemberCursor
+	limit int
+}
+
+func encodeMemberCursor(cursor domain.MemberCursor) string {
+	encodedCursor, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(encodedCursor)
+}
+
+func decodeMemberCursor(value string) (*domain.MemberCursor, error) {
+	invalidCursorError := errors.NewApplicationError("cursor is not valid", errors.InvalidQueryParameterErrorCode)
+
+	encodedCursor, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, invalidCursorError
+	}
+
+	var cursor domain.MemberCursor
+	if err := json.Unmarshal(encodedCursor, &cursor); err != nil || cursor.ID == "" {
+		return nil, invalidCursorError
+	}
+
+	return &cursor, nil
+}
+
+func parseMembersPageRequest(ctx *gin.Context) (*membersPageRequest, error) {
+	limit, err := parsePositiveUintQueryParameter(ctx, "limit")
+	if err != nil {
+		return nil, err
+	}
+
+	if limit > maxTreePageLimit {
+		return nil, errors.NewApplicationError(fmt.Sprintf("limit must be at most %d", maxTreePageLimit), errors.InvalidQueryParameterErrorCode)
+	}
+
+	cursor := ctx.Query("cursor")
+	if limit == 0 && cursor == "" {
+		return nil, nil
+	}
+
+	pageRequest := membersPageRequest{limit: defaultTreePageLimit}
+	if limit > 0 {
+		pageRequest.limit = int(limit)
+	}
+
+	if cursor != "" {
+		if pageRequest.after, err = decodeMemberCursor(cursor); err != nil {
+			return nil, err
+		}
+	}
+
+	return &pageRequest, nil
+}
+
+func familyGraphMembers(familyGraph domain.FamilyGraph, pageRequest *membersPageRequest) ([]*domain.Person, string) {
+	if pageRequest == nil {
+		return familyGraph.SortedMembers(), ""
+	}
+
+	members, nextCursor := familyGraph.MembersPage(pageRequest.after, pageRequest.limit)
+	if nextCursor == nil {
+		return members, ""
+	}
+
+	return members, encodeMemberCursor(*nextCursor)
+}
+
+func writeFamilyGraphMembersPage(ctx *gin.Context, familyGraph domain.FamilyGraph, pageRequest membersPageRequest) {
+	members, nextCursor := familyGraphMembers(familyGraph, &pageRequest)
+
+	response := PersonTreePageResponse{Members: []PersonWithRelationship{}, NextCursor: nextCursor}
+	for _, member := range members {
+		response.Members = append(response.Members, buildPersonsWithRelationshipFromPerson(*member))
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// writeFamilyGraphMembersAsNDJSON flushes every member as soon as it is encoded and stops when the client goes away.
+// familyGraph is already resolved in full: the relationships of a member, its generation and so its order depend on
+// the members around it, so members are not read from the database one at a time.
+func writeFamilyGraphMembersAsNDJSON(ctx *gin.Context, familyGraph domain.FamilyGraph, pageRequest *membersPageRequest) {
+	members, nextCursor := familyGraphMembers(familyGraph, pageRequest)
+	if nextCursor != "" {
+		ctx.Header(nextCursorHeader, nextCursor)
+	}
+
+	ctx.Header("Content-Type", ndjsonMediaType+"; charset=utf-8")
+	ctx.Status(http.StatusOK)
+
+	encoder := json.NewEncoder(ctx.Writer)
+	for _, member := range members {
+		if ctx.Request.Context().Err() != nil {
+			return
+		}
+
+		if err := encoder.Encode(buildPersonsWithRelationshipFromPerson(*member)); err != nil {
+			log.WithError(err).Error("server: failed to stream family tree member")
+			return
+		}
+		ctx.Writer.Flush()
+	}
+}
@@ -0,0 +1,117 @@
+package server_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/CaioBittencourt/arvore-genealogica/errors"
+	"github.com/CaioBittencourt/arvore-genealogica/repository/mongodb"
+	"github.com/CaioBittencourt/arvore-genealogica/server"
+	"github.com/CaioBittencourt/arvore-genealogica/server/routes"
+	"github.com/CaioBittencourt/arvore-genealogica/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func doJSONRequest(router *gin.Engine, method string, url string, body interface{}, res interface{}) (int, error) {
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			return 0, err
+		}
+	}
+
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest(method, url, &buf)
+	router.ServeHTTP(w, httpReq)
+
+	if res == nil || w.Body.Len() == 0 {
+		return w.Code, nil
+	}
+
+	return w.Code, json.Unmarshal(w.Body.Bytes(), res)
+}
+
+func TestTrees(t *testing.T) {
+	personRepository := mongodb.NewPersonRepository(*mongoClient, os.Getenv("MONGO_DATABASE"))
+	personService := service.NewPersonService(personRepository)
+	treeRepository := mongodb.NewTreeRepository(*mongoClient, os.Getenv("MONGO_DATABASE"))
+	treeService := service.NewTreeService(treeRepository)
+	router := routes.SetupRouter(personService, treeService)
+
+	var bittencourt, regis server.TreeResponse
+	statusCode, err := doJSONRequest(router, "POST", "/trees", server.StoreTreeRequest{Name: "Bittencourt", Owner: "caio"}, &bittencourt)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, statusCode)
+
+	statusCode, err = doJSONRequest(router, "POST", "/trees", server.StoreTreeRequest{Name: "Regis", Owner: "vivian"}, &regis)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, statusCode)
+
+	var trees server.TreesResponse
+	statusCode, err = doJSONRequest(router, "GET", "/trees?owner=caio", nil, &trees)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, statusCode)
+	assert.Equal(t, []server.TreeResponse{bittencourt}, trees.Trees)
+
+	var luis server.PersonResponse
+	statusCode, err = doJSONRequest(router, "POST", fmt.Sprintf("/trees/%s/person", bittencourt.ID), server.StorePersonRequest{Name: "Luis", Gender: "male"}, &luis)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, statusCode)
+
+	t.Run("should find person inside its tree", func(t *testing.T) {
+		statusCode, err := doJSONRequest(router, "GET", fmt.Sprintf("/trees/%s/person/%s", bittencourt.ID, luis.ID), nil, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, 200, statusCode)
+	})
+
+	t.Run("should not find person from another tree", func(t *testing.T) {
+		for _, url := range []string{
+			fmt.Sprintf("/trees/%s/person/%s", regis.ID, luis.ID),
+			fmt.Sprintf("/trees/%s/person/%s/tree", regis.ID, luis.ID),
+			fmt.Sprintf("/person/%s", luis.ID),
+		} {
+			var errorRes server.ErrorResponse
+			statusCode, err := doJSONRequest(router, "GET", url, nil, &errorRes)
+			assert.NoError(t, err)
+			assert.Equal(t, 404, statusCode, url)
+			assert.Equal(t, string(errors.PersonNotFoundErrorCode), errorRes.ErrorCode, url)
+		}
+	})
+
+	t.Run("should not link persons of different trees", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := json.NewEncoder(&buf).Encode(server.StorePersonRequest{Name: "Caio", Gender: "male", FatherID: &luis.ID}); err != nil {
+			t.Fatal(err)
+		}
+
+		w := httptest.NewRecorder()
+		httpReq, _ := http.NewRequest("POST", fmt.Sprintf("/trees/%s/person", regis.ID), &buf)
+		httpReq.Header.Set("If-Match", "*")
+		router.ServeHTTP(w, httpReq)
+
+		var errorRes server.ErrorResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &errorRes))
+		assert.Equal(t, 404, w.Code)
+		assert.Equal(t, string(errors.PersonNotFoundErrorCode), errorRes.ErrorCode)
+	})
+
+	t.Run("should delete tree with its persons", func(t *testing.T) {
+		statusCode, err := doJSONRequest(router, "DELETE", fmt.Sprintf("/trees/%s", bittencourt.ID), nil, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, 204, statusCode)
+
+		var errorRes server.ErrorResponse
+		statusCode, err = doJSONRequest(router, "GET", fmt.Sprintf("/trees/%s/person/%s", bittencourt.ID, luis.ID), nil, &errorRes)
+		assert.NoError(t, err)
+		assert.Equal(t, 404, statusCode)
+		assert.Equal(t, string(errors.TreeNotFoundErrorCode), errorRes.ErrorCode)
+	})
+
+	teardownTest()
+}
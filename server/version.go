@@ -0,0 +1,98 @@
+package server
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+	"github.com/CaioBittencourt/arvore-genealogica/errors"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	etagHeader    = "ETag"
+	ifMatchHeader = "If-Match"
+)
+
+// BuildPersonETag carries the person id along with its version, so a single If-Match header can hold the versions
+// of every person a mutation touches.
+func BuildPersonETag(personID string, version int64) string {
+	return fmt.Sprintf(`"%s-%d"`, personID, version)
+}
+
+// parseIfMatchHeader returns the version sent for each person id. anyVersion is true for "If-Match: *". Persons
+// stored before versions existed have version 0 on their entity tag, it is read as domain.UnversionedPersonVersion
+// so their version is still checked.
+func parseIfMatchHeader(ctx *gin.Context) (versionByPersonID map[string]int64, anyVersion bool, err error) {
+	versionByPersonID = make(map[string]int64)
+	for _, headerValue := range ctx.Request.Header.Values(ifMatchHeader) {
+		for _, etag := range strings.Split(headerValue, ",") {
+			etag = strings.TrimSpace(etag)
+			if etag == "" {
+				continue
+			}
+
+			if etag == "*" {
+				anyVersion = true
+				continue
+			}
+
+			etag = strings.TrimPrefix(etag, "W/")
+			personIDAndVersion := strings.Trim(etag, `"`)
+			separatorIndex := strings.LastIndex(personIDAndVersion, "-")
+			if separatorIndex == -1 {
+				return nil, false, errors.NewApplicationError(fmt.Sprintf("invalid entity tag %s on If-Match", etag), errors.InvalidHeaderErrorCode)
+			}
+
+			version, err := strconv.ParseInt(personIDAndVersion[separatorIndex+1:], 10, 64)
+			if err != nil {
+				return nil, false, errors.NewApplicationError(fmt.Sprintf("invalid entity tag %s on If-Match", etag), errors.InvalidHeaderErrorCode)
+			}
+
+			if version == 0 {
+				version = domain.UnversionedPersonVersion
+			}
+			versionByPersonID[personIDAndVersion[:separatorIndex]] = version
+		}
+	}
+
+	return versionByPersonID, anyVersion, nil
+}
+
+// applyIfMatchToPersons requires an entity tag on If-Match for every stored person referenced on persons and sets
+// the version read by the client on each reference, so the repository only updates persons still on that version.
+func applyIfMatchToPersons(ctx *gin.Context, persons []*domain.Person, isStoredPersonID func(personID string) bool) error {
+	versionByPersonID, anyVersion, err := parseIfMatchHeader(ctx)
+	if err != nil {
+		return err
+	}
+
+	if anyVersion {
+		return nil
+	}
+
+	for _, person := range persons {
+		for _, relative := range append(append([]*domain.Person{}, person.Parents...), person.Children...) {
+			if !isStoredPersonID(relative.ID) {
+				continue
+			}
+
+			version, ok := versionByPersonID[relative.ID]
+			if !ok {
+				return errors.NewApplicationError(
+					fmt.Sprintf("If-Match header must have the entity tag of person %s", relative.ID),
+					errors.PersonVersionRequiredErrorCode,
+				)
+			}
+
+			relative.Version = version
+		}
+	}
+
+	return nil
+}
+
+func setPersonETag(ctx *gin.Context, person domain.Person) {
+	ctx.Header(etagHeader, BuildPersonETag(person.ID, person.Version))
+}
@@ -0,0 +1,124 @@
+package server_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/CaioBittencourt/arvore-genealogica/errors"
+	"github.com/CaioBittencourt/arvore-genealogica/repository/mongodb"
+	"github.com/CaioBittencourt/arvore-genealogica/server"
+	"github.com/CaioBittencourt/arvore-genealogica/server/routes"
+	"github.com/CaioBittencourt/arvore-genealogica/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func doStorePersonRequestWithIfMatch(router *gin.Engine, req server.StorePersonRequest, ifMatch string) (*server.ErrorResponse, int, error) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(req); err != nil {
+		return nil, 0, err
+	}
+
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("POST", "/person", &buf)
+	if ifMatch != "" {
+		httpReq.Header.Set("If-Match", ifMatch)
+	}
+	router.ServeHTTP(w, httpReq)
+
+	if w.Code == 200 {
+		return nil, w.Code, nil
+	}
+
+	res := server.ErrorResponse{}
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		return nil, w.Code, err
+	}
+
+	return &res, w.Code, nil
+}
+
+func TestStoreWithVersions(t *testing.T) {
+	personRepository := mongodb.NewPersonRepository(*mongoClient, os.Getenv("MONGO_DATABASE"))
+	personService := service.NewPersonService(personRepository)
+	treeRepository := mongodb.NewTreeRepository(*mongoClient, os.Getenv("MONGO_DATABASE"))
+	treeService := service.NewTreeService(treeRepository)
+	router := routes.SetupRouter(personService, treeService)
+
+	insertedPersonByName := map[string]server.PersonResponse{}
+	if err := storePerson(router, server.StorePersonRequest{Name: "Tunico", Gender: "male"}, insertedPersonByName); err != nil {
+		t.Fatal(err)
+	}
+	tunicoID := insertedPersonByName["Tunico"].ID
+	staleETag := server.BuildPersonETag(tunicoID, insertedPersonByName["Tunico"].Version)
+
+	t.Run("should require If-Match when linking to a stored person", func(t *testing.T) {
+		errorRes, statusCode, err := doStorePersonRequestWithIfMatch(router, server.StorePersonRequest{Name: "Luis", Gender: "male", FatherID: &tunicoID}, "")
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(t, 428, statusCode)
+		assert.Equal(t, string(errors.PersonVersionRequiredErrorCode), errorRes.ErrorCode)
+	})
+
+	t.Run("should link when If-Match has the current version", func(t *testing.T) {
+		_, statusCode, err := doStorePersonRequestWithIfMatch(router, server.StorePersonRequest{Name: "Luis", Gender: "male", FatherID: &tunicoID}, staleETag)
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(t, 200, statusCode)
+	})
+
+	t.Run("should not link when the person changed since the version sent", func(t *testing.T) {
+		errorRes, statusCode, err := doStorePersonRequestWithIfMatch(router, server.StorePersonRequest{Name: "Claudia", Gender: "female", FatherID: &tunicoID}, staleETag)
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(t, 412, statusCode)
+		assert.Equal(t, string(errors.PersonVersionMismatchErrorCode), errorRes.ErrorCode)
+	})
+
+	t.Run("should refuse an entity tag that can not be read", func(t *testing.T) {
+		errorRes, statusCode, err := doStorePersonRequestWithIfMatch(router, server.StorePersonRequest{Name: "Claudia", Gender: "female", FatherID: &tunicoID}, `"`+tunicoID+`"`)
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(t, 400, statusCode)
+		assert.Equal(t, string(errors.InvalidHeaderErrorCode), errorRes.ErrorCode)
+	})
+
+	t.Run("should check the version of persons stored before versions existed", func(t *testing.T) {
+		zezeObjectID := primitive.NewObjectID()
+		if _, err := mongoClient.Database(os.Getenv("MONGO_DATABASE")).Collection("person").InsertOne(context.Background(), bson.M{"_id": zezeObjectID, "name": "Zézé", "gender": "female"}); err != nil {
+			t.Fatal(err)
+		}
+		zezeID := zezeObjectID.Hex()
+		unversionedETag := server.BuildPersonETag(zezeID, 0)
+
+		_, statusCode, err := doStorePersonRequestWithIfMatch(router, server.StorePersonRequest{Name: "Dayse", Gender: "female", MotherID: &zezeID}, unversionedETag)
+		if err != nil {
+			t.Error(err)
+		}
+		assert.Equal(t, 200, statusCode)
+
+		errorRes, statusCode, err := doStorePersonRequestWithIfMatch(router, server.StorePersonRequest{Name: "Vivian", Gender: "female", MotherID: &zezeID}, unversionedETag)
+		if err != nil {
+			t.Error(err)
+		}
+		assert.Equal(t, 412, statusCode)
+		assert.Equal(t, string(errors.PersonVersionMismatchErrorCode), errorRes.ErrorCode)
+	})
+
+	teardownTest()
+}
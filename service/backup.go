@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/CaioBittencourt/arvore-genealogica/backup"
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+	"github.com/CaioBittencourt/arvore-genealogica/errors"
+	"github.com/CaioBittencourt/arvore-genealogica/repository"
+	log "github.com/sirupsen/logrus"
+)
+
+type BackupService interface {
+	Backup(ctx context.Context, writer io.Writer) (*backup.Manifest, error)
+	Restore(ctx context.Context, reader io.Reader) (*backup.Manifest, *domain.IntegrityReport, error)
+}
+
+type backupService struct {
+	backupRepository repository.BackupRepository
+}
+
+func NewBackupService(
+	backupRepository repository.BackupRepository,
+) BackupService {
+	return backupService{
+		backupRepository: backupRepository,
+	}
+}
+
+func (bs backupService) Backup(ctx context.Context, writer io.Writer) (*backup.Manifest, error) {
+	dump, err := bs.backupRepository.Dump(ctx)
+	if err != nil {
+		log.WithError(err).Error("backup: failed to dump database")
+		return nil, err
+	}
+
+	manifest, err := backup.Write(writer, *dump, time.Now())
+	if err != nil {
+		log.WithError(err).Error("backup: failed to write backup")
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+// Restore only writes into an empty database. Links broken on the database the backup was taken from are
+// restored as they were, the returned report revalidates them by reading back what was stored.
+func (bs backupService) Restore(ctx context.Context, reader io.Reader) (*backup.Manifest, *domain.IntegrityReport, error) {
+	dump, manifest, err := backup.Read(reader)
+	if err != nil {
+		log.WithError(err).Error("backup: failed to read backup")
+		return nil, nil, err
+	}
+
+	for _, issue := range dump.Validate().Issues {
+		switch issue.Type {
+		case domain.DuplicatedPersonIDIssue:
+			return nil, nil, errors.NewApplicationError(fmt.Sprintf("person %s is listed more than once", issue.PersonID), errors.InvalidBackupErrorCode)
+		case domain.UnknownTreeIssue:
+			return nil, nil, errors.NewApplicationError(fmt.Sprintf("person %s belongs to the unknown tree %s", issue.PersonID, issue.RelatedPersonIDS[0]), errors.InvalidBackupErrorCode)
+		}
+	}
+
+	empty, err := bs.backupRepository.IsEmpty(ctx)
+	if err != nil {
+		log.WithError(err).Error("backup: failed to check if database is empty")
+		return nil, nil, err
+	}
+
+	if !empty {
+		return nil, nil, errors.NewApplicationError("backups can only be restored into an empty database", errors.DatabaseNotEmptyErrorCode)
+	}
+
+	if err := bs.backupRepository.Restore(ctx, *dump); err != nil {
+		log.WithError(err).Error("backup: failed to restore backup")
+		return nil, nil, err
+	}
+
+	restored, err := bs.backupRepository.Dump(ctx)
+	if err != nil {
+		log.WithError(err).Error("backup: failed to dump restored database")
+		return nil, nil, err
+	}
+
+	report := restored.Validate()
+	return manifest, &report, nil
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"io"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+	"github.com/CaioBittencourt/arvore-genealogica/errors"
+	"github.com/CaioBittencourt/arvore-genealogica/gedcom"
+	"github.com/CaioBittencourt/arvore-genealogica/gedcomx"
+	"github.com/CaioBittencourt/arvore-genealogica/spreadsheet"
+	log "github.com/sirupsen/logrus"
+)
+
+type ImportService interface {
+	ImportGedcom(ctx context.Context, reader io.Reader) (*gedcom.ImportReport, []domain.BatchItemError, error)
+	ImportGedcomX(ctx context.Context, reader io.Reader) (*gedcom.ImportReport, []domain.BatchItemError, error)
+	ImportCSV(ctx context.Context, reader io.Reader, dryRun bool) (*spreadsheet.ImportReport, error)
+}
+
+type importService struct {
+	personService PersonService
+}
+
+func NewImportService(
+	personService PersonService,
+) ImportService {
+	return importService{
+		personService: personService,
+	}
+}
+
+// ImportGedcom stores every individual of a GEDCOM 5.5.1 or 7 file in a single batch, so either the whole file is
+// imported or nothing is.
+func (is importService) ImportGedcom(ctx context.Context, reader io.Reader) (*gedcom.ImportReport, []domain.BatchItemError, error) {
+	records, err := gedcom.Parse(reader)
+	if err != nil {
+		log.WithError(err).Error("import: failed to parse gedcom")
+		return nil, nil, err
+	}
+
+	batch, report := gedcom.BuildPersonBatch(records)
+	return is.storeImportedBatch(ctx, batch, report)
+}
+
+func (is importService) ImportGedcomX(ctx context.Context, reader io.Reader) (*gedcom.ImportReport, []domain.BatchItemError, error) {
+	document, err := gedcomx.Decode(reader)
+	if err != nil {
+		log.WithError(err).Error("import: failed to decode gedcomx")
+		return nil, nil, err
+	}
+
+	batch, report := gedcomx.BuildPersonBatch(*document)
+	return is.storeImportedBatch(ctx, batch, report)
+}
+
+func (is importService) storeImportedBatch(ctx context.Context, batch domain.PersonBatch, report gedcom.ImportReport) (*gedcom.ImportReport, []domain.BatchItemError, error) {
+	report.IDByXRef = map[string]string{}
+	if len(batch) == 0 {
+		return &report, nil, nil
+	}
+
+	idByXRef, itemErrors, err := is.personService.StoreBatch(ctx, batch)
+	if err != nil {
+		log.WithError(err).Error("import: failed to store imported persons")
+		return nil, itemErrors, err
+	}
+
+	report.IDByXRef = idByXRef
+	return &report, nil, nil
+}
+
+// ImportCSV stores the rows of a CSV file in a single batch. Every row is checked before anything is stored, so the
+// report lists all the invalid rows at once. With dryRun the rows are only checked.
+func (is importService) ImportCSV(ctx context.Context, reader io.Reader, dryRun bool) (*spreadsheet.ImportReport, error) {
+	sheet, err := spreadsheet.Decode(reader)
+	if err != nil {
+		log.WithError(err).Error("import: failed to decode csv")
+		return nil, err
+	}
+
+	report := spreadsheet.ImportReport{Rows: len(sheet.Batch), DryRun: dryRun, IDByKey: map[string]string{}, Errors: sheet.Errors}
+	invalidRowsErr := errors.NewApplicationError("csv has invalid rows", errors.InvalidBatchErrorCode)
+
+	if dryRun || len(report.Errors) > 0 {
+		itemErrors, err := is.personService.ValidateBatch(ctx, sheet.Batch)
+		if err != nil && len(itemErrors) == 0 {
+			return nil, err
+		}
+
+		report.Errors = append(report.Errors, sheet.RowErrorsFromBatchItemErrors(itemErrors)...)
+		if len(report.Errors) > 0 {
+			return &report, invalidRowsErr
+		}
+
+		return &report, nil
+	}
+
+	idByKey, itemErrors, err := is.personService.StoreBatch(ctx, sheet.Batch)
+	if len(itemErrors) > 0 {
+		report.Errors = sheet.RowErrorsFromBatchItemErrors(itemErrors)
+		return &report, invalidRowsErr
+	}
+
+	if err != nil {
+		log.WithError(err).Error("import: failed to store csv rows")
+		return nil, err
+	}
+
+	report.IDByKey = idByKey
+	return &report, nil
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/CaioBittencourt/arvore-genealogica/domain"
 	"github.com/CaioBittencourt/arvore-genealogica/errors"
@@ -11,10 +12,22 @@ import (
 )
 
 type PersonService interface {
-	GetFamilyGraphByPersonID(ctx context.Context, personID string) (*domain.FamilyGraph, error)
-	BaconsNumber(ctx context.Context, personAID string, personBID string) (*uint, error)
-	GetRelationshipBetweenPersons(ctx context.Context, personAID string, personBID string) (*domain.Person, error)
+	GetPersonByID(ctx context.Context, personID string) (*domain.Person, error)
+	GetPersonsByIDS(ctx context.Context, personIDS []string) ([]domain.Person, error)
+	GetAllPersons(ctx context.Context) ([]domain.Person, error)
+	GetFamilyGraphByPersonID(ctx context.Context, personID string, scope domain.FamilyGraphScope) (*domain.FamilyGraph, error)
+	BaconsNumber(ctx context.Context, personAID string, personBID string, options domain.BaconsNumberOptions) (*uint, error)
+	GetRelationshipBetweenPersons(ctx context.Context, personAID string, personBID string, linkTypes []domain.LinkType) (*domain.Person, error)
 	Store(ctx context.Context, person domain.Person) (*domain.Person, error)
+	StoreBatch(ctx context.Context, batch domain.PersonBatch) (map[string]string, []domain.BatchItemError, error)
+	ValidateBatch(ctx context.Context, batch domain.PersonBatch) ([]domain.BatchItemError, error)
+	CheckIntegrity(ctx context.Context, repair bool) (*domain.IntegrityReport, error)
+	GetPersonHistory(ctx context.Context, personID string) ([]domain.PersonChange, error)
+	GetPersonAsOf(ctx context.Context, personID string, at time.Time) (*domain.Person, error)
+	GetFamilyGraphByPersonIDAsOf(ctx context.Context, personID string, at time.Time, scope domain.FamilyGraphScope) (*domain.FamilyGraph, error)
+	LinkParent(ctx context.Context, child domain.Person, parent domain.Person, role domain.ParentRoleType, linkType domain.LinkType) error
+	UnlinkParent(ctx context.Context, child domain.Person, parent domain.Person) error
+	WatchFamilyGraphEvents(ctx context.Context, personID string, scope domain.FamilyGraphScope) (<-chan domain.TreeEvent, error)
 }
 
 type personService struct {
@@ -29,61 +42,85 @@ func NewPersonService(
 	}
 }
 
-func (pc personService) GetFamilyGraphByPersonID(ctx context.Context, personID string) (*domain.FamilyGraph, error) {
-	familyGraph, err := pc.personRepository.GetPersonFamilyGraphByID(ctx, personID, nil)
+func (pc personService) GetPersonByID(ctx context.Context, personID string) (*domain.Person, error) {
+	persons, err := pc.personRepository.GetPersonWithImmediateRelativesByIDS(ctx, []string{personID})
 	if err != nil {
-		log.WithError(err).Error("person: failed to get family graph by ID")
+		log.WithError(err).Error("person: failed to get person by ID")
 		return nil, err
 	}
 
-	if familyGraph == nil {
-		return nil, errors.NewApplicationError("person not found", errors.PersonNotFoundErrorCode)
+	if len(persons) == 0 {
+		return nil, errors.NewApplicationError(fmt.Sprintf("person with id %s not found", personID), errors.PersonNotFoundErrorCode)
 	}
 
-	if err := familyGraph.PopulateFamilyWithRelationships(personID); err != nil {
-		log.WithError(err).Error("person: failed to populate family with relationships")
+	return &persons[0], nil
+}
+
+// GetPersonsByIDS reads every person with its parents and children in a single call, ids not found are skipped.
+func (pc personService) GetPersonsByIDS(ctx context.Context, personIDS []string) ([]domain.Person, error) {
+	persons, err := pc.personRepository.GetPersonWithImmediateRelativesByIDS(ctx, personIDS)
+	if err != nil {
+		log.WithError(err).Error("person: failed to get persons by IDS")
 		return nil, err
 	}
 
-	return familyGraph, err
+	return persons, nil
+}
+
+func (pc personService) GetAllPersons(ctx context.Context) ([]domain.Person, error) {
+	persons, err := pc.personRepository.GetAll(ctx)
+	if err != nil {
+		log.WithError(err).Error("person: failed to get all persons")
+		return nil, err
+	}
+
+	return persons, nil
 }
 
-func (pc personService) getBaconNumber(ctx context.Context, personAID string, personBID string) (*uint, error) {
-	familyGraph, err := pc.personRepository.GetPersonFamilyGraphByID(ctx, personAID, nil)
+func (pc personService) GetFamilyGraphByPersonID(ctx context.Context, personID string, scope domain.FamilyGraphScope) (*domain.FamilyGraph, error) {
+	familyGraph, err := pc.personRepository.GetPersonFamilyGraphByID(ctx, personID, scope)
 	if err != nil {
 		log.WithError(err).Error("person: failed to get family graph by ID")
 		return nil, err
 	}
 
 	if familyGraph == nil {
-		return nil, errors.NewApplicationError(fmt.Sprintf("person with id %s not found", personAID), errors.PersonNotFoundErrorCode)
+		return nil, errors.NewApplicationError("person not found", errors.PersonNotFoundErrorCode)
 	}
 
-	baconsNumber := familyGraph.BaconsNumber(personAID, personBID)
-	if baconsNumber == nil {
-		return nil, errors.NewApplicationError("persons dont belong to eachothers graph", errors.PersonNotFoundInGraph)
+	if err := familyGraph.PopulateFamilyWithRelationships(personID); err != nil {
+		log.WithError(err).Error("person: failed to populate family with relationships")
+		return nil, err
 	}
 
-	return baconsNumber, nil
+	return familyGraph, err
 }
 
-func (pc personService) BaconsNumber(ctx context.Context, personAID string, personBID string) (*uint, error) {
-	baconNumber, err := pc.getBaconNumber(ctx, personAID, personBID)
-	if err != nil && !errors.ErrorHasCode(err, errors.PersonNotFoundInGraph) {
+// baconsNumberSearchLimit is the most persons a bacons number search reads before giving up.
+const baconsNumberSearchLimit = 10000
+
+// BaconsNumber searches from both persons at once, reading their relatives as it goes, so any connected pair is found
+// no matter how far apart their ascendants are.
+func (pc personService) BaconsNumber(ctx context.Context, personAID string, personBID string, options domain.BaconsNumberOptions) (*uint, error) {
+	if err := options.Validate(); err != nil {
 		return nil, err
 	}
 
-	if baconNumber != nil {
-		return baconNumber, nil
+	baconNumber, err := pc.personRepository.SearchBaconsNumber(ctx, personAID, personBID, options, baconsNumberSearchLimit)
+	if err != nil {
+		log.WithError(err).Error("person: failed to search bacons number")
+		return nil, err
 	}
 
-	baconNumber, err = pc.getBaconNumber(ctx, personBID, personAID)
-	return baconNumber, err
+	if baconNumber == nil {
+		return nil, errors.NewApplicationError("persons dont belong to eachothers graph", errors.PersonNotFoundInGraph)
+	}
 
+	return baconNumber, nil
 }
 
-func (pc personService) getRelationshipBetweenPersons(ctx context.Context, personAID string, personBID string) (*domain.Person, error) {
-	familyGraph, err := pc.personRepository.GetPersonFamilyGraphByID(ctx, personAID, nil)
+func (pc personService) getRelationshipBetweenPersons(ctx context.Context, personAID string, personBID string, linkTypes []domain.LinkType) (*domain.Person, error) {
+	familyGraph, err := pc.personRepository.GetPersonFamilyGraphByID(ctx, personAID, domain.DefaultFamilyGraphScope())
 	if err != nil {
 		log.WithError(err).Error("person: failed to get family graph by ID")
 		return nil, err
@@ -93,7 +130,8 @@ func (pc personService) getRelationshipBetweenPersons(ctx context.Context, perso
 		return nil, errors.NewApplicationError(fmt.Sprintf("person with id %s not found", personAID), errors.PersonNotFoundErrorCode)
 	}
 
-	personWithRelationship := familyGraph.FindRelationshipBetweenPersons(personAID, personBID)
+	linkTypesGraph := familyGraph.WithLinkTypes(linkTypes)
+	personWithRelationship := linkTypesGraph.FindRelationshipBetweenPersons(personAID, personBID)
 	if personWithRelationship == nil {
 		return nil, errors.NewApplicationError("persons dont belong to eachothers graph", errors.PersonNotFoundInGraph)
 	}
@@ -101,8 +139,9 @@ func (pc personService) getRelationshipBetweenPersons(ctx context.Context, perso
 	return personWithRelationship, nil
 }
 
-func (pc personService) GetRelationshipBetweenPersons(ctx context.Context, personAID string, personBID string) (*domain.Person, error) {
-	personWithRelationship, err := pc.getRelationshipBetweenPersons(ctx, personAID, personBID)
+// GetRelationshipBetweenPersons only follows the parent links of linkTypes, every link type when it is empty.
+func (pc personService) GetRelationshipBetweenPersons(ctx context.Context, personAID string, personBID string, linkTypes []domain.LinkType) (*domain.Person, error) {
+	personWithRelationship, err := pc.getRelationshipBetweenPersons(ctx, personAID, personBID, linkTypes)
 	if err != nil && !errors.ErrorHasCode(err, errors.PersonNotFoundInGraph) {
 		return nil, err
 	}
@@ -111,7 +150,7 @@ func (pc personService) GetRelationshipBetweenPersons(ctx context.Context, perso
 		return personWithRelationship, nil
 	}
 
-	personWithRelationship, err = pc.getRelationshipBetweenPersons(ctx, personBID, personAID)
+	personWithRelationship, err = pc.getRelationshipBetweenPersons(ctx, personBID, personAID, linkTypes)
 	return personWithRelationship, err
 
 }
@@ -137,6 +176,26 @@ func (pc personService) Store(ctx context.Context, person domain.Person) (*domai
 		return nil, err
 	}
 
+	var parentIDSWithRole []string
+	for _, parent := range person.Parents {
+		if person.ParentRole(parent.ID) != domain.GenericParentRole {
+			parentIDSWithRole = append(parentIDSWithRole, parent.ID)
+		}
+	}
+
+	if len(parentIDSWithRole) > 0 {
+		parents, err := pc.personRepository.GetPersonWithImmediateRelativesByIDS(ctx, parentIDSWithRole)
+		if err != nil {
+			log.WithError(err).Error("person: failed to store person")
+			return nil, err
+		}
+
+		if err := person.ValidateParentGenders(parents); err != nil {
+			log.WithError(err).Error("person: validate failed for parents of person to store")
+			return nil, err
+		}
+	}
+
 	insertedPerson, err := pc.personRepository.Store(ctx, person)
 	if err != nil {
 		log.WithError(err).Error("person: failed to store person")
@@ -145,3 +204,288 @@ func (pc personService) Store(ctx context.Context, person domain.Person) (*domai
 
 	return insertedPerson, err
 }
+
+func (pc personService) CheckIntegrity(ctx context.Context, repair bool) (*domain.IntegrityReport, error) {
+	persons, err := pc.personRepository.GetAll(ctx)
+	if err != nil {
+		log.WithError(err).Error("person: failed to get all persons to check integrity")
+		return nil, err
+	}
+
+	report := domain.CheckIntegrity(persons)
+
+	fixableIssues := report.FixableIssues()
+	if !repair || len(fixableIssues) == 0 {
+		return &report, nil
+	}
+
+	if changeMetadata := domain.ChangeMetadataFromContext(ctx); changeMetadata.Reason == "" {
+		changeMetadata.Reason = "referential integrity repair"
+		ctx = domain.ContextWithChangeMetadata(ctx, changeMetadata)
+	}
+
+	if err := pc.personRepository.RepairIntegrityIssues(ctx, fixableIssues); err != nil {
+		log.WithError(err).Error("person: failed to repair integrity issues")
+		return nil, err
+	}
+
+	report.Repaired = true
+	return &report, nil
+}
+
+// ValidateBatch runs every check StoreBatch does without storing anything.
+func (pc personService) ValidateBatch(ctx context.Context, batch domain.PersonBatch) ([]domain.BatchItemError, error) {
+	if len(batch) == 0 {
+		return nil, errors.NewApplicationError("batch must have at least one person", errors.InvalidBatchErrorCode)
+	}
+
+	var existingPersons []domain.Person
+	if externalIDS := batch.ExternalIDS(); len(externalIDS) > 0 {
+		var err error
+		existingPersons, err = pc.personRepository.GetPersonWithImmediateRelativesByIDS(ctx, externalIDS)
+		if err != nil {
+			log.WithError(err).Error("person: failed to get persons referenced by batch")
+			return nil, err
+		}
+	}
+
+	if itemErrors := batch.Validate(existingPersons); len(itemErrors) > 0 {
+		log.WithField("errors", len(itemErrors)).Error("person: validate failed for batch")
+		return itemErrors, errors.NewApplicationError("batch has invalid persons", errors.InvalidBatchErrorCode)
+	}
+
+	return nil, nil
+}
+
+func (pc personService) StoreBatch(ctx context.Context, batch domain.PersonBatch) (map[string]string, []domain.BatchItemError, error) {
+	if itemErrors, err := pc.ValidateBatch(ctx, batch); err != nil {
+		return nil, itemErrors, err
+	}
+
+	idByTempID, err := pc.personRepository.StoreBatch(ctx, batch)
+	if err != nil {
+		log.WithError(err).Error("person: failed to store batch")
+		return nil, nil, err
+	}
+
+	return idByTempID, nil, nil
+}
+
+func (pc personService) GetPersonHistory(ctx context.Context, personID string) ([]domain.PersonChange, error) {
+	personChanges, err := pc.personRepository.GetPersonHistory(ctx, personID)
+	if err != nil {
+		log.WithError(err).Error("person: failed to get person history")
+		return nil, err
+	}
+
+	if len(personChanges) == 0 {
+		return nil, errors.NewApplicationError("person history not found", errors.PersonNotFoundErrorCode)
+	}
+
+	return personChanges, nil
+}
+
+func (pc personService) GetPersonAsOf(ctx context.Context, personID string, at time.Time) (*domain.Person, error) {
+	person, err := pc.personRepository.GetPersonAsOf(ctx, personID, at)
+	if err != nil {
+		log.WithError(err).Error("person: failed to get person as of time")
+		return nil, err
+	}
+
+	if person == nil {
+		return nil, errors.NewApplicationError("person not found at the given time", errors.PersonNotFoundErrorCode)
+	}
+
+	return person, nil
+}
+
+func (pc personService) GetFamilyGraphByPersonIDAsOf(ctx context.Context, personID string, at time.Time, scope domain.FamilyGraphScope) (*domain.FamilyGraph, error) {
+	familyGraph, err := pc.personRepository.GetPersonFamilyGraphAsOf(ctx, personID, at, scope)
+	if err != nil {
+		log.WithError(err).Error("person: failed to get family graph as of time")
+		return nil, err
+	}
+
+	if familyGraph == nil {
+		return nil, errors.NewApplicationError("person not found at the given time", errors.PersonNotFoundErrorCode)
+	}
+
+	if err := familyGraph.PopulateFamilyWithRelationships(personID); err != nil {
+		log.WithError(err).Error("person: failed to populate family with relationships")
+		return nil, err
+	}
+
+	return familyGraph, nil
+}
+
+// getPersonsToLink reads child and parent with their immediate relatives. The versions sent on child and parent are
+// kept, the ones read are used when they are not set.
+func (pc personService) getPersonsToLink(ctx context.Context, child domain.Person, parent domain.Person) (*domain.Person, *domain.Person, error) {
+	persons, err := pc.personRepository.GetPersonWithImmediateRelativesByIDS(ctx, []string{child.ID, parent.ID})
+	if err != nil {
+		log.WithError(err).Error("person: failed to get persons to link")
+		return nil, nil, err
+	}
+
+	personsByID := make(map[string]domain.Person)
+	for _, person := range persons {
+		personsByID[person.ID] = person
+	}
+
+	storedPersons := make([]*domain.Person, 2)
+	for i, person := range []domain.Person{child, parent} {
+		storedPerson, ok := personsByID[person.ID]
+		if !ok {
+			return nil, nil, errors.NewApplicationError(fmt.Sprintf("person with id %s not found", person.ID), errors.PersonNotFoundErrorCode)
+		}
+
+		if person.Version != 0 {
+			storedPerson.Version = person.Version
+		}
+		storedPersons[i] = &storedPerson
+	}
+
+	return storedPersons[0], storedPersons[1], nil
+}
+
+// LinkParent does nothing when parent already is a parent of child with role and linkType, and only changes them when
+// it is a parent with another role or link type.
+func (pc personService) LinkParent(ctx context.Context, child domain.Person, parent domain.Person, role domain.ParentRoleType, linkType domain.LinkType) error {
+	storedChild, storedParent, err := pc.getPersonsToLink(ctx, child, parent)
+	if err != nil {
+		return err
+	}
+
+	if storedChild.HasParent(*storedParent) && storedChild.ParentRole(parent.ID) == role && storedChild.ParentLinkType(parent.ID) == linkType {
+		return nil
+	}
+
+	// only the ancestors of the parent are needed to find cycles
+	parentFamilyGraph, err := pc.personRepository.GetPersonFamilyGraphByID(ctx, parent.ID, domain.FamilyGraphScope{ExcludeSpouses: true})
+	if err != nil {
+		log.WithError(err).Error("person: failed to get family graph of parent to link")
+		return err
+	}
+
+	var parentAncestorIDS map[string]bool
+	if parentFamilyGraph != nil {
+		parentAncestorIDS = parentFamilyGraph.AncestorIDS(parent.ID)
+	}
+
+	if err := domain.ValidateParentLink(*storedChild, *storedParent, role, linkType, parentAncestorIDS); err != nil {
+		log.WithError(err).Error("person: validate failed for parent to link")
+		return err
+	}
+
+	if err := pc.personRepository.LinkParent(ctx, *storedChild, *storedParent, role, linkType); err != nil {
+		log.WithError(err).Error("person: failed to link parent")
+		return err
+	}
+
+	return nil
+}
+
+func (pc personService) UnlinkParent(ctx context.Context, child domain.Person, parent domain.Person) error {
+	storedChild, storedParent, err := pc.getPersonsToLink(ctx, child, parent)
+	if err != nil {
+		return err
+	}
+
+	if !storedChild.HasParent(*storedParent) {
+		return errors.NewApplicationError(fmt.Sprintf("person %s is not a parent of %s", parent.ID, child.ID), errors.PersonNotFoundErrorCode)
+	}
+
+	if err := pc.personRepository.UnlinkParent(ctx, *storedChild, *storedParent); err != nil {
+		log.WithError(err).Error("person: failed to unlink parent")
+		return err
+	}
+
+	return nil
+}
+
+func (pc personService) getFamilyGraphMemberIDS(ctx context.Context, personID string, scope domain.FamilyGraphScope) (map[string]bool, error) {
+	familyGraph, err := pc.personRepository.GetPersonFamilyGraphByID(ctx, personID, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	if familyGraph == nil {
+		return nil, errors.NewApplicationError("person not found", errors.PersonNotFoundErrorCode)
+	}
+
+	memberIDS := map[string]bool{}
+	for memberID := range familyGraph.Members {
+		memberIDS[memberID] = true
+	}
+
+	return memberIDS, nil
+}
+
+// familyGraphRefreshInterval is the least time between two reads of the members of a watched family graph, so a burst
+// of changes does not read the whole graph once per change and per viewer.
+const familyGraphRefreshInterval = time.Second
+
+// WatchFamilyGraphEvents sends the changes to the tree that affect the family graph of personID until ctx is done.
+// The persons of each sent change join the members right away, since the graph may have grown with it, and the
+// members are read again when a later change arrives at least familyGraphRefreshInterval after the last read.
+// The channel is closed when the person is deleted or the stream of changes fails.
+func (pc personService) WatchFamilyGraphEvents(ctx context.Context, personID string, scope domain.FamilyGraphScope) (<-chan domain.TreeEvent, error) {
+	// watching before reading the graph, so changes made in between are not missed
+	treeEventStream, err := pc.personRepository.WatchTreeEvents(ctx)
+	if err != nil {
+		log.WithError(err).Error("person: failed to watch tree events")
+		return nil, err
+	}
+
+	memberIDS, err := pc.getFamilyGraphMemberIDS(ctx, personID, scope)
+	if err != nil {
+		treeEventStream.Close(context.Background())
+		return nil, err
+	}
+
+	familyGraphEvents := make(chan domain.TreeEvent)
+	go func() {
+		defer close(familyGraphEvents)
+		defer treeEventStream.Close(context.Background())
+
+		membersReadAt, membersChanged := time.Now(), false
+		for treeEventStream.Next(ctx) {
+			treeEvent := treeEventStream.Event()
+			if membersChanged && time.Since(membersReadAt) >= familyGraphRefreshInterval {
+				refreshedMemberIDS, err := pc.getFamilyGraphMemberIDS(ctx, personID, scope)
+				if err != nil {
+					if ctx.Err() == nil {
+						log.WithError(err).Error("person: failed to read family graph of watched person")
+					}
+					return
+				}
+				memberIDS, membersReadAt, membersChanged = refreshedMemberIDS, time.Now(), false
+			}
+
+			if !treeEvent.Affects(memberIDS) {
+				continue
+			}
+
+			select {
+			case familyGraphEvents <- treeEvent:
+			case <-ctx.Done():
+				return
+			}
+
+			if treeEvent.Type == domain.PersonDeletedTreeEvent && treeEvent.PersonID == personID {
+				return
+			}
+
+			for _, affectedPersonID := range treeEvent.AffectedPersonIDS {
+				memberIDS[affectedPersonID] = true
+			}
+			membersChanged = true
+		}
+
+		if err := treeEventStream.Err(); err != nil && ctx.Err() == nil {
+			log.WithError(err).Error("person: tree events stream failed")
+		}
+	}()
+
+	return familyGraphEvents, nil
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+	"github.com/CaioBittencourt/arvore-genealogica/errors"
+	"github.com/CaioBittencourt/arvore-genealogica/repository"
+	log "github.com/sirupsen/logrus"
+)
+
+type TreeService interface {
+	Store(ctx context.Context, tree domain.Tree) (*domain.Tree, error)
+	GetByID(ctx context.Context, treeID string) (*domain.Tree, error)
+	GetAll(ctx context.Context, owner string) ([]domain.Tree, error)
+	Delete(ctx context.Context, treeID string) error
+}
+
+type treeService struct {
+	treeRepository repository.TreeRepository
+}
+
+func NewTreeService(
+	treeRepository repository.TreeRepository,
+) TreeService {
+	return treeService{
+		treeRepository: treeRepository,
+	}
+}
+
+func (ts treeService) Store(ctx context.Context, tree domain.Tree) (*domain.Tree, error) {
+	tree.Name = strings.TrimSpace(tree.Name)
+	if len(tree.Name) < 2 {
+		return nil, errors.NewApplicationError("tree name must have more than 1 character", errors.InvalidTreeNameErrorCode)
+	}
+
+	storedTree, err := ts.treeRepository.Store(ctx, tree)
+	if err != nil {
+		log.WithError(err).Error("tree: failed to store tree")
+		return nil, err
+	}
+
+	return storedTree, nil
+}
+
+func (ts treeService) GetByID(ctx context.Context, treeID string) (*domain.Tree, error) {
+	tree, err := ts.treeRepository.GetByID(ctx, treeID)
+	if err != nil {
+		log.WithError(err).Error("tree: failed to get tree by ID")
+		return nil, err
+	}
+
+	if tree == nil {
+		return nil, errors.NewApplicationError(fmt.Sprintf("tree with id %s not found", treeID), errors.TreeNotFoundErrorCode)
+	}
+
+	return tree, nil
+}
+
+func (ts treeService) GetAll(ctx context.Context, owner string) ([]domain.Tree, error) {
+	trees, err := ts.treeRepository.GetAll(ctx, owner)
+	if err != nil {
+		log.WithError(err).Error("tree: failed to get trees")
+		return nil, err
+	}
+
+	return trees, nil
+}
+
+func (ts treeService) Delete(ctx context.Context, treeID string) error {
+	if _, err := ts.GetByID(ctx, treeID); err != nil {
+		return err
+	}
+
+	if err := ts.treeRepository.Delete(ctx, treeID); err != nil {
+		log.WithError(err).Error("tree: failed to delete tree")
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,347 @@
+package spreadsheet
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+	"github.com/CaioBittencourt/arvore-genealogica/errors"
+)
+
+const MediaType = "text/csv"
+
+const (
+	IDColumn     = "id"
+	NameColumn   = "name"
+	GenderColumn = "gender"
+	MotherColumn = "mother"
+	FatherColumn = "father"
+	// OtherParentsColumn has the parents that are not the biological mother or father, as id:link type or
+	// id:link type:role entries separated by semicolons, like "tunico:adoptive:father;ana:step"
+	OtherParentsColumn = "other_parents"
+	BirthDateColumn    = "birth_date"
+	BirthPlaceColumn   = "birth_place"
+	DeathDateColumn    = "death_date"
+	DeathPlaceColumn   = "death_place"
+)
+
+// Header is the header written on export. On import the columns may come in any order and only name and gender
+// are required.
+var Header = []string{IDColumn, NameColumn, GenderColumn, MotherColumn, FatherColumn, OtherParentsColumn, BirthDateColumn, BirthPlaceColumn, DeathDateColumn, DeathPlaceColumn}
+
+var parentRoleByColumn = map[string]domain.ParentRoleType{MotherColumn: domain.MotherParentRole, FatherColumn: domain.FatherParentRole}
+
+const (
+	otherParentsSeparator     = ";"
+	otherParentFieldSeparator = ":"
+)
+
+var requiredColumns = []string{NameColumn, GenderColumn}
+
+var gendersByValue = map[string]domain.GenderType{
+	"male":   domain.Male,
+	"m":      domain.Male,
+	"female": domain.Female,
+	"f":      domain.Female,
+}
+
+// RowError is a problem found on a row of the file. Column is empty when the error is not about a single column.
+type RowError struct {
+	Line   int
+	Key    string
+	Column string
+	Err    error
+}
+
+// ImportReport lists every row that could not be imported, IDByKey is only filled when the rows were stored.
+type ImportReport struct {
+	Rows    int
+	DryRun  bool
+	IDByKey map[string]string
+	Errors  []RowError
+}
+
+// Sheet is a decoded file, the person of each row is at the same index of the batch and Lines.
+type Sheet struct {
+	Batch  domain.PersonBatch
+	Lines  []int
+	Errors []RowError
+}
+
+type row struct {
+	line   int
+	values map[string]string
+}
+
+func (r row) lifeEvent(dateColumn string, placeColumn string) *domain.LifeEvent {
+	lifeEvent := domain.LifeEvent{Date: r.values[dateColumn], Place: r.values[placeColumn]}
+	if lifeEvent.Date == "" && lifeEvent.Place == "" {
+		return nil
+	}
+
+	return &lifeEvent
+}
+
+// otherParent is an entry of the other_parents column.
+type otherParent struct {
+	reference string
+	linkType  domain.LinkType
+	role      domain.ParentRoleType
+}
+
+func parseOtherParents(value string) ([]otherParent, error) {
+	var otherParents []otherParent
+	for _, entry := range strings.Split(value, otherParentsSeparator) {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		fields := strings.Split(entry, otherParentFieldSeparator)
+		if len(fields) < 2 || len(fields) > 3 || strings.TrimSpace(fields[0]) == "" {
+			return nil, errors.NewApplicationError(fmt.Sprintf("%s must be id:link type or id:link type:role", entry), errors.InvalidBatchErrorCode)
+		}
+
+		parent := otherParent{reference: strings.TrimSpace(fields[0]), linkType: domain.LinkType(strings.ToLower(strings.TrimSpace(fields[1]))), role: domain.GenericParentRole}
+		if !parent.linkType.IsValid() {
+			return nil, errors.NewApplicationError(fmt.Sprintf("parent link type %s is not valid", parent.linkType), errors.InvalidParentLinkTypeErrorCode)
+		}
+
+		if len(fields) == 3 {
+			parent.role = domain.ParentRoleType(strings.ToLower(strings.TrimSpace(fields[2])))
+			if !parent.role.IsValid() {
+				return nil, errors.NewApplicationError(fmt.Sprintf("parent role %s is not valid", parent.role), errors.InvalidParentRoleErrorCode)
+			}
+		}
+
+		otherParents = append(otherParents, parent)
+	}
+
+	return otherParents, nil
+}
+
+func formatOtherParent(parentID string, linkType domain.LinkType, role domain.ParentRoleType) string {
+	return strings.Join([]string{parentID, string(linkType), string(role)}, otherParentFieldSeparator)
+}
+
+func invalidCSV(message string) error {
+	return errors.NewApplicationError(message, errors.InvalidCSVErrorCode)
+}
+
+func readRows(reader io.Reader) ([]row, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.TrimLeadingSpace = true
+
+	header, err := csvReader.Read()
+	if err == io.EOF {
+		return nil, invalidCSV("file is empty")
+	}
+	if err != nil {
+		return nil, invalidCSV(err.Error())
+	}
+
+	columnIndexByName := make(map[string]int, len(header))
+	for i, column := range header {
+		column = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff")))
+		if _, ok := columnIndexByName[column]; ok {
+			return nil, invalidCSV(fmt.Sprintf("column %s is duplicated", column))
+		}
+		columnIndexByName[column] = i
+	}
+
+	for _, column := range requiredColumns {
+		if _, ok := columnIndexByName[column]; !ok {
+			return nil, invalidCSV(fmt.Sprintf("column %s is required", column))
+		}
+	}
+
+	var rows []row
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, invalidCSV(err.Error())
+		}
+
+		line, _ := csvReader.FieldPos(0)
+		values := make(map[string]string, len(columnIndexByName))
+		for column, index := range columnIndexByName {
+			values[column] = strings.TrimSpace(record[index])
+		}
+
+		rows = append(rows, row{line: line, values: values})
+	}
+
+	return rows, nil
+}
+
+// Decode reads a CSV file with a header row into a batch. Rows are keyed by their id column, or by their line when
+// it is empty, and the mother, father and other_parents columns reference either the key of another row or the id of
+// a stored person. Rows referencing a mother that is not female or a father that is not male, or with other parents
+// that can not be read, are reported, the remaining checks are the ones of the batch.
+func Decode(reader io.Reader) (*Sheet, error) {
+	rows, err := readRows(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	sheet := &Sheet{}
+	genderByKey := make(map[string]domain.GenderType, len(rows))
+	otherParentsByRow := make([][]otherParent, len(rows))
+	for i, r := range rows {
+		key := r.values[IDColumn]
+		if key == "" {
+			key = fmt.Sprintf("line %d", r.line)
+		}
+
+		gender, ok := gendersByValue[strings.ToLower(r.values[GenderColumn])]
+		if !ok {
+			gender = domain.GenderType(strings.ToLower(r.values[GenderColumn]))
+		}
+		if _, ok := genderByKey[key]; !ok {
+			genderByKey[key] = gender
+		}
+
+		person := domain.Person{
+			Name:   r.values[NameColumn],
+			Gender: gender,
+			Birth:  r.lifeEvent(BirthDateColumn, BirthPlaceColumn),
+			Death:  r.lifeEvent(DeathDateColumn, DeathPlaceColumn),
+		}
+		for _, column := range []string{MotherColumn, FatherColumn} {
+			if reference := r.values[column]; reference != "" {
+				person.AddParent(&domain.Person{ID: reference}, parentRoleByColumn[column])
+			}
+		}
+
+		otherParents, err := parseOtherParents(r.values[OtherParentsColumn])
+		if err != nil {
+			sheet.Errors = append(sheet.Errors, RowError{Line: r.line, Key: key, Column: OtherParentsColumn, Err: err})
+		}
+		for _, parent := range otherParents {
+			person.AddParent(&domain.Person{ID: parent.reference}, parent.role)
+			person.SetParentLinkType(parent.reference, parent.linkType)
+		}
+		otherParentsByRow[i] = otherParents
+
+		sheet.Batch = append(sheet.Batch, domain.BatchPerson{TempID: key, Person: person})
+		sheet.Lines = append(sheet.Lines, r.line)
+	}
+
+	expectedGenderByColumn := map[string]domain.GenderType{MotherColumn: domain.Female, FatherColumn: domain.Male}
+	for i, r := range rows {
+		key := sheet.Batch[i].TempID
+		addRowError := func(column string, message string) {
+			sheet.Errors = append(sheet.Errors, RowError{Line: r.line, Key: key, Column: column, Err: errors.NewApplicationError(message, errors.InvalidBatchErrorCode)})
+		}
+
+		mother, father := r.values[MotherColumn], r.values[FatherColumn]
+		if mother != "" && mother == father {
+			addRowError(FatherColumn, fmt.Sprintf("%s can not be both the mother and the father", mother))
+			continue
+		}
+
+		for _, column := range []string{MotherColumn, FatherColumn} {
+			reference := r.values[column]
+			if reference == "" {
+				continue
+			}
+
+			if reference == key {
+				addRowError(column, "person can not be its own parent")
+				continue
+			}
+
+			if gender, ok := genderByKey[reference]; ok && gender.IsValid() && gender != expectedGenderByColumn[column] {
+				addRowError(column, fmt.Sprintf("%s %s is %s", column, reference, gender))
+			}
+		}
+
+		for _, parent := range otherParentsByRow[i] {
+			if parent.reference == key {
+				addRowError(OtherParentsColumn, "person can not be its own parent")
+				continue
+			}
+
+			if parent.reference == mother || parent.reference == father {
+				addRowError(OtherParentsColumn, fmt.Sprintf("%s is already the mother or the father", parent.reference))
+				continue
+			}
+
+			if gender, ok := genderByKey[parent.reference]; ok && gender.IsValid() && parent.role.Gender() != "" && gender != parent.role.Gender() {
+				addRowError(OtherParentsColumn, fmt.Sprintf("%s %s is %s", parent.role, parent.reference, gender))
+			}
+		}
+	}
+
+	return sheet, nil
+}
+
+// RowErrorsFromBatchItemErrors points the errors found validating the batch to the rows they came from.
+func (s Sheet) RowErrorsFromBatchItemErrors(itemErrors []domain.BatchItemError) []RowError {
+	var rowErrors []RowError
+	for _, itemError := range itemErrors {
+		rowError := RowError{Key: itemError.TempID, Err: itemError.Err}
+		if itemError.Index >= 0 && itemError.Index < len(s.Lines) {
+			rowError.Line = s.Lines[itemError.Index]
+		}
+
+		rowErrors = append(rowErrors, rowError)
+	}
+
+	return rowErrors
+}
+
+func lifeEventValues(lifeEvent *domain.LifeEvent) (string, string) {
+	if lifeEvent == nil {
+		return "", ""
+	}
+
+	return lifeEvent.Date, lifeEvent.Place
+}
+
+// Encode writes the persons with the Header columns, sorted by id so exporting twice gives the same file. Only
+// biological mothers and fathers go to their column. Every other parent, generic ones too, goes to other_parents with
+// its link type and role, so the file is imported back with the same roles whatever the gender of the parents.
+func Encode(writer io.Writer, persons []domain.Person) error {
+	sortedPersons := append([]domain.Person{}, persons...)
+	sort.Slice(sortedPersons, func(i, j int) bool {
+		return sortedPersons[i].ID < sortedPersons[j].ID
+	})
+
+	csvWriter := csv.NewWriter(writer)
+	if err := csvWriter.Write(Header); err != nil {
+		return err
+	}
+
+	for _, person := range sortedPersons {
+		var mother, father string
+		var otherParents []string
+		for _, parent := range person.Parents {
+			linkType := person.ParentLinkType(parent.ID)
+			role := person.ParentRole(parent.ID)
+			switch {
+			case linkType == domain.BiologicalLinkType && role == domain.MotherParentRole && mother == "":
+				mother = parent.ID
+			case linkType == domain.BiologicalLinkType && role == domain.FatherParentRole && father == "":
+				father = parent.ID
+			default:
+				otherParents = append(otherParents, formatOtherParent(parent.ID, linkType, role))
+			}
+		}
+
+		birthDate, birthPlace := lifeEventValues(person.Birth)
+		deathDate, deathPlace := lifeEventValues(person.Death)
+		if err := csvWriter.Write([]string{person.ID, person.Name, string(person.Gender), mother, father, strings.Join(otherParents, otherParentsSeparator), birthDate, birthPlace, deathDate, deathPlace}); err != nil {
+			return err
+		}
+	}
+
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
@@ -0,0 +1,232 @@
+package spreadsheet
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/CaioBittencourt/arvore-genealogica/domain"
+	"github.com/CaioBittencourt/arvore-genealogica/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+const familyCSV = `id,name,gender,mother,father,birth_date,birth_place
+luis,Luis Bittencourt,M,,,,
+dayse,Dayse Bittencourt,female,,,,
+caio,Caio Bittencourt,male,dayse,luis,1 JAN 1990,Porto Alegre
+,Zézé,F,,64138dcbd2a5f3b8e8a24f1a,,
+`
+
+func TestDecode(t *testing.T) {
+	type testArgs struct {
+		testName         string
+		file             string
+		expectedErrCode  errors.ApplicationErrorCode
+		expectedRowLines []int
+		expectedColumns  []string
+	}
+
+	tests := []testArgs{
+		{
+			testName:        "should fail when a required column is missing",
+			file:            "id,name\ncaio,Caio\n",
+			expectedErrCode: errors.InvalidCSVErrorCode,
+		},
+		{
+			testName:        "should fail when the file is empty",
+			file:            "",
+			expectedErrCode: errors.InvalidCSVErrorCode,
+		},
+		{
+			testName:        "should fail when a row has a different number of columns",
+			file:            "name,gender\nCaio,male,extra\n",
+			expectedErrCode: errors.InvalidCSVErrorCode,
+		},
+		{
+			testName:         "should report a mother that is not female and a father that is not male",
+			file:             "id,name,gender,mother,father\nluis,Luis,male,,\ndayse,Dayse,female,,\ncaio,Caio,male,luis,dayse\n",
+			expectedRowLines: []int{4, 4},
+			expectedColumns:  []string{MotherColumn, FatherColumn},
+		},
+		{
+			testName:         "should report other parents that can not be read or that are already a parent",
+			file:             "id,name,gender,mother,father,other_parents\nluis,Luis,male,,,\ndayse,Dayse,female,,,\ncaio,Caio,male,,,luis:uncle\nvini,Vini,male,,luis,luis:adoptive;dayse:step:father\n",
+			expectedRowLines: []int{4, 5, 5},
+			expectedColumns:  []string{OtherParentsColumn, OtherParentsColumn, OtherParentsColumn},
+		},
+		{
+			testName:         "should report a person that is its own parent or both parents of another",
+			file:             "id,name,gender,mother,father\nluis,Luis,male,,luis\ncaio,Caio,male,luis,luis\n",
+			expectedRowLines: []int{2, 3},
+			expectedColumns:  []string{FatherColumn, FatherColumn},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(tt testArgs) func(t *testing.T) {
+			return func(t *testing.T) {
+				sheet, err := Decode(strings.NewReader(tt.file))
+				if tt.expectedErrCode != "" {
+					assert.True(t, errors.ErrorHasCode(err, tt.expectedErrCode), err)
+					return
+				}
+
+				assert.NoError(t, err)
+				var lines []int
+				var columns []string
+				for _, rowError := range sheet.Errors {
+					lines = append(lines, rowError.Line)
+					columns = append(columns, rowError.Column)
+				}
+				assert.Equal(t, tt.expectedRowLines, lines)
+				assert.Equal(t, tt.expectedColumns, columns)
+			}
+		}(tt))
+	}
+}
+
+func TestDecodeFamily(t *testing.T) {
+	sheet, err := Decode(strings.NewReader("\ufeff" + familyCSV))
+	assert.NoError(t, err)
+	assert.Empty(t, sheet.Errors)
+	assert.Len(t, sheet.Batch, 4)
+	assert.Equal(t, []int{2, 3, 4, 5}, sheet.Lines)
+
+	caio := sheet.Batch[2]
+	assert.Equal(t, "caio", caio.TempID)
+	assert.Equal(t, domain.Male, caio.Person.Gender)
+	assert.Equal(t, "dayse", caio.Person.Parents[0].ID)
+	assert.Equal(t, "luis", caio.Person.Parents[1].ID)
+	assert.Equal(t, domain.MotherParentRole, caio.Person.ParentRole("dayse"))
+	assert.Equal(t, domain.FatherParentRole, caio.Person.ParentRole("luis"))
+	assert.Equal(t, &domain.LifeEvent{Date: "1 JAN 1990", Place: "Porto Alegre"}, caio.Person.Birth)
+	assert.Nil(t, caio.Person.Death)
+
+	zeze := sheet.Batch[3]
+	assert.Equal(t, "line 5", zeze.TempID)
+	assert.Equal(t, domain.Female, zeze.Person.Gender)
+	assert.Equal(t, []string{"64138dcbd2a5f3b8e8a24f1a"}, sheet.Batch.ExternalIDS())
+}
+
+func TestRowErrorsFromBatchItemErrors(t *testing.T) {
+	sheet, err := Decode(strings.NewReader("id,name,gender,mother\ncaio,C,male,\nluis,Luis,unknown,nobody\n"))
+	assert.NoError(t, err)
+
+	rowErrors := sheet.RowErrorsFromBatchItemErrors(sheet.Batch.Validate(nil))
+	assert.Len(t, rowErrors, 2)
+
+	var lines []int
+	for _, rowError := range rowErrors {
+		lines = append(lines, rowError.Line)
+	}
+	assert.ElementsMatch(t, []int{2, 3}, lines)
+}
+
+func TestEncode(t *testing.T) {
+	luis := domain.Person{ID: "IDLuis", Name: "Luis", Gender: domain.Male}
+	dayse := domain.Person{ID: "IDDayse", Name: "Dayse, \"Dá\"", Gender: domain.Female, Death: &domain.LifeEvent{Date: "2020"}}
+	caio := domain.Person{ID: "IDCaio", Name: "Caio", Gender: domain.Male, Parents: []*domain.Person{{ID: "IDLuis"}, {ID: "IDDayse"}}, Birth: &domain.LifeEvent{Place: "Porto Alegre"}}
+	vini := domain.Person{ID: "IDVini", Name: "Vini", Gender: domain.Male}
+	vini.AddParent(&domain.Person{ID: "IDDayse"}, domain.GenericParentRole)
+	vini.AddParent(&domain.Person{ID: "IDLuis"}, domain.FatherParentRole)
+
+	var buffer bytes.Buffer
+	assert.NoError(t, Encode(&buffer, []domain.Person{luis, dayse, caio, vini}))
+
+	assert.Equal(t, strings.Join([]string{
+		"id,name,gender,mother,father,other_parents,birth_date,birth_place,death_date,death_place",
+		"IDCaio,Caio,male,,,IDLuis:biological:parent;IDDayse:biological:parent,,Porto Alegre,,",
+		`IDDayse,"Dayse, ""Dá""",female,,,,,,2020,`,
+		"IDLuis,Luis,male,,,,,,,",
+		"IDVini,Vini,male,,IDLuis,IDDayse:biological:parent,,,,",
+		"",
+	}, "\n"), buffer.String())
+
+	sheet, err := Decode(&buffer)
+	assert.NoError(t, err)
+	assert.Empty(t, sheet.Errors)
+	assert.Empty(t, sheet.Batch.Validate(nil))
+}
+
+func TestEncodeOtherParents(t *testing.T) {
+	luis := domain.Person{ID: "IDLuis", Name: "Luis", Gender: domain.Male}
+	dayse := domain.Person{ID: "IDDayse", Name: "Dayse", Gender: domain.Female}
+	tunico := domain.Person{ID: "IDTunico", Name: "Tunico", Gender: domain.Male}
+	ana := domain.Person{ID: "IDAna", Name: "Ana", Gender: domain.Female}
+	caio := domain.Person{ID: "IDCaio", Name: "Caio", Gender: domain.Male}
+	caio.AddParent(&domain.Person{ID: "IDLuis"}, domain.FatherParentRole)
+	caio.AddParent(&domain.Person{ID: "IDDayse"}, domain.MotherParentRole)
+	caio.AddParent(&domain.Person{ID: "IDTunico"}, domain.FatherParentRole)
+	caio.SetParentLinkType("IDTunico", domain.AdoptiveLinkType)
+	caio.AddParent(&domain.Person{ID: "IDAna"}, domain.GenericParentRole)
+	caio.SetParentLinkType("IDAna", domain.StepLinkType)
+
+	var buffer bytes.Buffer
+	assert.NoError(t, Encode(&buffer, []domain.Person{luis, dayse, tunico, ana, caio}))
+	assert.Contains(t, buffer.String(), "IDCaio,Caio,male,IDDayse,IDLuis,IDTunico:adoptive:father;IDAna:step:parent,,,,\n")
+
+	sheet, err := Decode(&buffer)
+	assert.NoError(t, err)
+	assert.Empty(t, sheet.Errors)
+	assert.Empty(t, sheet.Batch.Validate(nil))
+
+	decodedCaio := sheet.Batch[1].Person
+	assert.Len(t, decodedCaio.Parents, 4)
+	assert.Equal(t, map[string]domain.LinkType{"IDTunico": domain.AdoptiveLinkType, "IDAna": domain.StepLinkType}, decodedCaio.ParentLinkTypes)
+	assert.Equal(t, domain.FatherParentRole, decodedCaio.ParentRole("IDTunico"))
+	assert.Equal(t, domain.GenericParentRole, decodedCaio.ParentRole("IDAna"))
+}
+
+func TestEncodeSameSexParents(t *testing.T) {
+	type testArgs struct {
+		testName      string
+		parentRoles   []domain.ParentRoleType
+		expectedRoles []domain.ParentRoleType
+	}
+
+	tests := []testArgs{
+		{
+			testName:      "should import generic parents back as generic parents",
+			parentRoles:   []domain.ParentRoleType{domain.GenericParentRole, domain.GenericParentRole},
+			expectedRoles: []domain.ParentRoleType{domain.GenericParentRole, domain.GenericParentRole},
+		},
+		{
+			testName:      "should import a father and a generic parent back with their roles",
+			parentRoles:   []domain.ParentRoleType{domain.FatherParentRole, domain.GenericParentRole},
+			expectedRoles: []domain.ParentRoleType{domain.FatherParentRole, domain.GenericParentRole},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(tt testArgs) func(t *testing.T) {
+			return func(t *testing.T) {
+				a := domain.Person{ID: "a", Name: "Alan", Gender: domain.Male}
+				b := domain.Person{ID: "b", Name: "Bruno", Gender: domain.Male}
+				c := domain.Person{ID: "c", Name: "Clara", Gender: domain.Female}
+				c.AddParent(&domain.Person{ID: "a"}, tt.parentRoles[0])
+				c.AddParent(&domain.Person{ID: "b"}, tt.parentRoles[1])
+
+				var buffer bytes.Buffer
+				assert.NoError(t, Encode(&buffer, []domain.Person{a, b, c}))
+
+				sheet, err := Decode(&buffer)
+				assert.NoError(t, err)
+				assert.Empty(t, sheet.Errors)
+				assert.Empty(t, sheet.Batch.Validate(nil))
+
+				var decodedC *domain.Person
+				for _, batchPerson := range sheet.Batch {
+					if batchPerson.TempID == "c" {
+						decodedC = &batchPerson.Person
+					}
+				}
+
+				if assert.NotNil(t, decodedC) {
+					assert.Len(t, decodedC.Parents, 2)
+					assert.Empty(t, decodedC.ParentLinkTypes)
+					assert.Equal(t, tt.expectedRoles, []domain.ParentRoleType{decodedC.ParentRole("a"), decodedC.ParentRole("b")})
+				}
+			}
+		}(tt))
+	}
+}
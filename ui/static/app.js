@@ -0,0 +1,524 @@
+(function () {
+  'use strict';
+
+  const SVG_NS = 'http://www.w3.org/2000/svg';
+  const NODE_WIDTH = 150;
+  const NODE_HEIGHT = 44;
+  const COLUMN_WIDTH = 180;
+  const ROW_HEIGHT = 110;
+  const MIN_SCALE = 0.1;
+  const MAX_SCALE = 4;
+
+  // generation of the relative compared to the member the relationship belongs to, ancestors have higher generations
+  const generationDeltaByRelationship = {
+    parent: 1,
+    'aunt/uncle': 1,
+    child: -1,
+    nephew: -1,
+  };
+
+  const inverseRelationships = {
+    parent: 'child',
+    child: 'parent',
+    'aunt/uncle': 'nephew',
+    nephew: 'aunt/uncle',
+  };
+
+  const state = {
+    treeID: '',
+    rootID: '',
+    members: {},
+    relatives: {},
+    positions: {},
+    relationshipByKey: {},
+    baconsNumberByKey: {},
+    hoveredID: '',
+    view: { x: 0, y: 0, scale: 1 },
+  };
+
+  const byID = (id) => document.getElementById(id);
+  const chart = byID('chart');
+  const viewport = byID('viewport');
+  const tooltip = byID('tooltip');
+
+  function apiBase() {
+    return state.treeID ? '/trees/' + encodeURIComponent(state.treeID) : '';
+  }
+
+  async function getJSON(path) {
+    const response = await fetch(apiBase() + path, { headers: { Accept: 'application/json' } });
+    const body = await response.json().catch(() => ({}));
+    if (!response.ok) {
+      throw new Error(body.errorMessage || response.statusText);
+    }
+
+    return body;
+  }
+
+  function setStatus(message, isError) {
+    const status = byID('status');
+    status.textContent = message;
+    status.classList.toggle('error', Boolean(isError));
+  }
+
+  function memberName(id) {
+    const member = state.members[id];
+    return member ? member.name : id;
+  }
+
+  // Relationships are only computed from one of the persons of each pair, so every one is added to both persons.
+  function buildRelatives(members) {
+    const relatives = {};
+    const relativesOf = (id) => {
+      if (!relatives[id]) {
+        relatives[id] = { parents: new Set(), children: new Set(), spouses: new Set(), neighbours: [] };
+      }
+      return relatives[id];
+    };
+
+    Object.values(members).forEach((member) => {
+      relativesOf(member.id);
+      member.relationships.forEach(({ person, relationship }) => {
+        if (!members[person.id]) {
+          return;
+        }
+
+        const delta = generationDeltaByRelationship[relationship] || 0;
+        relativesOf(member.id).neighbours.push({ id: person.id, delta: delta });
+        relativesOf(person.id).neighbours.push({ id: member.id, delta: -delta });
+
+        if (relationship === 'parent') {
+          relativesOf(member.id).parents.add(person.id);
+          relativesOf(person.id).children.add(member.id);
+        } else if (relationship === 'child') {
+          relativesOf(member.id).children.add(person.id);
+          relativesOf(person.id).parents.add(member.id);
+        } else if (relationship === 'spouse') {
+          relativesOf(member.id).spouses.add(person.id);
+          relativesOf(person.id).spouses.add(member.id);
+        }
+      });
+    });
+
+    return relatives;
+  }
+
+  function assignGenerations(rootID, relatives) {
+    const generations = { [rootID]: 0 };
+    const queue = [rootID];
+    while (queue.length > 0) {
+      const id = queue.shift();
+      relatives[id].neighbours.forEach((neighbour) => {
+        if (generations[neighbour.id] === undefined) {
+          generations[neighbour.id] = generations[id] + neighbour.delta;
+          queue.push(neighbour.id);
+        }
+      });
+    }
+
+    Object.keys(relatives).forEach((id) => {
+      if (generations[id] === undefined) {
+        generations[id] = 0;
+      }
+    });
+
+    return generations;
+  }
+
+  // layout places one row per generation, oldest on top. Each person goes under the middle of its parents and
+  // spouses without parents in the tree are placed right beside their partner.
+  function layout(rootID, relatives) {
+    const generations = assignGenerations(rootID, relatives);
+    const idsByGeneration = {};
+    Object.keys(generations).forEach((id) => {
+      (idsByGeneration[generations[id]] = idsByGeneration[generations[id]] || []).push(id);
+    });
+
+    const positions = {};
+    const sortedGenerations = Object.keys(idsByGeneration).map(Number).sort((a, b) => b - a);
+    sortedGenerations.forEach((generation, row) => {
+      const ids = idsByGeneration[generation];
+      const parentsCenter = {};
+      ids.forEach((id) => {
+        const parentXs = [...relatives[id].parents].filter((parentID) => positions[parentID]).map((parentID) => positions[parentID].x);
+        if (parentXs.length > 0) {
+          parentsCenter[id] = parentXs.reduce((sum, x) => sum + x, 0) / parentXs.length;
+        }
+      });
+
+      const sortKey = (id) => (parentsCenter[id] === undefined ? Infinity : parentsCenter[id]);
+      ids.sort((a, b) => sortKey(a) - sortKey(b) || memberName(a).localeCompare(memberName(b)));
+
+      const ordered = [];
+      const added = new Set();
+      ids.forEach((id) => {
+        if (added.has(id)) {
+          return;
+        }
+
+        ordered.push(id);
+        added.add(id);
+        relatives[id].spouses.forEach((spouseID) => {
+          if (!added.has(spouseID) && generations[spouseID] === generation && parentsCenter[spouseID] === undefined) {
+            ordered.push(spouseID);
+            added.add(spouseID);
+          }
+        });
+      });
+
+      let previousX = -Infinity;
+      ordered.forEach((id) => {
+        const wantedX = parentsCenter[id] === undefined ? previousX + COLUMN_WIDTH : parentsCenter[id];
+        const x = Math.max(Number.isFinite(wantedX) ? wantedX : 0, previousX + COLUMN_WIDTH);
+        positions[id] = { x: x, y: row * ROW_HEIGHT };
+        previousX = x;
+      });
+    });
+
+    return positions;
+  }
+
+  function svgElement(name, attributes) {
+    const element = document.createElementNS(SVG_NS, name);
+    Object.keys(attributes).forEach((attribute) => element.setAttribute(attribute, attributes[attribute]));
+    return element;
+  }
+
+  function truncate(name) {
+    return name.length > 20 ? name.slice(0, 19) + '…' : name;
+  }
+
+  function render() {
+    const links = byID('links');
+    const nodes = byID('nodes');
+    links.replaceChildren();
+    nodes.replaceChildren();
+
+    Object.keys(state.relatives).forEach((id) => {
+      const position = state.positions[id];
+      state.relatives[id].parents.forEach((parentID) => {
+        const parentPosition = state.positions[parentID];
+        const startY = parentPosition.y + NODE_HEIGHT / 2;
+        const endY = position.y - NODE_HEIGHT / 2;
+        const middleY = (startY + endY) / 2;
+        links.appendChild(svgElement('path', {
+          class: 'link',
+          d: `M${parentPosition.x} ${startY} C${parentPosition.x} ${middleY} ${position.x} ${middleY} ${position.x} ${endY}`,
+        }));
+      });
+
+      state.relatives[id].spouses.forEach((spouseID) => {
+        const spousePosition = state.positions[spouseID];
+        if (id < spouseID && spousePosition.y === position.y) {
+          const [left, right] = position.x < spousePosition.x ? [position, spousePosition] : [spousePosition, position];
+          links.appendChild(svgElement('line', {
+            class: 'link spouse',
+            x1: left.x + NODE_WIDTH / 2,
+            y1: left.y,
+            x2: right.x - NODE_WIDTH / 2,
+            y2: right.y,
+          }));
+        }
+      });
+    });
+
+    Object.keys(state.positions).forEach((id) => {
+      const member = state.members[id];
+      const position = state.positions[id];
+      const gender = member.gender === 'male' || member.gender === 'female' ? member.gender : 'unknown';
+      const node = svgElement('g', {
+        class: `node ${gender}${id === state.rootID ? ' root' : ''}`,
+        transform: `translate(${position.x} ${position.y})`,
+        'data-id': id,
+      });
+      node.appendChild(svgElement('rect', {
+        x: -NODE_WIDTH / 2,
+        y: -NODE_HEIGHT / 2,
+        width: NODE_WIDTH,
+        height: NODE_HEIGHT,
+        rx: 6,
+      }));
+      const text = svgElement('text', { x: 0, y: 0 });
+      text.textContent = truncate(member.name);
+      node.appendChild(text);
+      nodes.appendChild(node);
+    });
+  }
+
+  function applyView() {
+    const { x, y, scale } = state.view;
+    viewport.setAttribute('transform', `translate(${x} ${y}) scale(${scale})`);
+  }
+
+  function fit() {
+    const ids = Object.keys(state.positions);
+    if (ids.length === 0) {
+      return;
+    }
+
+    const xs = ids.map((id) => state.positions[id].x);
+    const ys = ids.map((id) => state.positions[id].y);
+    const minX = Math.min(...xs) - NODE_WIDTH;
+    const maxX = Math.max(...xs) + NODE_WIDTH;
+    const minY = Math.min(...ys) - NODE_HEIGHT;
+    const maxY = Math.max(...ys) + NODE_HEIGHT;
+    const scale = Math.min(chart.clientWidth / (maxX - minX), chart.clientHeight / (maxY - minY), 1.5);
+
+    state.view = {
+      scale: scale,
+      x: (chart.clientWidth - (maxX - minX) * scale) / 2 - minX * scale,
+      y: (chart.clientHeight - (maxY - minY) * scale) / 2 - minY * scale,
+    };
+    applyView();
+  }
+
+  function centerOn(id) {
+    const position = state.positions[id];
+    state.view.x = chart.clientWidth / 2 - position.x * state.view.scale;
+    state.view.y = chart.clientHeight / 2 - position.y * state.view.scale;
+    applyView();
+  }
+
+  function zoom(factor, centerX, centerY) {
+    const scale = Math.min(MAX_SCALE, Math.max(MIN_SCALE, state.view.scale * factor));
+    const x = centerX === undefined ? chart.clientWidth / 2 : centerX;
+    const y = centerY === undefined ? chart.clientHeight / 2 : centerY;
+    state.view.x = x - (x - state.view.x) * (scale / state.view.scale);
+    state.view.y = y - (y - state.view.y) * (scale / state.view.scale);
+    state.view.scale = scale;
+    applyView();
+  }
+
+  function updateSearchOptions() {
+    const options = Object.values(state.members)
+      .map((member) => member.name)
+      .sort((a, b) => a.localeCompare(b))
+      .map((name) => {
+        const option = document.createElement('option');
+        option.value = name;
+        return option;
+      });
+    byID('members').replaceChildren(...options);
+  }
+
+  async function loadTree(personID) {
+    setStatus('Loading…');
+    try {
+      const tree = await getJSON(`/person/${encodeURIComponent(personID)}/tree`);
+      state.rootID = personID;
+      state.members = tree.members;
+      state.relatives = buildRelatives(tree.members);
+      state.positions = layout(personID, state.relatives);
+    } catch (err) {
+      setStatus(`Could not load the tree: ${err.message}`, true);
+      return;
+    }
+
+    byID('person-id').value = personID;
+    const params = new URLSearchParams({ person: personID });
+    if (state.treeID) {
+      params.set('tree', state.treeID);
+    }
+    history.replaceState(null, '', `?${params}`);
+
+    render();
+    fit();
+    updateSearchOptions();
+    setStatus(`${Object.keys(state.members).length} persons in the tree of ${memberName(personID)}`);
+  }
+
+  function describeRelationship(personWithRelationship, hoveredID) {
+    const otherID = personWithRelationship.id === hoveredID ? state.rootID : hoveredID;
+    const relationship = personWithRelationship.relationships.find((r) => r.person.id === otherID);
+    if (!relationship) {
+      return '';
+    }
+
+    if (personWithRelationship.id === state.rootID) {
+      return relationship.relationship;
+    }
+
+    return inverseRelationships[relationship.relationship] || relationship.relationship;
+  }
+
+  // relationshipToRoot returns what the person is to the root person, from the loaded tree when the relationship is
+  // a direct one and from the API otherwise.
+  async function relationshipToRoot(id) {
+    const key = `${state.treeID}|${state.rootID}|${id}`;
+    if (state.relationshipByKey[key] !== undefined) {
+      return state.relationshipByKey[key];
+    }
+
+    let relationship = describeRelationship(state.members[state.rootID], id) || describeRelationship(state.members[id], id);
+    if (!relationship) {
+      try {
+        const personWithRelationship = await getJSON(`/person/${encodeURIComponent(state.rootID)}/relationship/${encodeURIComponent(id)}`);
+        relationship = describeRelationship(personWithRelationship, id);
+      } catch (err) {
+        relationship = '';
+      }
+    }
+
+    state.relationshipByKey[key] = relationship || 'no known relationship';
+    return state.relationshipByKey[key];
+  }
+
+  async function baconsNumberToRoot(id) {
+    const key = `${state.treeID}|${state.rootID}|${id}`;
+    if (state.baconsNumberByKey[key] === undefined) {
+      try {
+        const response = await getJSON(`/person/${encodeURIComponent(state.rootID)}/baconNumber/${encodeURIComponent(id)}`);
+        state.baconsNumberByKey[key] = response.baconsNumber;
+      } catch (err) {
+        state.baconsNumberByKey[key] = null;
+      }
+    }
+
+    return state.baconsNumberByKey[key];
+  }
+
+  function showTooltip(id, lines, event) {
+    tooltip.replaceChildren(...lines.map((line, i) => {
+      const element = document.createElement(i === 0 ? 'strong' : 'div');
+      element.textContent = line;
+      return element;
+    }));
+
+    if (event) {
+      const bounds = chart.getBoundingClientRect();
+      tooltip.style.left = `${event.clientX - bounds.left + 12}px`;
+      tooltip.style.top = `${event.clientY - bounds.top + 12}px`;
+    }
+    tooltip.hidden = false;
+  }
+
+  async function hover(id, event) {
+    state.hoveredID = id;
+    const name = memberName(id);
+    if (id === state.rootID) {
+      showTooltip(id, [name, 'root person'], event);
+      return;
+    }
+
+    showTooltip(id, [name, 'loading relationship…'], event);
+    const [relationship, baconsNumber] = await Promise.all([relationshipToRoot(id), baconsNumberToRoot(id)]);
+    if (state.hoveredID !== id) {
+      return;
+    }
+
+    const lines = [name, `${relationship} of ${memberName(state.rootID)}`];
+    if (baconsNumber !== null) {
+      lines.push(`bacon's number: ${baconsNumber}`);
+    }
+    showTooltip(id, lines);
+  }
+
+  function nodeIDFromEvent(event) {
+    const node = event.target.closest('.node');
+    return node ? node.getAttribute('data-id') : '';
+  }
+
+  function find(query) {
+    const normalized = query.trim().toLocaleLowerCase();
+    const matches = Object.values(state.members).filter((member) => member.name.toLocaleLowerCase().includes(normalized));
+    document.querySelectorAll('.node.found').forEach((node) => node.classList.remove('found'));
+    if (!normalized || matches.length === 0) {
+      setStatus(`No person named "${query}" in this tree`, true);
+      return;
+    }
+
+    matches.sort((a, b) => (a.name.toLocaleLowerCase() === normalized ? -1 : 0) - (b.name.toLocaleLowerCase() === normalized ? -1 : 0));
+    matches.forEach((member) => {
+      const node = document.querySelector(`.node[data-id="${CSS.escape(member.id)}"]`);
+      if (node) {
+        node.classList.add('found');
+      }
+    });
+    centerOn(matches[0].id);
+    setStatus(matches.length === 1 ? `Found ${matches[0].name}` : `${matches.length} persons match "${query}"`);
+  }
+
+  let drag = null;
+  chart.addEventListener('pointerdown', (event) => {
+    drag = { x: event.clientX, y: event.clientY, viewX: state.view.x, viewY: state.view.y, moved: false };
+    chart.setPointerCapture(event.pointerId);
+  });
+
+  chart.addEventListener('pointermove', (event) => {
+    if (!drag) {
+      return;
+    }
+
+    const dx = event.clientX - drag.x;
+    const dy = event.clientY - drag.y;
+    if (Math.abs(dx) + Math.abs(dy) > 3) {
+      drag.moved = true;
+      chart.classList.add('dragging');
+      tooltip.hidden = true;
+    }
+    state.view.x = drag.viewX + dx;
+    state.view.y = drag.viewY + dy;
+    applyView();
+  });
+
+  chart.addEventListener('pointerup', (event) => {
+    const wasClick = drag && !drag.moved;
+    drag = null;
+    chart.classList.remove('dragging');
+
+    const id = wasClick ? nodeIDFromEvent(event) : '';
+    if (id && id !== state.rootID) {
+      tooltip.hidden = true;
+      loadTree(id);
+    }
+  });
+
+  chart.addEventListener('wheel', (event) => {
+    event.preventDefault();
+    const bounds = chart.getBoundingClientRect();
+    zoom(event.deltaY < 0 ? 1.1 : 1 / 1.1, event.clientX - bounds.left, event.clientY - bounds.top);
+  }, { passive: false });
+
+  chart.addEventListener('mouseover', (event) => {
+    const id = nodeIDFromEvent(event);
+    if (id && id !== state.hoveredID && !drag) {
+      hover(id, event);
+    }
+  });
+
+  chart.addEventListener('mouseout', (event) => {
+    const id = nodeIDFromEvent(event);
+    const related = event.relatedTarget && event.relatedTarget.closest ? event.relatedTarget.closest('.node') : null;
+    if (id && (!related || related.getAttribute('data-id') !== id)) {
+      state.hoveredID = '';
+      tooltip.hidden = true;
+    }
+  });
+
+  byID('zoom-in').addEventListener('click', () => zoom(1.25));
+  byID('zoom-out').addEventListener('click', () => zoom(0.8));
+  byID('zoom-reset').addEventListener('click', fit);
+
+  byID('load-form').addEventListener('submit', (event) => {
+    event.preventDefault();
+    const treeID = byID('tree-id').value.trim();
+    if (treeID !== state.treeID) {
+      state.treeID = treeID;
+      state.relationshipByKey = {};
+      state.baconsNumberByKey = {};
+    }
+    loadTree(byID('person-id').value.trim());
+  });
+
+  byID('search-form').addEventListener('submit', (event) => {
+    event.preventDefault();
+    find(byID('search').value);
+  });
+
+  const params = new URLSearchParams(location.search);
+  state.treeID = params.get('tree') || '';
+  byID('tree-id').value = state.treeID;
+  if (params.get('person')) {
+    loadTree(params.get('person'));
+  }
+})();
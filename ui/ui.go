@@ -0,0 +1,20 @@
+package ui
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed static
+var static embed.FS
+
+// FS holds the single page family tree viewer. It only uses the public API, so it works against any tree through
+// the ?tree= query parameter.
+func FS() fs.FS {
+	files, err := fs.Sub(static, "static")
+	if err != nil {
+		panic(err)
+	}
+
+	return files
+}
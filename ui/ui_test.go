@@ -0,0 +1,24 @@
+package ui
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFS(t *testing.T) {
+	index, err := fs.ReadFile(FS(), "index.html")
+	assert.NoError(t, err)
+	assert.Contains(t, string(index), `<script src="app.js"></script>`)
+	assert.Contains(t, string(index), `<link rel="stylesheet" href="style.css">`)
+
+	app, err := fs.ReadFile(FS(), "app.js")
+	assert.NoError(t, err)
+	for _, path := range []string{"/tree`", "/relationship/", "/baconNumber/"} {
+		assert.Contains(t, string(app), path)
+	}
+
+	_, err = fs.Stat(FS(), "style.css")
+	assert.NoError(t, err)
+}